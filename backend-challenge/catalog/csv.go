@@ -0,0 +1,177 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"oolio.com/kart/money"
+	"strconv"
+	"strings"
+)
+
+// csvColumns is the header of a CSV catalog, the allergens and diets columns hold comma separated lists and the meta
+// column holds a JSON object
+var csvColumns = []string{"id", "name", "category", "price", "status", "image_thumbnail", "image_mobile", "image_tablet", "image_desktop", "description", "allergens", "diets", "meta"}
+
+// DecodeCSV reads a catalog stored as CSV with a header row, columns may be in any order and only name, category and
+// price are required
+func DecodeCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []Record{}, nil
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "category", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", required)
+		}
+	}
+
+	records := []Record{}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", line, err)
+		}
+
+		record, err := decodeCSVRow(row, columns)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV row %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+}
+
+func decodeCSVRow(row []string, columns map[string]int) (Record, error) {
+	value := func(column string) string {
+		if i, ok := columns[column]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	record := Record{
+		Name:        value("name"),
+		Description: value("description"),
+		Category:    value("category"),
+		Status:      value("status"),
+		Image: Image{
+			Thumbnail: value("image_thumbnail"),
+			Mobile:    value("image_mobile"),
+			Tablet:    value("image_tablet"),
+			Desktop:   value("image_desktop"),
+		},
+		Allergens: splitList(value("allergens")),
+		Diets:     splitList(value("diets")),
+	}
+
+	var err error
+	if id := value("id"); id != "" {
+		if record.Id, err = strconv.ParseInt(id, 10, 64); err != nil {
+			return record, fmt.Errorf("invalid id %q", id)
+		}
+	}
+
+	if record.Price, err = money.ParseRound(value("price"), PriceRounding); err != nil {
+		return record, fmt.Errorf("invalid price %q", value("price"))
+	}
+
+	if meta := value("meta"); meta != "" {
+		if err = json.Unmarshal([]byte(meta), &record.Meta); err != nil {
+			return record, fmt.Errorf("meta must be a JSON object: %w", err)
+		}
+	}
+
+	return record, nil
+}
+
+// splitList splits a comma separated cell, an empty cell is an empty list
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// CSVEncoder streams records as CSV rows after a header row
+type CSVEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// NewCSVEncoder creates a new CSVEncoder
+func NewCSVEncoder(w io.Writer) *CSVEncoder {
+	return &CSVEncoder{w: csv.NewWriter(w)}
+}
+
+// Encode writes a record as the next row
+func (e *CSVEncoder) Encode(record *Record) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	var meta string
+	if len(record.Meta) > 0 {
+		data, err := json.Marshal(record.Meta)
+		if err != nil {
+			return fmt.Errorf("failed to encode meta of product %s: %w", record.Name, err)
+		}
+		meta = string(data)
+	}
+
+	var id string
+	if record.Id != 0 {
+		id = strconv.FormatInt(record.Id, 10)
+	}
+
+	return e.w.Write([]string{
+		id,
+		record.Name,
+		record.Category,
+		record.Price.String(),
+		record.Status,
+		record.Image.Thumbnail,
+		record.Image.Mobile,
+		record.Image.Tablet,
+		record.Image.Desktop,
+		record.Description,
+		strings.Join(record.Allergens, ","),
+		strings.Join(record.Diets, ","),
+		meta,
+	})
+}
+
+// Close writes the header of an empty catalog and flushes the buffered rows
+func (e *CSVEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *CSVEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.w.Write(csvColumns)
+}
@@ -0,0 +1,57 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DecodeJSON reads a catalog stored as a JSON array of records
+func DecodeJSON(r io.Reader) ([]Record, error) {
+	var records []Record
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON catalog: %w", err)
+	}
+	return records, nil
+}
+
+// JSONEncoder streams records as a JSON array
+type JSONEncoder struct {
+	w     io.Writer
+	count int
+}
+
+// NewJSONEncoder creates a new JSONEncoder
+func NewJSONEncoder(w io.Writer) *JSONEncoder {
+	return &JSONEncoder{w: w}
+}
+
+// Encode writes a record as the next element of the array
+func (e *JSONEncoder) Encode(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode product %s: %w", record.Name, err)
+	}
+
+	separator := ",\n"
+	if e.count == 0 {
+		separator = "[\n"
+	}
+	e.count++
+
+	if _, err = io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// Close terminates the array
+func (e *JSONEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
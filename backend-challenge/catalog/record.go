@@ -0,0 +1,111 @@
+// Package catalog reads and writes product catalogs in the file formats shared by the product migration and the
+// admin import and export endpoints. It only depends on the standard library and the money package so the migrations
+// module can use it.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"oolio.com/kart/money"
+	"strings"
+)
+
+// Supported catalog formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Record is a product as stored in a catalog file, products are identified by their name and category
+type Record struct {
+	Id          int64       `json:"id,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Category    string      `json:"category"`
+	Price       money.Money `json:"price"`
+	Status      string      `json:"status,omitempty"`
+	Image       Image       `json:"image"`
+	// Allergens and Diets are validated by the importer, the catalog package does not know their values
+	Allergens []string       `json:"allergens,omitempty"`
+	Diets     []string       `json:"diets,omitempty"`
+	Meta      map[string]any `json:"meta,omitempty"`
+}
+
+// PriceRounding is how prices with fractions of a cent, written by other tools, are rounded to the cents the database
+// keeps, so that re-imports of the same catalog are unchanged
+const PriceRounding = money.HalfUp
+
+// UnmarshalJSON decodes a record, rounding its price to the cent, a record without a price is free
+func (r *Record) UnmarshalJSON(data []byte) error {
+	type record Record
+	var fields struct {
+		*record
+		Price json.Number `json:"price"`
+	}
+	fields.record = (*record)(r)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if fields.Price == "" {
+		return nil
+	}
+
+	price, err := money.ParseRound(fields.Price.String(), PriceRounding)
+	if err != nil {
+		return fmt.Errorf("invalid price %q", fields.Price)
+	}
+	r.Price = price
+	return nil
+}
+
+// Image is the image set of a product as stored in a catalog file
+type Image struct {
+	Thumbnail string `json:"thumbnail"`
+	Mobile    string `json:"mobile"`
+	Tablet    string `json:"tablet"`
+	Desktop   string `json:"desktop"`
+}
+
+// Encoder writes records one at a time, Close must be called once all records are written
+type Encoder interface {
+	Encode(record *Record) error
+	Close() error
+}
+
+// ParseFormat returns the catalog format of a format name or content type, such as "csv" or "text/csv"
+func ParseFormat(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(strings.Split(value, ";")[0]))
+	switch value {
+	case FormatJSON, "application/json":
+		return FormatJSON, nil
+	case FormatCSV, "text/csv", "application/csv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported catalog format %q, use json or csv", value)
+	}
+}
+
+// Decode reads all records of a catalog in the given format
+func Decode(r io.Reader, format string) ([]Record, error) {
+	switch format {
+	case FormatJSON:
+		return DecodeJSON(r)
+	case FormatCSV:
+		return DecodeCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported catalog format %q, use json or csv", format)
+	}
+}
+
+// NewEncoder returns an encoder writing a catalog in the given format
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case FormatJSON:
+		return NewJSONEncoder(w), nil
+	case FormatCSV:
+		return NewCSVEncoder(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported catalog format %q, use json or csv", format)
+	}
+}
@@ -2,10 +2,15 @@ package configs
 
 import (
 	"errors"
+	"fmt"
 	"github.com/joho/godotenv"
 	"oolio.com/kart/constants"
+	"oolio.com/kart/i18n"
+	"oolio.com/kart/images"
+	"oolio.com/kart/money"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,6 +22,39 @@ var (
 	ReleaseEnv string
 	LogLevel   string
 	DBConfig   DatabaseConfig
+
+	// CatalogCacheControl is the Cache-Control header of the product endpoints
+	CatalogCacheControl = constants.DefaultCatalogCacheControl
+
+	// ProductCacheEnabled serves product reads from memory, invalidated through the database
+	ProductCacheEnabled = true
+
+	// StoreLocation is the time zone the availability windows of the products are in
+	StoreLocation = time.UTC
+
+	// StoreCurrency is the currency every price and order amount is in, returned along with them by the API
+	StoreCurrency = money.USD
+
+	// Locales are the locales products and categories are served in, negotiated from Accept-Language
+	Locales = fallbackLocales()
+
+	// Images configures where uploaded product images are stored and the sizes of their renditions
+	Images ImageConfiguration
+
+	// Recommendations configures how the products frequently bought together are computed
+	Recommendations = RecommendationConfiguration{
+		Window:           90 * 24 * time.Hour,
+		RefreshInterval:  time.Hour,
+		MinOrders:        2,
+		QuoteSuggestions: 3,
+	}
+
+	// Idempotency configures how long the responses of requests made with an Idempotency-Key header are kept
+	Idempotency = IdempotencyConfiguration{
+		TTL:             24 * time.Hour,
+		InFlightTimeout: time.Minute,
+		PurgeInterval:   time.Hour,
+	}
 )
 
 // DatabaseConfig contains the database configuration
@@ -41,6 +79,51 @@ type CouponConfiguration struct {
 	ForceMigration bool
 }
 
+// ImageConfiguration contains the product image upload configuration
+type ImageConfiguration struct {
+	StorageDir string
+	// BaseURL is prepended to the path of a stored image to build its URL, such as "/images" or a CDN address
+	BaseURL        string
+	MaxUploadSize  int64
+	ThumbnailWidth int
+	MobileWidth    int
+	TabletWidth    int
+	DesktopWidth   int
+}
+
+// RecommendationConfiguration contains the configuration of the products frequently bought together
+type RecommendationConfiguration struct {
+	// Window is how far back the orders the recommendations are computed from go
+	Window time.Duration
+	// RefreshInterval is the time between two computations of the recommendations, zero disables the job
+	RefreshInterval time.Duration
+	// MinOrders is the number of orders two products must have been bought together in to recommend one for the other
+	MinOrders int
+	// QuoteSuggestions is the number of products suggested with an order quote, zero disables the suggestions
+	QuoteSuggestions int
+}
+
+// IdempotencyConfiguration contains the configuration of the Idempotency-Key header
+type IdempotencyConfiguration struct {
+	// TTL is how long a key is kept, retries made after it expired are processed again
+	TTL time.Duration
+	// InFlightTimeout is how long a request can hold its key before a retry can claim it again, for requests of an
+	// instance that died before storing their response
+	InFlightTimeout time.Duration
+	// PurgeInterval is the time between two deletions of the expired keys
+	PurgeInterval time.Duration
+}
+
+// Renditions returns the renditions generated for every uploaded product image
+func (c ImageConfiguration) Renditions() []images.Rendition {
+	return []images.Rendition{
+		{Name: images.RenditionThumbnail, Width: c.ThumbnailWidth},
+		{Name: images.RenditionMobile, Width: c.MobileWidth},
+		{Name: images.RenditionTablet, Width: c.TabletWidth},
+		{Name: images.RenditionDesktop, Width: c.DesktopWidth},
+	}
+}
+
 // InitApplicationConfig loads the application config from the environment variables or env file
 func InitApplicationConfig() error {
 	isLocal, _ := strconv.ParseBool(os.Getenv(constants.IsLocal))
@@ -79,6 +162,43 @@ func setApplicationConfig() error {
 
 	LogLevel = os.Getenv(constants.LogLevel)
 
+	CatalogCacheControl = getEnvOrDefault(constants.CatalogCacheControl, constants.DefaultCatalogCacheControl)
+
+	ProductCacheEnabled, err = strconv.ParseBool(getEnvOrDefault(constants.ProductCacheEnabled, "true"))
+	if err != nil {
+		return err
+	}
+
+	if StoreLocation, err = time.LoadLocation(getEnvOrDefault(constants.StoreTimeZone, "UTC")); err != nil {
+		return err
+	}
+
+	// Prices and order amounts are stored in cents of the currency of the store
+	if StoreCurrency, err = money.ParseCurrency(getEnvOrDefault(constants.StoreCurrency, string(money.USD))); err != nil {
+		return err
+	}
+
+	if Locales, err = i18n.NewLocales(getEnvOrDefault(constants.DefaultLocale, constants.FallbackLocale), strings.Split(os.Getenv(constants.SupportedLocales), ",")); err != nil {
+		return err
+	}
+
+	if Images, err = loadImageConfiguration(); err != nil {
+		return err
+	}
+
+	if Recommendations, err = loadRecommendationConfiguration(); err != nil {
+		return err
+	}
+
+	ttlHours, err := strconv.Atoi(getEnvOrDefault(constants.IdempotencyKeyTTLHours, "24"))
+	if err != nil {
+		return err
+	}
+	if ttlHours <= 0 {
+		return errors.New(constants.IdempotencyKeyTTLHours + " must be positive")
+	}
+	Idempotency.TTL = time.Duration(ttlHours) * time.Hour
+
 	dbPort, err := strconv.Atoi(getEnvOrDefault(constants.DBPort, "5432"))
 	if err != nil {
 		return err
@@ -115,6 +235,83 @@ func setApplicationConfig() error {
 	return nil
 }
 
+// loadImageConfiguration reads the product image upload configuration, the rendition widths must be positive
+func loadImageConfiguration() (ImageConfiguration, error) {
+	config := ImageConfiguration{
+		StorageDir: getEnvOrDefault(constants.ImageStorageDir, "uploads"),
+		BaseURL:    getEnvOrDefault(constants.ImageBaseURL, constants.ImageRoute),
+	}
+
+	maxUploadSize, err := strconv.ParseInt(getEnvOrDefault(constants.ImageMaxUploadSize, "10485760"), 10, 64)
+	if err != nil {
+		return config, err
+	}
+	config.MaxUploadSize = maxUploadSize
+
+	widths := []struct {
+		key      string
+		fallback string
+		width    *int
+	}{
+		{constants.ImageThumbnailWidth, "200", &config.ThumbnailWidth},
+		{constants.ImageMobileWidth, "640", &config.MobileWidth},
+		{constants.ImageTabletWidth, "1024", &config.TabletWidth},
+		{constants.ImageDesktopWidth, "1600", &config.DesktopWidth},
+	}
+	for _, w := range widths {
+		if *w.width, err = strconv.Atoi(getEnvOrDefault(w.key, w.fallback)); err != nil {
+			return config, err
+		}
+		if *w.width <= 0 {
+			return config, errors.New(w.key + " must be positive")
+		}
+	}
+
+	return config, nil
+}
+
+// loadRecommendationConfiguration reads the configuration of the products frequently bought together
+func loadRecommendationConfiguration() (RecommendationConfiguration, error) {
+	var config RecommendationConfiguration
+
+	windowDays, err := strconv.Atoi(getEnvOrDefault(constants.RecommendationWindowDays, "90"))
+	if err != nil {
+		return config, err
+	}
+	if windowDays <= 0 {
+		return config, errors.New(constants.RecommendationWindowDays + " must be positive")
+	}
+	config.Window = time.Duration(windowDays) * 24 * time.Hour
+
+	refreshMinutes, err := strconv.Atoi(getEnvOrDefault(constants.RecommendationRefreshMinutes, "60"))
+	if err != nil {
+		return config, err
+	}
+	config.RefreshInterval = time.Duration(refreshMinutes) * time.Minute
+
+	if config.MinOrders, err = strconv.Atoi(getEnvOrDefault(constants.RecommendationMinOrders, "2")); err != nil {
+		return config, err
+	}
+	if config.MinOrders <= 0 {
+		return config, errors.New(constants.RecommendationMinOrders + " must be positive")
+	}
+
+	if config.QuoteSuggestions, err = strconv.Atoi(getEnvOrDefault(constants.RecommendationQuoteSuggestions, "3")); err != nil {
+		return config, err
+	}
+	if config.QuoteSuggestions > constants.MaxRecommendations {
+		return config, fmt.Errorf("%s must not be greater than %d", constants.RecommendationQuoteSuggestions, constants.MaxRecommendations)
+	}
+
+	return config, nil
+}
+
+// fallbackLocales only serves the fallback locale, until the configuration is loaded
+func fallbackLocales() *i18n.Locales {
+	locales, _ := i18n.NewLocales(constants.FallbackLocale, nil)
+	return locales
+}
+
 // getEnvOrDefault returns the value of the environment variable with the given key, or the fallback value if the environment variable is not set
 func getEnvOrDefault(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
@@ -20,5 +20,56 @@ const (
 	DBMaxIdleConns    = "DB_MAX_IDLE_CONNS"
 	DBConnMaxLifetime = "DB_CONN_MAX_LIFETIME"
 
+	CatalogCacheControl = "CATALOG_CACHE_CONTROL"
+	ProductCacheEnabled = "PRODUCT_CACHE_ENABLED"
+
+	StoreTimeZone = "STORE_TIME_ZONE"
+	StoreCurrency = "STORE_CURRENCY"
+
+	DefaultLocale    = "DEFAULT_LOCALE"
+	SupportedLocales = "SUPPORTED_LOCALES"
+
+	ImageStorageDir     = "IMAGE_STORAGE_DIR"
+	ImageBaseURL        = "IMAGE_BASE_URL"
+	ImageMaxUploadSize  = "IMAGE_MAX_UPLOAD_SIZE"
+	ImageThumbnailWidth = "IMAGE_THUMBNAIL_WIDTH"
+	ImageMobileWidth    = "IMAGE_MOBILE_WIDTH"
+	ImageTabletWidth    = "IMAGE_TABLET_WIDTH"
+	ImageDesktopWidth   = "IMAGE_DESKTOP_WIDTH"
+
+	RecommendationWindowDays       = "RECOMMENDATION_WINDOW_DAYS"
+	RecommendationRefreshMinutes   = "RECOMMENDATION_REFRESH_MINUTES"
+	RecommendationMinOrders        = "RECOMMENDATION_MIN_ORDERS"
+	RecommendationQuoteSuggestions = "RECOMMENDATION_QUOTE_SUGGESTIONS"
+
+	IdempotencyKeyTTLHours = "IDEMPOTENCY_KEY_TTL_HOURS"
+
 	ProdMode = "Prod"
+
+	NextCursorHeader = "X-Next-Cursor"
+	TotalCountHeader = "X-Total-Count"
+
+	APIKeyHeader             = "api_key"
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultCatalogCacheControl lets clients keep catalog responses but revalidate them with the ETag on every use
+	DefaultCatalogCacheControl = "public, no-cache"
+
+	MaxCatalogImportSize = 10 << 20
+
+	// FallbackLocale is the default locale when DEFAULT_LOCALE is not set
+	FallbackLocale = "en"
+
+	// MaxRecommendations is the number of recommendations kept for each product, the most a listing can return
+	MaxRecommendations = 20
+
+	// MaxIdempotencyKeyLength is the longest Idempotency-Key header accepted
+	MaxIdempotencyKeyLength = 255
+
+	// MaxIdempotentRequestSize is the largest body of a request sent with an Idempotency-Key header
+	MaxIdempotentRequestSize = 1 << 20
+
+	// ImageRoute is the path the app serves the images of the local image storage from
+	ImageRoute = "/images"
 )
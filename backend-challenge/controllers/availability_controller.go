@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+)
+
+type AvailabilityController struct {
+	availabilityService base.AvailabilityService
+}
+
+// NewAvailabilityController creates a new instance of AvailabilityController
+func NewAvailabilityController(availabilityService base.AvailabilityService) *AvailabilityController {
+	return &AvailabilityController{
+		availabilityService: availabilityService,
+	}
+}
+
+// GetProductAvailability godoc
+// @Summary      Get the availability of a product
+// @Description  Retrieve the windows during which a product can be ordered, its own or the ones of its nearest category
+// @Description  having any. A product without windows can be ordered at any time.
+// @Tags         availability
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Success      200 {object} responses.AvailabilityResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Router       /product/{productId}/availability [get]
+func (a *AvailabilityController) GetProductAvailability(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	availability, errDetails := a.availabilityService.GetProductAvailability(c.Request.Context(), productId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToAvailabilityResponse(availability, configs.StoreLocation.String()))
+}
+
+// SetProductAvailability godoc
+// @Summary      Set the availability of a product
+// @Description  Replace the windows during which a product can be ordered, in the store time zone. Without windows the
+// @Description  product follows the windows of its category.
+// @Tags         availability
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        request body requests.AvailabilityRequest true "Availability windows"
+// @Success      200 {object} responses.AvailabilityResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/availability [put]
+func (a *AvailabilityController) SetProductAvailability(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.AvailabilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	availability, errDetails := a.availabilityService.SetProductAvailability(c.Request.Context(), productId, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToAvailabilityResponse(availability, configs.StoreLocation.String()))
+}
+
+// GetCategoryAvailability godoc
+// @Summary      Get the availability of a category
+// @Description  Retrieve the windows defined on a category, they apply to the products of the category and its
+// @Description  subcategories that have no windows of their own or of a nearer category
+// @Tags         availability
+// @Produce      json
+// @Param        categoryId path int true "Category ID"
+// @Success      200 {object} responses.AvailabilityResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Router       /category/{categoryId}/availability [get]
+func (a *AvailabilityController) GetCategoryAvailability(c *gin.Context) {
+	categoryId, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	availability, errDetails := a.availabilityService.GetCategoryAvailability(c.Request.Context(), categoryId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToAvailabilityResponse(availability, configs.StoreLocation.String()))
+}
+
+// SetCategoryAvailability godoc
+// @Summary      Set the availability of a category
+// @Description  Replace the windows defined on a category, in the store time zone
+// @Tags         availability
+// @Accept       json
+// @Produce      json
+// @Param        categoryId path int true "Category ID"
+// @Param        request body requests.AvailabilityRequest true "Availability windows"
+// @Success      200 {object} responses.AvailabilityResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /category/{categoryId}/availability [put]
+func (a *AvailabilityController) SetCategoryAvailability(c *gin.Context) {
+	categoryId, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.AvailabilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	availability, errDetails := a.availabilityService.SetCategoryAvailability(c.Request.Context(), categoryId, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToAvailabilityResponse(availability, configs.StoreLocation.String()))
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+)
+
+type CacheController struct {
+	cacheService base.CacheService
+}
+
+// NewCacheController creates a new instance of CacheController
+func NewCacheController(cacheService base.CacheService) *CacheController {
+	return &CacheController{
+		cacheService: cacheService,
+	}
+}
+
+// GetCacheStats godoc
+// @Summary      Get cache statistics
+// @Description  Retrieve the hit and miss counters of the in-memory caches of this API instance
+// @Tags         admin
+// @Produce      json
+// @Success      200 {array} responses.CacheStatsResponse
+// @Failure      401 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /admin/cache/stats [get]
+func (cc *CacheController) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, responses.ToCacheStatsResponses(cc.cacheService.GetCacheStats()))
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/catalog"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/constants"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+)
+
+type CatalogController struct {
+	catalogService base.CatalogService
+}
+
+// NewCatalogController creates a new instance of CatalogController
+func NewCatalogController(catalogService base.CatalogService) *CatalogController {
+	return &CatalogController{
+		catalogService: catalogService,
+	}
+}
+
+// ImportProducts godoc
+// @Summary      Import products
+// @Description  Create and update products from a JSON or CSV catalog, as produced by the export. Products are matched by
+// @Description  name and category. A dry run reports what would be created, updated or left unchanged and which rows are
+// @Description  invalid, otherwise nothing is imported unless every row is valid.
+// @Tags         admin
+// @Accept       json
+// @Accept       text/csv
+// @Produce      json
+// @Param        format query string false "Catalog format, defaults to the Content-Type of the body" Enums(json, csv)
+// @Param        dryRun query bool   false "Only report what the import would change"
+// @Param        request body string true "Catalog, a JSON array of products or CSV with a header row"
+// @Success      200 {object} responses.ProductImportResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /admin/products/import [post]
+func (cc *CatalogController) ImportProducts(c *gin.Context) {
+	var request requests.ImportProductsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	format := request.Format
+	if format == "" {
+		var err error
+		if format, err = catalog.ParseFormat(c.ContentType()); err != nil {
+			c.JSON(http.StatusBadRequest, responses.APIResponse{
+				Code:    http.StatusBadRequest,
+				Type:    "validation_error",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxCatalogImportSize)
+	result, errDetails := cc.catalogService.ImportProducts(c.Request.Context(), body, format, request.DryRun)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductImportResponse(result))
+}
+
+// ExportProducts godoc
+// @Summary      Export products
+// @Description  Stream every product not deleted, whatever its status, as a JSON or CSV catalog that the import
+// @Description  accepts. Deleted products are left out, they are listed by GET /admin/products/deleted.
+// @Tags         admin
+// @Produce      json
+// @Produce      text/csv
+// @Param        format query string false "Catalog format (defaults to json)" Enums(json, csv)
+// @Success      200 {string} string "Catalog"
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /admin/products/export [get]
+func (cc *CatalogController) ExportProducts(c *gin.Context) {
+	var request requests.ExportProductsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	format := request.Format
+	if format == "" {
+		format = catalog.FormatJSON
+	}
+
+	contentType := "application/json"
+	if format == catalog.FormatCSV {
+		contentType = "text/csv"
+	}
+	c.Header("Content-Type", contentType+"; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="products.`+format+`"`)
+	c.Status(http.StatusOK)
+
+	if errDetails := cc.catalogService.ExportProducts(c.Request.Context(), c.Writer, format); errDetails != nil {
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+			return
+		}
+		// The catalog is partly sent already, the client notices the truncated file
+		configs.Logger.Error("product export aborted", zap.Any("error", errDetails))
+		c.Abort()
+	}
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+	"strconv"
+)
+
+type CategoryController struct {
+	categoryService base.CategoryService
+}
+
+// NewCategoryController creates a new instance of CategoryController
+func NewCategoryController(categoryService base.CategoryService) *CategoryController {
+	return &CategoryController{
+		categoryService: categoryService,
+	}
+}
+
+// GetCategoryTree godoc
+// @Summary      Get the category tree
+// @Description  Retrieve the top level categories with their subcategories in menu order. Product counts include the
+// @Description  available products of the subcategories. Inactive categories are left out with their subcategories
+// @Description  unless includeInactive is set.
+// @Tags         categories
+// @Produce      json
+// @Param        request query requests.CategoryTreeRequest false "Options"
+// @Success      200 {array} responses.CategoryResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Param        Accept-Language header string false "Locales to return the names in"
+// @Router       /category [get]
+func (cc *CategoryController) GetCategoryTree(c *gin.Context) {
+	var request requests.CategoryTreeRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	categories, errDetails := cc.categoryService.GetCategoryTree(c.Request.Context(), request.IncludeInactive)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToCategoryResponses(categories))
+}
+
+// GetCategory godoc
+// @Summary      Get category by ID
+// @Description  Retrieve a category with all its subcategories, active or not
+// @Tags         categories
+// @Produce      json
+// @Param        categoryId path int true "Category ID"
+// @Success      200 {object} responses.CategoryResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Param        Accept-Language header string false "Locales to return the names in"
+// @Router       /category/{categoryId} [get]
+func (cc *CategoryController) GetCategory(c *gin.Context) {
+	id, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	category, errDetails := cc.categoryService.GetCategory(c.Request.Context(), id)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToCategoryResponse(category))
+}
+
+// CreateCategory godoc
+// @Summary      Create a category
+// @Description  Add a top level category or a subcategory to the menu
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.CategoryRequest true "Category"
+// @Success      201 {object} responses.CategoryResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /category [post]
+func (cc *CategoryController) CreateCategory(c *gin.Context) {
+	var request requests.CategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	category, errDetails := cc.categoryService.CreateCategory(c.Request.Context(), &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.ToCategoryResponse(category))
+}
+
+// UpdateCategory godoc
+// @Summary      Replace a category
+// @Description  Rename, move, reorder, activate or deactivate a category. Its subcategories and products move with it.
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        categoryId path int true "Category ID"
+// @Param        request body requests.CategoryRequest true "Category"
+// @Success      200 {object} responses.CategoryResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /category/{categoryId} [put]
+func (cc *CategoryController) UpdateCategory(c *gin.Context) {
+	id, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.CategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	category, errDetails := cc.categoryService.UpdateCategory(c.Request.Context(), id, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToCategoryResponse(category))
+}
+
+// DeleteCategory godoc
+// @Summary      Delete a category
+// @Description  Remove a category that has no subcategories and no products
+// @Tags         categories
+// @Param        categoryId path int true "Category ID"
+// @Success      204
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /category/{categoryId} [delete]
+func (cc *CategoryController) DeleteCategory(c *gin.Context) {
+	id, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	if errDetails := cc.categoryService.DeleteCategory(c.Request.Context(), id); errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseCategoryId parses the categoryId path parameter and writes a 400 response when it is invalid
+func parseCategoryId(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("categoryId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: "invalid category id",
+		})
+		return 0, false
+	}
+
+	return id, true
+}
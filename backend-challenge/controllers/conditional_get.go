@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/i18n"
+	"strings"
+	"time"
+)
+
+// notModified sets the ETag, Last-Modified and Cache-Control headers of a catalog response from the time its data was
+// last modified. It reports whether the copy the client revalidates is still current, a 304 has been written then.
+func notModified(c *gin.Context, lastModified time.Time) bool {
+	etag := catalogETag(lastModified, i18n.LocaleFrom(c.Request.Context()))
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if configs.CatalogCacheControl != "" {
+		c.Header("Cache-Control", configs.CatalogCacheControl)
+	}
+
+	// If-Modified-Since is only considered without If-None-Match, as the ETag is the more precise of the two
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if !etagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		// Last-Modified only has a precision of seconds
+		if err != nil || lastModified.IsZero() || lastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// catalogETag derives a strong ETag from a modification time, database times have a precision of microseconds. The
+// locale of the response is part of the ETag since each locale is a different representation.
+func catalogETag(lastModified time.Time, locale string) string {
+	if locale == "" || locale == configs.Locales.Default {
+		return fmt.Sprintf(`"%x"`, lastModified.UnixMicro())
+	}
+	return fmt.Sprintf(`"%x-%s"`, lastModified.UnixMicro(), locale)
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag, using the weak comparison required for it
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+	"strconv"
+)
+
+type ModifierController struct {
+	modifierService base.ModifierService
+}
+
+// NewModifierController creates a new instance of ModifierController
+func NewModifierController(modifierService base.ModifierService) *ModifierController {
+	return &ModifierController{
+		modifierService: modifierService,
+	}
+}
+
+// GetModifierGroups godoc
+// @Summary      Get product modifier groups
+// @Description  Retrieve the modifier groups of a product, such as sizes or add-ons, with their selection rules and price deltas
+// @Tags         modifiers
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Success      200 {array} responses.ModifierGroupResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Router       /product/{productId}/modifier-groups [get]
+func (m *ModifierController) GetModifierGroups(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	groups, errDetails := m.modifierService.GetModifierGroups(c.Request.Context(), productId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToModifierGroupResponses(groups))
+}
+
+// CreateModifierGroup godoc
+// @Summary      Add a modifier group
+// @Description  Add a modifier group with its modifiers to a product
+// @Tags         modifiers
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        request body requests.ModifierGroupRequest true "Modifier group"
+// @Success      201 {object} responses.ModifierGroupResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/modifier-groups [post]
+func (m *ModifierController) CreateModifierGroup(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.ModifierGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	group, errDetails := m.modifierService.CreateModifierGroup(c.Request.Context(), productId, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.ToModifierGroupResponse(group))
+}
+
+// UpdateModifierGroup godoc
+// @Summary      Replace a modifier group
+// @Description  Replace a modifier group and its modifiers. Modifiers keep their ID as long as their name is unchanged,
+// @Description  modifiers left out are removed.
+// @Tags         modifiers
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        groupId path int true "Modifier group ID"
+// @Param        request body requests.ModifierGroupRequest true "Modifier group"
+// @Success      200 {object} responses.ModifierGroupResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/modifier-groups/{groupId} [put]
+func (m *ModifierController) UpdateModifierGroup(c *gin.Context) {
+	productId, groupId, ok := parseModifierGroupPath(c)
+	if !ok {
+		return
+	}
+
+	var request requests.ModifierGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	group, errDetails := m.modifierService.UpdateModifierGroup(c.Request.Context(), productId, groupId, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToModifierGroupResponse(group))
+}
+
+// DeleteModifierGroup godoc
+// @Summary      Delete a modifier group
+// @Description  Remove a modifier group and its modifiers from a product, placed orders keep the modifiers they were sold with
+// @Tags         modifiers
+// @Param        productId path int true "Product ID"
+// @Param        groupId path int true "Modifier group ID"
+// @Success      204
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/modifier-groups/{groupId} [delete]
+func (m *ModifierController) DeleteModifierGroup(c *gin.Context) {
+	productId, groupId, ok := parseModifierGroupPath(c)
+	if !ok {
+		return
+	}
+
+	if errDetails := m.modifierService.DeleteModifierGroup(c.Request.Context(), productId, groupId); errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseModifierGroupPath parses the productId and groupId path parameters and writes a 400 response when one is invalid
+func parseModifierGroupPath(c *gin.Context) (int64, int64, bool) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	groupId, err := strconv.ParseInt(c.Param("groupId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: "invalid modifier group id",
+		})
+		return 0, 0, false
+	}
+
+	return productId, groupId, true
+}
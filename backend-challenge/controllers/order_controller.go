@@ -2,7 +2,10 @@ package controllers
 
 import (
 	"net/http"
+	"oolio.com/kart/constants"
 	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/models"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -21,15 +24,23 @@ func NewOrderController(orderService base.OrderService) *OrderController {
 
 // PlaceOrder handles POST /api/order
 // @Summary      Place a new order
-// @Description  Create a new order with items and optional coupon code
+// @Description  Create a new order with items and optional coupon code. Orders placed with an Idempotency-Key header
+// @Description  are safe to retry: retries with the same key and body get the response of the first request back with
+// @Description  an Idempotent-Replayed header instead of placing another order, and the key reused with another body
+// @Description  is rejected with a 422.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Param        request body requests.PlaceOrderRequest true "Order details"
 // @Success      200 {object} responses.OrderResponse
+// @Header       200 {string} Idempotent-Replayed "true when the response is the one stored for the Idempotency-Key"
 // @Failure      400 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
 // @Failure      500 {object} responses.APIResponse
 // @Security     ApiKeyAuth
+// @Param        Accept-Language header string false "Locales to return the product names in"
+// @Param        Idempotency-Key header string false "Unique key of the order, up to 255 visible ASCII characters, to retry it safely"
 // @Param        api_key	  header    string    true   	"api_key must be set for authentication"
 // @Router       /order [post]
 func (oc *OrderController) PlaceOrder(c *gin.Context) {
@@ -46,13 +57,218 @@ func (oc *OrderController) PlaceOrder(c *gin.Context) {
 
 	response, errDetails := oc.orderService.PlaceOrder(c.Request.Context(), &request)
 	if errDetails != nil {
-		c.JSON(errDetails.ErrorCode, responses.APIResponse{
-			Code:    errDetails.ErrorCode,
-			Type:    "error",
-			Message: errDetails.Message,
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListOrders handles GET /api/order
+// @Summary      List orders
+// @Description  Retrieve a page of orders, newest first, optionally filtered and sorted. Pages are linked through the
+// @Description  opaque cursor returned in the X-Next-Cursor header, the number of matching orders is returned in X-Total-Count.
+// @Tags         orders
+// @Produce      json
+// @Param        from       query string   false "Only return orders placed at or after this RFC 3339 time"
+// @Param        to         query string   false "Only return orders placed before this RFC 3339 time"
+// @Param        couponCode query string   false "Only return orders placed with this coupon code"
+// @Param        minTotal   query number   false "Minimum total (inclusive)"
+// @Param        maxTotal   query number   false "Maximum total (inclusive)"
+// @Param        status     query []string false "Only return orders in any of these statuses" collectionFormat(csv) Enums(placed, accepted, preparing, ready, completed, cancelled)
+// @Param        sort       query string   false "Sort key, defaults to created_at" Enums(created_at, total)
+// @Param        direction  query string   false "Sort direction, defaults to desc" Enums(asc, desc)
+// @Param        limit      query int      false "Maximum number of orders to return (1-100), defaults to 20"
+// @Param        cursor     query string   false "Cursor of the next page, as returned in X-Next-Cursor"
+// @Success      200 {array} responses.OrderSummaryResponse
+// @Header       200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Header       200 {integer} X-Total-Count "Number of orders matching the filters"
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order [get]
+func (oc *OrderController) ListOrders(c *gin.Context) {
+	var request requests.ListOrdersRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	page, errDetails := oc.orderService.ListOrders(c.Request.Context(), request.ToOrderFilter())
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Header(constants.TotalCountHeader, strconv.FormatInt(page.TotalCount, 10))
+	if page.NextCursor != "" {
+		c.Header(constants.NextCursorHeader, page.NextCursor)
+	}
+
+	c.JSON(http.StatusOK, responses.ToOrderSummaryResponses(page.Orders))
+}
+
+// GetOrderById handles GET /api/order/:orderId
+// @Summary      Get an order
+// @Description  Retrieve a placed order with its items, the current details of their products and its totals
+// @Tags         orders
+// @Produce      json
+// @Param        orderId path string true "Order ID (UUID)"
+// @Success      200 {object} responses.OrderResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        Accept-Language header string false "Locales to return the product names in"
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order/{orderId} [get]
+func (oc *OrderController) GetOrderById(c *gin.Context) {
+	orderId, ok := parseOrderId(c)
+	if !ok {
+		return
+	}
+
+	response, errDetails := oc.orderService.GetOrderById(c.Request.Context(), orderId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// TransitionOrder handles POST /api/order/:orderId/transitions
+// @Summary      Change the status of an order
+// @Description  Move an order to a new status and record who moved it and why. Orders go from placed to accepted,
+// @Description  preparing, ready and completed, and can be cancelled until their preparation starts. The version of
+// @Description  the order the transition was decided on must be sent along, a transition made against an older version
+// @Description  is rejected with a 409 so that two terminals cannot move the same order at once.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        orderId path string true "Order ID (UUID)"
+// @Param        request body requests.OrderTransitionRequest true "Transition"
+// @Success      200 {object} responses.OrderResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        Accept-Language header string false "Locales to return the product names in"
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order/{orderId}/transitions [post]
+func (oc *OrderController) TransitionOrder(c *gin.Context) {
+	orderId, ok := parseOrderId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.OrderTransitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, errDetails := oc.orderService.TransitionOrder(c.Request.Context(), orderId, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOrderTransitions handles GET /api/order/:orderId/transitions
+// @Summary      Get the status history of an order
+// @Description  Retrieve the status changes of an order, oldest first
+// @Tags         orders
+// @Produce      json
+// @Param        orderId path string true "Order ID (UUID)"
+// @Success      200 {array} responses.OrderTransitionResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order/{orderId}/transitions [get]
+func (oc *OrderController) GetOrderTransitions(c *gin.Context) {
+	orderId, ok := parseOrderId(c)
+	if !ok {
+		return
+	}
+
+	changes, errDetails := oc.orderService.GetOrderTransitions(c.Request.Context(), orderId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToOrderTransitionResponses(changes))
+}
+
+// QuoteOrder handles POST /api/order/quote
+// @Summary      Quote an order
+// @Description  Price an order with the current prices of its products and modifiers and its coupon without placing
+// @Description  it, the same checks as placing the order apply. The quote suggests products frequently bought together
+// @Description  with the ones of the order.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.PlaceOrderRequest true "Order details"
+// @Success      200 {object} responses.OrderQuoteResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        Accept-Language header string false "Locales to return the product names in"
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order/quote [post]
+func (oc *OrderController) QuoteOrder(c *gin.Context) {
+	var request requests.PlaceOrderRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
 		})
 		return
 	}
 
+	response, errDetails := oc.orderService.QuoteOrder(c.Request.Context(), &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
+
+// parseOrderId reads the order ID of the path, writing a 400 response when it is not a UUID
+func parseOrderId(c *gin.Context) (string, bool) {
+	id := c.Param("orderId")
+	if !models.IsOrderId(id) {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: "invalid order id",
+		})
+		return "", false
+	}
+
+	return id, true
+}
@@ -3,6 +3,8 @@ package controllers
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"oolio.com/kart/constants"
+	"oolio.com/kart/dtos/requests"
 	"oolio.com/kart/dtos/responses"
 	"oolio.com/kart/services/base"
 	"strconv"
@@ -21,64 +23,439 @@ func NewProductController(productService base.ProductService) *ProductController
 
 // GetProducts godoc
 // @Summary      Get all products
-// @Description  Retrieve a list of all products
+// @Description  Retrieve a page of products, optionally filtered, searched and sorted. Pages are linked through the
+// @Description  opaque cursor returned in the X-Next-Cursor header, the number of matching products is returned in X-Total-Count.
+// @Description  Responses carry an ETag and Last-Modified, a request repeating either of them gets a 304 while no product has changed.
 // @Tags         products
 // @Produce      json
+// @Param        category   query string false "Only return products of this category path or its subcategories, e.g. Pizza > Vegetarian"
+// @Param        categoryId query int    false "Only return products of this category or its subcategories"
+// @Param        status    query string false "Only return products with this status, defaults to available" Enums(available, sold_out, hidden, discontinued)
+// @Param        minPrice  query number false "Minimum price (inclusive)"
+// @Param        maxPrice  query number false "Maximum price (inclusive)"
+// @Param        q         query string false "Case-insensitive search on the product name"
+// @Param        sort      query string false "Sort key" Enums(price, name, created_at)
+// @Param        direction query string false "Sort direction" Enums(asc, desc)
+// @Param        limit     query int    false "Maximum number of products to return (1-100)"
+// @Param        offset    query int    false "Number of products to skip, cannot be combined with cursor"
+// @Param        cursor    query string false "Cursor of the next page, as returned in X-Next-Cursor"
+// @Param        availableNow query bool false "Only return products that can be ordered at this time of day, such listings carry no ETag"
+// @Param        at        query string false "Only return products that can be ordered at this RFC 3339 time, cannot be combined with availableNow"
+// @Param        excludeAllergens query []string false "Only return products containing none of these allergens" collectionFormat(csv)
+// @Param        diet      query []string false "Only return products suitable for all of these diets" collectionFormat(csv)
+// @Param        If-None-Match     header string false "ETag of the copy to revalidate"
+// @Param        If-Modified-Since header string false "Last-Modified of the copy to revalidate"
+// @Param        Accept-Language   header string false "Locales to return the names and descriptions in"
 // @Success      200 {array} responses.ProductResponse
+// @Success      304
+// @Header       200,304 {string} ETag "Version of the product listing"
+// @Header       200,304 {string} Last-Modified "Time any product was last changed"
+// @Header       200,304 {string} Cache-Control "Caching policy of the catalog"
+// @Header       200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Header       200 {integer} X-Total-Count "Number of products matching the filters"
+// @Failure      400 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
 // @Router       /product [get]
 func (p *ProductController) GetProducts(c *gin.Context) {
-	limitQueryStr := c.Query("limit")
-	offsetQueryStr := c.Query("offset")
-
-	var limit, offset *int
-	if limitQueryStr != "" {
-		parsedLimit, err := strconv.Atoi(limitQueryStr)
-		if err == nil {
-			limit = &parsedLimit
-		}
+	var request requests.ListProductsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Invalid filters are rejected before the listing is revalidated, a 304 would tell the client they were valid
+	filter := request.ToProductFilter()
+	if errDetails := p.productService.ValidateProductFilter(filter); errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
 	}
 
-	if offsetQueryStr != "" {
-		parsedOffset, err := strconv.Atoi(offsetQueryStr)
-		if err == nil {
-			offset = &parsedOffset
+	if request.AvailableNow {
+		// The products available now change with the time of day, the listing cannot be revalidated
+		c.Header("Cache-Control", "no-store")
+	} else {
+		// The validators are read before the products, a change in between can only make them older than the body,
+		// which the next revalidation then replaces
+		lastModified, errDetails := p.productService.GetCatalogLastModified(c.Request.Context())
+		if errDetails != nil {
+			c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+			return
 		}
+
+		if notModified(c, lastModified) {
+			return
+		}
+	}
+
+	page, errDetails := p.productService.GetProducts(c.Request.Context(), filter)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Header(constants.TotalCountHeader, strconv.FormatInt(page.TotalCount, 10))
+	if page.NextCursor != "" {
+		c.Header(constants.NextCursorHeader, page.NextCursor)
 	}
 
-	products := p.productService.GetProducts(c.Request.Context(), limit, offset)
-	c.JSON(http.StatusOK, responses.ToProductResponses(products))
+	c.JSON(http.StatusOK, responses.ToProductResponses(page.Products))
 }
 
 // GetProductById godoc
 // @Summary      Get product by ID
-// @Description  Retrieve a single product by its ID
+// @Description  Retrieve a single product by its ID. Responses carry an ETag and Last-Modified, a request repeating
+// @Description  either of them gets a 304 while the product and its category are unchanged.
 // @Tags         products
 // @Produce      json
 // @Param        productId path int true "Product ID"
+// @Param        If-None-Match     header string false "ETag of the copy to revalidate"
+// @Param        If-Modified-Since header string false "Last-Modified of the copy to revalidate"
+// @Param        Accept-Language   header string false "Locales to return the names and descriptions in"
 // @Success      200 {object} responses.ProductResponse
+// @Success      304
+// @Header       200,304 {string} ETag "Version of the product"
+// @Header       200,304 {string} Last-Modified "Time the product or its category was last changed"
+// @Header       200,304 {string} Cache-Control "Caching policy of the catalog"
 // @Failure      400 {object} responses.APIResponse
 // @Failure      404 {object} responses.APIResponse
 // @Failure      500 {object} responses.APIResponse
 // @Router       /product/{productId} [get]
 func (p *ProductController) GetProductById(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("productId"), 10, 64)
-	if err != nil {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	lastModified, internalErr := p.productService.GetProductLastModified(c.Request.Context(), id)
+	if internalErr != nil {
+		c.JSON(internalErr.ErrorCode, responses.ToErrorResponse(internalErr))
+		return
+	}
+
+	if notModified(c, lastModified) {
+		return
+	}
+
+	product, internalErr := p.productService.GetProductById(c.Request.Context(), id)
+	if internalErr != nil {
+		c.JSON(internalErr.ErrorCode, responses.ToErrorResponse(internalErr))
+		return
+	}
+	c.JSON(http.StatusOK, responses.ToProductResponse(product))
+}
+
+// GetProductPrices godoc
+// @Summary      Get product price history
+// @Description  Retrieve every price a product has had, newest version first. Order items record the price version
+// @Description  they were placed with.
+// @Tags         products
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Success      200 {object} responses.ProductPricesResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Router       /product/{productId}/prices [get]
+func (p *ProductController) GetProductPrices(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	product, prices, internalErr := p.productService.GetProductPrices(c.Request.Context(), id)
+	if internalErr != nil {
+		c.JSON(internalErr.ErrorCode, responses.ToErrorResponse(internalErr))
+		return
+	}
+	c.JSON(http.StatusOK, responses.ToProductPricesResponse(product, prices))
+}
+
+// CreateProduct godoc
+// @Summary      Create a product
+// @Description  Add a new product to the menu
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.ProductRequest true "Product details"
+// @Success      201 {object} responses.ProductResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product [post]
+func (p *ProductController) CreateProduct(c *gin.Context) {
+	var request requests.ProductRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, responses.APIResponse{
 			Code:    http.StatusBadRequest,
-			Type:    "validation_error",
-			Message: "invalid product id",
+			Type:    "invalid_request",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	product, internalErr := p.productService.GetProductById(c.Request.Context(), id)
-	if internalErr != nil {
-		c.JSON(internalErr.ErrorCode, responses.APIResponse{
-			Code:    internalErr.ErrorCode,
-			Type:    "error",
-			Message: internalErr.Message,
+	product, errDetails := p.productService.CreateProduct(c.Request.Context(), &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.ToProductResponse(product))
+}
+
+// UpdateProduct godoc
+// @Summary      Replace a product
+// @Description  Replace all writable fields of an existing product
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        request body requests.ProductRequest true "Product details"
+// @Success      200 {object} responses.ProductResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId} [put]
+func (p *ProductController) UpdateProduct(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.ProductRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	product, errDetails := p.productService.UpdateProduct(c.Request.Context(), id, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductResponse(product))
+}
+
+// PatchProduct godoc
+// @Summary      Update a product
+// @Description  Update only the provided fields of an existing product
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        request body requests.PatchProductRequest true "Fields to update"
+// @Success      200 {object} responses.ProductResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId} [patch]
+func (p *ProductController) PatchProduct(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.PatchProductRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	product, errDetails := p.productService.PatchProduct(c.Request.Context(), id, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductResponse(product))
+}
+
+// UpdateProductStatus godoc
+// @Summary      Change product status
+// @Description  Move a product to another status. Discontinued products cannot change status anymore.
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        request body requests.ProductStatusRequest true "New status"
+// @Success      200 {object} responses.ProductResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/status [put]
+func (p *ProductController) UpdateProductStatus(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.ProductStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
 		})
 		return
 	}
+
+	product, errDetails := p.productService.UpdateProductStatus(c.Request.Context(), id, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
 	c.JSON(http.StatusOK, responses.ToProductResponse(product))
 }
+
+// DeleteProduct godoc
+// @Summary      Delete a product
+// @Description  Remove a product from the menu. Orders placed before keep referring to it and it can be restored.
+// @Tags         products
+// @Param        productId path int true "Product ID"
+// @Success      204
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId} [delete]
+func (p *ProductController) DeleteProduct(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	if errDetails := p.productService.DeleteProduct(c.Request.Context(), id); errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDeletedProducts godoc
+// @Summary      Get deleted products
+// @Description  Retrieve a page of deleted products with the same filters, sorting and pagination as the product
+// @Description  listing, whatever their status unless one is asked for
+// @Tags         admin
+// @Produce      json
+// @Param        category   query string false "Only return products of this category path or its subcategories"
+// @Param        categoryId query int    false "Only return products of this category or its subcategories"
+// @Param        status     query string false "Only return products with this status" Enums(available, sold_out, hidden, discontinued)
+// @Param        q          query string false "Case-insensitive search on the product name"
+// @Param        sort       query string false "Sort key" Enums(price, name, created_at)
+// @Param        direction  query string false "Sort direction" Enums(asc, desc)
+// @Param        limit      query int    false "Maximum number of products to return (1-100)"
+// @Param        offset     query int    false "Number of products to skip, cannot be combined with cursor"
+// @Param        cursor     query string false "Cursor of the next page, as returned in X-Next-Cursor"
+// @Success      200 {array} responses.ProductResponse
+// @Header       200 {string} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Header       200 {integer} X-Total-Count "Number of deleted products matching the filters"
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /admin/products/deleted [get]
+func (p *ProductController) GetDeletedProducts(c *gin.Context) {
+	var request requests.ListProductsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	filter := request.ToProductFilter()
+	filter.Deleted = true
+
+	page, errDetails := p.productService.GetProducts(c.Request.Context(), filter)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Header(constants.TotalCountHeader, strconv.FormatInt(page.TotalCount, 10))
+	if page.NextCursor != "" {
+		c.Header(constants.NextCursorHeader, page.NextCursor)
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductResponses(page.Products))
+}
+
+// RestoreProduct godoc
+// @Summary      Restore a deleted product
+// @Description  Put a deleted product back on the menu with the status it had when it was deleted
+// @Tags         admin
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Success      200 {object} responses.ProductResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /admin/products/{productId}/restore [post]
+func (p *ProductController) RestoreProduct(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	product, errDetails := p.productService.RestoreProduct(c.Request.Context(), id)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductResponse(product))
+}
+
+// parseProductId parses the productId path parameter and writes a 400 response when it is invalid
+func parseProductId(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("productId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: "invalid product id",
+		})
+		return 0, false
+	}
+
+	return id, true
+}
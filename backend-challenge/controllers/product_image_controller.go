@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+	"strconv"
+)
+
+// imageFormField is the multipart form field carrying the uploaded image
+const imageFormField = "image"
+
+type ProductImageController struct {
+	productImageService base.ProductImageService
+	maxUploadSize       int64
+}
+
+// NewProductImageController creates a new instance of ProductImageController accepting uploads of up to maxUploadSize bytes
+func NewProductImageController(productImageService base.ProductImageService, maxUploadSize int64) *ProductImageController {
+	return &ProductImageController{
+		productImageService: productImageService,
+		maxUploadSize:       maxUploadSize,
+	}
+}
+
+// UploadProductImage godoc
+// @Summary      Upload a product image
+// @Description  Upload one JPEG, PNG or GIF source image. The thumbnail, mobile, tablet and desktop renditions are
+// @Description  generated from it at the configured widths, stored and set as the image of the product.
+// @Tags         products
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        productId path     int  true "Product ID"
+// @Param        image     formData file true "Source image"
+// @Success      200 {object} responses.ProductResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      413 {object} responses.APIResponse
+// @Failure      415 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/image [post]
+func (pc *ProductImageController) UploadProductImage(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, pc.maxUploadSize)
+	header, err := c.FormFile(imageFormField)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, responses.APIResponse{
+				Code:    http.StatusRequestEntityTooLarge,
+				Type:    "invalid_request",
+				Message: "image must not be larger than " + strconv.FormatInt(pc.maxUploadSize, 10) + " bytes",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: "multipart form field " + imageFormField + " with the image is required",
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	product, errDetails := pc.productImageService.UploadProductImage(c.Request.Context(), id, file)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductResponse(product))
+}
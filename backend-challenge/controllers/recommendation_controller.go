@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+)
+
+type RecommendationController struct {
+	recommendationService base.RecommendationService
+}
+
+// NewRecommendationController creates a new instance of RecommendationController
+func NewRecommendationController(recommendationService base.RecommendationService) *RecommendationController {
+	return &RecommendationController{
+		recommendationService: recommendationService,
+	}
+}
+
+// GetProductRecommendations godoc
+// @Summary      Get the products frequently bought together with a product
+// @Description  Retrieve the products most often ordered along with a product in the recent orders, best match first.
+// @Description  Only products that can be ordered now are returned, the recommendations are recomputed periodically.
+// @Tags         products
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        limit     query int false "Maximum number of products to return (1-20), defaults to 5"
+// @Param        Accept-Language header string false "Locales to return the names and descriptions in"
+// @Success      200 {array} responses.ProductResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Router       /product/{productId}/recommendations [get]
+func (r *RecommendationController) GetProductRecommendations(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.RecommendationsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	products, errDetails := r.recommendationService.GetProductRecommendations(c.Request.Context(), productId, request.LimitOrDefault())
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductResponses(products))
+}
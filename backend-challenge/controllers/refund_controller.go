@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+	"strconv"
+)
+
+type RefundController struct {
+	refundService base.RefundService
+}
+
+// NewRefundController creates a new instance of RefundController
+func NewRefundController(refundService base.RefundService) *RefundController {
+	return &RefundController{
+		refundService: refundService,
+	}
+}
+
+// RefundOrder godoc
+// @Summary      Refund an order
+// @Description  Refund a completed order, in full or some quantities of its items, and return the receipt of the refund.
+// @Description  Without items, everything not refunded yet is refunded. Items are refunded at the share of the total
+// @Description  they were charged, the coupon discount included. Refunds are recorded next to the order, which keeps
+// @Description  its total. Orders that are not completed yet are cancelled through their transitions instead.
+// @Tags         refunds
+// @Accept       json
+// @Produce      json
+// @Param        orderId path string true "Order ID (UUID)"
+// @Param        request body requests.RefundRequest true "Refund"
+// @Success      201 {object} responses.RefundResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      409 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        Accept-Language header string false "Locales to return the product names in"
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order/{orderId}/refunds [post]
+func (r *RefundController) RefundOrder(c *gin.Context) {
+	orderId, ok := parseOrderId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.RefundRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	receipt, errDetails := r.refundService.RefundOrder(c.Request.Context(), orderId, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusCreated, receipt)
+}
+
+// GetRefunds godoc
+// @Summary      Get the refunds of an order
+// @Description  Retrieve the receipts of the refunds of an order, oldest first
+// @Tags         refunds
+// @Produce      json
+// @Param        orderId path string true "Order ID (UUID)"
+// @Success      200 {array} responses.RefundResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        Accept-Language header string false "Locales to return the product names in"
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order/{orderId}/refunds [get]
+func (r *RefundController) GetRefunds(c *gin.Context) {
+	orderId, ok := parseOrderId(c)
+	if !ok {
+		return
+	}
+
+	receipts, errDetails := r.refundService.GetRefunds(c.Request.Context(), orderId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, receipts)
+}
+
+// GetRefund godoc
+// @Summary      Get a refund receipt
+// @Description  Retrieve the receipt of a refund of an order
+// @Tags         refunds
+// @Produce      json
+// @Param        orderId  path string true "Order ID (UUID)"
+// @Param        refundId path int    true "Refund ID"
+// @Success      200 {object} responses.RefundResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        Accept-Language header string false "Locales to return the product names in"
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /order/{orderId}/refunds/{refundId} [get]
+func (r *RefundController) GetRefund(c *gin.Context) {
+	orderId, ok := parseOrderId(c)
+	if !ok {
+		return
+	}
+
+	refundId, err := strconv.ParseInt(c.Param("refundId"), 10, 64)
+	if err != nil || refundId <= 0 {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "validation_error",
+			Message: "invalid refund id",
+		})
+		return
+	}
+
+	receipt, errDetails := r.refundService.GetRefund(c.Request.Context(), orderId, refundId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
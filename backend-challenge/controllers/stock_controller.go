@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+)
+
+type StockController struct {
+	stockService base.StockService
+}
+
+// NewStockController creates a new instance of StockController
+func NewStockController(stockService base.StockService) *StockController {
+	return &StockController{
+		stockService: stockService,
+	}
+}
+
+// GetStock godoc
+// @Summary      Get product stock
+// @Description  Retrieve the stock left for a product together with its latest stock adjustments
+// @Tags         stock
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Success      200 {object} responses.StockResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/stock [get]
+func (s *StockController) GetStock(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	product, adjustments, errDetails := s.stockService.GetStock(c.Request.Context(), id)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToStockResponse(product, adjustments))
+}
+
+// AdjustStock godoc
+// @Summary      Adjust product stock
+// @Description  Change the stock of a product by a delta, set it to an absolute quantity or make it unlimited.
+// @Description  Every adjustment is recorded with its reason and actor.
+// @Tags         stock
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        request body requests.StockAdjustmentRequest true "Stock adjustment"
+// @Success      200 {object} responses.StockResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      422 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/stock [post]
+func (s *StockController) AdjustStock(c *gin.Context) {
+	id, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	product, adjustments, errDetails := s.stockService.AdjustStock(c.Request.Context(), id, &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToStockResponse(product, adjustments))
+}
@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+)
+
+type TranslationController struct {
+	translationService base.TranslationService
+}
+
+// NewTranslationController creates a new instance of TranslationController
+func NewTranslationController(translationService base.TranslationService) *TranslationController {
+	return &TranslationController{
+		translationService: translationService,
+	}
+}
+
+// GetProductTranslations godoc
+// @Summary      List the translations of a product
+// @Description  Retrieve the names and descriptions of a product in every locale it was translated into
+// @Tags         translations
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Success      200 {array} responses.ProductTranslationResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/translations [get]
+func (t *TranslationController) GetProductTranslations(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	translations, errDetails := t.translationService.GetProductTranslations(c.Request.Context(), productId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductTranslationsResponse(translations))
+}
+
+// SetProductTranslation godoc
+// @Summary      Translate a product
+// @Description  Create or replace the name and description of a product in a supported locale other than the default
+// @Description  one. Customers asking for the locale through Accept-Language get them instead of the default ones.
+// @Tags         translations
+// @Accept       json
+// @Produce      json
+// @Param        productId path int true "Product ID"
+// @Param        locale path string true "Locale of the translation" example(fr)
+// @Param        request body requests.ProductTranslationRequest true "Translation"
+// @Success      200 {object} responses.ProductTranslationResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/translations/{locale} [put]
+func (t *TranslationController) SetProductTranslation(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.ProductTranslationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	translation, errDetails := t.translationService.SetProductTranslation(c.Request.Context(), productId, c.Param("locale"), &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToProductTranslationResponse(translation))
+}
+
+// DeleteProductTranslation godoc
+// @Summary      Delete the translation of a product
+// @Description  Delete the translation of a product in a locale, customers asking for the locale get the default
+// @Description  name and description again
+// @Tags         translations
+// @Param        productId path int true "Product ID"
+// @Param        locale path string true "Locale of the translation" example(fr)
+// @Success      204
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /product/{productId}/translations/{locale} [delete]
+func (t *TranslationController) DeleteProductTranslation(c *gin.Context) {
+	productId, ok := parseProductId(c)
+	if !ok {
+		return
+	}
+
+	if errDetails := t.translationService.DeleteProductTranslation(c.Request.Context(), productId, c.Param("locale")); errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCategoryTranslations godoc
+// @Summary      List the translations of a category
+// @Description  Retrieve the names of a category in every locale it was translated into
+// @Tags         translations
+// @Produce      json
+// @Param        categoryId path int true "Category ID"
+// @Success      200 {array} responses.CategoryTranslationResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /category/{categoryId}/translations [get]
+func (t *TranslationController) GetCategoryTranslations(c *gin.Context) {
+	categoryId, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	translations, errDetails := t.translationService.GetCategoryTranslations(c.Request.Context(), categoryId)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToCategoryTranslationsResponse(translations))
+}
+
+// SetCategoryTranslation godoc
+// @Summary      Translate a category
+// @Description  Create or replace the name of a category in a supported locale other than the default one. The
+// @Description  category paths of products are translated one category at a time.
+// @Tags         translations
+// @Accept       json
+// @Produce      json
+// @Param        categoryId path int true "Category ID"
+// @Param        locale path string true "Locale of the translation" example(fr)
+// @Param        request body requests.CategoryTranslationRequest true "Translation"
+// @Success      200 {object} responses.CategoryTranslationResponse
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /category/{categoryId}/translations/{locale} [put]
+func (t *TranslationController) SetCategoryTranslation(c *gin.Context) {
+	categoryId, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	var request requests.CategoryTranslationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, responses.APIResponse{
+			Code:    http.StatusBadRequest,
+			Type:    "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	translation, errDetails := t.translationService.SetCategoryTranslation(c.Request.Context(), categoryId, c.Param("locale"), &request)
+	if errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.ToCategoryTranslationResponse(translation))
+}
+
+// DeleteCategoryTranslation godoc
+// @Summary      Delete the translation of a category
+// @Description  Delete the translation of a category in a locale, customers asking for the locale get the default name
+// @Description  again
+// @Tags         translations
+// @Param        categoryId path int true "Category ID"
+// @Param        locale path string true "Locale of the translation" example(fr)
+// @Success      204
+// @Failure      400 {object} responses.APIResponse
+// @Failure      401 {object} responses.APIResponse
+// @Failure      404 {object} responses.APIResponse
+// @Failure      500 {object} responses.APIResponse
+// @Security     ApiKeyAuth
+// @Param        api_key	  header    string    true   	"api_key must be set for authentication"
+// @Router       /category/{categoryId}/translations/{locale} [delete]
+func (t *TranslationController) DeleteCategoryTranslation(c *gin.Context) {
+	categoryId, ok := parseCategoryId(c)
+	if !ok {
+		return
+	}
+
+	if errDetails := t.translationService.DeleteCategoryTranslation(c.Request.Context(), categoryId, c.Param("locale")); errDetails != nil {
+		c.JSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,13 @@
+package requests
+
+// AvailabilityRequest represents the request to replace the availability windows of a product or category
+type AvailabilityRequest struct {
+	Windows []AvailabilityWindowRequest `json:"windows" binding:"max=50,dive" doc:"Windows during which the products can be ordered, none makes them available at any time"`
+} //@name AvailabilityReq
+
+// AvailabilityWindowRequest represents a window of an availability request, in the store time zone
+type AvailabilityWindowRequest struct {
+	DaysOfWeek []int  `json:"daysOfWeek" binding:"required,min=1,max=7,dive,min=1,max=7" example:"1,2,3,4,5" doc:"ISO days of the week the window opens on, 1 is Monday and 7 is Sunday"`
+	Start      string `json:"start" binding:"required,len=5" example:"07:00" doc:"Opening time as HH:MM"`
+	End        string `json:"end" binding:"required,len=5" example:"11:00" doc:"Closing time as HH:MM, 24:00 is midnight. An end before the start closes on the next day"`
+} //@name AvailabilityWindowReq
@@ -0,0 +1,12 @@
+package requests
+
+// ImportProductsRequest represents the query parameters of a catalog import
+type ImportProductsRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv" example:"csv" doc:"Catalog format, defaults to the Content-Type of the body"`
+	DryRun bool   `form:"dryRun" example:"true" doc:"Only report what the import would change"`
+}
+
+// ExportProductsRequest represents the query parameters of a catalog export
+type ExportProductsRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv" example:"csv" doc:"Catalog format (defaults to json)"`
+}
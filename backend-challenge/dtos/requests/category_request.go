@@ -0,0 +1,14 @@
+package requests
+
+// CategoryRequest represents the request to create or replace a category
+type CategoryRequest struct {
+	Name      string `json:"name" binding:"required,max=100" example:"Vegetarian" doc:"Category name"`
+	ParentId  string `json:"parentId,omitempty" binding:"omitempty,max=20" example:"1" doc:"ID of the parent category, absent for a top level category"`
+	SortOrder int    `json:"sortOrder,omitempty" example:"0" doc:"Position of the category among its siblings on the menu"`
+	Active    *bool  `json:"active,omitempty" example:"true" doc:"Whether the category is shown on the menu (defaults to true)"`
+} //@name CategoryReq
+
+// CategoryTreeRequest represents the query parameters accepted when retrieving the category tree
+type CategoryTreeRequest struct {
+	IncludeInactive bool `form:"includeInactive" example:"false" doc:"Also return inactive categories and their subcategories"`
+}
@@ -0,0 +1,19 @@
+package requests
+
+import "oolio.com/kart/money"
+
+// ModifierGroupRequest represents the request to create or replace a modifier group of a product
+type ModifierGroupRequest struct {
+	Name      string            `json:"name" binding:"required,max=100" example:"Size" doc:"Modifier group name"`
+	MinSelect int               `json:"minSelect" binding:"gte=0" example:"1" doc:"Minimum number of modifiers to choose"`
+	MaxSelect int               `json:"maxSelect" binding:"required,gte=1" example:"1" doc:"Maximum number of modifiers to choose"`
+	SortOrder int               `json:"sortOrder,omitempty" example:"0" doc:"Position of the group on the menu"`
+	Modifiers []ModifierRequest `json:"modifiers" binding:"required,min=1,max=50,dive" doc:"Modifiers of the group"`
+} //@name ModifierGroupReq
+
+// ModifierRequest represents a modifier of a modifier group request
+type ModifierRequest struct {
+	Name       string      `json:"name" binding:"required,max=100" example:"Large" doc:"Modifier name"`
+	PriceDelta money.Money `json:"priceDelta" binding:"gte=-99999999.99,lte=99999999.99" example:"2.5" doc:"Price added to one unit of the product, may be negative" swaggertype:"number"`
+	SortOrder  int         `json:"sortOrder,omitempty" example:"0" doc:"Position of the modifier in its group"`
+} //@name ModifierReq
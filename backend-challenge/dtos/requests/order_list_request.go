@@ -0,0 +1,47 @@
+package requests
+
+import (
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"time"
+)
+
+// DefaultOrderPageSize is the number of orders returned when no limit is asked for
+const DefaultOrderPageSize = 20
+
+// ListOrdersRequest represents the query parameters accepted when listing orders
+type ListOrdersRequest struct {
+	From       *time.Time   `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-05-01T00:00:00+10:00" doc:"Only return orders placed at or after this time"`
+	To         *time.Time   `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-05-02T00:00:00+10:00" doc:"Only return orders placed before this time"`
+	CouponCode string       `form:"couponCode" binding:"omitempty,max=20" example:"HAPPYHRS" doc:"Only return orders placed with this coupon code"`
+	MinTotal   *money.Money `form:"minTotal" binding:"omitempty,gte=0" example:"10" doc:"Minimum total (inclusive)" swaggertype:"number"`
+	MaxTotal   *money.Money `form:"maxTotal" binding:"omitempty,gte=0" example:"100" doc:"Maximum total (inclusive)" swaggertype:"number"`
+	// Statuses is a comma separated list, such as "placed,accepted"
+	Statuses  []string `form:"status" collection_format:"csv" binding:"omitempty,max=6,dive,oneof=placed accepted preparing ready completed cancelled" example:"placed,accepted" doc:"Only return orders in any of these statuses"`
+	Sort      string   `form:"sort" binding:"omitempty,oneof=created_at total" example:"created_at" doc:"Sort key (defaults to created_at)"`
+	Direction string   `form:"direction" binding:"omitempty,oneof=asc desc" example:"desc" doc:"Sort direction (defaults to desc, newest first)"`
+	Limit     *int     `form:"limit" binding:"omitempty,min=1,max=100" example:"20" doc:"Maximum number of orders to return"`
+	Cursor    string   `form:"cursor" binding:"omitempty,max=512" doc:"Opaque cursor returned in the X-Next-Cursor header of the previous page"`
+}
+
+// ToOrderFilter converts the query parameters to an order filter, limited to DefaultOrderPageSize orders when no
+// limit was asked for
+func (r *ListOrdersRequest) ToOrderFilter() *models.OrderFilter {
+	limit := DefaultOrderPageSize
+	if r.Limit != nil {
+		limit = *r.Limit
+	}
+
+	return &models.OrderFilter{
+		From:       r.From,
+		To:         r.To,
+		CouponCode: r.CouponCode,
+		MinTotal:   r.MinTotal,
+		MaxTotal:   r.MaxTotal,
+		Statuses:   r.Statuses,
+		Sort:       r.Sort,
+		Direction:  r.Direction,
+		Limit:      limit,
+		Cursor:     r.Cursor,
+	}
+}
@@ -8,6 +8,15 @@ type PlaceOrderRequest struct {
 
 // OrderItemRequest represents an item in the order request
 type OrderItemRequest struct {
-	ProductId string `json:"productId" binding:"required" example:"1" doc:"Product ID to order"`
-	Quantity  *int   `json:"quantity" binding:"required,gt=0" example:"2" doc:"Quantity to order (must be greater than 0)"`
+	ProductId string   `json:"productId" binding:"required" example:"1" doc:"Product ID to order"`
+	Quantity  *int     `json:"quantity" binding:"required,gt=0" example:"2" doc:"Quantity to order (must be greater than 0)"`
+	Modifiers []string `json:"modifiers,omitempty" binding:"omitempty,max=20,dive,required" example:"3,7" doc:"IDs of the modifiers chosen for the item"`
 } //@name OrderItemReq
+
+// OrderTransitionRequest represents the request to move an order to a new status
+type OrderTransitionRequest struct {
+	Status  string `json:"status" binding:"required,oneof=placed accepted preparing ready completed cancelled" example:"accepted" doc:"Status to move the order to"`
+	Version int    `json:"version" binding:"required,min=1" example:"1" doc:"Version of the order the transition was decided on, as last read"`
+	Actor   string `json:"actor" binding:"required,max=100" example:"kitchen" doc:"Terminal or person making the transition"`
+	Reason  string `json:"reason,omitempty" binding:"omitempty,max=500" example:"Customer changed their mind" doc:"Why the order was moved"`
+} //@name OrderTransitionReq
@@ -0,0 +1,51 @@
+package requests
+
+import (
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"time"
+)
+
+// ListProductsRequest represents the query parameters accepted when listing products
+type ListProductsRequest struct {
+	Category   string       `form:"category" binding:"omitempty,max=512" example:"Pizza" doc:"Only return products of this category path and its subcategories"`
+	CategoryId *int64       `form:"categoryId" binding:"omitempty,min=1" example:"1" doc:"Only return products of this category and its subcategories"`
+	Status     string       `form:"status" binding:"omitempty,oneof=available sold_out hidden discontinued" example:"available" doc:"Only return products with this status (defaults to available)"`
+	MinPrice   *money.Money `form:"minPrice" binding:"omitempty,gte=0" example:"5" doc:"Minimum price (inclusive)" swaggertype:"number"`
+	MaxPrice   *money.Money `form:"maxPrice" binding:"omitempty,gte=0" example:"20" doc:"Maximum price (inclusive)" swaggertype:"number"`
+	Query      string       `form:"q" binding:"omitempty,max=255" example:"pizza" doc:"Case-insensitive search on the product name"`
+	Sort       string       `form:"sort" binding:"omitempty,oneof=price name created_at" example:"price" doc:"Sort key"`
+	Direction  string       `form:"direction" binding:"omitempty,oneof=asc desc" example:"asc" doc:"Sort direction (defaults to asc)"`
+	Limit      *int         `form:"limit" binding:"omitempty,min=1,max=100" example:"20" doc:"Maximum number of products to return"`
+	Offset     *int         `form:"offset" binding:"omitempty,min=0,excluded_with=Cursor" example:"0" doc:"Number of products to skip, cannot be combined with cursor"`
+	Cursor     string       `form:"cursor" binding:"omitempty,max=512" doc:"Opaque cursor returned in the X-Next-Cursor header of the previous page"`
+	// AvailableNow and At only keep the products whose availability windows are open
+	AvailableNow bool       `form:"availableNow" example:"true" doc:"Only return products that can be ordered at this time of day"`
+	At           *time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty,excluded_with=AvailableNow" example:"2024-05-01T08:30:00+10:00" doc:"Only return products that can be ordered at this time, cannot be combined with availableNow"`
+	// ExcludeAllergens and Diets are comma separated lists, such as "gluten,nuts"
+	ExcludeAllergens []string `form:"excludeAllergens" collection_format:"csv" binding:"omitempty,max=14,dive,oneof=celery crustaceans dairy eggs fish gluten lupin molluscs mustard nuts peanuts sesame soy sulphites" example:"gluten,nuts" doc:"Only return products containing none of these allergens"`
+	Diets            []string `form:"diet" collection_format:"csv" binding:"omitempty,max=6,dive,oneof=vegetarian vegan halal kosher gluten_free dairy_free" example:"vegan" doc:"Only return products suitable for all of these diets"`
+}
+
+// ToProductFilter converts the query parameters to a product filter
+func (r *ListProductsRequest) ToProductFilter() *models.ProductFilter {
+	return &models.ProductFilter{
+		Category:   r.Category,
+		CategoryId: r.CategoryId,
+		Status:     r.Status,
+		MinPrice:   r.MinPrice,
+		MaxPrice:   r.MaxPrice,
+		Query:      r.Query,
+		Sort:       r.Sort,
+		Direction:  r.Direction,
+		Limit:      r.Limit,
+		Offset:     r.Offset,
+		Cursor:     r.Cursor,
+
+		AvailableNow: r.AvailableNow,
+		AvailableAt:  r.At,
+
+		ExcludeAllergens: r.ExcludeAllergens,
+		Diets:            r.Diets,
+	}
+}
@@ -0,0 +1,44 @@
+package requests
+
+import "oolio.com/kart/money"
+
+// ProductRequest represents the request to create or replace a product
+type ProductRequest struct {
+	Name        string         `json:"name" binding:"required,max=255" example:"Margherita Pizza" doc:"Product name"`
+	Description string         `json:"description,omitempty" binding:"max=2000" example:"Tomato, mozzarella and basil" doc:"Product description"`
+	Category    string         `json:"category,omitempty" binding:"required_without=CategoryId,max=512" example:"Pizza > Vegetarian" doc:"Product category path, missing categories are created"`
+	CategoryId  string         `json:"categoryId,omitempty" binding:"omitempty,max=20" example:"3" doc:"Product category ID, takes precedence over the category path"`
+	Price       *money.Money   `json:"price" binding:"required,gte=0,lte=99999999.99" example:"12.99" doc:"Product price in the currency of the store" swaggertype:"number"`
+	Status      string         `json:"status,omitempty" binding:"omitempty,oneof=available sold_out hidden discontinued" example:"available" doc:"Product status (defaults to available)"`
+	Image       ImageRequest   `json:"image" doc:"Product image set"`
+	Allergens   []string       `json:"allergens,omitempty" binding:"omitempty,max=14,dive,oneof=celery crustaceans dairy eggs fish gluten lupin molluscs mustard nuts peanuts sesame soy sulphites" example:"gluten,dairy" doc:"Allergens the product contains"`
+	Diets       []string       `json:"diets,omitempty" binding:"omitempty,max=6,dive,oneof=vegetarian vegan halal kosher gluten_free dairy_free" example:"vegetarian" doc:"Diets the product is suitable for, vegan implies vegetarian and dairy_free"`
+	Meta        map[string]any `json:"meta,omitempty" doc:"Optional free-form product metadata"`
+} //@name ProductReq
+
+// PatchProductRequest represents a partial update of a product, only the provided fields are changed
+type PatchProductRequest struct {
+	Name        *string        `json:"name,omitempty" binding:"omitempty,min=1,max=255" example:"Margherita Pizza" doc:"Product name"`
+	Description *string        `json:"description,omitempty" binding:"omitempty,max=2000" example:"Tomato, mozzarella and basil" doc:"Product description"`
+	Category    *string        `json:"category,omitempty" binding:"omitempty,min=1,max=512" example:"Pizza > Vegetarian" doc:"Product category path, missing categories are created"`
+	CategoryId  *string        `json:"categoryId,omitempty" binding:"omitempty,max=20" example:"3" doc:"Product category ID, takes precedence over the category path"`
+	Price       *money.Money   `json:"price,omitempty" binding:"omitempty,gte=0,lte=99999999.99" example:"12.99" doc:"Product price in the currency of the store" swaggertype:"number"`
+	Status      *string        `json:"status,omitempty" binding:"omitempty,oneof=available sold_out hidden discontinued" example:"available" doc:"Product status"`
+	Image       *ImageRequest  `json:"image,omitempty" doc:"Product image set, replaces the existing one"`
+	Allergens   *[]string      `json:"allergens,omitempty" binding:"omitempty,max=14,dive,oneof=celery crustaceans dairy eggs fish gluten lupin molluscs mustard nuts peanuts sesame soy sulphites" example:"gluten,dairy" doc:"Allergens the product contains, replaces the existing ones"`
+	Diets       *[]string      `json:"diets,omitempty" binding:"omitempty,max=6,dive,oneof=vegetarian vegan halal kosher gluten_free dairy_free" example:"vegetarian" doc:"Diets the product is suitable for, replaces the existing ones"`
+	Meta        map[string]any `json:"meta,omitempty" doc:"Product metadata, replaces the existing one"`
+} //@name PatchProductReq
+
+// ImageRequest represents the image set of a product
+type ImageRequest struct {
+	Thumbnail string `json:"thumbnail" example:"https://example.com/images/pizza-thumbnail.jpg" doc:"Thumbnail image URL"`
+	Mobile    string `json:"mobile" example:"https://example.com/images/pizza-mobile.jpg" doc:"Mobile image URL"`
+	Tablet    string `json:"tablet" example:"https://example.com/images/pizza-tablet.jpg" doc:"Tablet image URL"`
+	Desktop   string `json:"desktop" example:"https://example.com/images/pizza-desktop.jpg" doc:"Desktop image URL"`
+} //@name ImageReq
+
+// ProductStatusRequest represents the request to change the status of a product
+type ProductStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=available sold_out hidden discontinued" example:"sold_out" doc:"New product status"`
+} //@name ProductStatusReq
@@ -0,0 +1,17 @@
+package requests
+
+// DefaultRecommendationLimit is the number of recommendations returned when no limit is asked for
+const DefaultRecommendationLimit = 5
+
+// RecommendationsRequest represents the query parameters accepted when listing the recommendations of a product
+type RecommendationsRequest struct {
+	Limit *int `form:"limit" binding:"omitempty,min=1,max=20" example:"5" doc:"Maximum number of products to return"`
+}
+
+// LimitOrDefault returns the limit of the request, DefaultRecommendationLimit when none was asked for
+func (r *RecommendationsRequest) LimitOrDefault() int {
+	if r.Limit == nil {
+		return DefaultRecommendationLimit
+	}
+	return *r.Limit
+}
@@ -0,0 +1,15 @@
+package requests
+
+// RefundRequest represents the request to refund a completed order, in full or some of its items
+type RefundRequest struct {
+	Items   []RefundItemRequest `json:"items,omitempty" binding:"omitempty,max=100,dive" doc:"Items to refund, everything not refunded yet when absent"`
+	Actor   string              `json:"actor" binding:"required,max=100" example:"counter" doc:"Terminal or person giving the refund"`
+	Reason  string              `json:"reason,omitempty" binding:"omitempty,max=500" example:"Cold fries" doc:"Why the order is refunded"`
+	Restock bool                `json:"restock,omitempty" example:"false" doc:"Put the refunded quantities back in stock, for items returned untouched"`
+} //@name RefundReq
+
+// RefundItemRequest represents an item of a refund request
+type RefundItemRequest struct {
+	OrderItemId string `json:"orderItemId" binding:"required" example:"12" doc:"ID of the order item to refund"`
+	Quantity    int    `json:"quantity" binding:"required,min=1" example:"1" doc:"Quantity of the order item to refund"`
+} //@name RefundItemReq
@@ -0,0 +1,11 @@
+package requests
+
+// StockAdjustmentRequest represents a manual change of the stock of a product, exactly one of delta, quantity and
+// unlimited must be provided
+type StockAdjustmentRequest struct {
+	Delta     *int   `json:"delta,omitempty" binding:"omitempty,ne=0" example:"-2" doc:"Relative change of a limited stock"`
+	Quantity  *int   `json:"quantity,omitempty" binding:"omitempty,gte=0" example:"25" doc:"New absolute stock quantity"`
+	Unlimited bool   `json:"unlimited,omitempty" example:"false" doc:"Stop tracking the stock of the product"`
+	Reason    string `json:"reason" binding:"required,max=255" example:"daily delivery" doc:"Reason of the adjustment"`
+	Actor     string `json:"actor,omitempty" binding:"max=100" example:"kitchen" doc:"Who made the adjustment"`
+} //@name StockAdjustmentReq
@@ -0,0 +1,12 @@
+package requests
+
+// ProductTranslationRequest represents the request to translate a product into a locale
+type ProductTranslationRequest struct {
+	Name        string `json:"name" binding:"required,max=255" example:"Gaufre au chocolat" doc:"Name of the product in the locale"`
+	Description string `json:"description" binding:"max=2000" example:"Gaufre croustillante nappée de chocolat" doc:"Description of the product in the locale"`
+} //@name ProductTranslationReq
+
+// CategoryTranslationRequest represents the request to translate a category into a locale
+type CategoryTranslationRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Gaufres" doc:"Name of the category in the locale, without >"`
+} //@name CategoryTranslationReq
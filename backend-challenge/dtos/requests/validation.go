@@ -0,0 +1,21 @@
+package requests
+
+import (
+	"reflect"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"oolio.com/kart/money"
+)
+
+// The binding tags compare amounts of money as numbers, such as gte=0 on a price
+func init() {
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterCustomTypeFunc(func(field reflect.Value) any {
+			if amount, ok := field.Interface().(money.Money); ok {
+				return amount.InexactFloat64()
+			}
+			return nil
+		}, money.Money{})
+	}
+}
@@ -1,8 +1,37 @@
 package responses
 
+import "oolio.com/kart/exceptions/errors"
+
 // APIResponse represents the response for errors
 type APIResponse struct {
-	Code    int    `json:"code" example:"400" doc:"HTTP status code"`
-	Type    string `json:"type" example:"validation_error" doc:"Error type (validation_error, error, etc.)"`
-	Message string `json:"message" example:"invalid request" doc:"Human-readable error message"`
+	Code    int                 `json:"code" example:"400" doc:"HTTP status code"`
+	Type    string              `json:"type" example:"validation_error" doc:"Error type (validation_error, error, etc.)"`
+	Message string              `json:"message" example:"invalid request" doc:"Human-readable error message"`
+	Details []ItemErrorResponse `json:"details,omitempty" doc:"Per-item reasons when individual items of the request were rejected"`
 } //@name ApiResponse
+
+// ItemErrorResponse represents the reason a single item of a request was rejected
+type ItemErrorResponse struct {
+	ProductId string `json:"productId" example:"1" doc:"Product ID of the rejected item"`
+	Reason    string `json:"reason" example:"product_sold_out" doc:"Machine readable reason"`
+	Message   string `json:"message" example:"product is sold out" doc:"Human-readable reason"`
+} //@name ItemError
+
+// ToErrorResponse converts error details returned by a service to an API response
+func ToErrorResponse(errDetails *errors.ErrorDetails) APIResponse {
+	response := APIResponse{
+		Code:    errDetails.ErrorCode,
+		Type:    "error",
+		Message: errDetails.Message,
+	}
+
+	for _, item := range errDetails.Items {
+		response.Details = append(response.Details, ItemErrorResponse{
+			ProductId: item.ProductId,
+			Reason:    item.Reason,
+			Message:   item.Message,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,46 @@
+package responses
+
+import (
+	"oolio.com/kart/models"
+	"strconv"
+)
+
+// AvailabilityResponse represents the availability windows of a product or category in the API response
+type AvailabilityResponse struct {
+	TimeZone     string                        `json:"timeZone" example:"Australia/Sydney" doc:"Time zone of the store the windows are in"`
+	Inherited    bool                          `json:"inherited" example:"false" doc:"Whether the windows of the product are the ones of its category"`
+	AvailableNow bool                          `json:"availableNow" example:"true" doc:"Whether any of the windows is open now, always true without windows"`
+	Windows      []*AvailabilityWindowResponse `json:"windows" doc:"Windows during which the products can be ordered, none means at any time"`
+} //@name Availability
+
+// AvailabilityWindowResponse represents an availability window in the API response
+type AvailabilityWindowResponse struct {
+	Id         string `json:"id" example:"1" doc:"Window ID"`
+	CategoryId string `json:"categoryId,omitempty" example:"3" doc:"Category the window is defined on, absent for windows of the product"`
+	DaysOfWeek []int  `json:"daysOfWeek" example:"1,2,3,4,5" doc:"ISO days of the week the window opens on, 1 is Monday and 7 is Sunday"`
+	Start      string `json:"start" example:"07:00" doc:"Opening time"`
+	End        string `json:"end" example:"11:00" doc:"Closing time, on the next day when before the opening time"`
+} //@name AvailabilityWindow
+
+// ToAvailabilityResponse converts availability windows to an API response
+func ToAvailabilityResponse(availability *models.Availability, timeZone string) *AvailabilityResponse {
+	windows := make([]*AvailabilityWindowResponse, len(availability.Windows))
+	for i, window := range availability.Windows {
+		windows[i] = &AvailabilityWindowResponse{
+			Id:         strconv.FormatInt(window.Id, 10),
+			DaysOfWeek: window.DaysOfWeek,
+			Start:      models.FormatMinute(window.StartMinute),
+			End:        models.FormatMinute(window.EndMinute),
+		}
+		if window.CategoryId != nil {
+			windows[i].CategoryId = strconv.FormatInt(*window.CategoryId, 10)
+		}
+	}
+
+	return &AvailabilityResponse{
+		TimeZone:     timeZone,
+		Inherited:    availability.Inherited,
+		AvailableNow: availability.AvailableNow,
+		Windows:      windows,
+	}
+}
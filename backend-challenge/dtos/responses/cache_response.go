@@ -0,0 +1,31 @@
+package responses
+
+import "oolio.com/kart/models"
+
+// CacheStatsResponse represents the counters of an in-memory cache in the API response
+type CacheStatsResponse struct {
+	Name          string  `json:"name" example:"products" doc:"Cache name"`
+	Active        bool    `json:"active" example:"true" doc:"Whether reads are served from memory, false while database changes cannot be followed"`
+	Entries       int     `json:"entries" example:"42" doc:"Number of cached entries"`
+	Hits          int64   `json:"hits" example:"1200" doc:"Reads served from memory"`
+	Misses        int64   `json:"misses" example:"35" doc:"Reads sent to the database"`
+	HitRatio      float64 `json:"hitRatio" example:"0.97" doc:"Share of reads served from memory"`
+	Invalidations int64   `json:"invalidations" example:"3" doc:"Number of times the cache was emptied"`
+} //@name CacheStats
+
+// ToCacheStatsResponses converts the cache counters to API responses
+func ToCacheStatsResponses(stats []*models.CacheStats) []*CacheStatsResponse {
+	responses := make([]*CacheStatsResponse, len(stats))
+	for i, s := range stats {
+		responses[i] = &CacheStatsResponse{
+			Name:          s.Name,
+			Active:        s.Active,
+			Entries:       s.Entries,
+			Hits:          s.Hits,
+			Misses:        s.Misses,
+			HitRatio:      s.HitRatio(),
+			Invalidations: s.Invalidations,
+		}
+	}
+	return responses
+}
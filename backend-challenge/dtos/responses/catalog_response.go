@@ -0,0 +1,70 @@
+package responses
+
+import (
+	"oolio.com/kart/models"
+	"strconv"
+)
+
+// ProductImportResponse represents the outcome of a catalog import in the API response
+type ProductImportResponse struct {
+	DryRun    bool                          `json:"dryRun" example:"true" doc:"Whether the import only reports what it would change"`
+	Created   int                           `json:"created" example:"2" doc:"Number of products created"`
+	Updated   int                           `json:"updated" example:"1" doc:"Number of products updated"`
+	Unchanged int                           `json:"unchanged" example:"9" doc:"Number of products already up to date"`
+	Changes   []*ProductChangeResponse      `json:"changes" doc:"What happens to every valid product of the catalog"`
+	Errors    []*ProductImportErrorResponse `json:"errors" doc:"Products of the catalog that cannot be imported"`
+} //@name ProductImport
+
+// ProductChangeResponse represents what an import does with one product
+type ProductChangeResponse struct {
+	Row      int      `json:"row" example:"3" doc:"Position of the product in the catalog, starting at 1"`
+	Action   string   `json:"action" example:"updated" doc:"created, updated or unchanged"`
+	Id       string   `json:"id,omitempty" example:"1" doc:"Product ID, absent for products a dry run would create"`
+	Name     string   `json:"name" example:"Margherita Pizza" doc:"Product name"`
+	Category string   `json:"category" example:"Pizza" doc:"Product category"`
+	Fields   []string `json:"fields,omitempty" example:"price,status" doc:"Fields an update changes"`
+} //@name ProductChange
+
+// ProductImportErrorResponse represents a product of the catalog that cannot be imported
+type ProductImportErrorResponse struct {
+	Row      int    `json:"row" example:"4" doc:"Position of the product in the catalog, starting at 1"`
+	Name     string `json:"name" example:"Margherita Pizza" doc:"Product name"`
+	Category string `json:"category" example:"Pizza" doc:"Product category"`
+	Message  string `json:"message" example:"product price must not be negative" doc:"Why the product cannot be imported"`
+} //@name ProductImportError
+
+// ToProductImportResponse converts domain model to API response
+func ToProductImportResponse(result *models.ProductImport) *ProductImportResponse {
+	response := &ProductImportResponse{
+		DryRun:    result.DryRun,
+		Created:   result.Created,
+		Updated:   result.Updated,
+		Unchanged: result.Unchanged,
+		Changes:   make([]*ProductChangeResponse, len(result.Changes)),
+		Errors:    make([]*ProductImportErrorResponse, len(result.Errors)),
+	}
+
+	for i, change := range result.Changes {
+		response.Changes[i] = &ProductChangeResponse{
+			Row:      change.Row,
+			Action:   change.Action,
+			Name:     change.Product.Name,
+			Category: change.Product.Category,
+			Fields:   change.Fields,
+		}
+		if change.Product.Id != 0 {
+			response.Changes[i].Id = strconv.FormatInt(change.Product.Id, 10)
+		}
+	}
+
+	for i, importErr := range result.Errors {
+		response.Errors[i] = &ProductImportErrorResponse{
+			Row:      importErr.Row,
+			Name:     importErr.Name,
+			Category: importErr.Category,
+			Message:  importErr.Message,
+		}
+	}
+
+	return response
+}
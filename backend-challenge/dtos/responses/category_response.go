@@ -0,0 +1,46 @@
+package responses
+
+import (
+	"oolio.com/kart/models"
+	"strconv"
+)
+
+// CategoryResponse represents a category with its subcategories in the API response
+type CategoryResponse struct {
+	Id           string              `json:"id" example:"2" doc:"Category ID"`
+	ParentId     string              `json:"parentId,omitempty" example:"1" doc:"ID of the parent category, absent for a top level category"`
+	Name         string              `json:"name" example:"Vegetarian" doc:"Category name"`
+	Path         string              `json:"path" example:"Pizza > Vegetarian" doc:"Names of the category and its ancestors, the category of a product"`
+	SortOrder    int                 `json:"sortOrder" example:"0" doc:"Position of the category among its siblings on the menu"`
+	Active       bool                `json:"active" example:"true" doc:"Whether the category is shown on the menu"`
+	ProductCount int64               `json:"productCount" example:"4" doc:"Number of available products in the category and its subcategories"`
+	Children     []*CategoryResponse `json:"children" doc:"Subcategories in menu order"`
+} //@name Category
+
+// ToCategoryResponse converts a category and its subcategories to an API response
+func ToCategoryResponse(category *models.Category) *CategoryResponse {
+	response := &CategoryResponse{
+		Id:           strconv.FormatInt(category.Id, 10),
+		Name:         category.Name,
+		Path:         category.Path,
+		SortOrder:    category.SortOrder,
+		Active:       category.Active,
+		ProductCount: category.ProductCount,
+		Children:     ToCategoryResponses(category.Children),
+	}
+
+	if category.ParentId != nil {
+		response.ParentId = strconv.FormatInt(*category.ParentId, 10)
+	}
+
+	return response
+}
+
+// ToCategoryResponses converts multiple categories to API responses
+func ToCategoryResponses(categories []*models.Category) []*CategoryResponse {
+	responses := make([]*CategoryResponse, len(categories))
+	for i, category := range categories {
+		responses[i] = ToCategoryResponse(category)
+	}
+	return responses
+}
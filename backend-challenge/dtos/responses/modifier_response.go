@@ -0,0 +1,59 @@
+package responses
+
+import (
+	"oolio.com/kart/configs"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"strconv"
+)
+
+// ModifierGroupResponse represents a modifier group of a product in the API response
+type ModifierGroupResponse struct {
+	Id        string              `json:"id" example:"1" doc:"Modifier group ID"`
+	Name      string              `json:"name" example:"Size" doc:"Modifier group name"`
+	MinSelect int                 `json:"minSelect" example:"1" doc:"Minimum number of modifiers to choose"`
+	MaxSelect int                 `json:"maxSelect" example:"1" doc:"Maximum number of modifiers to choose"`
+	SortOrder int                 `json:"sortOrder" example:"0" doc:"Position of the group on the menu"`
+	Modifiers []*ModifierResponse `json:"modifiers" doc:"Modifiers of the group"`
+	Currency  money.Currency      `json:"currency" example:"USD" doc:"ISO 4217 code of the currency of the amounts, the currency of the store" swaggertype:"string"`
+} //@name ModifierGroup
+
+// ModifierResponse represents a modifier in the API response
+type ModifierResponse struct {
+	Id         string      `json:"id" example:"3" doc:"Modifier ID, used to choose the modifier when ordering"`
+	Name       string      `json:"name" example:"Large" doc:"Modifier name"`
+	PriceDelta money.Money `json:"priceDelta" example:"2.5" doc:"Price added to one unit of the product" swaggertype:"number"`
+	SortOrder  int         `json:"sortOrder" example:"0" doc:"Position of the modifier in its group"`
+} //@name Modifier
+
+// ToModifierGroupResponse converts domain model to API response
+func ToModifierGroupResponse(group *models.ModifierGroup) *ModifierGroupResponse {
+	modifiers := make([]*ModifierResponse, len(group.Modifiers))
+	for i, modifier := range group.Modifiers {
+		modifiers[i] = &ModifierResponse{
+			Id:         strconv.FormatInt(modifier.Id, 10),
+			Name:       modifier.Name,
+			PriceDelta: modifier.PriceDelta,
+			SortOrder:  modifier.SortOrder,
+		}
+	}
+
+	return &ModifierGroupResponse{
+		Id:        strconv.FormatInt(group.Id, 10),
+		Name:      group.Name,
+		MinSelect: group.MinSelect,
+		MaxSelect: group.MaxSelect,
+		SortOrder: group.SortOrder,
+		Modifiers: modifiers,
+		Currency:  configs.StoreCurrency,
+	}
+}
+
+// ToModifierGroupResponses converts multiple domain models to API responses
+func ToModifierGroupResponses(groups []*models.ModifierGroup) []*ModifierGroupResponse {
+	responses := make([]*ModifierGroupResponse, len(groups))
+	for i, group := range groups {
+		responses[i] = ToModifierGroupResponse(group)
+	}
+	return responses
+}
@@ -1,38 +1,176 @@
 package responses
 
 import (
+	"oolio.com/kart/configs"
 	"oolio.com/kart/models"
+	"oolio.com/kart/money"
 	"strconv"
+	"time"
 )
 
-// OrderResponse represents the response after placing an order
+// OrderResponse represents a placed order
 type OrderResponse struct {
 	CouponCode string              `json:"couponCode" example:"SAVE1000" doc:"Coupon code used for the order"`
 	Items      []OrderItemResponse `json:"items" doc:"List of items in the order"`
 	Id         string              `json:"id" example:"550e8400-e29b-41d4-a716-446655440000" doc:"Unique order ID (UUID)"`
+	Status     string              `json:"status" example:"placed" doc:"Status of the order"`
+	Version    int                 `json:"version" example:"1" doc:"Version of the order, to send along with its next transition"`
 	Products   []*ProductResponse  `json:"products" doc:"Detailed product information for each item"`
+	Subtotal   money.Money         `json:"subtotal" example:"100" doc:"Price of the items before the discount" swaggertype:"number"`
+	Discounts  money.Money         `json:"discounts" example:"10" doc:"Amount taken off the subtotal by the coupon" swaggertype:"number"`
+	Total      money.Money         `json:"total" example:"90" doc:"Price paid for the order" swaggertype:"number"`
+	Currency   money.Currency      `json:"currency" example:"USD" doc:"ISO 4217 code of the currency of the amounts, the currency of the store" swaggertype:"string"`
+	CreatedAt  time.Time           `json:"createdAt" example:"2024-01-01T12:00:00Z" doc:"When the order was placed"`
+	ModifiedAt time.Time           `json:"modifiedAt" example:"2024-01-01T12:00:00Z" doc:"When the order was last changed"`
 } //@name Order
 
+// OrderSummaryResponse represents an order in an order listing, without its items
+type OrderSummaryResponse struct {
+	Id         string         `json:"id" example:"550e8400-e29b-41d4-a716-446655440000" doc:"Unique order ID (UUID)"`
+	CouponCode string         `json:"couponCode" example:"HAPPYHRS" doc:"Coupon code used for the order"`
+	Status     string         `json:"status" example:"placed" doc:"Status of the order"`
+	Version    int            `json:"version" example:"1" doc:"Version of the order, to send along with its next transition"`
+	Subtotal   money.Money    `json:"subtotal" example:"100" doc:"Price of the items before the discount" swaggertype:"number"`
+	Discounts  money.Money    `json:"discounts" example:"10" doc:"Amount taken off the subtotal by the coupon" swaggertype:"number"`
+	Total      money.Money    `json:"total" example:"90" doc:"Price paid for the order" swaggertype:"number"`
+	Currency   money.Currency `json:"currency" example:"USD" doc:"ISO 4217 code of the currency of the amounts, the currency of the store" swaggertype:"string"`
+	CreatedAt  time.Time      `json:"createdAt" example:"2024-01-01T12:00:00Z" doc:"When the order was placed"`
+	ModifiedAt time.Time      `json:"modifiedAt" example:"2024-01-01T12:00:00Z" doc:"When the order was last changed"`
+} //@name OrderSummary
+
+// OrderTransitionResponse represents a status change of an order in the API response
+type OrderTransitionResponse struct {
+	Id        string    `json:"id" example:"1" doc:"Transition ID"`
+	From      string    `json:"from" example:"placed" doc:"Status the order moved from"`
+	To        string    `json:"to" example:"accepted" doc:"Status the order moved to"`
+	Actor     string    `json:"actor" example:"kitchen" doc:"Terminal or person that made the transition"`
+	Reason    string    `json:"reason,omitempty" example:"Customer changed their mind" doc:"Why the order was moved, absent when none was given"`
+	CreatedAt time.Time `json:"createdAt" example:"2024-01-01T12:05:00Z" doc:"When the transition was made"`
+} //@name OrderTransition
+
+// OrderQuoteResponse represents the price of an order that was not placed
+type OrderQuoteResponse struct {
+	CouponCode  string              `json:"couponCode" example:"SAVE1000" doc:"Coupon code applied to the order"`
+	Items       []OrderItemResponse `json:"items" doc:"List of items in the order"`
+	Products    []*ProductResponse  `json:"products" doc:"Detailed product information for each item"`
+	Subtotal    money.Money         `json:"subtotal" example:"27" doc:"Price of the items before the discount" swaggertype:"number"`
+	Discounts   money.Money         `json:"discounts" example:"0" doc:"Amount taken off the subtotal by the coupon" swaggertype:"number"`
+	Total       money.Money         `json:"total" example:"27" doc:"Price to pay" swaggertype:"number"`
+	Currency    money.Currency      `json:"currency" example:"USD" doc:"ISO 4217 code of the currency of the amounts, the currency of the store" swaggertype:"string"`
+	Suggestions []*ProductResponse  `json:"suggestions,omitempty" doc:"Products frequently bought together with the ones of the order, absent when there are none"`
+} //@name OrderQuote
+
 // OrderItemResponse represents a line item in the order response
 type OrderItemResponse struct {
-	ProductId string `json:"productId" example:"1" doc:"Product ID"`
-	Quantity  int    `json:"quantity" example:"2" doc:"Quantity ordered"`
+	Id        string                      `json:"id,omitempty" example:"12" doc:"Order item ID, to refund the item by, absent from quotes"`
+	ProductId string                      `json:"productId" example:"1" doc:"Product ID"`
+	Quantity  int                         `json:"quantity" example:"2" doc:"Quantity ordered"`
+	UnitPrice money.Money                 `json:"unitPrice" example:"15.5" doc:"Price of one unit, its modifiers included" swaggertype:"number"`
+	LineTotal money.Money                 `json:"lineTotal" example:"31" doc:"Price of the line before the discount, the unit price times the quantity" swaggertype:"number"`
+	Modifiers []OrderItemModifierResponse `json:"modifiers,omitempty" doc:"Modifiers chosen for the item"`
 } //@name OrderItem
 
+// OrderItemModifierResponse represents a modifier chosen for a line item in the order response
+type OrderItemModifierResponse struct {
+	Id         string      `json:"id" example:"3" doc:"Modifier ID"`
+	Group      string      `json:"group" example:"Size" doc:"Name of the modifier group"`
+	Name       string      `json:"name" example:"Large" doc:"Modifier name"`
+	PriceDelta money.Money `json:"priceDelta" example:"2.5" doc:"Price added to one unit of the product" swaggertype:"number"`
+} //@name OrderItemModifier
+
 // ToOrderResponse converts domain models to API response
 func ToOrderResponse(order *models.Order, items []models.OrderItem, products []*models.Product) *OrderResponse {
+	return &OrderResponse{
+		Id:         order.Id,
+		Items:      toOrderItemResponses(items),
+		Products:   ToProductResponses(products),
+		CouponCode: order.CouponCode,
+		Status:     order.Status,
+		Version:    order.Version,
+		Subtotal:   order.Subtotal,
+		Discounts:  order.Discount,
+		Total:      order.Total,
+		Currency:   configs.StoreCurrency,
+		CreatedAt:  order.CreatedAt,
+		ModifiedAt: order.ModifiedAt,
+	}
+}
+
+// ToOrderSummaryResponses converts orders to the API responses of an order listing
+func ToOrderSummaryResponses(orders []*models.Order) []*OrderSummaryResponse {
+	summaries := make([]*OrderSummaryResponse, len(orders))
+	for i, order := range orders {
+		summaries[i] = &OrderSummaryResponse{
+			Id:         order.Id,
+			CouponCode: order.CouponCode,
+			Status:     order.Status,
+			Version:    order.Version,
+			Subtotal:   order.Subtotal,
+			Discounts:  order.Discount,
+			Total:      order.Total,
+			Currency:   configs.StoreCurrency,
+			CreatedAt:  order.CreatedAt,
+			ModifiedAt: order.ModifiedAt,
+		}
+	}
+	return summaries
+}
+
+// ToOrderTransitionResponses converts the status changes of an order to API responses
+func ToOrderTransitionResponses(changes []*models.OrderStatusChange) []*OrderTransitionResponse {
+	transitions := make([]*OrderTransitionResponse, len(changes))
+	for i, change := range changes {
+		transitions[i] = &OrderTransitionResponse{
+			Id:        strconv.FormatInt(change.Id, 10),
+			From:      change.FromStatus,
+			To:        change.ToStatus,
+			Actor:     change.Actor,
+			Reason:    change.Reason,
+			CreatedAt: change.CreatedAt,
+		}
+	}
+	return transitions
+}
+
+// ToOrderQuoteResponse converts a priced order and the products suggested with it to an API response
+func ToOrderQuoteResponse(order *models.Order, items []models.OrderItem, products []*models.Product, suggestions []*models.Product) *OrderQuoteResponse {
+	response := &OrderQuoteResponse{
+		CouponCode: order.CouponCode,
+		Items:      toOrderItemResponses(items),
+		Products:   ToProductResponses(products),
+		Subtotal:   order.Subtotal,
+		Discounts:  order.Discount,
+		Total:      order.Total,
+		Currency:   configs.StoreCurrency,
+	}
+	if len(suggestions) > 0 {
+		response.Suggestions = ToProductResponses(suggestions)
+	}
+	return response
+}
+
+// toOrderItemResponses converts the lines of an order to API responses
+func toOrderItemResponses(items []models.OrderItem) []OrderItemResponse {
 	itemResponses := make([]OrderItemResponse, len(items))
 	for i, item := range items {
 		itemResponses[i] = OrderItemResponse{
 			ProductId: strconv.Itoa(int(item.ProductId)),
 			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			LineTotal: item.Price,
+		}
+		// Quoted items are not saved and have no ID yet
+		if item.Id != 0 {
+			itemResponses[i].Id = strconv.FormatInt(item.Id, 10)
+		}
+		for _, modifier := range item.Modifiers {
+			itemResponses[i].Modifiers = append(itemResponses[i].Modifiers, OrderItemModifierResponse{
+				Id:         strconv.FormatInt(modifier.ModifierId, 10),
+				Group:      modifier.GroupName,
+				Name:       modifier.Name,
+				PriceDelta: modifier.PriceDelta,
+			})
 		}
 	}
-
-	return &OrderResponse{
-		Id:         order.Id,
-		Items:      itemResponses,
-		Products:   ToProductResponses(products),
-		CouponCode: order.CouponCode,
-	}
+	return itemResponses
 }
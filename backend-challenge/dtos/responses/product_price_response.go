@@ -0,0 +1,46 @@
+package responses
+
+import (
+	"oolio.com/kart/configs"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"strconv"
+	"time"
+)
+
+// ProductPricesResponse represents the price history of a product in the API response
+type ProductPricesResponse struct {
+	ProductId      string                  `json:"productId" example:"1" doc:"Product ID"`
+	CurrentVersion int                     `json:"currentVersion" example:"2" doc:"Version of the current price"`
+	Prices         []*ProductPriceResponse `json:"prices" doc:"Every price of the product, newest version first"`
+	Currency       money.Currency          `json:"currency" example:"USD" doc:"ISO 4217 code of the currency of the amounts, the currency of the store" swaggertype:"string"`
+} //@name ProductPrices
+
+// ProductPriceResponse represents a version of the price of a product in the API response
+type ProductPriceResponse struct {
+	Version       int         `json:"version" example:"2" doc:"Price version, recorded on the order items placed at this price"`
+	Price         money.Money `json:"price" example:"6.5" doc:"Price of the product" swaggertype:"number"`
+	EffectiveFrom time.Time   `json:"effectiveFrom" example:"2024-01-01T12:00:00Z" doc:"When the price came into effect"`
+	EffectiveTo   *time.Time  `json:"effectiveTo,omitempty" example:"2024-02-01T12:00:00Z" doc:"When the price was replaced, absent for the current price"`
+} //@name ProductPrice
+
+// ToProductPricesResponse converts a product and its price history to an API response
+func ToProductPricesResponse(product *models.Product, prices []*models.ProductPrice) *ProductPricesResponse {
+	response := &ProductPricesResponse{
+		ProductId:      strconv.FormatInt(product.Id, 10),
+		CurrentVersion: product.PriceVersion,
+		Currency:       configs.StoreCurrency,
+		Prices:         make([]*ProductPriceResponse, len(prices)),
+	}
+
+	for i, price := range prices {
+		response.Prices[i] = &ProductPriceResponse{
+			Version:       price.Version,
+			Price:         price.Price,
+			EffectiveFrom: price.EffectiveFrom,
+			EffectiveTo:   price.EffectiveTo,
+		}
+	}
+
+	return response
+}
@@ -1,25 +1,57 @@
 package responses
 
 import (
+	"oolio.com/kart/configs"
 	"oolio.com/kart/models"
+	"oolio.com/kart/money"
 	"strconv"
+	"time"
 )
 
 // ProductResponse represents a product in the API response
 type ProductResponse struct {
-	Id       string  `json:"id" example:"1" doc:"Unique product ID"`
-	Name     string  `json:"name" example:"Margherita Pizza" doc:"Product name"`
-	Category string  `json:"category" example:"Pizza" doc:"Product category"`
-	Price    float64 `json:"price" example:"12.99" doc:"Product price in USD"`
+	Id          string         `json:"id" example:"1" doc:"Unique product ID"`
+	Name        string         `json:"name" example:"Margherita Pizza" doc:"Product name, in the locale of the response"`
+	Description string         `json:"description,omitempty" example:"Tomato, mozzarella and basil" doc:"Product description, in the locale of the response"`
+	Category    string         `json:"category" example:"Pizza" doc:"Product category path, nested categories are separated by >"`
+	CategoryId  string         `json:"categoryId" example:"1" doc:"Product category ID"`
+	Price       money.Money    `json:"price" example:"12.99" doc:"Product price in the currency of the store" swaggertype:"number"`
+	Currency    money.Currency `json:"currency" example:"USD" doc:"ISO 4217 code of the currency of the amounts, the currency of the store" swaggertype:"string"`
+	Image       ImageResponse  `json:"image" doc:"Product image set"`
+	Status      string         `json:"status" example:"available" doc:"Product availability status"`
+	Allergens   []string       `json:"allergens" example:"gluten,dairy" doc:"Allergens the product contains"`
+	Diets       []string       `json:"diets" example:"vegetarian" doc:"Diets the product is suitable for"`
+	DeletedAt   *time.Time     `json:"deletedAt,omitempty" example:"2024-02-01T12:00:00Z" doc:"When the product was removed from the menu, absent for products on the menu"`
 } //@name Product
 
+// ImageResponse represents the image set of a product in the API response
+type ImageResponse struct {
+	Thumbnail string `json:"thumbnail" example:"https://orderfoodonline.deno.dev/public/images/image-waffle-thumbnail.jpg" doc:"Thumbnail image URL"`
+	Mobile    string `json:"mobile" example:"https://orderfoodonline.deno.dev/public/images/image-waffle-mobile.jpg" doc:"Mobile image URL"`
+	Tablet    string `json:"tablet" example:"https://orderfoodonline.deno.dev/public/images/image-waffle-tablet.jpg" doc:"Tablet image URL"`
+	Desktop   string `json:"desktop" example:"https://orderfoodonline.deno.dev/public/images/image-waffle-desktop.jpg" doc:"Desktop image URL"`
+} //@name Image
+
 // ToProductResponse converts domain model to API response
 func ToProductResponse(product *models.Product) *ProductResponse {
 	return &ProductResponse{
-		Id:       strconv.Itoa(int(product.Id)),
-		Name:     product.Name,
-		Price:    product.Price,
-		Category: product.Category,
+		Id:          strconv.Itoa(int(product.Id)),
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Currency:    configs.StoreCurrency,
+		Category:    product.Category,
+		CategoryId:  strconv.FormatInt(product.CategoryId, 10),
+		Image: ImageResponse{
+			Thumbnail: product.Image.Thumbnail,
+			Mobile:    product.Image.Mobile,
+			Tablet:    product.Image.Tablet,
+			Desktop:   product.Image.Desktop,
+		},
+		Status:    product.Status,
+		Allergens: nonNilStrings(product.Allergens),
+		Diets:     nonNilStrings(product.Diets),
+		DeletedAt: product.DeletedAt,
 	}
 }
 
@@ -31,3 +63,11 @@ func ToProductResponses(products []*models.Product) []*ProductResponse {
 	}
 	return responses
 }
+
+// nonNilStrings returns an empty slice for nil so that empty lists are encoded as [] rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,69 @@
+package responses
+
+import (
+	"oolio.com/kart/configs"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"strconv"
+	"time"
+)
+
+// RefundResponse represents the receipt of a refund
+type RefundResponse struct {
+	Id            string               `json:"id" example:"1" doc:"Refund ID"`
+	OrderId       string               `json:"orderId" example:"550e8400-e29b-41d4-a716-446655440000" doc:"ID of the refunded order"`
+	Items         []RefundItemResponse `json:"items" doc:"Items refunded"`
+	Amount        money.Money          `json:"amount" example:"13" doc:"Amount given back" swaggertype:"number"`
+	Reason        string               `json:"reason,omitempty" example:"Cold fries" doc:"Why the order was refunded, absent when none was given"`
+	Actor         string               `json:"actor" example:"counter" doc:"Terminal or person that gave the refund"`
+	Restocked     bool                 `json:"restocked" example:"false" doc:"Whether the refunded quantities were put back in stock"`
+	OrderTotal    money.Money          `json:"orderTotal" example:"27" doc:"Total charged for the order" swaggertype:"number"`
+	RefundedTotal money.Money          `json:"refundedTotal" example:"13" doc:"Amount given back for the order by this refund and the ones before" swaggertype:"number"`
+	Remaining     money.Money          `json:"remaining" example:"14" doc:"Amount of the order left to refund after this refund" swaggertype:"number"`
+	Currency      money.Currency       `json:"currency" example:"USD" doc:"ISO 4217 code of the currency of the amounts, the currency of the store" swaggertype:"string"`
+	CreatedAt     time.Time            `json:"createdAt" example:"2024-01-01T12:30:00Z" doc:"When the refund was given"`
+} //@name Refund
+
+// RefundItemResponse represents a refunded order item on a refund receipt
+type RefundItemResponse struct {
+	OrderItemId string      `json:"orderItemId" example:"12" doc:"ID of the order item"`
+	ProductId   string      `json:"productId" example:"1" doc:"Product ID"`
+	Name        string      `json:"name" example:"Chicken Waffle" doc:"Product name"`
+	Quantity    int         `json:"quantity" example:"1" doc:"Quantity refunded"`
+	Amount      money.Money `json:"amount" example:"13" doc:"Amount given back for the item" swaggertype:"number"`
+} //@name RefundItem
+
+// ToRefundResponse converts a refund of an order to a receipt, refundedTotal being the amount given back by the refund
+// and the ones before it
+func ToRefundResponse(order *models.Order, refund *models.Refund, refundedTotal money.Money, productNames map[int64]string) *RefundResponse {
+	items := make([]RefundItemResponse, len(refund.Items))
+	for i, item := range refund.Items {
+		items[i] = RefundItemResponse{
+			OrderItemId: strconv.FormatInt(item.OrderItemId, 10),
+			ProductId:   strconv.FormatInt(item.ProductId, 10),
+			Name:        productNames[item.ProductId],
+			Quantity:    item.Quantity,
+			Amount:      item.Amount,
+		}
+	}
+
+	remaining, err := order.Total.Sub(refundedTotal)
+	if err != nil || remaining.Sign() < 0 {
+		remaining = money.Money{}
+	}
+
+	return &RefundResponse{
+		Id:            strconv.FormatInt(refund.Id, 10),
+		OrderId:       refund.OrderId,
+		Items:         items,
+		Amount:        refund.Amount,
+		Reason:        refund.Reason,
+		Actor:         refund.Actor,
+		Restocked:     refund.Restock,
+		OrderTotal:    order.Total,
+		RefundedTotal: refundedTotal,
+		Remaining:     remaining,
+		Currency:      configs.StoreCurrency,
+		CreatedAt:     refund.CreatedAt,
+	}
+}
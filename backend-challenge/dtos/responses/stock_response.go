@@ -0,0 +1,50 @@
+package responses
+
+import (
+	"oolio.com/kart/models"
+	"strconv"
+	"time"
+)
+
+// StockResponse represents the stock of a product and its latest adjustments in the API response
+type StockResponse struct {
+	ProductId   string                     `json:"productId" example:"1" doc:"Product ID"`
+	Quantity    *int                       `json:"quantity,omitempty" example:"25" doc:"Items left in stock, absent when the stock is unlimited"`
+	Unlimited   bool                       `json:"unlimited" example:"false" doc:"Whether the stock of the product is tracked"`
+	Adjustments []*StockAdjustmentResponse `json:"adjustments" doc:"Latest stock adjustments, newest first"`
+} //@name Stock
+
+// StockAdjustmentResponse represents an audited stock adjustment in the API response
+type StockAdjustmentResponse struct {
+	Id            string    `json:"id" example:"1" doc:"Adjustment ID"`
+	OrderId       string    `json:"orderId,omitempty" example:"0f8fad5b-d9cb-469f-a165-70867728950e" doc:"Order that consumed the stock"`
+	Delta         *int      `json:"delta,omitempty" example:"-2" doc:"Change of the stock"`
+	QuantityAfter *int      `json:"quantityAfter,omitempty" example:"23" doc:"Stock after the adjustment, absent when unlimited"`
+	Reason        string    `json:"reason" example:"order" doc:"Reason of the adjustment"`
+	Actor         string    `json:"actor,omitempty" example:"kitchen" doc:"Who made the adjustment"`
+	CreatedAt     time.Time `json:"createdAt" example:"2024-01-01T12:00:00Z" doc:"When the adjustment was made"`
+} //@name StockAdjustment
+
+// ToStockResponse converts a product and its stock adjustments to an API response
+func ToStockResponse(product *models.Product, adjustments []*models.StockAdjustment) *StockResponse {
+	response := &StockResponse{
+		ProductId:   strconv.FormatInt(product.Id, 10),
+		Quantity:    product.StockQuantity,
+		Unlimited:   product.StockQuantity == nil,
+		Adjustments: make([]*StockAdjustmentResponse, len(adjustments)),
+	}
+
+	for i, adjustment := range adjustments {
+		response.Adjustments[i] = &StockAdjustmentResponse{
+			Id:            strconv.FormatInt(adjustment.Id, 10),
+			OrderId:       adjustment.OrderId,
+			Delta:         adjustment.Delta,
+			QuantityAfter: adjustment.QuantityAfter,
+			Reason:        adjustment.Reason,
+			Actor:         adjustment.Actor,
+			CreatedAt:     adjustment.CreatedAt,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,63 @@
+package responses
+
+import (
+	"oolio.com/kart/models"
+	"strconv"
+	"time"
+)
+
+// ProductTranslationResponse represents the translation of a product in the API response
+type ProductTranslationResponse struct {
+	ProductId   string    `json:"productId" example:"1" doc:"Product ID"`
+	Locale      string    `json:"locale" example:"fr" doc:"Locale of the translation"`
+	Name        string    `json:"name" example:"Gaufre au chocolat" doc:"Name of the product in the locale"`
+	Description string    `json:"description,omitempty" example:"Gaufre croustillante nappée de chocolat" doc:"Description of the product in the locale"`
+	ModifiedAt  time.Time `json:"modifiedAt" example:"2024-01-01T00:00:00Z" doc:"When the translation was last changed"`
+} //@name ProductTranslation
+
+// CategoryTranslationResponse represents the translation of a category in the API response
+type CategoryTranslationResponse struct {
+	CategoryId string    `json:"categoryId" example:"3" doc:"Category ID"`
+	Locale     string    `json:"locale" example:"fr" doc:"Locale of the translation"`
+	Name       string    `json:"name" example:"Gaufres" doc:"Name of the category in the locale"`
+	ModifiedAt time.Time `json:"modifiedAt" example:"2024-01-01T00:00:00Z" doc:"When the translation was last changed"`
+} //@name CategoryTranslation
+
+// ToProductTranslationResponse converts a product translation to an API response
+func ToProductTranslationResponse(translation *models.ProductTranslation) *ProductTranslationResponse {
+	return &ProductTranslationResponse{
+		ProductId:   strconv.FormatInt(translation.ProductId, 10),
+		Locale:      translation.Locale,
+		Name:        translation.Name,
+		Description: translation.Description,
+		ModifiedAt:  translation.ModifiedAt,
+	}
+}
+
+// ToProductTranslationsResponse converts the translations of a product to an API response
+func ToProductTranslationsResponse(translations []*models.ProductTranslation) []*ProductTranslationResponse {
+	response := make([]*ProductTranslationResponse, len(translations))
+	for i, translation := range translations {
+		response[i] = ToProductTranslationResponse(translation)
+	}
+	return response
+}
+
+// ToCategoryTranslationResponse converts a category translation to an API response
+func ToCategoryTranslationResponse(translation *models.CategoryTranslation) *CategoryTranslationResponse {
+	return &CategoryTranslationResponse{
+		CategoryId: strconv.FormatInt(translation.CategoryId, 10),
+		Locale:     translation.Locale,
+		Name:       translation.Name,
+		ModifiedAt: translation.ModifiedAt,
+	}
+}
+
+// ToCategoryTranslationsResponse converts the translations of a category to an API response
+func ToCategoryTranslationsResponse(translations []*models.CategoryTranslation) []*CategoryTranslationResponse {
+	response := make([]*CategoryTranslationResponse, len(translations))
+	for i, translation := range translations {
+		response[i] = ToCategoryTranslationResponse(translation)
+	}
+	return response
+}
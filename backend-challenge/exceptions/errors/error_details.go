@@ -1,7 +1,15 @@
 package errors
 
 type ErrorDetails struct {
-	ErrorTimestamp int64  `json:"timestamp"`
-	Message        string `json:"error_message"`
-	ErrorCode      int    `json:"error_code"`
+	ErrorTimestamp int64       `json:"timestamp"`
+	Message        string      `json:"error_message"`
+	ErrorCode      int         `json:"error_code"`
+	Items          []ItemError `json:"items,omitempty"`
+}
+
+// ItemError describes why a single item of a request was rejected
+type ItemError struct {
+	ProductId string `json:"product_id"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
 }
@@ -13,3 +13,18 @@ func UnprocessableEntityException(message string) *errors.ErrorDetails {
 		ErrorCode:      http.StatusUnprocessableEntity,
 	}
 }
+
+// UnprocessableItemsException reports a request that was rejected because of the listed items
+func UnprocessableItemsException(message string, items []errors.ItemError) *errors.ErrorDetails {
+	return &errors.ErrorDetails{
+		ErrorTimestamp: time.Now().UnixMilli(),
+		Message:        message,
+		ErrorCode:      http.StatusUnprocessableEntity,
+		Items:          items,
+	}
+}
+
+// InsufficientStockException reports the items of a request that exceed the stock left
+func InsufficientStockException(items []errors.ItemError) *errors.ErrorDetails {
+	return UnprocessableItemsException("insufficient stock", items)
+}
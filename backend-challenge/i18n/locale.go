@@ -0,0 +1,94 @@
+// Package i18n negotiates the locale of a request from its Accept-Language header among the locales the store
+// supports, and carries the negotiated locale through the request context.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Locales are the locales the store serves, the default locale is the one products and categories are written in
+type Locales struct {
+	// Default is the locale of the names and descriptions stored on the products and categories themselves
+	Default string
+	// Supported lists the default locale first, followed by the locales translations can be written in
+	Supported []string
+	matcher   language.Matcher
+}
+
+// NewLocales validates the locales and returns them in their canonical form, such as "pt-BR", the default locale is
+// supported whether listed or not
+func NewLocales(defaultLocale string, supported []string) (*Locales, error) {
+	defaultTag, err := language.Parse(strings.TrimSpace(defaultLocale))
+	if err != nil {
+		return nil, fmt.Errorf("invalid default locale %q", defaultLocale)
+	}
+
+	locales := &Locales{Default: defaultTag.String(), Supported: []string{defaultTag.String()}}
+	tags := []language.Tag{defaultTag}
+	for _, locale := range supported {
+		if strings.TrimSpace(locale) == "" {
+			continue
+		}
+
+		tag, err := language.Parse(strings.TrimSpace(locale))
+		if err != nil {
+			return nil, fmt.Errorf("invalid supported locale %q", locale)
+		}
+		if _, found := locales.Canonical(tag.String()); found {
+			continue
+		}
+
+		locales.Supported = append(locales.Supported, tag.String())
+		tags = append(tags, tag)
+	}
+
+	locales.matcher = language.NewMatcher(tags)
+	return locales, nil
+}
+
+// Negotiate returns the supported locale that best matches an Accept-Language header, the default locale when none
+// does or the header is missing or malformed
+func (l *Locales) Negotiate(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return l.Default
+	}
+
+	preferred, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(preferred) == 0 {
+		return l.Default
+	}
+
+	_, index, confidence := l.matcher.Match(preferred...)
+	if confidence == language.No {
+		return l.Default
+	}
+	return l.Supported[index]
+}
+
+// Canonical returns the supported locale a locale names regardless of its case, such as "pt-BR" for "pt-br"
+func (l *Locales) Canonical(locale string) (string, bool) {
+	for _, supported := range l.Supported {
+		if strings.EqualFold(supported, locale) {
+			return supported, true
+		}
+	}
+	return "", false
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the negotiated locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFrom returns the locale negotiated for the request of ctx, empty when none was, which stands for the default
+// locale
+func LocaleFrom(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
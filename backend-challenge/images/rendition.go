@@ -0,0 +1,167 @@
+// Package images generates the renditions of a product image, the thumbnail, mobile, tablet and desktop versions of
+// one source image. It only depends on the standard library.
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// Rendition names, one per field of the product image
+const (
+	RenditionThumbnail = "thumbnail"
+	RenditionMobile    = "mobile"
+	RenditionTablet    = "tablet"
+	RenditionDesktop   = "desktop"
+)
+
+// maxSourcePixels bounds the size of a decoded source image, a small file can describe a huge image
+const maxSourcePixels = 50_000_000
+
+// jpegQuality is the quality renditions without transparency are encoded with
+const jpegQuality = 85
+
+// ErrUnsupportedImage is returned for sources that are not a JPEG, PNG or GIF image
+var ErrUnsupportedImage = errors.New("image must be a JPEG, PNG or GIF")
+
+// Rendition is a version of the source image scaled to a width, the height follows the aspect ratio of the source
+type Rendition struct {
+	Name  string
+	Width int
+}
+
+// Encoded is a rendition ready to be stored
+type Encoded struct {
+	Name        string
+	Width       int
+	Height      int
+	ContentType string
+	// Extension is the file extension matching the content type, without the dot
+	Extension string
+	Content   []byte
+}
+
+// Generate decodes the source image and encodes every rendition of it. Renditions with transparency are encoded as
+// PNG, the others as JPEG. Sources are never scaled up, a rendition wider than the source keeps the source size.
+func Generate(source []byte, renditions []Rendition) ([]*Encoded, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(source))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxSourcePixels {
+		return nil, fmt.Errorf("image must not have more than %d pixels", maxSourcePixels)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(source))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	src := toRGBA(decoded)
+	opaque := src.Opaque()
+
+	encoded := make([]*Encoded, 0, len(renditions))
+	for _, rendition := range renditions {
+		if rendition.Width <= 0 {
+			return nil, fmt.Errorf("rendition %s must have a positive width", rendition.Name)
+		}
+
+		resized := Resize(src, rendition.Width)
+		result, err := encode(resized, opaque)
+		if err != nil {
+			return nil, err
+		}
+		result.Name = rendition.Name
+		encoded = append(encoded, result)
+	}
+	return encoded, nil
+}
+
+// Resize scales src down to width with an area-averaging filter, keeping the aspect ratio. Images that are not wider
+// than width are returned as they are.
+func Resize(src *image.RGBA, width int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if width >= srcWidth {
+		return src
+	}
+
+	height := max(1, (srcHeight*width+srcWidth/2)/srcWidth)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			// Premultiplied values average without darkening the edges of transparent areas
+			var r, g, b, a int
+			for sy := y0; sy < y1; sy++ {
+				row := src.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					pix := src.Pix[row : row+4 : row+4]
+					r += int(pix[0])
+					g += int(pix[1])
+					b += int(pix[2])
+					a += int(pix[3])
+					row += 4
+				}
+			}
+
+			count := (x1 - x0) * (y1 - y0)
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8((r + count/2) / count)
+			dst.Pix[offset+1] = uint8((g + count/2) / count)
+			dst.Pix[offset+2] = uint8((b + count/2) / count)
+			dst.Pix[offset+3] = uint8((a + count/2) / count)
+		}
+	}
+	return dst
+}
+
+// toRGBA converts any decoded image to RGBA, the format Resize works on
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// encode encodes an image as JPEG, or as PNG when the source has transparency
+func encode(img *image.RGBA, opaque bool) (*Encoded, error) {
+	var buffer bytes.Buffer
+	result := &Encoded{Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+
+	if opaque {
+		if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		result.ContentType = "image/jpeg"
+		result.Extension = "jpg"
+	} else {
+		if err := png.Encode(&buffer, img); err != nil {
+			return nil, err
+		}
+		result.ContentType = "image/png"
+		result.Extension = "png"
+	}
+
+	result.Content = buffer.Bytes()
+	return result, nil
+}
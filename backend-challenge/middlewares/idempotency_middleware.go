@@ -0,0 +1,138 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/constants"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/services/base"
+	"strconv"
+)
+
+// IdempotencyMiddleware makes the requests sent with an Idempotency-Key header safe to retry. The first request with a
+// key is processed and its response stored, retries of it get the stored response back with an Idempotent-Replayed
+// header, and the key used with a different request is rejected. Requests without the header are processed as usual.
+// Keys are scoped to the API key of the request, the same key sent with another API key is another request.
+func IdempotencyMiddleware(idempotencyService base.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(constants.IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if !validIdempotencyKey(key) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, responses.APIResponse{
+				Code:    http.StatusBadRequest,
+				Type:    "invalid_request",
+				Message: "invalid idempotency key",
+			})
+			return
+		}
+
+		// The body is held in memory to be fingerprinted, bodies larger than an order can be are not read
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxIdempotentRequestSize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, responses.APIResponse{
+					Code:    http.StatusRequestEntityTooLarge,
+					Type:    "invalid_request",
+					Message: "request body must not be larger than " + strconv.Itoa(constants.MaxIdempotentRequestSize) + " bytes",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, responses.APIResponse{
+				Code:    http.StatusBadRequest,
+				Type:    "invalid_request",
+				Message: "failed to read request body",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key = scopedIdempotencyKey(c.GetHeader(constants.APIKeyHeader), key)
+		fingerprint := requestFingerprint(c.Request, body)
+		record, errDetails := idempotencyService.Begin(c.Request.Context(), key, fingerprint)
+		if errDetails != nil {
+			c.AbortWithStatusJSON(errDetails.ErrorCode, responses.ToErrorResponse(errDetails))
+			return
+		}
+
+		if record != nil {
+			c.Header(constants.IdempotentReplayedHeader, "true")
+			c.Data(record.StatusCode, "application/json; charset=utf-8", record.Body)
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// The response is stored even when the client went away, its retry is the one that needs it
+		ctx := context.WithoutCancel(c.Request.Context())
+		if errDetails = idempotencyService.Complete(ctx, key, fingerprint, writer.Status(), writer.body.Bytes()); errDetails != nil {
+			configs.Logger.Error("failed to complete idempotent request", zap.String("key", key), zap.Any("error", errDetails))
+		}
+	}
+}
+
+// recordingWriter keeps a copy of the body written to the response
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// requestFingerprint hashes the method, path and body of a request, JSON bodies compacted so that retries formatting
+// the same body differently are still the same request
+func requestFingerprint(request *http.Request, body []byte) string {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, body); err == nil {
+		body = compacted.Bytes()
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// scopedIdempotencyKey prefixes a key with a hash of the API key it was sent with, so that clients with different API
+// keys cannot claim or replay the requests of each other. The API key itself is never stored.
+func scopedIdempotencyKey(apiKey string, key string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:8]) + ":" + key
+}
+
+// validIdempotencyKey tells whether a key is short enough and only made of visible ASCII characters
+func validIdempotencyKey(key string) bool {
+	if len(key) > constants.MaxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < '!' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/i18n"
+)
+
+// LocaleMiddleware negotiates the locale of the request from its Accept-Language header and adds it to the request
+// context, responses name the locale they are in
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := configs.Locales.Negotiate(c.GetHeader("Accept-Language"))
+		c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+
+		c.Header("Content-Language", locale)
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"oolio.com/kart/models"
+)
+
+// CategoryMigration moves a database created before categories existed from the category column of the products to
+// the categories table. Category strings are read as category paths, so "Pizza > Vegetarian" becomes a subcategory.
+type CategoryMigration struct {
+	pool *pgxpool.Pool
+}
+
+func NewCategoryMigration(pool *pgxpool.Pool) *CategoryMigration {
+	return &CategoryMigration{
+		pool: pool,
+	}
+}
+
+// queryRower is implemented by both the pool and a transaction
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (cm *CategoryMigration) Run(ctx context.Context) error {
+	needsMigration, err := cm.checkMigrationNeeded(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check migration status: %w", err)
+	}
+
+	if !needsMigration {
+		log.Println("Categories already migrated, skipping migration")
+		return nil
+	}
+
+	log.Println("Starting category migration")
+
+	tx, err := cm.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS categories (
+			id          BIGSERIAL PRIMARY KEY,
+			parent_id   BIGINT REFERENCES categories(id),
+			name        VARCHAR(100) NOT NULL,
+			path        TEXT NOT NULL UNIQUE,
+			sort_order  INTEGER NOT NULL DEFAULT 0,
+			active      BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			modified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CHECK (parent_id <> id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id)`,
+		`ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id BIGINT REFERENCES categories(id)`,
+	}
+	for _, statement := range statements {
+		if _, err = tx.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create categories table: %w", err)
+		}
+	}
+
+	converted, err := cm.convertCategories(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	statements = []string{
+		`ALTER TABLE products ALTER COLUMN category_id SET NOT NULL`,
+		`ALTER TABLE products DROP CONSTRAINT IF EXISTS products_name_category_key`,
+		// Names are unique among the products on the menu only, as in schemas.sql, so deleted products free their name
+		`ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_products_name_category_id ON products(name, category_id) WHERE deleted_at IS NULL`,
+		`DROP INDEX IF EXISTS idx_products_category`,
+		`CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id)`,
+		`ALTER TABLE products DROP COLUMN category`,
+	}
+	for _, statement := range statements {
+		if _, err = tx.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("failed to switch products to category ids: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit category migration: %w", err)
+	}
+
+	log.Printf("Category migration completed: %d categories converted", converted)
+	return nil
+}
+
+// checkMigrationNeeded reports whether the products still have a category column
+func (cm *CategoryMigration) checkMigrationNeeded(ctx context.Context) (bool, error) {
+	var exists bool
+	err := cm.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'products' AND column_name = 'category'
+		)`).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// convertCategories creates a category for every distinct category string and points the products at it
+func (cm *CategoryMigration) convertCategories(ctx context.Context, tx pgx.Tx) (int, error) {
+	rows, err := tx.Query(ctx, `SELECT DISTINCT category FROM products`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read product categories: %w", err)
+	}
+
+	categories, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("failed to read product categories: %w", err)
+	}
+
+	for _, category := range categories {
+		categoryId, err := ensureCategory(ctx, tx, category)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = tx.Exec(ctx, `UPDATE products SET category_id = $1 WHERE category = $2`, categoryId, category); err != nil {
+			return 0, fmt.Errorf("failed to set category of products in %q: %w", category, err)
+		}
+	}
+
+	return len(categories), nil
+}
+
+// ensureCategory returns the ID of the category at the end of a category path, creating the missing categories the
+// same way the API does
+func ensureCategory(ctx context.Context, q queryRower, path string) (int64, error) {
+	names, err := models.ParseCategoryPath(path)
+	if err != nil {
+		return 0, fmt.Errorf("invalid category %q: %w", path, err)
+	}
+
+	var categoryId *int64
+	for i, name := range names {
+		var id int64
+		err = q.QueryRow(ctx, `
+			INSERT INTO categories (parent_id, name, path)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (path) DO UPDATE SET path = EXCLUDED.path
+			RETURNING id`,
+			categoryId, name, models.CategoryPath(names[:i+1]),
+		).Scan(&id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create category %q: %w", models.CategoryPath(names[:i+1]), err)
+		}
+		categoryId = &id
+	}
+
+	return *categoryId, nil
+}
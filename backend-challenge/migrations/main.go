@@ -28,10 +28,11 @@ type Config struct {
 	CouponForceMigration bool
 
 	ProductDataFile string
+	SchemaFile      string
 }
 
 func main() {
-	migrationType := flag.String("type", "all", "Migration type: coupon, product, or all")
+	migrationType := flag.String("type", "all", "Migration type: category, schema, coupon, product, or all")
 	envFile := flag.String("env", ".env", "Path to .env file")
 	flag.Parse()
 
@@ -51,6 +52,17 @@ func main() {
 	log.Println("Connected to database successfully")
 
 	switch *migrationType {
+	case "category":
+		if err := runCategoryMigration(ctx, pool); err != nil {
+			log.Fatalf("Category migration failed: %v", err)
+		}
+	case "schema":
+		if err := runCategoryMigration(ctx, pool); err != nil {
+			log.Fatalf("Category migration failed: %v", err)
+		}
+		if err := runSchemaMigration(ctx, pool, config); err != nil {
+			log.Fatalf("Schema migration failed: %v", err)
+		}
 	case "coupon":
 		if err := runCouponMigration(ctx, pool, config); err != nil {
 			log.Fatalf("Coupon migration failed: %v", err)
@@ -61,6 +73,12 @@ func main() {
 		}
 	case "all":
 		log.Println("Running all migrations...")
+		if err := runCategoryMigration(ctx, pool); err != nil {
+			log.Fatalf("Category migration failed: %v", err)
+		}
+		if err := runSchemaMigration(ctx, pool, config); err != nil {
+			log.Fatalf("Schema migration failed: %v", err)
+		}
 		if err := runProductMigration(ctx, pool, config); err != nil {
 			log.Fatalf("Product migration failed: %v", err)
 		}
@@ -68,7 +86,7 @@ func main() {
 			log.Fatalf("Coupon migration failed: %v", err)
 		}
 	default:
-		log.Fatalf("Invalid migration type: %s. Use: coupon, product, or all", *migrationType)
+		log.Fatalf("Invalid migration type: %s. Use: category, schema, coupon, product, or all", *migrationType)
 	}
 
 	log.Println("All migrations completed successfully!")
@@ -90,6 +108,7 @@ func loadConfig() *Config {
 		CouponS3BaseURL:      getEnv("COUPON_S3_BASE_URL", "https://orderfoodonline-files.s3.ap-southeast-2.amazonaws.com"),
 		CouponForceMigration: forceMigration,
 		ProductDataFile:      getEnv("PRODUCT_DATA_FILE", "../data/product.json"),
+		SchemaFile:           getEnv("SCHEMA_FILE", "../schemas/schemas.sql"),
 	}
 }
 
@@ -175,6 +194,20 @@ func runCouponMigration(ctx context.Context, pool *pgxpool.Pool, config *Config)
 	return nil
 }
 
+func runCategoryMigration(ctx context.Context, pool *pgxpool.Pool) error {
+	log.Println("Starting category migration...")
+
+	migration := NewCategoryMigration(pool)
+	return migration.Run(ctx)
+}
+
+func runSchemaMigration(ctx context.Context, pool *pgxpool.Pool, config *Config) error {
+	log.Println("Starting schema migration...")
+
+	migration := NewSchemaMigration(pool, config.SchemaFile)
+	return migration.Run(ctx)
+}
+
 func runProductMigration(ctx context.Context, pool *pgxpool.Pool, config *Config) error {
 	log.Println("Starting product migration...")
 
@@ -6,20 +6,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"oolio.com/kart/catalog"
 )
 
-type Product struct {
-	ID       int64                  `json:"id"`
-	Name     string                 `json:"name"`
-	Category string                 `json:"category"`
-	Price    float64                `json:"price"`
-	Status   string                 `json:"status"`
-	Image    map[string]interface{} `json:"image"`
-	Meta     map[string]interface{} `json:"meta,omitempty"`
-}
-
 type ProductMigration struct {
 	pool         *pgxpool.Pool
 	dataFilePath string
@@ -45,9 +38,9 @@ func (pm *ProductMigration) Run(ctx context.Context) error {
 
 	log.Println("Starting product migration")
 
-	products, err := pm.loadProductsFromJSON()
+	products, err := pm.loadProducts()
 	if err != nil {
-		return fmt.Errorf("failed to load products from JSON: %w", err)
+		return fmt.Errorf("failed to load products: %w", err)
 	}
 
 	if err := pm.insertProducts(ctx, products); err != nil {
@@ -68,22 +61,35 @@ func (pm *ProductMigration) checkMigrationNeeded(ctx context.Context) (bool, err
 	return count == 0, nil
 }
 
-func (pm *ProductMigration) loadProductsFromJSON() ([]Product, error) {
-	data, err := os.ReadFile(pm.dataFilePath)
+// loadProducts reads the product file in the same JSON or CSV catalog formats as the admin import, picked by extension
+func (pm *ProductMigration) loadProducts() ([]catalog.Record, error) {
+	format, err := catalog.ParseFormat(strings.TrimPrefix(filepath.Ext(pm.dataFilePath), "."))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read product file: %w", err)
+		return nil, err
 	}
 
-	var products []Product
-	if err := json.Unmarshal(data, &products); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal products: %w", err)
+	file, err := os.Open(pm.dataFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read product file: %w", err)
 	}
+	defer file.Close()
 
-	return products, nil
+	return catalog.Decode(file, format)
 }
 
-func (pm *ProductMigration) insertProducts(ctx context.Context, products []Product) error {
+func (pm *ProductMigration) insertProducts(ctx context.Context, products []catalog.Record) error {
+	categories := make(map[string]int64)
 	for _, product := range products {
+		categoryId, found := categories[product.Category]
+		if !found {
+			var err error
+			categoryId, err = ensureCategory(ctx, pm.pool, product.Category)
+			if err != nil {
+				return fmt.Errorf("failed to create category for product %s: %w", product.Name, err)
+			}
+			categories[product.Category] = categoryId
+		}
+
 		imageJSON, err := json.Marshal(product.Image)
 		if err != nil {
 			return fmt.Errorf("failed to marshal image for product %s: %w", product.Name, err)
@@ -98,22 +104,28 @@ func (pm *ProductMigration) insertProducts(ctx context.Context, products []Produ
 		}
 
 		query := `
-			INSERT INTO products (name, category, price, status, image, meta)
-			VALUES ($1, $2, $3, $4, $5, $6)
-			ON CONFLICT (name, category) DO UPDATE
-			SET price = EXCLUDED.price,
+			INSERT INTO products (name, description, category_id, price, status, image, allergens, diets, meta)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (name, category_id) WHERE deleted_at IS NULL DO UPDATE
+			SET description = EXCLUDED.description,
+			    price = EXCLUDED.price,
 			    status = EXCLUDED.status,
 			    image = EXCLUDED.image,
+			    allergens = EXCLUDED.allergens,
+			    diets = EXCLUDED.diets,
 			    meta = EXCLUDED.meta,
 			    modified_at = NOW()
 		`
 
 		_, err = pm.pool.Exec(ctx, query,
 			product.Name,
-			product.Category,
+			product.Description,
+			categoryId,
 			product.Price,
-			"available",
+			productStatus(product.Status),
 			imageJSON,
+			stringList(product.Allergens),
+			stringList(product.Diets),
 			metaJSON,
 		)
 
@@ -124,3 +136,21 @@ func (pm *ProductMigration) insertProducts(ctx context.Context, products []Produ
 
 	return nil
 }
+
+// stringList returns an empty list for nil, the allergens and diets columns are NOT NULL and checked by the database
+func stringList(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// productStatus returns the status from the product file, products without a known status are loaded as available
+func productStatus(status string) string {
+	switch status {
+	case "available", "sold_out", "hidden", "discontinued":
+		return status
+	default:
+		return "available"
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaMigration upgrades an existing database to schemas.sql. The columns added to the tables of older databases are
+// added first, schemas.sql then creates whatever does not exist yet, and the constraints CREATE TABLE IF NOT EXISTS
+// cannot add to existing tables are added last. Every step can be run again.
+type SchemaMigration struct {
+	pool       *pgxpool.Pool
+	schemaFile string
+}
+
+func NewSchemaMigration(pool *pgxpool.Pool, schemaFile string) *SchemaMigration {
+	return &SchemaMigration{
+		pool:       pool,
+		schemaFile: schemaFile,
+	}
+}
+
+// columnUpgrades add the columns schemas.sql expects on the tables of databases created before them, and drop the
+// constraints it no longer has
+var columnUpgrades = []string{
+	`ALTER TABLE IF EXISTS products ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS products ADD COLUMN IF NOT EXISTS stock_quantity INTEGER`,
+	`ALTER TABLE IF EXISTS products ADD COLUMN IF NOT EXISTS price_version INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE IF EXISTS products ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}'`,
+	`ALTER TABLE IF EXISTS products ADD COLUMN IF NOT EXISTS diets TEXT[] NOT NULL DEFAULT '{}'`,
+	`ALTER TABLE IF EXISTS products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+	`ALTER TABLE IF EXISTS products DROP CONSTRAINT IF EXISTS products_name_category_id_key`,
+	`ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'placed'`,
+	`ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE IF EXISTS order_items ADD COLUMN IF NOT EXISTS price_version INTEGER`,
+	// Order items of the same product with different modifiers are separate lines
+	`ALTER TABLE IF EXISTS order_items DROP CONSTRAINT IF EXISTS order_items_order_id_product_id_key`,
+	// Idempotency keys are stored with the scope of their API key in front
+	`ALTER TABLE IF EXISTS idempotency_keys ALTER COLUMN key TYPE VARCHAR(272)`,
+}
+
+// dataUpgrades bring the rows of older databases in line with the constraints added below
+var dataUpgrades = []string{
+	`UPDATE products SET status = 'available'
+	 WHERE status NOT IN ('available', 'sold_out', 'hidden', 'discontinued')`,
+	`ALTER TABLE products ALTER COLUMN status SET DEFAULT 'available'`,
+	// Products created before the price history have their current price as their first version
+	`INSERT INTO product_price_history (product_id, version, price, effective_from)
+	 SELECT id, price_version, price, created_at FROM products
+	 ON CONFLICT (product_id, version) DO NOTHING`,
+	// Orders placed with a coupon before its uses were recorded used it, until they were cancelled
+	`INSERT INTO coupon_redemptions (order_id, coupon_code, redeemed_at, released_at)
+	 SELECT id, coupon_code, created_at, CASE WHEN status = 'cancelled' THEN modified_at END FROM orders
+	 WHERE coupon_code <> ''
+	 ON CONFLICT (order_id) DO NOTHING`,
+}
+
+// constraintUpgrade is a constraint of schemas.sql on a table that may have been created before it
+type constraintUpgrade struct {
+	table      string
+	name       string
+	definition string
+}
+
+// constraintUpgrades are named the way Postgres names the constraints of schemas.sql, so that they are found on the
+// databases created from it
+var constraintUpgrades = []constraintUpgrade{
+	{"products", "products_status_check", `CHECK (status IN ('available', 'sold_out', 'hidden', 'discontinued'))`},
+	{"products", "products_stock_quantity_check", `CHECK (stock_quantity >= 0)`},
+	{"products", "products_allergens_check", `CHECK (allergens <@ kart.known_allergens())`},
+	{"products", "products_diets_check",
+		`CHECK (diets <@ ARRAY['vegetarian', 'vegan', 'halal', 'kosher', 'gluten_free', 'dairy_free'])`},
+	{"orders", "orders_status_check",
+		`CHECK (status IN ('placed', 'accepted', 'preparing', 'ready', 'completed', 'cancelled'))`},
+	{"order_items", "order_items_unit_price_check", `CHECK (unit_price >= 0)`},
+	{"order_items", "order_items_product_id_price_version_fkey",
+		`FOREIGN KEY (product_id, price_version) REFERENCES product_price_history(product_id, version)`},
+}
+
+func (sm *SchemaMigration) Run(ctx context.Context) error {
+	schema, err := os.ReadFile(sm.schemaFile)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	log.Println("Starting schema migration")
+
+	tx, err := sm.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, statement := range columnUpgrades {
+		if _, err = tx.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("failed to add columns: %w", err)
+		}
+	}
+
+	// Statements without arguments run in the simple protocol, which accepts a whole script
+	if _, err = tx.Exec(ctx, string(schema)); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", sm.schemaFile, err)
+	}
+
+	for _, statement := range dataUpgrades {
+		if _, err = tx.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("failed to upgrade existing rows: %w", err)
+		}
+	}
+
+	added := 0
+	for _, constraint := range constraintUpgrades {
+		created, err := addConstraint(ctx, tx, constraint)
+		if err != nil {
+			return err
+		}
+		if created {
+			added++
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit schema migration: %w", err)
+	}
+
+	log.Printf("Schema migration completed: %d constraints added", added)
+	return nil
+}
+
+// addConstraint adds a constraint to a table unless the table already has it, and reports whether it was added
+func addConstraint(ctx context.Context, tx pgx.Tx, constraint constraintUpgrade) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_constraint
+			WHERE conrelid = to_regclass($1) AND conname = $2
+		)`, constraint.table, constraint.name).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check constraint %s: %w", constraint.name, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	statement := fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s %s`, constraint.table, constraint.name, constraint.definition)
+	if _, err = tx.Exec(ctx, statement); err != nil {
+		return false, fmt.Errorf("failed to add constraint %s: %w", constraint.name, err)
+	}
+	return true, nil
+}
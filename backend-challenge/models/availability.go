@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinutesPerDay is the end of a window closing at midnight
+const MinutesPerDay = 24 * 60
+
+// AvailabilityWindow is a period of the week during which a product, or every product of a category, can be ordered.
+// Times are in the store time zone, a window ending before it starts runs past midnight into the next day.
+type AvailabilityWindow struct {
+	Id int64 `json:"id"`
+	// Either ProductId or CategoryId is set, the owner of the window
+	ProductId  *int64 `json:"product_id,omitempty"`
+	CategoryId *int64 `json:"category_id,omitempty"`
+	// DaysOfWeek are ISO days of the week the window opens on, 1 is Monday and 7 is Sunday
+	DaysOfWeek []int `json:"days_of_week"`
+	// StartMinute and EndMinute are minutes since midnight, EndMinute is exclusive
+	StartMinute int `json:"start_minute"`
+	EndMinute   int `json:"end_minute"`
+}
+
+// Availability is the set of windows in effect for a product or defined by a category, no windows means always available
+type Availability struct {
+	Windows []*AvailabilityWindow
+	// Inherited is set when the windows of a product are the ones of its category
+	Inherited bool
+	// AvailableNow reports whether any of the windows is open at the time they were read
+	AvailableNow bool
+}
+
+// IsOpenAt reports whether the window is open at the wall clock time of local, kart.availability_window_open is the
+// database counterpart
+func (w *AvailabilityWindow) IsOpenAt(local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+
+	if w.StartMinute < w.EndMinute {
+		return w.opensOn(local.Weekday()) && minute >= w.StartMinute && minute < w.EndMinute
+	}
+
+	// Overnight windows belong to the day they open on
+	return (w.opensOn(local.Weekday()) && minute >= w.StartMinute) ||
+		(w.opensOn(local.AddDate(0, 0, -1).Weekday()) && minute < w.EndMinute)
+}
+
+// String describes the window, such as "Mon,Tue 07:00-11:00"
+func (w *AvailabilityWindow) String() string {
+	days := make([]string, len(w.DaysOfWeek))
+	for i, day := range w.DaysOfWeek {
+		days[i] = time.Weekday(day % 7).String()[:3]
+	}
+	return fmt.Sprintf("%s %s-%s", strings.Join(days, ","), FormatMinute(w.StartMinute), FormatMinute(w.EndMinute))
+}
+
+func (w *AvailabilityWindow) opensOn(weekday time.Weekday) bool {
+	isoDay := int(weekday)
+	if weekday == time.Sunday {
+		isoDay = 7
+	}
+
+	for _, day := range w.DaysOfWeek {
+		if day == isoDay {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAvailableAt reports whether any of the windows is open at the wall clock time of local, a product without windows
+// is always available
+func IsAvailableAt(windows []*AvailabilityWindow, local time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+
+	for _, window := range windows {
+		if window.IsOpenAt(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatMinute formats minutes since midnight as HH:MM, the end of the day is 24:00
+func FormatMinute(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// ParseMinute parses a HH:MM time of day into minutes since midnight, 24:00 is accepted as the end of the day
+func ParseMinute(value string) (int, error) {
+	if len(value) != 5 || value[2] != ':' || !isDigits(value[:2]) || !isDigits(value[3:]) {
+		return 0, fmt.Errorf("time %q must be formatted as HH:MM", value)
+	}
+
+	hour, _ := strconv.Atoi(value[:2])
+	minute, _ := strconv.Atoi(value[3:])
+	total := hour*60 + minute
+	if minute > 59 || total > MinutesPerDay {
+		return 0, fmt.Errorf("time %q must be between 00:00 and 24:00", value)
+	}
+	return total, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
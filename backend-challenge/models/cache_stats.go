@@ -0,0 +1,20 @@
+package models
+
+// CacheStats holds the counters of an in-memory cache
+type CacheStats struct {
+	Name string
+	// Active is false while the cache cannot learn about changes, all reads then go to the database
+	Active        bool
+	Entries       int
+	Hits          int64
+	Misses        int64
+	Invalidations int64
+}
+
+// HitRatio returns the share of reads served from memory
+func (s *CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
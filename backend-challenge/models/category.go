@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CategoryPathSeparator separates the names of nested categories in a category path, e.g. "Pizza > Vegetarian"
+const CategoryPathSeparator = " > "
+
+// Category groups products on the menu, categories can be nested under a parent category
+type Category struct {
+	Id       int64  `json:"id"`
+	ParentId *int64 `json:"parent_id,omitempty"`
+	Name     string `json:"name"`
+	// Path is the names of the category and its ancestors joined by CategoryPathSeparator, maintained by the repository
+	Path      string `json:"path"`
+	SortOrder int    `json:"sort_order"`
+	Active    bool   `json:"active"`
+	// ProductCount is the number of available products of the category and its subcategories
+	ProductCount int64       `json:"product_count"`
+	Children     []*Category `json:"children,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	ModifiedAt   time.Time   `json:"modified_at"`
+}
+
+// ParseCategoryPath splits a category path into the names of its categories from the top level down. Whitespace around
+// the names is ignored, so "Pizza>Vegetarian" and "Pizza > Vegetarian" are the same path.
+func ParseCategoryPath(path string) ([]string, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("category is required")
+	}
+
+	names := strings.Split(path, ">")
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		if err := ValidateCategoryName(names[i]); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// CategoryPath joins the names of nested categories into a category path
+func CategoryPath(names []string) string {
+	return strings.Join(names, CategoryPathSeparator)
+}
+
+// ValidateCategoryName checks the name of a single category, ">" is reserved to separate nested categories
+func ValidateCategoryName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("category name is required")
+	case len(name) > 100:
+		return fmt.Errorf("category name must not be longer than 100 characters")
+	case strings.Contains(name, ">"):
+		return fmt.Errorf("category name must not contain >")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Allergens a product can contain, the major food allergens of the EU labelling rules. The CHECK constraint of
+// kart.products.allergens lists the same values.
+const (
+	AllergenCelery      = "celery"
+	AllergenCrustaceans = "crustaceans"
+	AllergenDairy       = "dairy"
+	AllergenEggs        = "eggs"
+	AllergenFish        = "fish"
+	AllergenGluten      = "gluten"
+	AllergenLupin       = "lupin"
+	AllergenMolluscs    = "molluscs"
+	AllergenMustard     = "mustard"
+	AllergenNuts        = "nuts"
+	AllergenPeanuts     = "peanuts"
+	AllergenSesame      = "sesame"
+	AllergenSoy         = "soy"
+	AllergenSulphites   = "sulphites"
+)
+
+// Diets a product can be suitable for, the CHECK constraint of kart.products.diets lists the same values
+const (
+	DietVegetarian = "vegetarian"
+	DietVegan      = "vegan"
+	DietHalal      = "halal"
+	DietKosher     = "kosher"
+	DietGlutenFree = "gluten_free"
+	DietDairyFree  = "dairy_free"
+)
+
+var allergens = []string{
+	AllergenCelery, AllergenCrustaceans, AllergenDairy, AllergenEggs, AllergenFish, AllergenGluten, AllergenLupin,
+	AllergenMolluscs, AllergenMustard, AllergenNuts, AllergenPeanuts, AllergenSesame, AllergenSoy, AllergenSulphites,
+}
+
+// dietExclusions lists the allergens a product suitable for each diet cannot contain
+var dietExclusions = map[string][]string{
+	DietVegetarian: {AllergenFish, AllergenCrustaceans, AllergenMolluscs},
+	DietVegan:      {AllergenDairy, AllergenEggs, AllergenFish, AllergenCrustaceans, AllergenMolluscs},
+	DietHalal:      {},
+	DietKosher:     {},
+	DietGlutenFree: {AllergenGluten},
+	DietDairyFree:  {AllergenDairy},
+}
+
+// dietImplications lists the diets a product suitable for each diet is also suitable for
+var dietImplications = map[string][]string{
+	DietVegan: {DietVegetarian, DietDairyFree},
+}
+
+// IsValidAllergen reports whether allergen is one of the defined allergens
+func IsValidAllergen(allergen string) bool {
+	return slices.Contains(allergens, allergen)
+}
+
+// IsValidDiet reports whether diet is one of the defined diets
+func IsValidDiet(diet string) bool {
+	_, ok := dietExclusions[diet]
+	return ok
+}
+
+// NormalizeDietaryAttributes sorts and deduplicates the allergens and diets of a product, adds the diets implied by
+// the others and checks that no diet contradicts the allergens, such as a vegan product containing dairy
+func NormalizeDietaryAttributes(productAllergens []string, diets []string) ([]string, []string, error) {
+	normalizedAllergens := make([]string, 0, len(productAllergens))
+	for _, allergen := range productAllergens {
+		if !IsValidAllergen(allergen) {
+			return nil, nil, fmt.Errorf("unknown allergen %q", allergen)
+		}
+		normalizedAllergens = append(normalizedAllergens, allergen)
+	}
+
+	normalizedDiets := make([]string, 0, len(diets))
+	for _, diet := range diets {
+		if !IsValidDiet(diet) {
+			return nil, nil, fmt.Errorf("unknown diet %q", diet)
+		}
+		normalizedDiets = append(normalizedDiets, diet)
+		normalizedDiets = append(normalizedDiets, dietImplications[diet]...)
+	}
+
+	slices.Sort(normalizedAllergens)
+	slices.Sort(normalizedDiets)
+	normalizedAllergens = slices.Compact(normalizedAllergens)
+	normalizedDiets = slices.Compact(normalizedDiets)
+
+	// The diets asked for are checked before the implied ones so that the error names the former
+	for _, diet := range append(slices.Clone(diets), normalizedDiets...) {
+		for _, allergen := range dietExclusions[diet] {
+			if slices.Contains(normalizedAllergens, allergen) {
+				return nil, nil, fmt.Errorf("a %s product cannot contain %s", diet, allergen)
+			}
+		}
+	}
+
+	return normalizedAllergens, normalizedDiets, nil
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is a request made with an Idempotency-Key header, kept with the response it got so that retries of
+// the request get the same response instead of being processed again
+type IdempotencyRecord struct {
+	Key string `json:"key"`
+	// Fingerprint identifies the request the key was first used with, a request with another fingerprint is rejected
+	Fingerprint string `json:"fingerprint"`
+	// StatusCode and Body are the response of the request, unset while the request is still being processed
+	StatusCode int       `json:"status_code,omitempty"`
+	Body       []byte    `json:"body,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Completed tells whether the response of the request was stored
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
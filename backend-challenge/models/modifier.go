@@ -0,0 +1,38 @@
+package models
+
+import (
+	"oolio.com/kart/money"
+	"time"
+)
+
+// ModifierGroup is a set of options of a product, such as sizes or add-ons, of which between MinSelect and MaxSelect
+// must be chosen when ordering the product
+type ModifierGroup struct {
+	Id         int64       `json:"id"`
+	ProductId  int64       `json:"product_id"`
+	Name       string      `json:"name"`
+	MinSelect  int         `json:"min_select"`
+	MaxSelect  int         `json:"max_select"`
+	SortOrder  int         `json:"sort_order"`
+	Modifiers  []*Modifier `json:"modifiers"`
+	CreatedAt  time.Time   `json:"created_at"`
+	ModifiedAt time.Time   `json:"modified_at"`
+}
+
+// Modifier is a selectable option of a modifier group, its price delta is added to the unit price of the product
+type Modifier struct {
+	Id         int64       `json:"id"`
+	GroupId    int64       `json:"group_id"`
+	Name       string      `json:"name"`
+	PriceDelta money.Money `json:"price_delta"`
+	SortOrder  int         `json:"sort_order"`
+}
+
+// OrderItemModifier is a modifier chosen for an order line, names and price are copied so the line keeps describing
+// what was sold after the modifier changes
+type OrderItemModifier struct {
+	ModifierId int64       `json:"modifier_id"`
+	GroupName  string      `json:"group_name"`
+	Name       string      `json:"name"`
+	PriceDelta money.Money `json:"price_delta"`
+}
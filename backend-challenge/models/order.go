@@ -1,16 +1,63 @@
 package models
 
 import (
+	"fmt"
+	"oolio.com/kart/money"
+	"regexp"
+	"slices"
 	"time"
 )
 
+// Statuses of an order, the CHECK constraint of kart.orders.status lists the same values
+const (
+	OrderStatusPlaced    = "placed"
+	OrderStatusAccepted  = "accepted"
+	OrderStatusPreparing = "preparing"
+	OrderStatusReady     = "ready"
+	OrderStatusCompleted = "completed"
+	OrderStatusCancelled = "cancelled"
+)
+
+// orderStatusTransitions lists the statuses an order may move to from each status. Orders can be cancelled until
+// their preparation starts, completed and cancelled are final.
+var orderStatusTransitions = map[string][]string{
+	OrderStatusPlaced:    {OrderStatusAccepted, OrderStatusCancelled},
+	OrderStatusAccepted:  {OrderStatusPreparing, OrderStatusCancelled},
+	OrderStatusPreparing: {OrderStatusReady},
+	OrderStatusReady:     {OrderStatusCompleted},
+	OrderStatusCompleted: {},
+	OrderStatusCancelled: {},
+}
+
+// orderIdPattern matches the UUIDs orders are identified by
+var orderIdPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IsOrderId reports whether id has the form of an order ID
+func IsOrderId(id string) bool {
+	return orderIdPattern.MatchString(id)
+}
+
+// NextOrderStatuses returns the statuses an order may move to from status
+func NextOrderStatuses(status string) []string {
+	return orderStatusTransitions[status]
+}
+
+// CanTransitionOrderStatus reports whether an order may move from one status to another, staying in the same status
+// is not a transition
+func CanTransitionOrderStatus(from, to string) bool {
+	return slices.Contains(orderStatusTransitions[from], to)
+}
+
 // Order represents a customer order
 type Order struct {
-	Id         string         `json:"id"`
-	CouponCode string         `json:"coupon_code,omitempty"`
-	Subtotal   float64        `json:"subtotal,omitempty"`
-	Discount   float64        `json:"discount,omitempty"`
-	Total      float64        `json:"total,omitempty"`
+	Id         string `json:"id"`
+	CouponCode string `json:"coupon_code,omitempty"`
+	Status     string `json:"status"`
+	// Version is incremented by every change of status, a change only applies to the version it was made against
+	Version    int            `json:"version"`
+	Subtotal   money.Money    `json:"subtotal,omitzero"`
+	Discount   money.Money    `json:"discount,omitzero"`
+	Total      money.Money    `json:"total,omitzero"`
 	Meta       map[string]any `json:"meta,omitempty"`
 	CreatedAt  time.Time      `json:"created_at"`
 	ModifiedAt time.Time      `json:"modified_at"`
@@ -18,13 +65,55 @@ type Order struct {
 
 // OrderItem represents a line item in an order
 type OrderItem struct {
-	Id         int64          `json:"id,omitempty"`
-	OrderId    string         `json:"order_id,omitempty"`
-	ProductId  int64          `json:"product_id"`
-	Quantity   int            `json:"quantity"`
-	UnitPrice  float64        `json:"unit_price,omitempty"`
-	Price      float64        `json:"price,omitempty"`
-	Meta       map[string]any `json:"meta,omitempty"`
-	CreatedAt  time.Time      `json:"created_at,omitempty"`
-	ModifiedAt time.Time      `json:"modified_at"`
+	Id        int64       `json:"id,omitempty"`
+	OrderId   string      `json:"order_id,omitempty"`
+	ProductId int64       `json:"product_id"`
+	Quantity  int         `json:"quantity"`
+	UnitPrice money.Money `json:"unit_price,omitzero"`
+	// PriceVersion is the version of the product price the unit price was computed from
+	PriceVersion int `json:"price_version,omitempty"`
+	// Price is the line total, the unit price times the quantity
+	Price      money.Money         `json:"price,omitzero"`
+	Modifiers  []OrderItemModifier `json:"modifiers,omitempty"`
+	Meta       map[string]any      `json:"meta,omitempty"`
+	CreatedAt  time.Time           `json:"created_at,omitempty"`
+	ModifiedAt time.Time           `json:"modified_at"`
+}
+
+// OrderStatusChange records a transition of an order from one status to another
+type OrderStatusChange struct {
+	Id         int64     `json:"id"`
+	OrderId    string    `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PriceOrder prices the lines of an order at their unit price times their quantity, and the order at the sum of its
+// lines less its discount. Amounts are exact, so the subtotal is always the sum of the line totals. Orders whose lines
+// or subtotal do not fit in the NUMERIC(10, 2) columns they are stored in are rejected.
+func PriceOrder(order *Order, items []OrderItem) error {
+	lines := make([]money.Money, len(items))
+	for i := range items {
+		price, err := items[i].UnitPrice.Mul(items[i].Quantity)
+		if err != nil || price.Cmp(MaxPrice) > 0 {
+			return fmt.Errorf("line total of product %d must not be greater than %s", items[i].ProductId, MaxPrice)
+		}
+		items[i].Price = price
+		lines[i] = price
+	}
+
+	subtotal, err := money.Sum(lines...)
+	if err != nil || subtotal.Cmp(MaxPrice) > 0 {
+		return fmt.Errorf("order subtotal must not be greater than %s", MaxPrice)
+	}
+	total, err := subtotal.Sub(order.Discount)
+	if err != nil {
+		return fmt.Errorf("order total is out of range: %w", err)
+	}
+	order.Subtotal = subtotal
+	order.Total = total
+	return nil
 }
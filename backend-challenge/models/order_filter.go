@@ -0,0 +1,42 @@
+package models
+
+import (
+	"oolio.com/kart/money"
+	"time"
+)
+
+// Sort keys supported when listing orders
+const (
+	OrderSortCreatedAt = "created_at"
+	OrderSortTotal     = "total"
+)
+
+// OrderFilter holds the criteria used to list orders
+type OrderFilter struct {
+	// From and To bound the time orders were placed at, From is inclusive and To exclusive
+	From       *time.Time
+	To         *time.Time
+	CouponCode string
+	MinTotal   *money.Money
+	MaxTotal   *money.Money
+	// Statuses only lists the orders in any of the statuses
+	Statuses  []string
+	Sort      string
+	Direction string
+	Limit     int
+	Cursor    string
+	After     *OrderCursor
+}
+
+// OrderCursor is the keyset position after which the next page of orders starts
+type OrderCursor struct {
+	Value any
+	Id    string
+}
+
+// OrderPage is a single page of an order listing
+type OrderPage struct {
+	Orders     []*Order
+	TotalCount int64
+	NextCursor string
+}
@@ -1,18 +1,56 @@
 package models
 
-import "time"
+import (
+	"oolio.com/kart/money"
+	"time"
+)
+
+// Product statuses, only available products can be ordered
+const (
+	ProductStatusAvailable    = "available"
+	ProductStatusSoldOut      = "sold_out"
+	ProductStatusHidden       = "hidden"
+	ProductStatusDiscontinued = "discontinued"
+)
+
+// MaxPrice is the largest amount a NUMERIC(10, 2) column holds
+var MaxPrice = money.MustParse("99999999.99")
+
+// productStatusTransitions lists the statuses a product may move to from each status, discontinued is final
+var productStatusTransitions = map[string][]string{
+	ProductStatusAvailable:    {ProductStatusSoldOut, ProductStatusHidden, ProductStatusDiscontinued},
+	ProductStatusSoldOut:      {ProductStatusAvailable, ProductStatusHidden, ProductStatusDiscontinued},
+	ProductStatusHidden:       {ProductStatusAvailable, ProductStatusSoldOut, ProductStatusDiscontinued},
+	ProductStatusDiscontinued: {},
+}
 
 // Product represents a food item available for order
 type Product struct {
-	Id         int64          `json:"id"`
-	Name       string         `json:"name"`
-	Image      Image          `json:"image"`
-	Price      float64        `json:"price"`
-	Category   string         `json:"category"`
-	Status     string         `json:"status"`
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+	// Name and Description are in the default locale, unless the product was localized for a request
+	Description string      `json:"description"`
+	Image       Image       `json:"image"`
+	Price       money.Money `json:"price"`
+	// PriceVersion is incremented by the database every time the price changes
+	PriceVersion int   `json:"price_version"`
+	CategoryId   int64 `json:"category_id"`
+	// Category is the path of the category of the product, see CategoryPath
+	Category string `json:"category"`
+	// CategoryInactive is set when the category of the product or one of its ancestors is inactive, the product is
+	// then off the menu and cannot be ordered
+	CategoryInactive bool   `json:"category_inactive,omitempty"`
+	Status           string `json:"status"`
+	// StockQuantity is the number of items left in stock, nil means the stock is unlimited
+	StockQuantity *int `json:"stock_quantity,omitempty"`
+	// Allergens and Diets are sorted and never nil, see NormalizeDietaryAttributes
+	Allergens  []string       `json:"allergens"`
+	Diets      []string       `json:"diets"`
 	Meta       map[string]any `json:"meta,omitempty"`
 	CreatedAt  time.Time      `json:"created_at"`
 	ModifiedAt time.Time      `json:"modified_at"`
+	// DeletedAt is set once the product is removed from the menu, it can no longer be ordered but can be restored
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // Image represents the image metadata of a product
@@ -22,3 +60,33 @@ type Image struct {
 	Desktop   string `json:"desktop"`
 	Tablet    string `json:"tablet"`
 }
+
+// IsValidProductStatus reports whether status is one of the defined product statuses
+func IsValidProductStatus(status string) bool {
+	_, ok := productStatusTransitions[status]
+	return ok
+}
+
+// CanTransitionProductStatus reports whether a product may move from one status to another, staying in the same status is always allowed
+func CanTransitionProductStatus(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	for _, allowed := range productStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOrderable reports whether the product can currently be ordered
+func (p *Product) IsOrderable() bool {
+	return p.DeletedAt == nil && !p.CategoryInactive && p.Status == ProductStatusAvailable
+}
+
+// IsDeleted reports whether the product was removed from the menu
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
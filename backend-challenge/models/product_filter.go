@@ -0,0 +1,60 @@
+package models
+
+import (
+	"oolio.com/kart/money"
+	"time"
+)
+
+// Sort keys supported when listing products
+const (
+	ProductSortId        = "id"
+	ProductSortPrice     = "price"
+	ProductSortName      = "name"
+	ProductSortCreatedAt = "created_at"
+)
+
+// Sort directions supported when listing products
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ProductFilter holds the criteria used to list products
+type ProductFilter struct {
+	// Category is a category path, products of its subcategories match as well
+	Category   string
+	CategoryId *int64
+	Status     string
+	MinPrice   *money.Money
+	MaxPrice   *money.Money
+	Query      string
+	Sort       string
+	Direction  string
+	Limit      *int
+	Offset     *int
+	Cursor     string
+	After      *ProductCursor
+	// Deleted lists the deleted products instead of the products on the menu
+	Deleted bool
+	// AvailableNow only lists the products whose availability windows are open, it is resolved into AvailableAt
+	AvailableNow bool
+	// AvailableAt only lists the products whose availability windows are open at that time
+	AvailableAt *time.Time
+	// ExcludeAllergens only lists the products containing none of the allergens
+	ExcludeAllergens []string
+	// Diets only lists the products suitable for all of the diets
+	Diets []string
+}
+
+// ProductCursor is the keyset position after which the next page of products starts
+type ProductCursor struct {
+	Value any
+	Id    int64
+}
+
+// ProductPage is a single page of a product listing
+type ProductPage struct {
+	Products   []*Product
+	TotalCount int64
+	NextCursor string
+}
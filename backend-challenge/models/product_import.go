@@ -0,0 +1,78 @@
+package models
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Actions a catalog import takes for a product
+const (
+	ImportActionCreate    = "created"
+	ImportActionUpdate    = "updated"
+	ImportActionUnchanged = "unchanged"
+)
+
+// ProductKey identifies a product across environments, IDs differ between databases but names within a category do not
+type ProductKey struct {
+	Name     string
+	Category string
+}
+
+// ProductImport is the outcome of importing a catalog, or what the outcome would be for a dry run
+type ProductImport struct {
+	DryRun    bool
+	Changes   []*ProductChange
+	Errors    []*ProductImportError
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+// ProductChange is what an import does with one product of the catalog
+type ProductChange struct {
+	Row     int
+	Action  string
+	Product *Product
+	// Fields lists the fields an update changes
+	Fields []string
+}
+
+// ProductImportError is a product of the catalog that cannot be imported
+type ProductImportError struct {
+	Row      int
+	Name     string
+	Category string
+	Message  string
+}
+
+// Key returns the key identifying the product across environments
+func (p *Product) Key() ProductKey {
+	return ProductKey{Name: p.Name, Category: p.Category}
+}
+
+// ChangedFields lists the importable fields that differ between the product and another version of it
+func (p *Product) ChangedFields(other *Product) []string {
+	var fields []string
+	if p.Description != other.Description {
+		fields = append(fields, "description")
+	}
+	if p.Price != other.Price {
+		fields = append(fields, "price")
+	}
+	if p.Status != other.Status {
+		fields = append(fields, "status")
+	}
+	if p.Image != other.Image {
+		fields = append(fields, "image")
+	}
+	if !slices.Equal(p.Allergens, other.Allergens) {
+		fields = append(fields, "allergens")
+	}
+	if !slices.Equal(p.Diets, other.Diets) {
+		fields = append(fields, "diets")
+	}
+	if !(len(p.Meta) == 0 && len(other.Meta) == 0) && !reflect.DeepEqual(p.Meta, other.Meta) {
+		fields = append(fields, "meta")
+	}
+	return fields
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"oolio.com/kart/money"
+	"time"
+)
+
+// ProductPrice is an entry of the append-only price history of a product
+type ProductPrice struct {
+	ProductId     int64       `json:"product_id"`
+	Version       int         `json:"version"`
+	Price         money.Money `json:"price"`
+	EffectiveFrom time.Time   `json:"effective_from"`
+	// EffectiveTo is when the next version replaced this price, nil for the current price
+	EffectiveTo *time.Time `json:"effective_to,omitempty"`
+}
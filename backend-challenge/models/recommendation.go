@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Recommendation is a product frequently bought together with another one
+type Recommendation struct {
+	ProductId            int64 `json:"product_id"`
+	RecommendedProductId int64 `json:"recommended_product_id"`
+	// Score is the cosine similarity of the orders of both products, from 0 excluded to 1
+	Score float64 `json:"score"`
+	// OrderCount is the number of recent orders both products were bought together in
+	OrderCount int       `json:"order_count"`
+	ComputedAt time.Time `json:"computed_at"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"oolio.com/kart/money"
+	"time"
+)
+
+// Refund is an entry of the refund ledger of an order, the order itself keeps the total it was charged
+type Refund struct {
+	Id      int64  `json:"id"`
+	OrderId string `json:"order_id"`
+	// Amount is the sum of the amounts of the items
+	Amount money.Money  `json:"amount"`
+	Reason string       `json:"reason,omitempty"`
+	Actor  string       `json:"actor"`
+	Items  []RefundItem `json:"items"`
+	// Restock puts the refunded quantities back in the stock they were taken from
+	Restock   bool      `json:"restock"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RefundItem is the part of a refund given back for a line of the order
+type RefundItem struct {
+	OrderItemId int64       `json:"order_item_id"`
+	ProductId   int64       `json:"product_id"`
+	Quantity    int         `json:"quantity"`
+	Amount      money.Money `json:"amount"`
+}
+
+// RefundedAmount returns the amount given back by refunds, and fails when it does not fit in the range of Money
+func RefundedAmount(refunds []*Refund) (money.Money, error) {
+	amounts := make([]money.Money, len(refunds))
+	for i, refund := range refunds {
+		amounts[i] = refund.Amount
+	}
+	return money.Sum(amounts...)
+}
+
+// PlanRefund prices a refund of the requested quantities of the lines of an order, keyed by order item ID, or of
+// everything not refunded yet when quantities is nil. Lines are refunded at the share of the total they were charged,
+// the coupon discount spread over them and rounded half up to the cent, and the refund leaving nothing to refund gives
+// back the rest of the total so that rounding never makes the refunds of an order differ from its total.
+func PlanRefund(order *Order, items []OrderItem, previous []*Refund, quantities map[int64]int) (*Refund, error) {
+	refunded := make(map[int64]int)
+	for _, refund := range previous {
+		for _, item := range refund.Items {
+			refunded[item.OrderItemId] += item.Quantity
+		}
+	}
+
+	if quantities != nil {
+		for itemId := range quantities {
+			if !containsItem(items, itemId) {
+				return nil, fmt.Errorf("order item %d is not part of the order", itemId)
+			}
+		}
+	}
+
+	refund := &Refund{OrderId: order.Id, Items: []RefundItem{}}
+	remainingAfter := 0
+	for _, item := range items {
+		left := item.Quantity - refunded[item.Id]
+		quantity := left
+		if quantities != nil {
+			quantity = quantities[item.Id]
+		}
+		if quantity > left {
+			return nil, fmt.Errorf("only %d of order item %d left to refund", left, item.Id)
+		}
+		remainingAfter += left - quantity
+		if quantity == 0 {
+			continue
+		}
+
+		// The line total times the share of the line refunded times the share of the subtotal charged
+		var amount money.Money
+		if order.Subtotal.Sign() > 0 {
+			amount = item.Price.Scale(int64(quantity)*order.Total.Cents(), int64(item.Quantity)*order.Subtotal.Cents(), money.HalfUp)
+		}
+		refund.Items = append(refund.Items, RefundItem{
+			OrderItemId: item.Id,
+			ProductId:   item.ProductId,
+			Quantity:    quantity,
+			Amount:      amount,
+		})
+		var err error
+		if refund.Amount, err = refund.Amount.Add(amount); err != nil {
+			return nil, fmt.Errorf("refund amount is out of range: %w", err)
+		}
+	}
+
+	if len(refund.Items) == 0 {
+		return nil, fmt.Errorf("nothing left to refund")
+	}
+
+	// The last refund absorbs the cents rounding left over, on its last line
+	refundedAmount, err := RefundedAmount(previous)
+	if err != nil {
+		return nil, fmt.Errorf("refunded amount is out of range: %w", err)
+	}
+	left, err := order.Total.Sub(refundedAmount)
+	if err != nil {
+		return nil, fmt.Errorf("amount left to refund is out of range: %w", err)
+	}
+	if remainingAfter == 0 || refund.Amount.Cmp(left) > 0 {
+		last := &refund.Items[len(refund.Items)-1]
+		rounding, err := left.Sub(refund.Amount)
+		if err == nil {
+			last.Amount, err = last.Amount.Add(rounding)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("refund amount is out of range: %w", err)
+		}
+		refund.Amount = left
+	}
+
+	return refund, nil
+}
+
+func containsItem(items []OrderItem, itemId int64) bool {
+	for _, item := range items {
+		if item.Id == itemId {
+			return true
+		}
+	}
+	return false
+}
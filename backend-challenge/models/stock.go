@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Reasons recorded for stock adjustments that are not made by hand
+const (
+	StockReasonOrder          = "order"
+	StockReasonOrderCancelled = "order_cancelled"
+	StockReasonRefund         = "refund"
+)
+
+// StockAdjustment is an audited change of the stock of a product
+type StockAdjustment struct {
+	Id        int64  `json:"id"`
+	ProductId int64  `json:"product_id"`
+	OrderId   string `json:"order_id,omitempty"`
+	// Delta is the change applied to a limited stock, nil when the stock was set or made unlimited
+	Delta *int `json:"delta,omitempty"`
+	// QuantityAfter is the stock after the adjustment, nil means unlimited
+	QuantityAfter *int      `json:"quantity_after,omitempty"`
+	Reason        string    `json:"reason"`
+	Actor         string    `json:"actor,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ProductTranslation is the name and description of a product in a locale other than the default one
+type ProductTranslation struct {
+	ProductId   int64     `json:"product_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ModifiedAt  time.Time `json:"modified_at"`
+}
+
+// CategoryTranslation is the name of a category in a locale other than the default one
+type CategoryTranslation struct {
+	CategoryId int64 `json:"category_id"`
+	// Path is the path of the category in the default locale, products refer to their category by it
+	Path       string    `json:"path"`
+	Locale     string    `json:"locale"`
+	Name       string    `json:"name"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
@@ -0,0 +1,319 @@
+// Package money represents amounts of money exactly, in the cents the NUMERIC(10, 2) columns of the database keep them
+// in. Every amount is in the currency of the store, configured once and returned along with the amounts by the API.
+// Adding, subtracting and multiplying by a quantity are exact, and the operations that can end between two cents, such
+// as spreading a discount over the lines of an order, round with a mode chosen by the caller.
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Currency is the ISO 4217 code of a currency
+type Currency string
+
+// USD is the currency of the store unless configured otherwise
+const USD Currency = "USD"
+
+// nonCentCurrencies are the currencies whose minor unit is not the hundredth, amounts kept in cents cannot hold them
+var nonCentCurrencies = map[Currency]bool{
+	"BHD": true, "BIF": true, "CLP": true, "DJF": true, "GNF": true, "IQD": true, "ISK": true, "JOD": true,
+	"JPY": true, "KMF": true, "KRW": true, "KWD": true, "LYD": true, "OMR": true, "PYG": true, "RWF": true,
+	"TND": true, "UGX": true, "UYI": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// ParseCurrency validates an ISO 4217 currency code, such as "usd", and returns it upper cased. Only the currencies
+// counted in cents are supported.
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if len(currency) != 3 || strings.Trim(string(currency), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("invalid currency %q", code)
+	}
+	if nonCentCurrencies[currency] {
+		return "", fmt.Errorf("currency %s is not counted in cents", currency)
+	}
+	return currency, nil
+}
+
+// RoundingMode tells how an amount ending between two cents is rounded to one of them
+type RoundingMode int
+
+const (
+	// HalfUp rounds to the nearest cent and halves away from zero, the way NUMERIC columns round
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to the nearest cent and halves to the even cent
+	HalfEven
+	// Down drops the fraction of a cent, rounding toward zero
+	Down
+)
+
+// Money is an exact amount of money, counted in cents. The zero value is nothing.
+type Money struct {
+	cents int64
+}
+
+// FromCents returns an amount of cents
+func FromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// Parse reads a decimal amount, such as "12.99" or "-0.5". Amounts with fractions of a cent are rejected rather than
+// rounded.
+func Parse(value string) (Money, error) {
+	cents, err := parseCents(value)
+	if err != nil {
+		return Money{}, err
+	}
+	if !cents.IsInt() {
+		return Money{}, fmt.Errorf("amount %q has fractions of a cent", value)
+	}
+	return fromCents(value, cents.Num())
+}
+
+// ParseRound reads a decimal amount like Parse, and rounds fractions of a cent with the rounding mode instead of
+// rejecting them
+func ParseRound(value string, mode RoundingMode) (Money, error) {
+	cents, err := parseCents(value)
+	if err != nil {
+		return Money{}, err
+	}
+	return fromCents(value, divide(cents.Num(), cents.Denom(), mode))
+}
+
+// parseCents reads a decimal amount as an exact number of cents, possibly with fractions of a cent
+func parseCents(value string) (*big.Rat, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, "/xXpP_") {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount.Mul(amount, big.NewRat(100, 1)), nil
+}
+
+// fromCents returns a whole number of cents, value is the parsed text for the error
+func fromCents(value string, cents *big.Int) (Money, error) {
+	if !cents.IsInt64() {
+		return Money{}, fmt.Errorf("amount %q is out of range", value)
+	}
+	return Money{cents: cents.Int64()}, nil
+}
+
+// MustParse reads a decimal amount like Parse and panics when it is invalid, for amounts written in the code
+func MustParse(value string) Money {
+	amount, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// Sum adds amounts, and fails when the sum does not fit in the range of Money
+func Sum(amounts ...Money) (Money, error) {
+	total := new(big.Int)
+	for _, amount := range amounts {
+		total.Add(total, big.NewInt(amount.cents))
+	}
+	if !total.IsInt64() {
+		return Money{}, errors.New("sum of amounts is out of range")
+	}
+	return Money{cents: total.Int64()}, nil
+}
+
+// Cents returns the amount in cents
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// IsZero tells whether the amount is nothing
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+// Sign returns -1, 0 or 1 when the amount is negative, nothing or positive
+func (m Money) Sign() int {
+	switch {
+	case m.cents < 0:
+		return -1
+	case m.cents > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Cmp returns -1, 0 or 1 when the amount is less than, equal to or greater than another amount
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.cents < other.cents:
+		return -1
+	case m.cents > other.cents:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns the sum of the amount and another amount, and fails when the sum does not fit in the range of Money
+func (m Money) Add(other Money) (Money, error) {
+	return Sum(m, other)
+}
+
+// Sub returns the amount less another amount, and fails when the difference does not fit in the range of Money
+func (m Money) Sub(other Money) (Money, error) {
+	difference := new(big.Int).Sub(big.NewInt(m.cents), big.NewInt(other.cents))
+	if !difference.IsInt64() {
+		return Money{}, errors.New("difference of amounts is out of range")
+	}
+	return Money{cents: difference.Int64()}, nil
+}
+
+// Neg returns the opposite of the amount
+func (m Money) Neg() Money {
+	return Money{cents: -m.cents}
+}
+
+// Mul returns the amount times a quantity, and fails when the product does not fit in the range of Money
+func (m Money) Mul(quantity int) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.cents), big.NewInt(int64(quantity)))
+	if !product.IsInt64() {
+		return Money{}, fmt.Errorf("%s times %d is out of range", m, quantity)
+	}
+	return Money{cents: product.Int64()}, nil
+}
+
+// Scale returns the amount times numerator over denominator, rounded to the cent with the rounding mode. It panics
+// when the denominator is zero or the result does not fit in the range of Money, scale by ratios up to one.
+func (m Money) Scale(numerator, denominator int64, mode RoundingMode) Money {
+	if denominator == 0 {
+		panic("money: scale by a zero denominator")
+	}
+
+	n := new(big.Int).Mul(big.NewInt(m.cents), big.NewInt(numerator))
+	d := big.NewInt(denominator)
+	if d.Sign() < 0 {
+		n.Neg(n)
+		d.Neg(d)
+	}
+	quotient := divide(n, d, mode)
+	if !quotient.IsInt64() {
+		panic("money: scaled amount is out of range")
+	}
+	return Money{cents: quotient.Int64()}
+}
+
+// divide returns n over a positive d rounded to an integer with the rounding mode
+func divide(n, d *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(n, d, new(big.Int))
+	if remainder.Sign() != 0 && mode != Down {
+		// Compare twice the remainder to the denominator to tell which integer is nearer
+		half := new(big.Int).Abs(remainder)
+		half.Lsh(half, 1)
+		cmp := half.Cmp(d)
+		if cmp > 0 || (cmp == 0 && (mode == HalfUp || quotient.Bit(0) == 1)) {
+			quotient.Add(quotient, big.NewInt(int64(n.Sign())))
+		}
+	}
+	return quotient
+}
+
+// String returns the amount as the shortest decimal, such as "12.99", "15.5" or "31"
+func (m Money) String() string {
+	sign := ""
+	cents := m.cents
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	units, fraction := cents/100, cents%100
+	switch {
+	case fraction == 0:
+		return sign + strconv.FormatInt(units, 10)
+	case fraction%10 == 0:
+		return fmt.Sprintf("%s%d.%d", sign, units, fraction/10)
+	default:
+		return fmt.Sprintf("%s%d.%02d", sign, units, fraction)
+	}
+}
+
+// InexactFloat64 returns the nearest float64 of the amount, for validations and logs that do not compute with it
+func (m Money) InexactFloat64() float64 {
+	return float64(m.cents) / 100
+}
+
+// MarshalJSON encodes the amount as a JSON number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes an amount from a JSON number or string, null leaves the amount unchanged
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	value := string(data)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	amount, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
+
+// UnmarshalParam decodes an amount from a query or form parameter
+func (m *Money) UnmarshalParam(param string) error {
+	amount, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
+
+// ScanNumeric reads an amount from a NUMERIC column
+func (m *Money) ScanNumeric(value pgtype.Numeric) error {
+	if !value.Valid {
+		return errors.New("cannot scan NULL into money")
+	}
+	if value.NaN || value.InfinityModifier != pgtype.Finite {
+		return errors.New("cannot scan a NaN or infinite numeric into money")
+	}
+
+	cents := new(big.Int).Set(value.Int)
+	exp := int64(value.Exp) + 2
+	if exp >= 0 {
+		cents.Mul(cents, new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	} else {
+		var remainder big.Int
+		cents.QuoRem(cents, new(big.Int).Exp(big.NewInt(10), big.NewInt(-exp), nil), &remainder)
+		if remainder.Sign() != 0 {
+			return errors.New("cannot scan a numeric with fractions of a cent into money")
+		}
+	}
+
+	if !cents.IsInt64() {
+		return errors.New("numeric is out of the range of money")
+	}
+	*m = Money{cents: cents.Int64()}
+	return nil
+}
+
+// NumericValue writes the amount to a NUMERIC column or parameter
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(m.cents), Exp: -2, Valid: true}, nil
+}
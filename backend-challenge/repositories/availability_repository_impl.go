@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type AvailabilityRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewAvailabilityRepositoryImpl creates a new instance of AvailabilityRepositoryImpl
+func NewAvailabilityRepositoryImpl(pool *pgxpool.Pool) *AvailabilityRepositoryImpl {
+	return &AvailabilityRepositoryImpl{pool: pool}
+}
+
+// ReplaceProductWindows Replaces the availability windows of a product that is not deleted
+func (a *AvailabilityRepositoryImpl) ReplaceProductWindows(ctx context.Context, productId int64, windows []*models.AvailabilityWindow) *errors.ErrorDetails {
+	return a.replaceWindows(ctx,
+		"UPDATE products SET modified_at = NOW() WHERE id = $1 AND deleted_at IS NULL",
+		"DELETE FROM availability_windows WHERE product_id = $1",
+		`INSERT INTO availability_windows (product_id, days_of_week, start_minute, end_minute)
+         VALUES ($1, $2, $3, $4)
+         RETURNING id`,
+		"product not found", productId, windows)
+}
+
+// ReplaceCategoryWindows Replaces the availability windows of a category
+func (a *AvailabilityRepositoryImpl) ReplaceCategoryWindows(ctx context.Context, categoryId int64, windows []*models.AvailabilityWindow) *errors.ErrorDetails {
+	return a.replaceWindows(ctx,
+		"UPDATE categories SET modified_at = NOW() WHERE id = $1",
+		"DELETE FROM availability_windows WHERE category_id = $1",
+		`INSERT INTO availability_windows (category_id, days_of_week, start_minute, end_minute)
+         VALUES ($1, $2, $3, $4)
+         RETURNING id`,
+		"category not found", categoryId, windows)
+}
+
+// replaceWindows replaces the windows of an owner in one transaction. Touching the owner locks it against concurrent
+// replacements, and its modified_at change invalidates the conditional requests and caches of the catalog.
+func (a *AvailabilityRepositoryImpl) replaceWindows(ctx context.Context, touchQuery, deleteQuery, insertQuery, notFound string, ownerId int64, windows []*models.AvailabilityWindow) *errors.ErrorDetails {
+	tx, err := a.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	tag, err := tx.Exec(ctx, touchQuery, ownerId)
+	if err != nil {
+		configs.Logger.Error("failed to update availability windows", zap.Error(err))
+		return exceptions.GenericException("failed to update availability windows", http.StatusInternalServerError)
+	}
+	if tag.RowsAffected() == 0 {
+		configs.Logger.Error(notFound, zap.Int64("id", ownerId))
+		return exceptions.GenericException(notFound, http.StatusNotFound)
+	}
+
+	if _, err = tx.Exec(ctx, deleteQuery, ownerId); err != nil {
+		configs.Logger.Error("failed to delete availability windows", zap.Error(err))
+		return exceptions.GenericException("failed to update availability windows", http.StatusInternalServerError)
+	}
+
+	for _, window := range windows {
+		err = tx.QueryRow(ctx, insertQuery, ownerId, window.DaysOfWeek, window.StartMinute, window.EndMinute).Scan(&window.Id)
+		if err != nil {
+			configs.Logger.Error("failed to save availability window", zap.Error(err))
+			return exceptions.GenericException("failed to update availability windows", http.StatusInternalServerError)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// GetCategoryWindows Retrieves the availability windows defined on a category from the database
+func (a *AvailabilityRepositoryImpl) GetCategoryWindows(ctx context.Context, categoryId int64) ([]*models.AvailabilityWindow, *errors.ErrorDetails) {
+	query := `SELECT id, product_id, category_id, days_of_week, start_minute, end_minute
+              FROM availability_windows
+              WHERE category_id = $1
+              ORDER BY start_minute, id`
+
+	rows, err := a.pool.Query(ctx, query, categoryId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch availability windows", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch availability windows", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	windows := make([]*models.AvailabilityWindow, 0)
+	for rows.Next() {
+		window, err := scanAvailabilityWindow(rows)
+		if err != nil {
+			configs.Logger.Error("failed to scan availability window", zap.Error(err))
+			return nil, exceptions.GenericException("failed to fetch availability windows", http.StatusInternalServerError)
+		}
+		windows = append(windows, window)
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("failed to fetch availability windows", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch availability windows", http.StatusInternalServerError)
+	}
+
+	return windows, nil
+}
+
+// GetWindowsByProductIds Retrieves the availability windows in effect for each of the products from the database
+func (a *AvailabilityRepositoryImpl) GetWindowsByProductIds(ctx context.Context, productIds []int64) (map[int64][]*models.AvailabilityWindow, *errors.ErrorDetails) {
+	query := `SELECT product_id, id, owner_product_id, owner_category_id, days_of_week, start_minute, end_minute
+              FROM product_availability_windows
+              WHERE product_id = ANY($1)
+              ORDER BY product_id, start_minute, id`
+
+	rows, err := a.pool.Query(ctx, query, productIds)
+	if err != nil {
+		configs.Logger.Error("failed to fetch availability windows", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch availability windows", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	windows := make(map[int64][]*models.AvailabilityWindow)
+	for rows.Next() {
+		var productId int64
+		window := &models.AvailabilityWindow{}
+		err = rows.Scan(&productId, &window.Id, &window.ProductId, &window.CategoryId, &window.DaysOfWeek, &window.StartMinute, &window.EndMinute)
+		if err != nil {
+			configs.Logger.Error("failed to scan availability window", zap.Error(err))
+			return nil, exceptions.GenericException("failed to fetch availability windows", http.StatusInternalServerError)
+		}
+		windows[productId] = append(windows[productId], window)
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("failed to fetch availability windows", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch availability windows", http.StatusInternalServerError)
+	}
+
+	return windows, nil
+}
+
+func scanAvailabilityWindow(row pgx.Row) (*models.AvailabilityWindow, error) {
+	window := &models.AvailabilityWindow{}
+	err := row.Scan(&window.Id, &window.ProductId, &window.CategoryId, &window.DaysOfWeek, &window.StartMinute, &window.EndMinute)
+	if err != nil {
+		return nil, err
+	}
+	return window, nil
+}
@@ -0,0 +1,24 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type AvailabilityRepository interface {
+	// ReplaceProductWindows replaces the availability windows of a product, without windows of its own the product
+	// follows the windows of its category
+	ReplaceProductWindows(ctx context.Context, productId int64, windows []*models.AvailabilityWindow) *errors.ErrorDetails
+
+	// ReplaceCategoryWindows replaces the availability windows of a category, they apply to the products of the category
+	// and its subcategories that have no windows of their own or of a nearer category
+	ReplaceCategoryWindows(ctx context.Context, categoryId int64, windows []*models.AvailabilityWindow) *errors.ErrorDetails
+
+	// GetCategoryWindows retrieves the availability windows defined on a category
+	GetCategoryWindows(ctx context.Context, categoryId int64) ([]*models.AvailabilityWindow, *errors.ErrorDetails)
+
+	// GetWindowsByProductIds retrieves the availability windows in effect for each of the products, products without
+	// windows are missing from the result
+	GetWindowsByProductIds(ctx context.Context, productIds []int64) (map[int64][]*models.AvailabilityWindow, *errors.ErrorDetails)
+}
@@ -0,0 +1,18 @@
+package base
+
+import "oolio.com/kart/models"
+
+// Cache is an in-memory cache in front of a repository, it only serves reads while it is active
+type Cache interface {
+	// Activate starts serving reads from memory, entries cached before are dropped
+	Activate()
+
+	// Deactivate sends every read to the database until the cache is activated again
+	Deactivate()
+
+	// Invalidate drops every cached entry
+	Invalidate()
+
+	// Stats returns the counters of the cache
+	Stats() *models.CacheStats
+}
@@ -0,0 +1,19 @@
+package base
+
+import (
+	"context"
+
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type CatalogRepository interface {
+	// GetByKeys retrieves the products matching the given name and category pairs
+	GetByKeys(ctx context.Context, keys []models.ProductKey) ([]*models.Product, *errors.ErrorDetails)
+
+	// UpsertProducts creates or updates the products by name and category in a single transaction
+	UpsertProducts(ctx context.Context, products []*models.Product) *errors.ErrorDetails
+
+	// ExportProducts calls fn for every product ordered by ID, stopping at the first error fn returns
+	ExportProducts(ctx context.Context, fn func(product *models.Product) error) *errors.ErrorDetails
+}
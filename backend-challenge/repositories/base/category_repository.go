@@ -0,0 +1,28 @@
+package base
+
+import (
+	"context"
+
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type CategoryRepository interface {
+	// Save saves a new category to the database
+	Save(ctx context.Context, category *models.Category) *errors.ErrorDetails
+
+	// Update updates a category in the database together with the path of its subcategories
+	Update(ctx context.Context, category *models.Category) *errors.ErrorDetails
+
+	// Delete deletes a category without subcategories or products from the database
+	Delete(ctx context.Context, id int64) *errors.ErrorDetails
+
+	// GetById retrieves a category by its ID from the database
+	GetById(ctx context.Context, id int64) (*models.Category, *errors.ErrorDetails)
+
+	// ListCategories retrieves every category with the number of available products it directly contains
+	ListCategories(ctx context.Context) ([]*models.Category, *errors.ErrorDetails)
+
+	// EnsurePath retrieves the category at the end of a path, creating the missing categories of the path
+	EnsurePath(ctx context.Context, names []string) (*models.Category, *errors.ErrorDetails)
+}
@@ -0,0 +1,24 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	// ClaimKey reserves an idempotency key for a request until ttl has passed. It returns nil when the request claimed
+	// the key, or the record of the request holding it. Keys of requests still being processed after inFlightTimeout
+	// can be claimed again.
+	ClaimKey(ctx context.Context, key string, fingerprint string, ttl time.Duration, inFlightTimeout time.Duration) (*models.IdempotencyRecord, *errors.ErrorDetails)
+
+	// SaveResponse stores the response of the request that claimed a key
+	SaveResponse(ctx context.Context, key string, fingerprint string, statusCode int, body []byte) *errors.ErrorDetails
+
+	// ReleaseKey gives up the claim of a request on a key, so that a retry of the request is processed again
+	ReleaseKey(ctx context.Context, key string, fingerprint string) *errors.ErrorDetails
+
+	// DeleteExpiredKeys deletes the keys past their expiry and returns how many were deleted
+	DeleteExpiredKeys(ctx context.Context) (int64, *errors.ErrorDetails)
+}
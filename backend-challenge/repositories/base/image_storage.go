@@ -0,0 +1,17 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/exceptions/errors"
+)
+
+type ImageStorage interface {
+	// Save stores the content of an image under key and returns the URL it is served from
+	Save(ctx context.Context, key string, contentType string, content []byte) (string, *errors.ErrorDetails)
+
+	// Delete removes the image stored under key, deleting an image that does not exist is not an error
+	Delete(ctx context.Context, key string) *errors.ErrorDetails
+
+	// KeyOf returns the key of the image served from url, false when the image is not kept by the storage
+	KeyOf(url string) (string, bool)
+}
@@ -0,0 +1,22 @@
+package base
+
+import (
+	"context"
+
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type ModifierRepository interface {
+	// SaveGroup saves a new modifier group with its modifiers
+	SaveGroup(ctx context.Context, group *models.ModifierGroup) *errors.ErrorDetails
+
+	// UpdateGroup replaces a modifier group and its modifiers, modifiers keep their ID as long as their name is unchanged
+	UpdateGroup(ctx context.Context, group *models.ModifierGroup) *errors.ErrorDetails
+
+	// DeleteGroup deletes a modifier group of a product with its modifiers
+	DeleteGroup(ctx context.Context, productId int64, groupId int64) *errors.ErrorDetails
+
+	// GetGroupsByProductIds retrieves the modifier groups with their modifiers of the given products, keyed by product ID
+	GetGroupsByProductIds(ctx context.Context, productIds []int64) (map[int64][]*models.ModifierGroup, *errors.ErrorDetails)
+}
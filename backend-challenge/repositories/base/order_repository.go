@@ -10,4 +10,17 @@ import (
 type OrderRepository interface {
 	// CreateOrder creates a new order in the database
 	CreateOrder(ctx context.Context, order *models.Order, items []models.OrderItem) *errors.ErrorDetails
+
+	// GetOrderById retrieves an order with its items and their modifiers by its ID from the database
+	GetOrderById(ctx context.Context, id string) (*models.Order, []models.OrderItem, *errors.ErrorDetails)
+
+	// ListOrders retrieves the orders matching the filter and the total number of matches from the database
+	ListOrders(ctx context.Context, filter *models.OrderFilter) ([]*models.Order, int64, *errors.ErrorDetails)
+
+	// TransitionOrderStatus moves an order to a new status and records the change, as long as the order is still at
+	// the version and in the status it was read with
+	TransitionOrderStatus(ctx context.Context, order *models.Order, change *models.OrderStatusChange) *errors.ErrorDetails
+
+	// GetStatusHistory retrieves the status changes of an order from the database, oldest first
+	GetStatusHistory(ctx context.Context, orderId string) ([]*models.OrderStatusChange, *errors.ErrorDetails)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"time"
 )
 
 type ProductRepository interface {
@@ -13,12 +14,33 @@ type ProductRepository interface {
 	// Update updates an existing product in the database
 	Update(ctx context.Context, product *models.Product) *errors.ErrorDetails
 
-	// GetById retrieves a product by its ID from the database
+	// UpdateStatus sets the status of a product as long as it still has the expected status
+	UpdateStatus(ctx context.Context, product *models.Product, expectedStatus string) *errors.ErrorDetails
+
+	// UpdateImage sets the image of a product, leaving its other fields as they are
+	UpdateImage(ctx context.Context, product *models.Product) *errors.ErrorDetails
+
+	// Delete marks a product as deleted, the row is kept for the orders referring to it
+	Delete(ctx context.Context, id int64) *errors.ErrorDetails
+
+	// Restore puts a deleted product back on the menu
+	Restore(ctx context.Context, id int64) *errors.ErrorDetails
+
+	// GetById retrieves a product that is not deleted by its ID from the database
 	GetById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails)
 
-	// ListProducts retrieves a list of products from the database
-	ListProducts(ctx context.Context, limit, offset *int) []*models.Product
+	// GetLastModified retrieves when a product or its category was last changed
+	GetLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails)
+
+	// GetCatalogLastModified retrieves when any product or category was last created, changed or deleted
+	GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails)
+
+	// ListProducts retrieves the products matching the filter and the total number of matches from the database
+	ListProducts(ctx context.Context, filter *models.ProductFilter) ([]*models.Product, int64, *errors.ErrorDetails)
+
+	// GetPriceHistory retrieves the price history of a product, newest version first
+	GetPriceHistory(ctx context.Context, productId int64) ([]*models.ProductPrice, *errors.ErrorDetails)
 
-	// GetByIds retrieves a list of products by their IDs from the database
+	// GetByIds retrieves a list of products by their IDs from the database, deleted products included
 	GetByIds(ctx context.Context, ids []int64) ([]*models.Product, *errors.ErrorDetails)
 }
@@ -0,0 +1,18 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"time"
+)
+
+type RecommendationRepository interface {
+	// GetRecommendations retrieves the recommendations for the products, best score first
+	GetRecommendations(ctx context.Context, productIds []int64) ([]*models.Recommendation, *errors.ErrorDetails)
+
+	// RefreshRecommendations replaces the recommendations with the ones computed from the orders placed since a time,
+	// keeping the best perProduct ones of the products bought together in at least minOrders orders. It returns the
+	// number of recommendations stored, or false when another instance is refreshing them.
+	RefreshRecommendations(ctx context.Context, since time.Time, minOrders int, perProduct int) (int64, bool, *errors.ErrorDetails)
+}
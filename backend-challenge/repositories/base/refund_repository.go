@@ -0,0 +1,17 @@
+package base
+
+import (
+	"context"
+
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type RefundRepository interface {
+	// CreateRefund records a refund of a completed order, as long as the order still has the number of refunds the
+	// refund was priced against. Restocking refunds put the refunded quantities back in stock.
+	CreateRefund(ctx context.Context, refund *models.Refund, previousRefunds int) *errors.ErrorDetails
+
+	// GetRefundsByOrderId retrieves the refunds of an order with their items, oldest first
+	GetRefundsByOrderId(ctx context.Context, orderId string) ([]*models.Refund, *errors.ErrorDetails)
+}
@@ -0,0 +1,17 @@
+package base
+
+import (
+	"context"
+
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type StockRepository interface {
+	// AdjustStock applies a stock adjustment to a product and records it in the audit trail. A non nil Delta is
+	// added to the current stock, otherwise the stock is set to QuantityAfter.
+	AdjustStock(ctx context.Context, adjustment *models.StockAdjustment) *errors.ErrorDetails
+
+	// GetAdjustments retrieves the most recent stock adjustments of a product
+	GetAdjustments(ctx context.Context, productId int64, limit int) ([]*models.StockAdjustment, *errors.ErrorDetails)
+}
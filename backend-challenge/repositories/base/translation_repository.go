@@ -0,0 +1,35 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type TranslationRepository interface {
+	// GetProductTranslations retrieves the translations of the products in a locale, products without one are missing
+	// from the result
+	GetProductTranslations(ctx context.Context, productIds []int64, locale string) (map[int64]*models.ProductTranslation, *errors.ErrorDetails)
+
+	// GetCategoryTranslations retrieves the translations of the categories with the given paths in a locale, keyed by
+	// path, categories without one are missing from the result
+	GetCategoryTranslations(ctx context.Context, paths []string, locale string) (map[string]*models.CategoryTranslation, *errors.ErrorDetails)
+
+	// ListProductTranslations retrieves the translations of a product in every locale
+	ListProductTranslations(ctx context.Context, productId int64) ([]*models.ProductTranslation, *errors.ErrorDetails)
+
+	// SaveProductTranslation creates or replaces the translation of a product in its locale
+	SaveProductTranslation(ctx context.Context, translation *models.ProductTranslation) *errors.ErrorDetails
+
+	// DeleteProductTranslation deletes the translation of a product in a locale
+	DeleteProductTranslation(ctx context.Context, productId int64, locale string) *errors.ErrorDetails
+
+	// ListCategoryTranslations retrieves the translations of a category in every locale
+	ListCategoryTranslations(ctx context.Context, categoryId int64) ([]*models.CategoryTranslation, *errors.ErrorDetails)
+
+	// SaveCategoryTranslation creates or replaces the translation of a category in its locale
+	SaveCategoryTranslation(ctx context.Context, translation *models.CategoryTranslation) *errors.ErrorDetails
+
+	// DeleteCategoryTranslation deletes the translation of a category in a locale
+	DeleteCategoryTranslation(ctx context.Context, categoryId int64, locale string) *errors.ErrorDetails
+}
@@ -0,0 +1,369 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+)
+
+// maxCachedProductLists bounds the number of cached listings, searches can produce any number of distinct filters.
+// Once it is reached the least recently used listing makes room for the new one.
+const maxCachedProductLists = 1000
+
+// productList is a cached result of ListProducts
+type productList struct {
+	products   []*models.Product
+	totalCount int64
+	// lastUsed is the tick of the clock of the cache the listing was last stored or served at
+	lastUsed atomic.Uint64
+}
+
+// CachingProductRepository serves the product reads from memory and passes everything else to the wrapped repository.
+// Writes made through it drop the cache right away, writes made anywhere else are announced by the database on the
+// catalog channel, see ListenForCatalogChanges. The cache starts inactive and only serves reads once activated.
+type CachingProductRepository struct {
+	repository base.ProductRepository
+
+	mu sync.RWMutex
+	// generation is incremented on every invalidation, a read that started before one must not store its result
+	generation          uint64
+	active              bool
+	products            map[int64]*models.Product
+	lastModified        map[int64]time.Time
+	catalogLastModified *time.Time
+	lists               map[string]*productList
+	// clock ticks on every use of a listing, it orders the listings from the least recently used
+	clock atomic.Uint64
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+}
+
+// NewCachingProductRepository creates a new instance of CachingProductRepository in front of repository
+func NewCachingProductRepository(repository base.ProductRepository) *CachingProductRepository {
+	c := &CachingProductRepository{repository: repository}
+	c.resetLocked()
+	return c
+}
+
+// Activate Starts serving reads from memory, entries cached before are dropped
+func (c *CachingProductRepository) Activate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active = true
+	c.resetLocked()
+}
+
+// Deactivate Sends every read to the database until the cache is activated again
+func (c *CachingProductRepository) Deactivate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active = false
+	c.resetLocked()
+}
+
+// Invalidate Drops every cached entry
+func (c *CachingProductRepository) Invalidate() {
+	c.reset()
+}
+
+// Stats Returns the counters of the cache
+func (c *CachingProductRepository) Stats() *models.CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := len(c.products) + len(c.lastModified) + len(c.lists)
+	if c.catalogLastModified != nil {
+		entries++
+	}
+
+	return &models.CacheStats{
+		Name:          "products",
+		Active:        c.active,
+		Entries:       entries,
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+	}
+}
+
+// Save Saves a new product to the database
+func (c *CachingProductRepository) Save(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	defer c.reset()
+	return c.repository.Save(ctx, product)
+}
+
+// Update Updates an existing product in the database
+func (c *CachingProductRepository) Update(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	defer c.reset()
+	return c.repository.Update(ctx, product)
+}
+
+// UpdateStatus Sets the status of a product as long as it still has the expected status
+func (c *CachingProductRepository) UpdateStatus(ctx context.Context, product *models.Product, expectedStatus string) *errors.ErrorDetails {
+	defer c.reset()
+	return c.repository.UpdateStatus(ctx, product, expectedStatus)
+}
+
+// UpdateImage Sets the image of a product
+func (c *CachingProductRepository) UpdateImage(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	defer c.reset()
+	return c.repository.UpdateImage(ctx, product)
+}
+
+// Delete Deletes a product from the database
+func (c *CachingProductRepository) Delete(ctx context.Context, id int64) *errors.ErrorDetails {
+	defer c.reset()
+	return c.repository.Delete(ctx, id)
+}
+
+// Restore Puts a deleted product back on the menu
+func (c *CachingProductRepository) Restore(ctx context.Context, id int64) *errors.ErrorDetails {
+	defer c.reset()
+	return c.repository.Restore(ctx, id)
+}
+
+// GetById Retrieves a product that is not deleted by its ID from memory or the database
+func (c *CachingProductRepository) GetById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
+	var cached *models.Product
+	generation, found := c.read(func() bool {
+		var found bool
+		cached, found = c.products[id]
+		// Deleted products are cached for GetByIds, the wrapped repository answers with the not found error
+		return found && !cached.IsDeleted()
+	})
+	if found {
+		// Cached products are replaced but never changed, copying outside the lock is safe
+		return cloneProduct(cached), nil
+	}
+
+	product, err := c.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(generation, func() {
+		c.products[id] = cloneProduct(product)
+	})
+	return product, nil
+}
+
+// GetLastModified Retrieves when a product or its category was last changed from memory or the database
+func (c *CachingProductRepository) GetLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails) {
+	var lastModified time.Time
+	generation, found := c.read(func() bool {
+		var found bool
+		lastModified, found = c.lastModified[id]
+		return found
+	})
+	if found {
+		return lastModified, nil
+	}
+
+	lastModified, err := c.repository.GetLastModified(ctx, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.store(generation, func() {
+		c.lastModified[id] = lastModified
+	})
+	return lastModified, nil
+}
+
+// GetCatalogLastModified Retrieves when any product or category was last created, changed or deleted from memory or
+// the database
+func (c *CachingProductRepository) GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails) {
+	var lastModified time.Time
+	generation, found := c.read(func() bool {
+		if c.catalogLastModified == nil {
+			return false
+		}
+		lastModified = *c.catalogLastModified
+		return true
+	})
+	if found {
+		return lastModified, nil
+	}
+
+	lastModified, err := c.repository.GetCatalogLastModified(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.store(generation, func() {
+		c.catalogLastModified = &lastModified
+	})
+	return lastModified, nil
+}
+
+// ListProducts Retrieves the products matching the filter and the total number of matches from memory or the database.
+// Listings of the products available at a time are not cached, their key changes with the time.
+func (c *CachingProductRepository) ListProducts(ctx context.Context, filter *models.ProductFilter) ([]*models.Product, int64, *errors.ErrorDetails) {
+	if filter.AvailableAt != nil {
+		return c.repository.ListProducts(ctx, filter)
+	}
+
+	keyJSON, keyErr := json.Marshal(filter)
+	if keyErr != nil {
+		return c.repository.ListProducts(ctx, filter)
+	}
+	key := string(keyJSON)
+
+	var list *productList
+	generation, found := c.read(func() bool {
+		var found bool
+		list, found = c.lists[key]
+		return found
+	})
+	if found {
+		list.lastUsed.Store(c.clock.Add(1))
+		return cloneProducts(list.products), list.totalCount, nil
+	}
+
+	products, totalCount, err := c.repository.ListProducts(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	c.store(generation, func() {
+		if _, found := c.lists[key]; !found && len(c.lists) >= maxCachedProductLists {
+			c.evictLeastRecentlyUsedListLocked()
+		}
+		list := &productList{products: cloneProducts(products), totalCount: totalCount}
+		list.lastUsed.Store(c.clock.Add(1))
+		c.lists[key] = list
+	})
+	return products, totalCount, nil
+}
+
+// GetPriceHistory Retrieves the price history of a product from the database, it is not cached
+func (c *CachingProductRepository) GetPriceHistory(ctx context.Context, productId int64) ([]*models.ProductPrice, *errors.ErrorDetails) {
+	return c.repository.GetPriceHistory(ctx, productId)
+}
+
+// GetByIds Retrieves a list of products by their IDs, deleted products included, only the products missing from memory are read from the database
+func (c *CachingProductRepository) GetByIds(ctx context.Context, ids []int64) ([]*models.Product, *errors.ErrorDetails) {
+	var products []*models.Product
+	var missing []int64
+
+	c.mu.RLock()
+	generation := c.generation
+	active := c.active
+	if active {
+		for _, id := range ids {
+			if product, found := c.products[id]; found {
+				products = append(products, cloneProduct(product))
+			} else {
+				missing = append(missing, id)
+			}
+		}
+	}
+	c.mu.RUnlock()
+
+	if !active {
+		c.misses.Add(int64(len(ids)))
+		return c.repository.GetByIds(ctx, ids)
+	}
+
+	c.hits.Add(int64(len(products)))
+	c.misses.Add(int64(len(missing)))
+	if len(missing) == 0 {
+		return products, nil
+	}
+
+	fetched, err := c.repository.GetByIds(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(generation, func() {
+		for _, product := range fetched {
+			c.products[product.Id] = cloneProduct(product)
+		}
+	})
+	return append(products, fetched...), nil
+}
+
+// evictLeastRecentlyUsedListLocked drops the listing that was stored or served the longest time ago
+func (c *CachingProductRepository) evictLeastRecentlyUsedListLocked() {
+	var oldestKey string
+	var oldest uint64
+	for key, list := range c.lists {
+		if lastUsed := list.lastUsed.Load(); oldestKey == "" || lastUsed < oldest {
+			oldestKey, oldest = key, lastUsed
+		}
+	}
+	delete(c.lists, oldestKey)
+}
+
+// read looks an entry up with lookup while the cache is active and counts the hit or miss. It returns the generation
+// the result of a miss has to be stored with.
+func (c *CachingProductRepository) read(lookup func() bool) (uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.active && lookup() {
+		c.hits.Add(1)
+		return c.generation, true
+	}
+
+	c.misses.Add(1)
+	return c.generation, false
+}
+
+// store runs fn to cache the result of a read, unless the cache was invalidated or deactivated since the read started
+func (c *CachingProductRepository) store(generation uint64, fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.active && c.generation == generation {
+		fn()
+	}
+}
+
+// reset drops every cached entry and counts the invalidation
+func (c *CachingProductRepository) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidations.Add(1)
+	c.resetLocked()
+}
+
+func (c *CachingProductRepository) resetLocked() {
+	c.generation++
+	c.products = make(map[int64]*models.Product)
+	c.lastModified = make(map[int64]time.Time)
+	c.catalogLastModified = nil
+	c.lists = make(map[string]*productList)
+}
+
+// cloneProduct copies a product so that callers changing it do not change the cached one
+func cloneProduct(product *models.Product) *models.Product {
+	clone := *product
+	if product.StockQuantity != nil {
+		quantity := *product.StockQuantity
+		clone.StockQuantity = &quantity
+	}
+	clone.Allergens = slices.Clone(product.Allergens)
+	clone.Diets = slices.Clone(product.Diets)
+	clone.Meta = maps.Clone(product.Meta)
+	return &clone
+}
+
+func cloneProducts(products []*models.Product) []*models.Product {
+	clones := make([]*models.Product, len(products))
+	for i, product := range products {
+		clones[i] = cloneProduct(product)
+	}
+	return clones
+}
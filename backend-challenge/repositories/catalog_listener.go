@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/repositories/base"
+)
+
+// CatalogChannel is the channel the database notifies on whenever products or categories change, see schemas.sql
+const CatalogChannel = "kart_catalog"
+
+// maxListenBackoff is the longest wait between two attempts to listen on the catalog channel again
+const maxListenBackoff = 30 * time.Second
+
+// ListenForCatalogChanges Invalidates the caches on every notification of the catalog channel until ctx is done. The
+// caches are only active while the channel is listened to, changes would go unnoticed otherwise.
+func ListenForCatalogChanges(ctx context.Context, pool *pgxpool.Pool, caches ...base.Cache) {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := listenForCatalogChanges(ctx, pool, caches)
+		for _, cache := range caches {
+			cache.Deactivate()
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > maxListenBackoff {
+			backoff = time.Second
+		}
+		configs.Logger.Warn("stopped listening for catalog changes, caches are bypassed until listening again",
+			zap.Error(err), zap.Duration("retryIn", backoff))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxListenBackoff)
+	}
+}
+
+// listenForCatalogChanges listens on the catalog channel with a connection of its own until it fails
+func listenForCatalogChanges(ctx context.Context, pool *pgxpool.Pool, caches []base.Cache) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A listening connection must not go back to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{CatalogChannel}.Sanitize()); err != nil {
+		return err
+	}
+
+	// Changes made while nobody was listening are unknown, activating starts from an empty cache
+	for _, cache := range caches {
+		cache.Activate()
+	}
+	configs.Logger.Info("listening for catalog changes", zap.String("channel", CatalogChannel))
+
+	for {
+		if _, err = conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+
+		for _, cache := range caches {
+			cache.Invalidate()
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type CatalogRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewCatalogRepositoryImpl creates a new instance of CatalogRepositoryImpl
+func NewCatalogRepositoryImpl(pool *pgxpool.Pool) *CatalogRepositoryImpl {
+	return &CatalogRepositoryImpl{pool: pool}
+}
+
+// GetByKeys Retrieves the products matching the given name and category pairs from the database
+func (c *CatalogRepositoryImpl) GetByKeys(ctx context.Context, keys []models.ProductKey) ([]*models.Product, *errors.ErrorDetails) {
+	names := make([]string, len(keys))
+	categories := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.Name
+		categories[i] = key.Category
+	}
+
+	query := `SELECT ` + productColumns + `
+              FROM ` + productTable + `
+              WHERE p.deleted_at IS NULL
+                AND (p.name, c.path) IN (SELECT * FROM UNNEST($1::text[], $2::text[]))`
+
+	rows, err := c.pool.Query(ctx, query, names, categories)
+	if err != nil {
+		configs.Logger.Error("failed to query products by key", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch products", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	products := []*models.Product{}
+	for rows.Next() {
+		product, scanErr := scanProduct(rows)
+		if scanErr != nil {
+			configs.Logger.Error("failed to scan product", zap.Error(scanErr))
+			return nil, exceptions.GenericException("failed to fetch products", http.StatusInternalServerError)
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading products", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch products", http.StatusInternalServerError)
+	}
+
+	return products, nil
+}
+
+// UpsertProducts Creates or updates the products by name and category in a single transaction, the categories of the
+// products are created when they do not exist yet
+func (c *CatalogRepositoryImpl) UpsertProducts(ctx context.Context, products []*models.Product) *errors.ErrorDetails {
+	if len(products) == 0 {
+		return nil
+	}
+
+	tx, err := c.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	categories := make(map[string]*models.Category)
+	for _, product := range products {
+		category, found := categories[product.Category]
+		if !found {
+			names, parseErr := models.ParseCategoryPath(product.Category)
+			if parseErr != nil {
+				configs.Logger.Error("invalid product category", zap.String("category", product.Category), zap.Error(parseErr))
+				return exceptions.BadRequestException(parseErr.Error())
+			}
+
+			category, err = ensureCategoryPath(ctx, tx, names)
+			if err != nil {
+				return categoryError(err, "failed to import categories")
+			}
+			categories[product.Category] = category
+		}
+		product.CategoryId = category.Id
+	}
+
+	batch := &pgx.Batch{}
+	for _, product := range products {
+		imageJSON, err := json.Marshal(product.Image)
+		if err != nil {
+			configs.Logger.Error("failed to marshal product image", zap.Error(err))
+			return exceptions.GenericException("failed to marshal product image", http.StatusInternalServerError)
+		}
+
+		var metaJSON []byte
+		if product.Meta != nil {
+			metaJSON, err = json.Marshal(product.Meta)
+			if err != nil {
+				configs.Logger.Error("failed to marshal product meta", zap.Error(err))
+				return exceptions.GenericException("failed to marshal product meta", http.StatusInternalServerError)
+			}
+		}
+
+		batch.Queue(`INSERT INTO products (name, description, category_id, price, status, image, allergens, diets, meta)
+                     VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                     ON CONFLICT (name, category_id) WHERE deleted_at IS NULL DO UPDATE
+                     SET description = EXCLUDED.description,
+                         price = EXCLUDED.price,
+                         status = EXCLUDED.status,
+                         image = EXCLUDED.image,
+                         allergens = EXCLUDED.allergens,
+                         diets = EXCLUDED.diets,
+                         meta = EXCLUDED.meta,
+                         modified_at = NOW()
+                     RETURNING id, price_version, created_at, modified_at`,
+			product.Name,
+			product.Description,
+			product.CategoryId,
+			product.Price,
+			product.Status,
+			imageJSON,
+			nonNil(product.Allergens),
+			nonNil(product.Diets),
+			metaJSON,
+		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for _, product := range products {
+		if err = results.QueryRow().Scan(&product.Id, &product.PriceVersion, &product.CreatedAt, &product.ModifiedAt); err != nil {
+			results.Close()
+			configs.Logger.Error("failed to import product", zap.String("name", product.Name), zap.Error(err))
+			return exceptions.GenericException("failed to import products", http.StatusInternalServerError)
+		}
+	}
+
+	if err = results.Close(); err != nil {
+		configs.Logger.Error("failed to close batch results", zap.Error(err))
+		return exceptions.GenericException("failed to import products", http.StatusInternalServerError)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// ExportProducts Calls fn for every product that is not deleted ordered by ID without loading the whole catalog in memory
+func (c *CatalogRepositoryImpl) ExportProducts(ctx context.Context, fn func(product *models.Product) error) *errors.ErrorDetails {
+	rows, err := c.pool.Query(ctx, `SELECT `+productColumns+` FROM `+productTable+` WHERE p.deleted_at IS NULL ORDER BY p.id`)
+	if err != nil {
+		configs.Logger.Error("failed to query products", zap.Error(err))
+		return exceptions.GenericException("failed to export products", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, scanErr := scanProduct(rows)
+		if scanErr != nil {
+			configs.Logger.Error("failed to scan product", zap.Error(scanErr))
+			return exceptions.GenericException("failed to export products", http.StatusInternalServerError)
+		}
+
+		if err = fn(product); err != nil {
+			configs.Logger.Error("failed to write exported product", zap.Int64("id", product.Id), zap.Error(err))
+			return exceptions.GenericException("failed to export products", http.StatusInternalServerError)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading products", zap.Error(err))
+		return exceptions.GenericException("failed to export products", http.StatusInternalServerError)
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"strings"
+
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+// categoryColumns is the column list read by scanCategory
+const categoryColumns = `id, parent_id, name, path, sort_order, active, created_at, modified_at`
+
+type CategoryRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewCategoryRepositoryImpl creates a new instance of CategoryRepositoryImpl
+func NewCategoryRepositoryImpl(pool *pgxpool.Pool) *CategoryRepositoryImpl {
+	return &CategoryRepositoryImpl{pool: pool}
+}
+
+// Save Saves a new category to the database, its path is derived from its parent
+func (r *CategoryRepositoryImpl) Save(ctx context.Context, category *models.Category) *errors.ErrorDetails {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	path, errDetails := childCategoryPath(ctx, tx, category.ParentId, category.Name)
+	if errDetails != nil {
+		return errDetails
+	}
+
+	query := `INSERT INTO categories (parent_id, name, path, sort_order, active)
+              VALUES ($1, $2, $3, $4, $5)
+              RETURNING id, created_at, modified_at`
+
+	err = tx.QueryRow(ctx, query,
+		category.ParentId,
+		category.Name,
+		path,
+		category.SortOrder,
+		category.Active,
+	).Scan(&category.Id, &category.CreatedAt, &category.ModifiedAt)
+	if err != nil {
+		return categoryError(err, "failed to save category")
+	}
+	category.Path = path
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Update Updates a category in the database, renaming or moving a category also changes the path of its subcategories
+func (r *CategoryRepositoryImpl) Update(ctx context.Context, category *models.Category) *errors.ErrorDetails {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	var currentPath string
+	if err = tx.QueryRow(ctx, "SELECT path FROM categories WHERE id = $1 FOR UPDATE", category.Id).Scan(&currentPath); err != nil {
+		return categoryError(err, "failed to update category")
+	}
+
+	if category.ParentId != nil && *category.ParentId == category.Id {
+		configs.Logger.Error("category cannot be its own parent", zap.Int64("id", category.Id))
+		return exceptions.UnprocessableEntityException("a category cannot be its own parent")
+	}
+
+	path, errDetails := childCategoryPath(ctx, tx, category.ParentId, category.Name)
+	if errDetails != nil {
+		return errDetails
+	}
+
+	subcategoryPrefix := currentPath + models.CategoryPathSeparator
+	if strings.HasPrefix(path, subcategoryPrefix) {
+		configs.Logger.Error("category cannot be moved into its subcategories", zap.Int64("id", category.Id))
+		return exceptions.UnprocessableEntityException("a category cannot be moved into one of its subcategories")
+	}
+
+	query := `UPDATE categories
+              SET parent_id = $1,
+                  name = $2,
+                  path = $3,
+                  sort_order = $4,
+                  active = $5,
+                  modified_at = NOW()
+              WHERE id = $6
+              RETURNING created_at, modified_at`
+
+	err = tx.QueryRow(ctx, query,
+		category.ParentId,
+		category.Name,
+		path,
+		category.SortOrder,
+		category.Active,
+		category.Id,
+	).Scan(&category.CreatedAt, &category.ModifiedAt)
+	if err != nil {
+		return categoryError(err, "failed to update category")
+	}
+	category.Path = path
+
+	if path != currentPath {
+		_, err = tx.Exec(ctx, `UPDATE categories
+                               SET path = $1 || substr(path, length($2) + 1),
+                                   modified_at = NOW()
+                               WHERE starts_with(path, $3)`,
+			path, currentPath, subcategoryPrefix)
+		if err != nil {
+			return categoryError(err, "failed to update subcategories")
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Delete Deletes a category without subcategories or products from the database
+func (r *CategoryRepositoryImpl) Delete(ctx context.Context, id int64) *errors.ErrorDetails {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM categories WHERE id = $1", id)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			configs.Logger.Error("category still has subcategories or products", zap.Error(err))
+			return exceptions.GenericException("category still has subcategories or products", http.StatusConflict)
+		}
+		configs.Logger.Error("failed to delete category", zap.Error(err))
+		return exceptions.GenericException("failed to delete category", http.StatusInternalServerError)
+	}
+
+	if tag.RowsAffected() == 0 {
+		configs.Logger.Error("category not found", zap.Int64("id", id))
+		return exceptions.GenericException("category not found", http.StatusNotFound)
+	}
+
+	return nil
+}
+
+// GetById Retrieves a category by its ID from the database
+func (r *CategoryRepositoryImpl) GetById(ctx context.Context, id int64) (*models.Category, *errors.ErrorDetails) {
+	category := &models.Category{}
+	err := r.pool.QueryRow(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = $1`, id).Scan(scanCategory(category)...)
+	if err != nil {
+		return nil, categoryError(err, "failed to fetch category")
+	}
+
+	return category, nil
+}
+
+// ListCategories Retrieves every category with the number of available products it directly contains from the database
+func (r *CategoryRepositoryImpl) ListCategories(ctx context.Context) ([]*models.Category, *errors.ErrorDetails) {
+	query := `SELECT c.id, c.parent_id, c.name, c.path, c.sort_order, c.active, c.created_at, c.modified_at,
+                     COUNT(p.id) FILTER (WHERE p.status = $1 AND p.deleted_at IS NULL)
+              FROM categories c
+              LEFT JOIN products p ON p.category_id = c.id
+              GROUP BY c.id
+              ORDER BY c.sort_order, c.name, c.id`
+
+	rows, err := r.pool.Query(ctx, query, models.ProductStatusAvailable)
+	if err != nil {
+		configs.Logger.Error("failed to list categories", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch categories", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	categories := []*models.Category{}
+	for rows.Next() {
+		category := &models.Category{}
+		if err = rows.Scan(append(scanCategory(category), &category.ProductCount)...); err != nil {
+			configs.Logger.Error("failed to scan category", zap.Error(err))
+			return nil, exceptions.GenericException("failed to fetch categories", http.StatusInternalServerError)
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading categories", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch categories", http.StatusInternalServerError)
+	}
+
+	return categories, nil
+}
+
+// EnsurePath Retrieves the category at the end of a path, creating the categories of the path that do not exist yet
+func (r *CategoryRepositoryImpl) EnsurePath(ctx context.Context, names []string) (*models.Category, *errors.ErrorDetails) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	category, err := ensureCategoryPath(ctx, tx, names)
+	if err != nil {
+		return nil, categoryError(err, "failed to create category")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return nil, exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return category, nil
+}
+
+// ensureCategoryPath finds or creates every category of a path from the top level down and returns the last one
+func ensureCategoryPath(ctx context.Context, tx pgx.Tx, names []string) (*models.Category, error) {
+	// Updating the path of an existing category to itself makes RETURNING yield its columns without a second query
+	query := `INSERT INTO categories (parent_id, name, path)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (path) DO UPDATE SET path = EXCLUDED.path
+              RETURNING ` + categoryColumns
+
+	var category *models.Category
+	for i, name := range names {
+		var parentId *int64
+		if category != nil {
+			parentId = &category.Id
+		}
+
+		category = &models.Category{}
+		err := tx.QueryRow(ctx, query, parentId, name, models.CategoryPath(names[:i+1])).Scan(scanCategory(category)...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return category, nil
+}
+
+// childCategoryPath returns the path of a category named name under the given parent, which is locked until the end
+// of the transaction so that it cannot be renamed or moved concurrently
+func childCategoryPath(ctx context.Context, tx pgx.Tx, parentId *int64, name string) (string, *errors.ErrorDetails) {
+	if parentId == nil {
+		return name, nil
+	}
+
+	var parentPath string
+	err := tx.QueryRow(ctx, "SELECT path FROM categories WHERE id = $1 FOR SHARE", *parentId).Scan(&parentPath)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("parent category not found", zap.Int64("id", *parentId))
+			return "", exceptions.UnprocessableEntityException("parent category not found")
+		}
+		configs.Logger.Error("failed to fetch parent category", zap.Error(err))
+		return "", exceptions.GenericException("failed to fetch parent category", http.StatusInternalServerError)
+	}
+
+	return parentPath + models.CategoryPathSeparator + name, nil
+}
+
+// scanCategory returns the scan destinations of categoryColumns
+func scanCategory(category *models.Category) []any {
+	return []any{
+		&category.Id,
+		&category.ParentId,
+		&category.Name,
+		&category.Path,
+		&category.SortOrder,
+		&category.Active,
+		&category.CreatedAt,
+		&category.ModifiedAt,
+	}
+}
+
+func categoryError(err error, message string) *errors.ErrorDetails {
+	if err == pgx.ErrNoRows {
+		configs.Logger.Error("category not found", zap.Error(err))
+		return exceptions.GenericException("category not found", http.StatusNotFound)
+	}
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+		configs.Logger.Error("category with this name already exists in the parent category", zap.Error(err))
+		return exceptions.GenericException("category with this name already exists in the parent category", http.StatusConflict)
+	}
+	configs.Logger.Error(message, zap.Error(err))
+	return exceptions.GenericException(message, http.StatusInternalServerError)
+}
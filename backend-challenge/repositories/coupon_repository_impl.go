@@ -12,6 +12,7 @@ import (
 
 	"oolio.com/kart/exceptions"
 	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
 	"oolio.com/kart/repositories/base"
 )
 
@@ -88,3 +89,29 @@ func (r *CouponRepositoryImpl) GetCouponFileCount(ctx context.Context, code stri
 
 	return fileCount, true, nil
 }
+
+// redeemCoupon records the use of its coupon by an order, if it was placed with one
+func redeemCoupon(ctx context.Context, tx pgx.Tx, order *models.Order) *errors.ErrorDetails {
+	if order.CouponCode == "" {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `INSERT INTO coupon_redemptions (order_id, coupon_code) VALUES ($1, $2)`, order.Id, order.CouponCode)
+	if err != nil {
+		configs.Logger.Error("failed to redeem coupon", zap.Error(err))
+		return exceptions.GenericException("failed to redeem coupon", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// releaseCoupon gives back the use of a coupon taken by an order, so that it no longer counts towards the uses of the
+// coupon. Orders placed without a coupon have nothing to release.
+func releaseCoupon(ctx context.Context, tx pgx.Tx, orderId string) *errors.ErrorDetails {
+	_, err := tx.Exec(ctx, `UPDATE coupon_redemptions SET released_at = NOW()
+                            WHERE order_id = $1 AND released_at IS NULL`, orderId)
+	if err != nil {
+		configs.Logger.Error("failed to release coupon", zap.Error(err))
+		return exceptions.GenericException("failed to release coupon", http.StatusInternalServerError)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"time"
+)
+
+type IdempotencyRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewIdempotencyRepositoryImpl creates a new instance of IdempotencyRepositoryImpl
+func NewIdempotencyRepositoryImpl(pool *pgxpool.Pool) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{pool: pool}
+}
+
+// ClaimKey Reserves an idempotency key for a request. The key is claimed by a single statement, so that when replicas
+// of the API receive retries of a request at the same time only one of them processes it. Expired keys and keys whose
+// request is still being processed after inFlightTimeout, its instance having most likely died, are claimed again.
+func (r *IdempotencyRepositoryImpl) ClaimKey(ctx context.Context, key string, fingerprint string, ttl time.Duration, inFlightTimeout time.Duration) (*models.IdempotencyRecord, *errors.ErrorDetails) {
+	var claimed bool
+	err := r.pool.QueryRow(ctx, `INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+                                 VALUES ($1, $2, now() + make_interval(secs => $3))
+                                 ON CONFLICT (key) DO UPDATE
+                                     SET fingerprint   = EXCLUDED.fingerprint,
+                                         status_code   = NULL,
+                                         response_body = NULL,
+                                         created_at    = now(),
+                                         expires_at    = EXCLUDED.expires_at
+                                     WHERE idempotency_keys.expires_at <= now()
+                                        OR (idempotency_keys.status_code IS NULL
+                                            AND idempotency_keys.created_at <= now() - make_interval(secs => $4))
+                                 RETURNING true`,
+		key, fingerprint, ttl.Seconds(), inFlightTimeout.Seconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != pgx.ErrNoRows {
+		configs.Logger.Error("failed to claim idempotency key", zap.Error(err))
+		return nil, exceptions.GenericException("failed to claim idempotency key", http.StatusInternalServerError)
+	}
+
+	record := &models.IdempotencyRecord{Key: key}
+	var statusCode *int
+	err = r.pool.QueryRow(ctx, `SELECT fingerprint, status_code, response_body, created_at, expires_at
+                                FROM idempotency_keys
+                                WHERE key = $1`, key).
+		Scan(&record.Fingerprint, &statusCode, &record.Body, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			// The key expired and was deleted since it could not be claimed, the request is retried like an in flight one
+			configs.Logger.Error("idempotency key deleted while claimed", zap.String("key", key))
+			return nil, exceptions.GenericException("a request with this idempotency key is being processed, retry later", http.StatusConflict)
+		}
+		configs.Logger.Error("failed to fetch idempotency key", zap.Error(err))
+		return nil, exceptions.GenericException("failed to claim idempotency key", http.StatusInternalServerError)
+	}
+
+	if statusCode != nil {
+		record.StatusCode = *statusCode
+	}
+	return record, nil
+}
+
+// SaveResponse Stores the response of the request that claimed a key, unless the key was claimed again meanwhile
+func (r *IdempotencyRepositoryImpl) SaveResponse(ctx context.Context, key string, fingerprint string, statusCode int, body []byte) *errors.ErrorDetails {
+	tag, err := r.pool.Exec(ctx, `UPDATE idempotency_keys
+                                  SET status_code = $3, response_body = $4
+                                  WHERE key = $1 AND fingerprint = $2 AND status_code IS NULL`,
+		key, fingerprint, statusCode, body)
+	if err != nil {
+		configs.Logger.Error("failed to save idempotent response", zap.Error(err))
+		return exceptions.GenericException("failed to save idempotent response", http.StatusInternalServerError)
+	}
+
+	if tag.RowsAffected() == 0 {
+		configs.Logger.Warn("idempotency key was claimed again before its response was saved", zap.String("key", key))
+	}
+	return nil
+}
+
+// ReleaseKey Deletes the key claimed by a request that has no response to store
+func (r *IdempotencyRepositoryImpl) ReleaseKey(ctx context.Context, key string, fingerprint string) *errors.ErrorDetails {
+	_, err := r.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND fingerprint = $2 AND status_code IS NULL`,
+		key, fingerprint)
+	if err != nil {
+		configs.Logger.Error("failed to release idempotency key", zap.Error(err))
+		return exceptions.GenericException("failed to release idempotency key", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// DeleteExpiredKeys Deletes the keys past their expiry
+func (r *IdempotencyRepositoryImpl) DeleteExpiredKeys(ctx context.Context) (int64, *errors.ErrorDetails) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= now()")
+	if err != nil {
+		configs.Logger.Error("failed to delete expired idempotency keys", zap.Error(err))
+		return 0, exceptions.GenericException("failed to delete expired idempotency keys", http.StatusInternalServerError)
+	}
+	return tag.RowsAffected(), nil
+}
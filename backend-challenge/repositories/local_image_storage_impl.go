@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type LocalImageStorageImpl struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalImageStorageImpl creates a new instance of LocalImageStorageImpl keeping the images in dir, they are served
+// from baseURL
+func NewLocalImageStorageImpl(dir string, baseURL string) *LocalImageStorageImpl {
+	return &LocalImageStorageImpl{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Save Writes an image to a file under the storage directory, readers never see a partially written file
+func (s *LocalImageStorageImpl) Save(ctx context.Context, key string, contentType string, content []byte) (string, *errors.ErrorDetails) {
+	name, ok := s.path(key)
+	if !ok {
+		configs.Logger.Error("invalid image key", zap.String("key", key))
+		return "", exceptions.GenericException("invalid image key", http.StatusInternalServerError)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		configs.Logger.Error("failed to create image directory", zap.Error(err))
+		return "", exceptions.GenericException("failed to store image", http.StatusInternalServerError)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		configs.Logger.Error("failed to create image file", zap.Error(err))
+		return "", exceptions.GenericException("failed to store image", http.StatusInternalServerError)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), name)
+	}
+	if err != nil {
+		configs.Logger.Error("failed to write image file", zap.String("key", key), zap.Error(err))
+		return "", exceptions.GenericException("failed to store image", http.StatusInternalServerError)
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+// Delete Removes the file of an image
+func (s *LocalImageStorageImpl) Delete(ctx context.Context, key string) *errors.ErrorDetails {
+	name, ok := s.path(key)
+	if !ok {
+		configs.Logger.Error("invalid image key", zap.String("key", key))
+		return exceptions.GenericException("invalid image key", http.StatusInternalServerError)
+	}
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		configs.Logger.Error("failed to delete image file", zap.String("key", key), zap.Error(err))
+		return exceptions.GenericException("failed to delete image", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// KeyOf Returns the key of an image URL starting with the base URL of the storage
+func (s *LocalImageStorageImpl) KeyOf(url string) (string, bool) {
+	key, found := strings.CutPrefix(url, s.baseURL+"/")
+	if !found {
+		return "", false
+	}
+
+	_, ok := s.path(key)
+	return key, ok
+}
+
+// path returns the file of a key, keys are slash separated and must stay within the storage directory
+func (s *LocalImageStorageImpl) path(key string) (string, bool) {
+	if key == "" || path.Clean("/"+key) != "/"+key {
+		return "", false
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), true
+}
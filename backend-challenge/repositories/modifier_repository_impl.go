@@ -0,0 +1,260 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+)
+
+type ModifierRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewModifierRepositoryImpl creates a new instance of ModifierRepositoryImpl
+func NewModifierRepositoryImpl(pool *pgxpool.Pool) *ModifierRepositoryImpl {
+	return &ModifierRepositoryImpl{pool: pool}
+}
+
+// SaveGroup Saves a new modifier group with its modifiers to the database
+func (m *ModifierRepositoryImpl) SaveGroup(ctx context.Context, group *models.ModifierGroup) *errors.ErrorDetails {
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	query := `INSERT INTO modifier_groups (product_id, name, min_select, max_select, sort_order)
+              VALUES ($1, $2, $3, $4, $5)
+              RETURNING id, created_at, modified_at`
+
+	err = tx.QueryRow(ctx, query,
+		group.ProductId,
+		group.Name,
+		group.MinSelect,
+		group.MaxSelect,
+		group.SortOrder,
+	).Scan(&group.Id, &group.CreatedAt, &group.ModifiedAt)
+	if err != nil {
+		return modifierGroupError(err, "failed to save modifier group")
+	}
+
+	if errDetails := upsertModifiers(ctx, tx, group); errDetails != nil {
+		return errDetails
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// UpdateGroup Replaces a modifier group and its modifiers in the database
+func (m *ModifierRepositoryImpl) UpdateGroup(ctx context.Context, group *models.ModifierGroup) *errors.ErrorDetails {
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	query := `UPDATE modifier_groups
+              SET name = $1,
+                  min_select = $2,
+                  max_select = $3,
+                  sort_order = $4,
+                  modified_at = NOW()
+              WHERE id = $5 AND product_id = $6
+              RETURNING created_at, modified_at`
+
+	err = tx.QueryRow(ctx, query,
+		group.Name,
+		group.MinSelect,
+		group.MaxSelect,
+		group.SortOrder,
+		group.Id,
+		group.ProductId,
+	).Scan(&group.CreatedAt, &group.ModifiedAt)
+	if err != nil {
+		return modifierGroupError(err, "failed to update modifier group")
+	}
+
+	names := make([]string, len(group.Modifiers))
+	for i, modifier := range group.Modifiers {
+		names[i] = modifier.Name
+	}
+
+	// Modifiers that were left out are removed, the ones already ordered stay described by their order line
+	if _, err = tx.Exec(ctx, "DELETE FROM modifiers WHERE group_id = $1 AND NOT (name = ANY($2))", group.Id, names); err != nil {
+		configs.Logger.Error("failed to delete modifiers", zap.Error(err))
+		return exceptions.GenericException("failed to update modifier group", http.StatusInternalServerError)
+	}
+
+	if errDetails := upsertModifiers(ctx, tx, group); errDetails != nil {
+		return errDetails
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// DeleteGroup Deletes a modifier group of a product with its modifiers from the database
+func (m *ModifierRepositoryImpl) DeleteGroup(ctx context.Context, productId int64, groupId int64) *errors.ErrorDetails {
+	tag, err := m.pool.Exec(ctx, "DELETE FROM modifier_groups WHERE id = $1 AND product_id = $2", groupId, productId)
+	if err != nil {
+		configs.Logger.Error("failed to delete modifier group", zap.Error(err))
+		return exceptions.GenericException("failed to delete modifier group", http.StatusInternalServerError)
+	}
+
+	if tag.RowsAffected() == 0 {
+		configs.Logger.Error("modifier group not found", zap.Int64("id", groupId))
+		return exceptions.GenericException("modifier group not found", http.StatusNotFound)
+	}
+
+	return nil
+}
+
+// GetGroupsByProductIds Retrieves the modifier groups with their modifiers of the given products from the database
+func (m *ModifierRepositoryImpl) GetGroupsByProductIds(ctx context.Context, productIds []int64) (map[int64][]*models.ModifierGroup, *errors.ErrorDetails) {
+	query := `SELECT g.id, g.product_id, g.name, g.min_select, g.max_select, g.sort_order, g.created_at, g.modified_at,
+                     m.id, m.name, m.price_delta, m.sort_order
+              FROM modifier_groups g
+              LEFT JOIN modifiers m ON m.group_id = g.id
+              WHERE g.product_id = ANY($1)
+              ORDER BY g.product_id, g.sort_order, g.id, m.sort_order, m.id`
+
+	rows, err := m.pool.Query(ctx, query, productIds)
+	if err != nil {
+		configs.Logger.Error("failed to query modifier groups", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch modifier groups", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	groups := make(map[int64][]*models.ModifierGroup)
+	var current *models.ModifierGroup
+	for rows.Next() {
+		group := &models.ModifierGroup{Modifiers: []*models.Modifier{}}
+		var modifierId *int64
+		var modifierName *string
+		var priceDelta *money.Money
+		var sortOrder *int
+
+		err = rows.Scan(
+			&group.Id,
+			&group.ProductId,
+			&group.Name,
+			&group.MinSelect,
+			&group.MaxSelect,
+			&group.SortOrder,
+			&group.CreatedAt,
+			&group.ModifiedAt,
+			&modifierId,
+			&modifierName,
+			&priceDelta,
+			&sortOrder,
+		)
+		if err != nil {
+			configs.Logger.Error("failed to scan modifier group", zap.Error(err))
+			return nil, exceptions.GenericException("failed to fetch modifier groups", http.StatusInternalServerError)
+		}
+
+		if current == nil || current.Id != group.Id {
+			current = group
+			groups[group.ProductId] = append(groups[group.ProductId], group)
+		}
+
+		if modifierId != nil {
+			current.Modifiers = append(current.Modifiers, &models.Modifier{
+				Id:         *modifierId,
+				GroupId:    current.Id,
+				Name:       *modifierName,
+				PriceDelta: *priceDelta,
+				SortOrder:  *sortOrder,
+			})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading modifier groups", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch modifier groups", http.StatusInternalServerError)
+	}
+
+	return groups, nil
+}
+
+// upsertModifiers inserts the modifiers of a group, modifiers that already exist with the same name are updated in place
+func upsertModifiers(ctx context.Context, tx pgx.Tx, group *models.ModifierGroup) *errors.ErrorDetails {
+	if len(group.Modifiers) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, modifier := range group.Modifiers {
+		batch.Queue(`INSERT INTO modifiers (group_id, name, price_delta, sort_order)
+                     VALUES ($1, $2, $3, $4)
+                     ON CONFLICT (group_id, name) DO UPDATE
+                     SET price_delta = EXCLUDED.price_delta,
+                         sort_order = EXCLUDED.sort_order
+                     RETURNING id`,
+			group.Id,
+			modifier.Name,
+			modifier.PriceDelta,
+			modifier.SortOrder,
+		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, modifier := range group.Modifiers {
+		if err := results.QueryRow().Scan(&modifier.Id); err != nil {
+			configs.Logger.Error("failed to save modifier", zap.Error(err))
+			return exceptions.GenericException("failed to save modifiers", http.StatusInternalServerError)
+		}
+		modifier.GroupId = group.Id
+	}
+
+	return nil
+}
+
+// modifierGroupError maps the errors of writing a modifier group to API errors
+func modifierGroupError(err error, message string) *errors.ErrorDetails {
+	if err == pgx.ErrNoRows {
+		configs.Logger.Error("modifier group not found", zap.Error(err))
+		return exceptions.GenericException("modifier group not found", http.StatusNotFound)
+	}
+	if pgErr, ok := err.(*pgconn.PgError); ok {
+		switch pgErr.Code {
+		case "23505":
+			configs.Logger.Error("modifier group with this name already exists", zap.Error(err))
+			return exceptions.GenericException("modifier group with this name already exists", http.StatusConflict)
+		case "23503":
+			configs.Logger.Error("product not found", zap.Error(err))
+			return exceptions.GenericException("product not found", http.StatusNotFound)
+		}
+	}
+	configs.Logger.Error(message, zap.Error(err))
+	return exceptions.GenericException(message, http.StatusInternalServerError)
+}
+
+// rollback rolls back a transaction that was not committed
+func rollback(ctx context.Context, tx pgx.Tx) {
+	if err := tx.Rollback(ctx); err != nil && err != pgx.ErrTxClosed {
+		configs.Logger.Error("failed to rollback transaction", zap.Error(err))
+	}
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
@@ -12,6 +13,7 @@ import (
 	"oolio.com/kart/exceptions"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"strings"
 )
 
 type OrderRepositoryImpl struct {
@@ -47,7 +49,7 @@ func (o *OrderRepositoryImpl) CreateOrder(ctx context.Context, order *models.Ord
 
 	orderQuery := `INSERT INTO orders (coupon_code, subtotal, discount, total, meta)
                    VALUES ($1, $2, $3, $4, $5)
-                   RETURNING id, created_at, modified_at`
+                   RETURNING id, status, version, created_at, modified_at`
 
 	err = tx.QueryRow(ctx, orderQuery,
 		order.CouponCode,
@@ -55,7 +57,7 @@ func (o *OrderRepositoryImpl) CreateOrder(ctx context.Context, order *models.Ord
 		order.Discount,
 		order.Total,
 		metaJSON,
-	).Scan(&order.Id, &order.CreatedAt, &order.ModifiedAt)
+	).Scan(&order.Id, &order.Status, &order.Version, &order.CreatedAt, &order.ModifiedAt)
 
 	if err != nil {
 		txErr := tx.Rollback(ctx)
@@ -66,6 +68,22 @@ func (o *OrderRepositoryImpl) CreateOrder(ctx context.Context, order *models.Ord
 		return exceptions.GenericException("failed to save order", http.StatusInternalServerError)
 	}
 
+	if errDetails := consumeStock(ctx, tx, order.Id, items); errDetails != nil {
+		txErr := tx.Rollback(ctx)
+		if txErr != nil {
+			configs.Logger.Error("failed to rollback transaction", zap.Error(txErr))
+		}
+		return errDetails
+	}
+
+	if errDetails := redeemCoupon(ctx, tx, order); errDetails != nil {
+		txErr := tx.Rollback(ctx)
+		if txErr != nil {
+			configs.Logger.Error("failed to rollback transaction", zap.Error(txErr))
+		}
+		return errDetails
+	}
+
 	if len(items) > 0 {
 		batch := &pgx.Batch{}
 
@@ -84,13 +102,14 @@ func (o *OrderRepositoryImpl) CreateOrder(ctx context.Context, order *models.Ord
 			}
 
 			batch.Queue(
-				`INSERT INTO order_items (order_id, product_id, quantity, unit_price, price, meta)
-				 VALUES ($1, $2, $3, $4, $5, $6)
+				`INSERT INTO order_items (order_id, product_id, quantity, unit_price, price_version, price, meta)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7)
 				 RETURNING id, created_at`,
 				order.Id,
 				items[i].ProductId,
 				items[i].Quantity,
 				items[i].UnitPrice,
+				items[i].PriceVersion,
 				items[i].Price,
 				itemMetaJSON,
 			)
@@ -120,6 +139,180 @@ func (o *OrderRepositoryImpl) CreateOrder(ctx context.Context, order *models.Ord
 			}
 			return exceptions.GenericException("failed to close batch results", http.StatusInternalServerError)
 		}
+
+		if errDetails := saveOrderItemModifiers(ctx, tx, items); errDetails != nil {
+			txErr := tx.Rollback(ctx)
+			if txErr != nil {
+				configs.Logger.Error("failed to rollback transaction", zap.Error(txErr))
+			}
+			return errDetails
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// GetOrderById Retrieves an order with its items and their modifiers by its ID from the database, items in the order
+// they were placed in
+func (o *OrderRepositoryImpl) GetOrderById(ctx context.Context, id string) (*models.Order, []models.OrderItem, *errors.ErrorDetails) {
+	query := `SELECT ` + orderColumns + `
+              FROM orders
+              WHERE id = $1`
+
+	order, err := scanOrder(o.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("order not found", zap.String("id", id))
+			return nil, nil, exceptions.GenericException("order not found", http.StatusNotFound)
+		}
+		configs.Logger.Error("failed to fetch order", zap.Error(err))
+		return nil, nil, exceptions.GenericException("failed to fetch order", http.StatusInternalServerError)
+	}
+
+	itemsQuery := `SELECT id, order_id, product_id, quantity, unit_price, COALESCE(price_version, 0), price, meta, created_at
+                   FROM order_items
+                   WHERE order_id = $1
+                   ORDER BY id`
+
+	rows, err := o.pool.Query(ctx, itemsQuery, id)
+	if err != nil {
+		configs.Logger.Error("failed to fetch order items", zap.Error(err))
+		return nil, nil, exceptions.GenericException("failed to fetch order items", http.StatusInternalServerError)
+	}
+
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.OrderItem, error) {
+		var item models.OrderItem
+		var itemMetaJSON []byte
+		err := row.Scan(&item.Id, &item.OrderId, &item.ProductId, &item.Quantity, &item.UnitPrice, &item.PriceVersion,
+			&item.Price, &itemMetaJSON, &item.CreatedAt)
+		if err == nil && itemMetaJSON != nil {
+			err = json.Unmarshal(itemMetaJSON, &item.Meta)
+		}
+		return item, err
+	})
+	if err != nil {
+		configs.Logger.Error("failed to scan order item", zap.Error(err))
+		return nil, nil, exceptions.GenericException("failed to fetch order items", http.StatusInternalServerError)
+	}
+
+	if errDetails := loadOrderItemModifiers(ctx, o.pool, id, items); errDetails != nil {
+		return nil, nil, errDetails
+	}
+
+	return order, items, nil
+}
+
+// ListOrders Retrieves the orders matching the filter and the total number of matches from the database
+func (o *OrderRepositoryImpl) ListOrders(ctx context.Context, filter *models.OrderFilter) ([]*models.Order, int64, *errors.ErrorDetails) {
+	where, args := buildOrderConditions(filter)
+
+	countQuery := "SELECT COUNT(*) FROM orders"
+	if len(where) > 0 {
+		countQuery += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var totalCount int64
+	if err := o.pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		configs.Logger.Error("failed to count orders", zap.Error(err))
+		return nil, 0, exceptions.GenericException("failed to count orders", http.StatusInternalServerError)
+	}
+
+	comparator := ">"
+	direction := "ASC"
+	if filter.Direction == models.SortDesc {
+		comparator = "<"
+		direction = "DESC"
+	}
+
+	sortColumn, ok := orderSortColumns[filter.Sort]
+	if !ok {
+		sortColumn = orderSortColumns[models.OrderSortCreatedAt]
+	}
+
+	// The ID breaks ties between orders placed at the same time or with the same total
+	if filter.After != nil {
+		args = append(args, filter.After.Value, filter.After.Id)
+		where = append(where, fmt.Sprintf("(%s, id) %s ($%d, $%d::uuid)", sortColumn, comparator, len(args)-1, len(args)))
+	}
+
+	query := `SELECT ` + orderColumns + `
+              FROM orders`
+
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", sortColumn, direction, direction, len(args))
+
+	rows, err := o.pool.Query(ctx, query, args...)
+	if err != nil {
+		configs.Logger.Error("failed to list orders", zap.Error(err))
+		return nil, 0, exceptions.GenericException("failed to fetch orders", http.StatusInternalServerError)
+	}
+
+	orders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Order, error) {
+		return scanOrder(row)
+	})
+	if err != nil {
+		configs.Logger.Error("failed to scan order", zap.Error(err))
+		return nil, 0, exceptions.GenericException("failed to fetch orders", http.StatusInternalServerError)
+	}
+
+	return orders, totalCount, nil
+}
+
+// TransitionOrderStatus Moves an order to the status of the change and records the change, as long as the order is
+// still at the version and in the status it was read with. Cancelling an order puts back the stock it took.
+func (o *OrderRepositoryImpl) TransitionOrderStatus(ctx context.Context, order *models.Order, change *models.OrderStatusChange) *errors.ErrorDetails {
+	tx, err := o.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	query := `UPDATE orders
+              SET status = $1,
+                  version = version + 1,
+                  modified_at = NOW()
+              WHERE id = $2 AND status = $3 AND version = $4
+              RETURNING version, modified_at`
+
+	err = tx.QueryRow(ctx, query, change.ToStatus, order.Id, change.FromStatus, order.Version).Scan(&order.Version, &order.ModifiedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("order status was changed concurrently", zap.String("id", order.Id))
+			return exceptions.GenericException("order was changed concurrently, reload it and retry", http.StatusConflict)
+		}
+		configs.Logger.Error("failed to update order status", zap.Error(err))
+		return exceptions.GenericException("failed to update order status", http.StatusInternalServerError)
+	}
+
+	historyQuery := `INSERT INTO order_status_history (order_id, from_status, to_status, actor, reason)
+                     VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+                     RETURNING id, created_at`
+
+	err = tx.QueryRow(ctx, historyQuery, order.Id, change.FromStatus, change.ToStatus, change.Actor, change.Reason).
+		Scan(&change.Id, &change.CreatedAt)
+	if err != nil {
+		configs.Logger.Error("failed to record order status change", zap.Error(err))
+		return exceptions.GenericException("failed to update order status", http.StatusInternalServerError)
+	}
+
+	// Cancelled orders give back the stock and the coupon use they took
+	if change.ToStatus == models.OrderStatusCancelled {
+		if errDetails := restoreStock(ctx, tx, order.Id, nil, models.StockReasonOrderCancelled, change.Actor); errDetails != nil {
+			return errDetails
+		}
+		if errDetails := releaseCoupon(ctx, tx, order.Id); errDetails != nil {
+			return errDetails
+		}
 	}
 
 	if err = tx.Commit(ctx); err != nil {
@@ -127,5 +320,180 @@ func (o *OrderRepositoryImpl) CreateOrder(ctx context.Context, order *models.Ord
 		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
 	}
 
+	order.Status = change.ToStatus
+	change.OrderId = order.Id
+	return nil
+}
+
+// GetStatusHistory Retrieves the status changes of an order from the database, oldest first
+func (o *OrderRepositoryImpl) GetStatusHistory(ctx context.Context, orderId string) ([]*models.OrderStatusChange, *errors.ErrorDetails) {
+	var exists bool
+	if err := o.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, orderId).Scan(&exists); err != nil {
+		configs.Logger.Error("failed to fetch order", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch order status history", http.StatusInternalServerError)
+	}
+	if !exists {
+		configs.Logger.Error("order not found", zap.String("id", orderId))
+		return nil, exceptions.GenericException("order not found", http.StatusNotFound)
+	}
+
+	query := `SELECT id, order_id, from_status, to_status, actor, COALESCE(reason, ''), created_at
+              FROM order_status_history
+              WHERE order_id = $1
+              ORDER BY id`
+
+	rows, err := o.pool.Query(ctx, query, orderId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch order status history", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch order status history", http.StatusInternalServerError)
+	}
+
+	changes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.OrderStatusChange, error) {
+		change := &models.OrderStatusChange{}
+		err := row.Scan(&change.Id, &change.OrderId, &change.FromStatus, &change.ToStatus, &change.Actor, &change.Reason,
+			&change.CreatedAt)
+		return change, err
+	})
+	if err != nil {
+		configs.Logger.Error("failed to scan order status change", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch order status history", http.StatusInternalServerError)
+	}
+
+	return changes, nil
+}
+
+// orderColumns are the columns scanOrder reads, the amounts of orders placed before they were recorded read as zero
+const orderColumns = `id, COALESCE(coupon_code, ''), status, version, COALESCE(subtotal, 0), COALESCE(discount, 0),
+                      COALESCE(total, 0), meta, created_at, modified_at`
+
+// scanOrder scans a row of orderColumns into an order
+func scanOrder(row pgx.Row) (*models.Order, error) {
+	order := &models.Order{}
+	var metaJSON []byte
+	err := row.Scan(&order.Id, &order.CouponCode, &order.Status, &order.Version, &order.Subtotal, &order.Discount,
+		&order.Total, &metaJSON, &order.CreatedAt, &order.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if metaJSON != nil {
+		if err = json.Unmarshal(metaJSON, &order.Meta); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// orderSortColumns maps the supported sort keys to their columns, only these values are ever interpolated into SQL
+var orderSortColumns = map[string]string{
+	models.OrderSortCreatedAt: "created_at",
+	models.OrderSortTotal:     "COALESCE(total, 0)",
+}
+
+// buildOrderConditions translates the filter into SQL conditions and their arguments
+func buildOrderConditions(filter *models.OrderFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+
+	if filter.CouponCode != "" {
+		addCondition("coupon_code = $%d", filter.CouponCode)
+	}
+
+	if filter.MinTotal != nil {
+		addCondition("COALESCE(total, 0) >= $%d", *filter.MinTotal)
+	}
+
+	if filter.MaxTotal != nil {
+		addCondition("COALESCE(total, 0) <= $%d", *filter.MaxTotal)
+	}
+
+	if len(filter.Statuses) > 0 {
+		addCondition("status = ANY($%d)", filter.Statuses)
+	}
+
+	return conditions, args
+}
+
+// loadOrderItemModifiers loads the modifiers chosen for the items of an order into them. A modifier deleted since the
+// order was placed has no ID anymore but keeps its name and price.
+func loadOrderItemModifiers(ctx context.Context, pool *pgxpool.Pool, orderId string, items []models.OrderItem) *errors.ErrorDetails {
+	query := `SELECT m.order_item_id, COALESCE(m.modifier_id, 0), m.group_name, m.name, m.price_delta
+              FROM order_item_modifiers m
+              JOIN order_items i ON i.id = m.order_item_id
+              WHERE i.order_id = $1
+              ORDER BY m.id`
+
+	rows, err := pool.Query(ctx, query, orderId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch order item modifiers", zap.Error(err))
+		return exceptions.GenericException("failed to fetch order item modifiers", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	itemIndexes := make(map[int64]int, len(items))
+	for i := range items {
+		itemIndexes[items[i].Id] = i
+	}
+
+	for rows.Next() {
+		var itemId int64
+		var modifier models.OrderItemModifier
+		if err = rows.Scan(&itemId, &modifier.ModifierId, &modifier.GroupName, &modifier.Name, &modifier.PriceDelta); err != nil {
+			configs.Logger.Error("failed to scan order item modifier", zap.Error(err))
+			return exceptions.GenericException("failed to fetch order item modifiers", http.StatusInternalServerError)
+		}
+
+		if i, found := itemIndexes[itemId]; found {
+			items[i].Modifiers = append(items[i].Modifiers, modifier)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("failed to fetch order item modifiers", zap.Error(err))
+		return exceptions.GenericException("failed to fetch order item modifiers", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// saveOrderItemModifiers saves the modifiers chosen for the already saved order items
+func saveOrderItemModifiers(ctx context.Context, tx pgx.Tx, items []models.OrderItem) *errors.ErrorDetails {
+	batch := &pgx.Batch{}
+	for i := range items {
+		for _, modifier := range items[i].Modifiers {
+			batch.Queue(
+				`INSERT INTO order_item_modifiers (order_item_id, modifier_id, group_name, name, price_delta)
+				 VALUES ($1, $2, $3, $4, $5)`,
+				items[i].Id,
+				modifier.ModifierId,
+				modifier.GroupName,
+				modifier.Name,
+				modifier.PriceDelta,
+			)
+		}
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		configs.Logger.Error("failed to save order item modifiers", zap.Error(err))
+		return exceptions.GenericException("failed to save order item modifiers", http.StatusInternalServerError)
+	}
+
 	return nil
 }
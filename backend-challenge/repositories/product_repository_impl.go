@@ -10,12 +10,24 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"oolio.com/kart/configs"
+	"strings"
+	"time"
 
 	"oolio.com/kart/exceptions"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
 )
 
+// productColumns is the column list read by scanProduct, selected from productTable
+const productColumns = `p.id, p.name, p.description, p.category_id, c.path, p.price, p.price_version, p.status, p.stock_quantity, p.image, p.allergens, p.diets, p.meta, p.created_at, p.modified_at, p.deleted_at, ` + inactiveCategory
+
+// inactiveCategory tells whether the category c of a product or one of its ancestors, the categories whose path
+// prefixes its path, is inactive
+const inactiveCategory = `EXISTS (SELECT 1 FROM categories a WHERE NOT a.active AND (a.id = c.id OR starts_with(c.path, a.path || ' > ')))`
+
+// productTable joins the products with their category, products are aliased p and categories c
+const productTable = `products p JOIN categories c ON c.id = p.category_id`
+
 type ProductRepositoryImpl struct {
 	pool *pgxpool.Pool
 }
@@ -41,23 +53,30 @@ func (p *ProductRepositoryImpl) Save(ctx context.Context, product *models.Produc
 		}
 	}
 
-	query := `INSERT INTO products (name, category, price, status, image, meta)
-              VALUES ($1, $2, $3, $4, $5, $6)
-              RETURNING id, created_at, modified_at`
+	query := `INSERT INTO products (name, description, category_id, price, status, image, allergens, diets, meta)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+              RETURNING id, price_version, created_at, modified_at`
 
 	err = p.pool.QueryRow(ctx, query,
 		product.Name,
-		product.Category,
+		product.Description,
+		product.CategoryId,
 		product.Price,
 		product.Status,
 		imageJSON,
+		nonNil(product.Allergens),
+		nonNil(product.Diets),
 		metaJSON,
-	).Scan(&product.Id, &product.CreatedAt, &product.ModifiedAt)
+	).Scan(&product.Id, &product.PriceVersion, &product.CreatedAt, &product.ModifiedAt)
 
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
-			configs.Logger.Error("product already exists", zap.Error(err))
-			return exceptions.BadRequestException("product already exists")
+			configs.Logger.Error("product with this name and category already exists", zap.Error(err))
+			return exceptions.GenericException("product with this name and category already exists", http.StatusConflict)
+		}
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			configs.Logger.Error("product category not found", zap.Error(err))
+			return exceptions.UnprocessableEntityException("product category not found")
 		}
 		configs.Logger.Error("failed to save product", zap.Error(err))
 		return exceptions.GenericException("failed to save product", http.StatusInternalServerError)
@@ -90,24 +109,30 @@ func (p *ProductRepositoryImpl) Update(ctx context.Context, product *models.Prod
 
 	query := `UPDATE products
               SET name = $1,
-                  category = $2,
-                  price = $3,
-                  status = $4,
-                  image = $5,
-                  meta = $6,
+                  description = $2,
+                  category_id = $3,
+                  price = $4,
+                  status = $5,
+                  image = $6,
+                  allergens = $7,
+                  diets = $8,
+                  meta = $9,
                   modified_at = NOW()
-              WHERE id = $7
-              RETURNING created_at, modified_at`
+              WHERE id = $10 AND deleted_at IS NULL
+              RETURNING price_version, created_at, modified_at`
 
 	err = p.pool.QueryRow(ctx, query,
 		product.Name,
-		product.Category,
+		product.Description,
+		product.CategoryId,
 		product.Price,
 		product.Status,
 		imageJSON,
+		nonNil(product.Allergens),
+		nonNil(product.Diets),
 		metaJSON,
 		product.Id,
-	).Scan(&product.CreatedAt, &product.ModifiedAt)
+	).Scan(&product.PriceVersion, &product.CreatedAt, &product.ModifiedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -118,6 +143,10 @@ func (p *ProductRepositoryImpl) Update(ctx context.Context, product *models.Prod
 			configs.Logger.Error("product with this name and category already exists", zap.Error(err))
 			return exceptions.GenericException("product with this name and category already exists", http.StatusConflict)
 		}
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			configs.Logger.Error("product category not found", zap.Error(err))
+			return exceptions.UnprocessableEntityException("product category not found")
+		}
 		configs.Logger.Error("failed to update product", zap.Error(err))
 		return exceptions.GenericException("failed to update product", http.StatusInternalServerError)
 	}
@@ -125,11 +154,105 @@ func (p *ProductRepositoryImpl) Update(ctx context.Context, product *models.Prod
 	return nil
 }
 
+// UpdateStatus Sets the status of a product as long as it still has the expected status
+func (p *ProductRepositoryImpl) UpdateStatus(ctx context.Context, product *models.Product, expectedStatus string) *errors.ErrorDetails {
+	query := `UPDATE products
+              SET status = $1,
+                  modified_at = NOW()
+              WHERE id = $2 AND status = $3 AND deleted_at IS NULL
+              RETURNING modified_at`
+
+	err := p.pool.QueryRow(ctx, query, product.Status, product.Id, expectedStatus).Scan(&product.ModifiedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("product status was changed concurrently", zap.Int64("id", product.Id))
+			return exceptions.GenericException("product status was changed concurrently", http.StatusConflict)
+		}
+		configs.Logger.Error("failed to update product status", zap.Error(err))
+		return exceptions.GenericException("failed to update product status", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// UpdateImage Sets the image of a product without touching its other fields
+func (p *ProductRepositoryImpl) UpdateImage(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	imageJSON, err := json.Marshal(product.Image)
+	if err != nil {
+		configs.Logger.Error("failed to marshal product image", zap.Error(err))
+		return exceptions.GenericException("failed to marshal product image", http.StatusInternalServerError)
+	}
+
+	query := `UPDATE products
+              SET image = $1,
+                  modified_at = NOW()
+              WHERE id = $2 AND deleted_at IS NULL
+              RETURNING modified_at`
+
+	err = p.pool.QueryRow(ctx, query, imageJSON, product.Id).Scan(&product.ModifiedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("product not found", zap.Int64("id", product.Id))
+			return exceptions.GenericException("product not found", http.StatusNotFound)
+		}
+		configs.Logger.Error("failed to update product image", zap.Error(err))
+		return exceptions.GenericException("failed to update product image", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// Delete Marks a product as deleted, the row is kept for the orders referring to it
+func (p *ProductRepositoryImpl) Delete(ctx context.Context, id int64) *errors.ErrorDetails {
+	query := `UPDATE products
+              SET deleted_at = NOW(),
+                  modified_at = NOW()
+              WHERE id = $1 AND deleted_at IS NULL`
+
+	tag, err := p.pool.Exec(ctx, query, id)
+	if err != nil {
+		configs.Logger.Error("failed to delete product", zap.Error(err))
+		return exceptions.GenericException("failed to delete product", http.StatusInternalServerError)
+	}
+
+	if tag.RowsAffected() == 0 {
+		configs.Logger.Error("product not found", zap.Int64("id", id))
+		return exceptions.GenericException("product not found", http.StatusNotFound)
+	}
+
+	return nil
+}
+
+// Restore Puts a deleted product back on the menu
+func (p *ProductRepositoryImpl) Restore(ctx context.Context, id int64) *errors.ErrorDetails {
+	query := `UPDATE products
+              SET deleted_at = NULL,
+                  modified_at = NOW()
+              WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	tag, err := p.pool.Exec(ctx, query, id)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			configs.Logger.Error("product with this name and category already exists", zap.Error(err))
+			return exceptions.GenericException("product with this name and category already exists", http.StatusConflict)
+		}
+		configs.Logger.Error("failed to restore product", zap.Error(err))
+		return exceptions.GenericException("failed to restore product", http.StatusInternalServerError)
+	}
+
+	if tag.RowsAffected() == 0 {
+		configs.Logger.Error("deleted product not found", zap.Int64("id", id))
+		return exceptions.GenericException("deleted product not found", http.StatusNotFound)
+	}
+
+	return nil
+}
+
 // GetById Retrieves a product by its ID from the database
 func (p *ProductRepositoryImpl) GetById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
-	query := `SELECT id, name, category, price, status, image, meta, created_at, modified_at
-              FROM products
-              WHERE id = $1`
+	query := `SELECT ` + productColumns + `
+              FROM ` + productTable + `
+              WHERE p.id = $1 AND p.deleted_at IS NULL`
 
 	row := p.pool.QueryRow(ctx, query, id)
 	product, err := scanProduct(row)
@@ -145,53 +268,140 @@ func (p *ProductRepositoryImpl) GetById(ctx context.Context, id int64) (*models.
 	return product, nil
 }
 
-// ListProducts Retrieves a list of products from the database
-func (p *ProductRepositoryImpl) ListProducts(ctx context.Context, offset, limit *int) []*models.Product {
-	query := `SELECT id, name, category, price, status, image, meta, created_at, modified_at
-              FROM products
-              ORDER BY id`
+// GetLastModified Retrieves when a product or its category was last changed
+func (p *ProductRepositoryImpl) GetLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails) {
+	query := `SELECT GREATEST(p.modified_at, c.modified_at)
+              FROM ` + productTable + `
+              WHERE p.id = $1 AND p.deleted_at IS NULL`
 
-	var args []any
-	paramIdx := 1
+	var lastModified time.Time
+	if err := p.pool.QueryRow(ctx, query, id).Scan(&lastModified); err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("product not found", zap.Int64("id", id))
+			return time.Time{}, exceptions.GenericException("product not found", http.StatusNotFound)
+		}
+		configs.Logger.Error("failed to fetch product modification time", zap.Error(err))
+		return time.Time{}, exceptions.GenericException("failed to fetch product", http.StatusInternalServerError)
+	}
+
+	return lastModified, nil
+}
+
+// GetCatalogLastModified Retrieves when any product or category was last created, changed or when a product was last
+// deleted, the zero time when the catalog was never touched
+func (p *ProductRepositoryImpl) GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails) {
+	query := `SELECT GREATEST(
+                  (SELECT MAX(modified_at) FROM products),
+                  (SELECT MAX(modified_at) FROM categories),
+                  (SELECT deleted_at FROM product_deletions)
+              )`
+
+	var lastModified *time.Time
+	if err := p.pool.QueryRow(ctx, query).Scan(&lastModified); err != nil {
+		configs.Logger.Error("failed to fetch catalog modification time", zap.Error(err))
+		return time.Time{}, exceptions.GenericException("failed to fetch products", http.StatusInternalServerError)
+	}
+
+	if lastModified == nil {
+		return time.Time{}, nil
+	}
+	return *lastModified, nil
+}
+
+// ListProducts Retrieves the products matching the filter and the total number of matches from the database
+func (p *ProductRepositoryImpl) ListProducts(ctx context.Context, filter *models.ProductFilter) ([]*models.Product, int64, *errors.ErrorDetails) {
+	where, args := buildProductConditions(filter)
+
+	countQuery := "SELECT COUNT(*) FROM " + productTable
+	if len(where) > 0 {
+		countQuery += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var totalCount int64
+	if err := p.pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		configs.Logger.Error("failed to count products", zap.Error(err))
+		return nil, 0, exceptions.GenericException("failed to count products", http.StatusInternalServerError)
+	}
 
-	if offset != nil {
+	comparator := ">"
+	direction := "ASC"
+	if filter.Direction == models.SortDesc {
+		comparator = "<"
+		direction = "DESC"
+	}
+
+	sortColumn, ok := productSortColumns[filter.Sort]
+	if !ok {
+		sortColumn = productSortColumns[models.ProductSortId]
+	}
+
+	if filter.After != nil {
+		if sortColumn == "p.id" {
+			args = append(args, filter.After.Id)
+			where = append(where, fmt.Sprintf("p.id %s $%d", comparator, len(args)))
+		} else {
+			args = append(args, filter.After.Value, filter.After.Id)
+			where = append(where, fmt.Sprintf("(%s, p.id) %s ($%d, $%d)", sortColumn, comparator, len(args)-1, len(args)))
+		}
+	}
+
+	query := `SELECT ` + productColumns + `
+              FROM ` + productTable
+
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	if sortColumn == "p.id" {
+		query += fmt.Sprintf(" ORDER BY p.id %s", direction)
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s %s, p.id %s", sortColumn, direction, direction)
+	}
+
+	paramIdx := len(args) + 1
+
+	if filter.Offset != nil {
 		query += fmt.Sprintf(" OFFSET $%d", paramIdx)
-		args = append(args, *offset)
+		args = append(args, *filter.Offset)
 		paramIdx++
 	}
 
-	if limit != nil {
+	if filter.Limit != nil {
 		query += fmt.Sprintf(" LIMIT $%d", paramIdx)
-		args = append(args, *limit)
+		args = append(args, *filter.Limit)
 	}
 
 	rows, err := p.pool.Query(ctx, query, args...)
 	if err != nil {
-		return []*models.Product{}
+		configs.Logger.Error("failed to list products", zap.Error(err))
+		return nil, 0, exceptions.GenericException("failed to fetch products", http.StatusInternalServerError)
 	}
 	defer rows.Close()
 
-	var products []*models.Product
+	products := []*models.Product{}
 	for rows.Next() {
 		product, scanErr := scanProduct(rows)
 		if scanErr != nil {
+			configs.Logger.Error("failed to scan product", zap.Error(scanErr))
 			continue
 		}
 		products = append(products, product)
 	}
 
-	if rows.Err() != nil {
-		return []*models.Product{}
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading products", zap.Error(err))
+		return nil, 0, exceptions.GenericException("failed to fetch products", http.StatusInternalServerError)
 	}
 
-	return products
+	return products, totalCount, nil
 }
 
-// GetByIds Retrieves a list of products by their IDs from the database
+// GetByIds Retrieves a list of products by their IDs from the database, deleted products included so that the items
+// of past orders can still be resolved
 func (p *ProductRepositoryImpl) GetByIds(ctx context.Context, ids []int64) ([]*models.Product, *errors.ErrorDetails) {
-	query := `SELECT id, name, category, price, status, image, meta, created_at, modified_at
-              FROM products
-              WHERE id = ANY($1)`
+	query := `SELECT ` + productColumns + `
+              FROM ` + productTable + `
+              WHERE p.id = ANY($1)`
 
 	rows, err := p.pool.Query(ctx, query, ids)
 	if err != nil {
@@ -215,6 +425,118 @@ func (p *ProductRepositoryImpl) GetByIds(ctx context.Context, ids []int64) ([]*m
 	return products, nil
 }
 
+// GetPriceHistory Retrieves the price history of a product from the database, newest version first
+func (p *ProductRepositoryImpl) GetPriceHistory(ctx context.Context, productId int64) ([]*models.ProductPrice, *errors.ErrorDetails) {
+	query := `SELECT product_id, version, price, effective_from,
+                     LEAD(effective_from) OVER (ORDER BY version) AS effective_to
+              FROM product_price_history
+              WHERE product_id = $1
+              ORDER BY version DESC`
+
+	rows, err := p.pool.Query(ctx, query, productId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch product price history", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch product price history", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	prices := []*models.ProductPrice{}
+	for rows.Next() {
+		price := &models.ProductPrice{}
+		if err = rows.Scan(&price.ProductId, &price.Version, &price.Price, &price.EffectiveFrom, &price.EffectiveTo); err != nil {
+			configs.Logger.Error("failed to scan product price", zap.Error(err))
+			return nil, exceptions.GenericException("failed to fetch product price history", http.StatusInternalServerError)
+		}
+		prices = append(prices, price)
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading product price history", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch product price history", http.StatusInternalServerError)
+	}
+
+	return prices, nil
+}
+
+// productSortColumns maps the supported sort keys to their columns, only these values are ever interpolated into SQL
+var productSortColumns = map[string]string{
+	models.ProductSortId:        "p.id",
+	models.ProductSortPrice:     "p.price",
+	models.ProductSortName:      "p.name",
+	models.ProductSortCreatedAt: "p.created_at",
+}
+
+// buildProductConditions builds the parameterized WHERE conditions for a product filter
+func buildProductConditions(filter *models.ProductFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	// A category matches its own products and the products of its subcategories
+	if filter.Category != "" {
+		addCondition("(c.path = $%[1]d OR starts_with(c.path, $%[1]d || ' > '))", filter.Category)
+	}
+
+	if filter.CategoryId != nil {
+		addCondition("(c.id = $%[1]d OR starts_with(c.path, (SELECT path FROM categories WHERE id = $%[1]d) || ' > '))", *filter.CategoryId)
+	}
+
+	// The menu leaves out the products of inactive categories and their subcategories, as the category tree does
+	if filter.Deleted {
+		conditions = append(conditions, "p.deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "p.deleted_at IS NULL", "NOT "+inactiveCategory)
+	}
+
+	if filter.Status != "" {
+		addCondition("p.status = $%d", filter.Status)
+	}
+
+	if filter.MinPrice != nil {
+		addCondition("p.price >= $%d", *filter.MinPrice)
+	}
+
+	if filter.MaxPrice != nil {
+		addCondition("p.price <= $%d", *filter.MaxPrice)
+	}
+
+	if filter.Query != "" {
+		addCondition(`p.name ILIKE $%d ESCAPE '\'`, "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+
+	// Availability windows are wall clock times of the store
+	if filter.AvailableAt != nil {
+		args = append(args, *filter.AvailableAt, configs.StoreLocation.String())
+		conditions = append(conditions, fmt.Sprintf("product_available_at(p.id, $%d::timestamptz AT TIME ZONE $%d::text)", len(args)-1, len(args)))
+	}
+
+	// Both are containment queries served by the GIN indexes of absent_allergens(allergens) and diets
+	if len(filter.ExcludeAllergens) > 0 {
+		addCondition("absent_allergens(p.allergens) @> $%d::text[]", filter.ExcludeAllergens)
+	}
+
+	if len(filter.Diets) > 0 {
+		addCondition("p.diets @> $%d::text[]", filter.Diets)
+	}
+
+	return conditions, args
+}
+
+// nonNil returns an empty slice for nil, the array columns of the products are NOT NULL
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// likeEscaper escapes the LIKE wildcards so that search terms are matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func scanProduct(row pgx.Row) (*models.Product, error) {
 	var (
 		imageBytes []byte
@@ -226,13 +548,21 @@ func scanProduct(row pgx.Row) (*models.Product, error) {
 	err := row.Scan(
 		&product.Id,
 		&product.Name,
+		&product.Description,
+		&product.CategoryId,
 		&product.Category,
 		&product.Price,
+		&product.PriceVersion,
 		&product.Status,
+		&product.StockQuantity,
 		&imageBytes,
+		&product.Allergens,
+		&product.Diets,
 		&metaBytes,
 		&product.CreatedAt,
 		&product.ModifiedAt,
+		&product.DeletedAt,
+		&product.CategoryInactive,
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"time"
+)
+
+type RecommendationRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewRecommendationRepositoryImpl creates a new instance of RecommendationRepositoryImpl
+func NewRecommendationRepositoryImpl(pool *pgxpool.Pool) *RecommendationRepositoryImpl {
+	return &RecommendationRepositoryImpl{pool: pool}
+}
+
+// GetRecommendations Retrieves the recommendations for the products from the database, best score first
+func (r *RecommendationRepositoryImpl) GetRecommendations(ctx context.Context, productIds []int64) ([]*models.Recommendation, *errors.ErrorDetails) {
+	query := `SELECT product_id, recommended_product_id, score, order_count, computed_at
+              FROM product_recommendations
+              WHERE product_id = ANY($1)
+              ORDER BY score DESC, recommended_product_id`
+
+	rows, err := r.pool.Query(ctx, query, productIds)
+	if err != nil {
+		configs.Logger.Error("failed to fetch recommendations", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch recommendations", http.StatusInternalServerError)
+	}
+
+	recommendations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Recommendation, error) {
+		recommendation := &models.Recommendation{}
+		err := row.Scan(&recommendation.ProductId, &recommendation.RecommendedProductId, &recommendation.Score,
+			&recommendation.OrderCount, &recommendation.ComputedAt)
+		return recommendation, err
+	})
+	if err != nil {
+		configs.Logger.Error("failed to scan recommendation", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch recommendations", http.StatusInternalServerError)
+	}
+
+	return recommendations, nil
+}
+
+// RefreshRecommendations Recomputes the recommendations from the co-occurrences of the products in the recent orders.
+// The recommendations are replaced in one transaction, so readers never see them half computed, and an advisory lock
+// keeps the instances of the API from computing them at the same time.
+func (r *RecommendationRepositoryImpl) RefreshRecommendations(ctx context.Context, since time.Time, minOrders int, perProduct int) (int64, bool, *errors.ErrorDetails) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return 0, false, exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	var locked bool
+	if err = tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext('kart.product_recommendations'))").Scan(&locked); err != nil {
+		configs.Logger.Error("failed to lock recommendations", zap.Error(err))
+		return 0, false, exceptions.GenericException("failed to refresh recommendations", http.StatusInternalServerError)
+	}
+	if !locked {
+		return 0, false, nil
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM product_recommendations"); err != nil {
+		configs.Logger.Error("failed to delete recommendations", zap.Error(err))
+		return 0, false, exceptions.GenericException("failed to refresh recommendations", http.StatusInternalServerError)
+	}
+
+	// A product ordered twice in an order counts once, the score of a pair is the number of orders with both products
+	// over the geometric mean of the numbers of orders with each
+	query := `WITH recent_items AS (
+                  SELECT DISTINCT i.order_id, i.product_id
+                  FROM order_items i
+                  JOIN orders o ON o.id = i.order_id
+                  WHERE o.created_at >= $1
+              ),
+              product_orders AS (
+                  SELECT product_id, COUNT(*) AS order_count
+                  FROM recent_items
+                  GROUP BY product_id
+              ),
+              pairs AS (
+                  SELECT a.product_id, b.product_id AS recommended_product_id, COUNT(*) AS order_count
+                  FROM recent_items a
+                  JOIN recent_items b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+                  GROUP BY a.product_id, b.product_id
+                  HAVING COUNT(*) >= $2
+              ),
+              scored AS (
+                  SELECT p.product_id, p.recommended_product_id, p.order_count,
+                         p.order_count / SQRT(pa.order_count::DOUBLE PRECISION * pb.order_count) AS score
+                  FROM pairs p
+                  JOIN product_orders pa ON pa.product_id = p.product_id
+                  JOIN product_orders pb ON pb.product_id = p.recommended_product_id
+              ),
+              ranked AS (
+                  SELECT *, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY score DESC, recommended_product_id) AS rank
+                  FROM scored
+              )
+              INSERT INTO product_recommendations (product_id, recommended_product_id, score, order_count)
+              SELECT product_id, recommended_product_id, LEAST(score, 1), order_count
+              FROM ranked
+              WHERE rank <= $3`
+
+	tag, err := tx.Exec(ctx, query, since, minOrders, perProduct)
+	if err != nil {
+		configs.Logger.Error("failed to compute recommendations", zap.Error(err))
+		return 0, false, exceptions.GenericException("failed to refresh recommendations", http.StatusInternalServerError)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return 0, false, exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return tag.RowsAffected(), true, nil
+}
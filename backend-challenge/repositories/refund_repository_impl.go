@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type RefundRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewRefundRepositoryImpl creates a new instance of RefundRepositoryImpl
+func NewRefundRepositoryImpl(pool *pgxpool.Pool) *RefundRepositoryImpl {
+	return &RefundRepositoryImpl{pool: pool}
+}
+
+// CreateRefund Records a refund of a completed order and its items, as long as the order still has the number of
+// refunds the refund was priced against. Restocking refunds put the refunded quantities back in stock.
+func (r *RefundRepositoryImpl) CreateRefund(ctx context.Context, refund *models.Refund, previousRefunds int) *errors.ErrorDetails {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	// Refunds of an order are serialized on the order row, so that two refunds cannot both give back the same items.
+	// They are counted once the lock is held, the statement then sees every refund committed before.
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, refund.OrderId).Scan(&status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("order not found", zap.String("id", refund.OrderId))
+			return exceptions.GenericException("order not found", http.StatusNotFound)
+		}
+		configs.Logger.Error("failed to lock order", zap.Error(err))
+		return exceptions.GenericException("failed to save refund", http.StatusInternalServerError)
+	}
+
+	var refunds int
+	if err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM order_refunds WHERE order_id = $1`, refund.OrderId).Scan(&refunds); err != nil {
+		configs.Logger.Error("failed to count refunds", zap.Error(err))
+		return exceptions.GenericException("failed to save refund", http.StatusInternalServerError)
+	}
+
+	if status != models.OrderStatusCompleted || refunds != previousRefunds {
+		configs.Logger.Error("order was refunded concurrently", zap.String("id", refund.OrderId))
+		return exceptions.GenericException("order was changed concurrently, reload it and retry", http.StatusConflict)
+	}
+
+	err = tx.QueryRow(ctx, `INSERT INTO order_refunds (order_id, amount, reason, actor, restock)
+                            VALUES ($1, $2, $3, $4, $5)
+                            RETURNING id, created_at`,
+		refund.OrderId, refund.Amount, nullIfEmpty(refund.Reason), refund.Actor, refund.Restock).Scan(&refund.Id, &refund.CreatedAt)
+	if err != nil {
+		configs.Logger.Error("failed to save refund", zap.Error(err))
+		return exceptions.GenericException("failed to save refund", http.StatusInternalServerError)
+	}
+
+	batch := &pgx.Batch{}
+	quantities := make(map[int64]int)
+	for _, item := range refund.Items {
+		batch.Queue(`INSERT INTO order_refund_items (refund_id, order_item_id, quantity, amount) VALUES ($1, $2, $3, $4)`,
+			refund.Id, item.OrderItemId, item.Quantity, item.Amount)
+		quantities[item.ProductId] += item.Quantity
+	}
+
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		configs.Logger.Error("failed to save refund items", zap.Error(err))
+		return exceptions.GenericException("failed to save refund", http.StatusInternalServerError)
+	}
+
+	if refund.Restock {
+		if errDetails := restoreStock(ctx, tx, refund.OrderId, quantities, models.StockReasonRefund, refund.Actor); errDetails != nil {
+			return errDetails
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// GetRefundsByOrderId Retrieves the refunds of an order with their items from the database, oldest first
+func (r *RefundRepositoryImpl) GetRefundsByOrderId(ctx context.Context, orderId string) ([]*models.Refund, *errors.ErrorDetails) {
+	rows, err := r.pool.Query(ctx, `SELECT id, order_id, amount, COALESCE(reason, ''), actor, restock, created_at
+                                    FROM order_refunds
+                                    WHERE order_id = $1
+                                    ORDER BY id`, orderId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch refunds", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch refunds", http.StatusInternalServerError)
+	}
+
+	refunds, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Refund, error) {
+		refund := &models.Refund{Items: []models.RefundItem{}}
+		err := row.Scan(&refund.Id, &refund.OrderId, &refund.Amount, &refund.Reason, &refund.Actor, &refund.Restock,
+			&refund.CreatedAt)
+		return refund, err
+	})
+	if err != nil {
+		configs.Logger.Error("failed to scan refund", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch refunds", http.StatusInternalServerError)
+	}
+
+	if len(refunds) == 0 {
+		return refunds, nil
+	}
+
+	rows, err = r.pool.Query(ctx, `SELECT ri.refund_id, ri.order_item_id, i.product_id, ri.quantity, ri.amount
+                                   FROM order_refund_items ri
+                                   JOIN order_refunds r ON r.id = ri.refund_id
+                                   JOIN order_items i ON i.id = ri.order_item_id
+                                   WHERE r.order_id = $1
+                                   ORDER BY ri.refund_id, ri.order_item_id`, orderId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch refund items", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch refunds", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	refundIndexes := make(map[int64]int, len(refunds))
+	for i, refund := range refunds {
+		refundIndexes[refund.Id] = i
+	}
+
+	for rows.Next() {
+		var refundId int64
+		var item models.RefundItem
+		if err = rows.Scan(&refundId, &item.OrderItemId, &item.ProductId, &item.Quantity, &item.Amount); err != nil {
+			configs.Logger.Error("failed to scan refund item", zap.Error(err))
+			return nil, exceptions.GenericException("failed to fetch refunds", http.StatusInternalServerError)
+		}
+
+		if i, found := refundIndexes[refundId]; found {
+			refunds[i].Items = append(refunds[i].Items, item)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading refund items", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch refunds", http.StatusInternalServerError)
+	}
+
+	return refunds, nil
+}
@@ -0,0 +1,313 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"strconv"
+
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type StockRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewStockRepositoryImpl creates a new instance of StockRepositoryImpl
+func NewStockRepositoryImpl(pool *pgxpool.Pool) *StockRepositoryImpl {
+	return &StockRepositoryImpl{pool: pool}
+}
+
+// AdjustStock applies a stock adjustment to a product and records it in the audit trail
+func (s *StockRepositoryImpl) AdjustStock(ctx context.Context, adjustment *models.StockAdjustment) *errors.ErrorDetails {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	var current *int
+	err = tx.QueryRow(ctx, "SELECT stock_quantity FROM products WHERE id = $1 FOR UPDATE", adjustment.ProductId).Scan(&current)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("product not found", zap.Int64("id", adjustment.ProductId))
+			return exceptions.GenericException("product not found", http.StatusNotFound)
+		}
+		configs.Logger.Error("failed to lock product stock", zap.Error(err))
+		return exceptions.GenericException("failed to adjust stock", http.StatusInternalServerError)
+	}
+
+	if adjustment.Delta != nil {
+		if current == nil {
+			configs.Logger.Error("cannot adjust unlimited stock", zap.Int64("id", adjustment.ProductId))
+			return exceptions.UnprocessableEntityException("stock of this product is unlimited, set a quantity first")
+		}
+
+		quantityAfter := *current + *adjustment.Delta
+		if quantityAfter < 0 {
+			configs.Logger.Error("insufficient stock", zap.Int64("id", adjustment.ProductId), zap.Int("stock", *current))
+			return exceptions.InsufficientStockException([]errors.ItemError{insufficientStockItem(adjustment.ProductId, *current)})
+		}
+		adjustment.QuantityAfter = &quantityAfter
+	} else if current != nil && adjustment.QuantityAfter != nil {
+		delta := *adjustment.QuantityAfter - *current
+		adjustment.Delta = &delta
+	}
+
+	if _, err = tx.Exec(ctx, "UPDATE products SET stock_quantity = $1 WHERE id = $2", adjustment.QuantityAfter, adjustment.ProductId); err != nil {
+		configs.Logger.Error("failed to update product stock", zap.Error(err))
+		return exceptions.GenericException("failed to adjust stock", http.StatusInternalServerError)
+	}
+
+	err = tx.QueryRow(ctx, insertStockAdjustmentQuery,
+		adjustment.ProductId,
+		nullIfEmpty(adjustment.OrderId),
+		adjustment.Delta,
+		adjustment.QuantityAfter,
+		adjustment.Reason,
+		nullIfEmpty(adjustment.Actor),
+	).Scan(&adjustment.Id, &adjustment.CreatedAt)
+	if err != nil {
+		configs.Logger.Error("failed to record stock adjustment", zap.Error(err))
+		return exceptions.GenericException("failed to adjust stock", http.StatusInternalServerError)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// GetAdjustments retrieves the most recent stock adjustments of a product
+func (s *StockRepositoryImpl) GetAdjustments(ctx context.Context, productId int64, limit int) ([]*models.StockAdjustment, *errors.ErrorDetails) {
+	query := `SELECT id, product_id, COALESCE(order_id::text, ''), delta, quantity_after, reason, COALESCE(actor, ''), created_at
+              FROM product_stock_adjustments
+              WHERE product_id = $1
+              ORDER BY created_at DESC, id DESC
+              LIMIT $2`
+
+	rows, err := s.pool.Query(ctx, query, productId, limit)
+	if err != nil {
+		configs.Logger.Error("failed to query stock adjustments", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch stock adjustments", http.StatusInternalServerError)
+	}
+	defer rows.Close()
+
+	adjustments := []*models.StockAdjustment{}
+	for rows.Next() {
+		adjustment := &models.StockAdjustment{}
+		err = rows.Scan(
+			&adjustment.Id,
+			&adjustment.ProductId,
+			&adjustment.OrderId,
+			&adjustment.Delta,
+			&adjustment.QuantityAfter,
+			&adjustment.Reason,
+			&adjustment.Actor,
+			&adjustment.CreatedAt,
+		)
+		if err != nil {
+			configs.Logger.Error("failed to scan stock adjustment", zap.Error(err))
+			return nil, exceptions.GenericException("failed to fetch stock adjustments", http.StatusInternalServerError)
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading stock adjustments", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch stock adjustments", http.StatusInternalServerError)
+	}
+
+	return adjustments, nil
+}
+
+// consumeStock locks the limited stock of the ordered products and takes the ordered quantities out of it, every
+// product without enough stock is reported in a single error so the caller can surface them per item
+func consumeStock(ctx context.Context, tx pgx.Tx, orderId string, items []models.OrderItem) *errors.ErrorDetails {
+	quantities := make(map[int64]int)
+	productIds := make([]int64, 0, len(items))
+	for i := range items {
+		if _, found := quantities[items[i].ProductId]; !found {
+			productIds = append(productIds, items[i].ProductId)
+		}
+		quantities[items[i].ProductId] += items[i].Quantity
+	}
+
+	// Rows are locked in id order so that concurrent orders cannot deadlock each other
+	rows, err := tx.Query(ctx, `SELECT id, stock_quantity FROM products
+                                WHERE id = ANY($1) AND stock_quantity IS NOT NULL
+                                ORDER BY id
+                                FOR UPDATE`, productIds)
+	if err != nil {
+		configs.Logger.Error("failed to lock product stock", zap.Error(err))
+		return exceptions.GenericException("failed to reserve stock", http.StatusInternalServerError)
+	}
+
+	stock := make(map[int64]int)
+	var lockedIds []int64
+	for rows.Next() {
+		var id int64
+		var quantity int
+		if err = rows.Scan(&id, &quantity); err != nil {
+			rows.Close()
+			configs.Logger.Error("failed to scan product stock", zap.Error(err))
+			return exceptions.GenericException("failed to reserve stock", http.StatusInternalServerError)
+		}
+		stock[id] = quantity
+		lockedIds = append(lockedIds, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading product stock", zap.Error(err))
+		return exceptions.GenericException("failed to reserve stock", http.StatusInternalServerError)
+	}
+
+	var insufficient []errors.ItemError
+	for _, id := range lockedIds {
+		if stock[id] < quantities[id] {
+			insufficient = append(insufficient, insufficientStockItem(id, stock[id]))
+		}
+	}
+
+	if len(insufficient) > 0 {
+		configs.Logger.Error("insufficient stock", zap.Any("items", insufficient))
+		return exceptions.InsufficientStockException(insufficient)
+	}
+
+	if len(lockedIds) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, id := range lockedIds {
+		delta := -quantities[id]
+		quantityAfter := stock[id] + delta
+		batch.Queue("UPDATE products SET stock_quantity = $1 WHERE id = $2", quantityAfter, id)
+		batch.Queue(insertStockAdjustmentQuery, id, orderId, delta, quantityAfter, models.StockReasonOrder, nil)
+	}
+
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		configs.Logger.Error("failed to consume stock", zap.Error(err))
+		return exceptions.GenericException("failed to reserve stock", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// restoreStock puts back the stock an order took from its products, at most the given quantity of each product or
+// everything still taken when quantities is nil. Products whose stock was made unlimited since get nothing back.
+func restoreStock(ctx context.Context, tx pgx.Tx, orderId string, quantities map[int64]int, reason string, actor string) *errors.ErrorDetails {
+	// Rows are locked in id order like consumeStock does, before the stock still taken is summed up
+	rows, err := tx.Query(ctx, `SELECT id, stock_quantity FROM products
+                                WHERE id IN (SELECT product_id FROM product_stock_adjustments WHERE order_id = $1)
+                                  AND stock_quantity IS NOT NULL
+                                ORDER BY id
+                                FOR UPDATE`, orderId)
+	if err != nil {
+		configs.Logger.Error("failed to lock product stock", zap.Error(err))
+		return exceptions.GenericException("failed to restore stock", http.StatusInternalServerError)
+	}
+
+	stock := make(map[int64]int)
+	var lockedIds []int64
+	for rows.Next() {
+		var id int64
+		var quantity int
+		if err = rows.Scan(&id, &quantity); err != nil {
+			rows.Close()
+			configs.Logger.Error("failed to scan product stock", zap.Error(err))
+			return exceptions.GenericException("failed to restore stock", http.StatusInternalServerError)
+		}
+		stock[id] = quantity
+		lockedIds = append(lockedIds, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading product stock", zap.Error(err))
+		return exceptions.GenericException("failed to restore stock", http.StatusInternalServerError)
+	}
+
+	if len(lockedIds) == 0 {
+		return nil
+	}
+
+	// The stock still taken is what the order consumed less what earlier refunds already put back
+	rows, err = tx.Query(ctx, `SELECT product_id, -SUM(delta)::int FROM product_stock_adjustments
+                               WHERE order_id = $1 AND delta IS NOT NULL
+                               GROUP BY product_id`, orderId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch consumed stock", zap.Error(err))
+		return exceptions.GenericException("failed to restore stock", http.StatusInternalServerError)
+	}
+
+	taken := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var quantity int
+		if err = rows.Scan(&id, &quantity); err != nil {
+			rows.Close()
+			configs.Logger.Error("failed to scan consumed stock", zap.Error(err))
+			return exceptions.GenericException("failed to restore stock", http.StatusInternalServerError)
+		}
+		taken[id] = quantity
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		configs.Logger.Error("error reading consumed stock", zap.Error(err))
+		return exceptions.GenericException("failed to restore stock", http.StatusInternalServerError)
+	}
+
+	batch := &pgx.Batch{}
+	for _, id := range lockedIds {
+		delta := taken[id]
+		if quantities != nil {
+			delta = min(delta, quantities[id])
+		}
+		if delta <= 0 {
+			continue
+		}
+
+		quantityAfter := stock[id] + delta
+		batch.Queue("UPDATE products SET stock_quantity = $1 WHERE id = $2", quantityAfter, id)
+		batch.Queue(insertStockAdjustmentQuery, id, orderId, delta, quantityAfter, reason, nullIfEmpty(actor))
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		configs.Logger.Error("failed to restore stock", zap.Error(err))
+		return exceptions.GenericException("failed to restore stock", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+const insertStockAdjustmentQuery = `INSERT INTO product_stock_adjustments (product_id, order_id, delta, quantity_after, reason, actor)
+                                    VALUES ($1, $2, $3, $4, $5, $6)
+                                    RETURNING id, created_at`
+
+func insufficientStockItem(productId int64, available int) errors.ItemError {
+	return errors.ItemError{
+		ProductId: strconv.FormatInt(productId, 10),
+		Reason:    "insufficient_stock",
+		Message:   fmt.Sprintf("only %d left in stock", available),
+	}
+}
+
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,232 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"time"
+)
+
+// categoryTouchQuery touches a category with its subcategories, whose paths and so the categories of their products
+// are translated with it
+const categoryTouchQuery = `UPDATE categories SET modified_at = NOW()
+                            WHERE id = $1
+                               OR starts_with(path, (SELECT path FROM categories WHERE id = $1) || ' > ')`
+
+type TranslationRepositoryImpl struct {
+	pool *pgxpool.Pool
+}
+
+// NewTranslationRepositoryImpl creates a new instance of TranslationRepositoryImpl
+func NewTranslationRepositoryImpl(pool *pgxpool.Pool) *TranslationRepositoryImpl {
+	return &TranslationRepositoryImpl{pool: pool}
+}
+
+// GetProductTranslations Retrieves the translations of the products in a locale from the database
+func (t *TranslationRepositoryImpl) GetProductTranslations(ctx context.Context, productIds []int64, locale string) (map[int64]*models.ProductTranslation, *errors.ErrorDetails) {
+	query := `SELECT product_id, locale, name, description, modified_at
+              FROM product_translations
+              WHERE product_id = ANY($1) AND locale = $2`
+
+	rows, err := t.pool.Query(ctx, query, productIds, locale)
+	if err != nil {
+		configs.Logger.Error("failed to fetch product translations", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch product translations", http.StatusInternalServerError)
+	}
+
+	translations, err := pgx.CollectRows(rows, scanProductTranslation)
+	if err != nil {
+		configs.Logger.Error("failed to scan product translation", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch product translations", http.StatusInternalServerError)
+	}
+
+	byProduct := make(map[int64]*models.ProductTranslation, len(translations))
+	for _, translation := range translations {
+		byProduct[translation.ProductId] = translation
+	}
+	return byProduct, nil
+}
+
+// GetCategoryTranslations Retrieves the translations of the categories with the given paths in a locale from the database
+func (t *TranslationRepositoryImpl) GetCategoryTranslations(ctx context.Context, paths []string, locale string) (map[string]*models.CategoryTranslation, *errors.ErrorDetails) {
+	query := `SELECT t.category_id, c.path, t.locale, t.name, t.modified_at
+              FROM category_translations t
+              JOIN categories c ON c.id = t.category_id
+              WHERE c.path = ANY($1) AND t.locale = $2`
+
+	rows, err := t.pool.Query(ctx, query, paths, locale)
+	if err != nil {
+		configs.Logger.Error("failed to fetch category translations", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch category translations", http.StatusInternalServerError)
+	}
+
+	translations, err := pgx.CollectRows(rows, scanCategoryTranslation)
+	if err != nil {
+		configs.Logger.Error("failed to scan category translation", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch category translations", http.StatusInternalServerError)
+	}
+
+	byPath := make(map[string]*models.CategoryTranslation, len(translations))
+	for _, translation := range translations {
+		byPath[translation.Path] = translation
+	}
+	return byPath, nil
+}
+
+// ListProductTranslations Retrieves the translations of a product in every locale from the database
+func (t *TranslationRepositoryImpl) ListProductTranslations(ctx context.Context, productId int64) ([]*models.ProductTranslation, *errors.ErrorDetails) {
+	query := `SELECT product_id, locale, name, description, modified_at
+              FROM product_translations
+              WHERE product_id = $1
+              ORDER BY locale`
+
+	rows, err := t.pool.Query(ctx, query, productId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch product translations", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch product translations", http.StatusInternalServerError)
+	}
+
+	translations, err := pgx.CollectRows(rows, scanProductTranslation)
+	if err != nil {
+		configs.Logger.Error("failed to scan product translation", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch product translations", http.StatusInternalServerError)
+	}
+	return translations, nil
+}
+
+// SaveProductTranslation Creates or replaces the translation of a product that is not deleted
+func (t *TranslationRepositoryImpl) SaveProductTranslation(ctx context.Context, translation *models.ProductTranslation) *errors.ErrorDetails {
+	modifiedAt, err := t.writeTranslation(ctx,
+		"UPDATE products SET modified_at = NOW() WHERE id = $1 AND deleted_at IS NULL",
+		`INSERT INTO product_translations (product_id, locale, name, description)
+         VALUES ($1, $2, $3, $4)
+         ON CONFLICT (product_id, locale) DO UPDATE
+         SET name = EXCLUDED.name,
+             description = EXCLUDED.description,
+             modified_at = NOW()
+         RETURNING modified_at`,
+		"product not found", translation.ProductId, translation.Locale, translation.Name, translation.Description)
+	if err != nil {
+		return err
+	}
+
+	translation.ModifiedAt = modifiedAt
+	return nil
+}
+
+// DeleteProductTranslation Deletes the translation of a product in a locale
+func (t *TranslationRepositoryImpl) DeleteProductTranslation(ctx context.Context, productId int64, locale string) *errors.ErrorDetails {
+	_, err := t.writeTranslation(ctx,
+		"UPDATE products SET modified_at = NOW() WHERE id = $1",
+		"DELETE FROM product_translations WHERE product_id = $1 AND locale = $2 RETURNING NOW()",
+		"product not found", productId, locale)
+	return err
+}
+
+// ListCategoryTranslations Retrieves the translations of a category in every locale from the database
+func (t *TranslationRepositoryImpl) ListCategoryTranslations(ctx context.Context, categoryId int64) ([]*models.CategoryTranslation, *errors.ErrorDetails) {
+	query := `SELECT t.category_id, c.path, t.locale, t.name, t.modified_at
+              FROM category_translations t
+              JOIN categories c ON c.id = t.category_id
+              WHERE t.category_id = $1
+              ORDER BY t.locale`
+
+	rows, err := t.pool.Query(ctx, query, categoryId)
+	if err != nil {
+		configs.Logger.Error("failed to fetch category translations", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch category translations", http.StatusInternalServerError)
+	}
+
+	translations, err := pgx.CollectRows(rows, scanCategoryTranslation)
+	if err != nil {
+		configs.Logger.Error("failed to scan category translation", zap.Error(err))
+		return nil, exceptions.GenericException("failed to fetch category translations", http.StatusInternalServerError)
+	}
+	return translations, nil
+}
+
+// SaveCategoryTranslation Creates or replaces the translation of a category
+func (t *TranslationRepositoryImpl) SaveCategoryTranslation(ctx context.Context, translation *models.CategoryTranslation) *errors.ErrorDetails {
+	modifiedAt, err := t.writeTranslation(ctx,
+		categoryTouchQuery,
+		`INSERT INTO category_translations (category_id, locale, name)
+         VALUES ($1, $2, $3)
+         ON CONFLICT (category_id, locale) DO UPDATE
+         SET name = EXCLUDED.name,
+             modified_at = NOW()
+         RETURNING modified_at`,
+		"category not found", translation.CategoryId, translation.Locale, translation.Name)
+	if err != nil {
+		return err
+	}
+
+	translation.ModifiedAt = modifiedAt
+	return nil
+}
+
+// DeleteCategoryTranslation Deletes the translation of a category in a locale
+func (t *TranslationRepositoryImpl) DeleteCategoryTranslation(ctx context.Context, categoryId int64, locale string) *errors.ErrorDetails {
+	_, err := t.writeTranslation(ctx,
+		categoryTouchQuery,
+		"DELETE FROM category_translations WHERE category_id = $1 AND locale = $2 RETURNING NOW()",
+		"category not found", categoryId, locale)
+	return err
+}
+
+// writeTranslation runs a write of a translation returning a time after touching its owner in one transaction. Touching
+// the owner locks it against concurrent writes, and its modified_at change invalidates the conditional requests and
+// caches of the catalog. A write that returns no row did not find the translation.
+func (t *TranslationRepositoryImpl) writeTranslation(ctx context.Context, touchQuery, writeQuery, notFound string, ownerId int64, args ...any) (time.Time, *errors.ErrorDetails) {
+	var modifiedAt time.Time
+
+	tx, err := t.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		configs.Logger.Error("failed to begin transaction", zap.Error(err))
+		return modifiedAt, exceptions.GenericException("failed to begin transaction", http.StatusInternalServerError)
+	}
+	defer rollback(ctx, tx)
+
+	tag, err := tx.Exec(ctx, touchQuery, ownerId)
+	if err != nil {
+		configs.Logger.Error("failed to update translation", zap.Error(err))
+		return modifiedAt, exceptions.GenericException("failed to update translation", http.StatusInternalServerError)
+	}
+	if tag.RowsAffected() == 0 {
+		configs.Logger.Error(notFound, zap.Int64("id", ownerId))
+		return modifiedAt, exceptions.GenericException(notFound, http.StatusNotFound)
+	}
+
+	if err = tx.QueryRow(ctx, writeQuery, append([]any{ownerId}, args...)...).Scan(&modifiedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			configs.Logger.Error("translation not found", zap.Int64("id", ownerId), zap.Any("args", args))
+			return modifiedAt, exceptions.GenericException("translation not found", http.StatusNotFound)
+		}
+		configs.Logger.Error("failed to update translation", zap.Error(err))
+		return modifiedAt, exceptions.GenericException("failed to update translation", http.StatusInternalServerError)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		configs.Logger.Error("failed to commit transaction", zap.Error(err))
+		return modifiedAt, exceptions.GenericException("failed to commit transaction", http.StatusInternalServerError)
+	}
+
+	return modifiedAt, nil
+}
+
+func scanProductTranslation(row pgx.CollectableRow) (*models.ProductTranslation, error) {
+	translation := &models.ProductTranslation{}
+	err := row.Scan(&translation.ProductId, &translation.Locale, &translation.Name, &translation.Description, &translation.ModifiedAt)
+	return translation, err
+}
+
+func scanCategoryTranslation(row pgx.CollectableRow) (*models.CategoryTranslation, error) {
+	translation := &models.CategoryTranslation{}
+	err := row.Scan(&translation.CategoryId, &translation.Path, &translation.Locale, &translation.Name, &translation.ModifiedAt)
+	return translation, err
+}
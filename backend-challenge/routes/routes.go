@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/pprof"
@@ -15,6 +16,7 @@ import (
 	"oolio.com/kart/docs"
 	"oolio.com/kart/middlewares"
 	"oolio.com/kart/repositories"
+	repositoryBase "oolio.com/kart/repositories/base"
 	"oolio.com/kart/services"
 	"time"
 )
@@ -29,7 +31,12 @@ func InitializeRoutes() *gin.Engine {
 
 	router := gin.New()
 
-	router.Use(cors.Default())
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.ExposeHeaders = []string{constants.NextCursorHeader, constants.TotalCountHeader, "ETag"}
+	corsConfig.ExposeHeaders = append(corsConfig.ExposeHeaders, "Content-Language", constants.IdempotentReplayedHeader)
+	corsConfig.AddAllowHeaders("If-None-Match", "If-Modified-Since", "Accept-Language", constants.IdempotencyKeyHeader)
+	router.Use(cors.New(corsConfig))
 
 	router.Use(ginZap.RecoveryWithZap(configs.Logger, true))
 	router.Use(ginZap.Ginzap(configs.Logger, time.RFC3339, false))
@@ -39,6 +46,7 @@ func InitializeRoutes() *gin.Engine {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	kartRouter := router.Group("/api")
+	kartRouter.Use(middlewares.LocaleMiddleware())
 	kartRouter.GET("/health", controllers.HealthCheckController.HealthCheck)
 
 	pool, err := repositories.Pool()
@@ -54,18 +62,113 @@ func InitializeRoutes() *gin.Engine {
 
 	productRepository := repositories.NewProductRepositoryImpl(pool)
 	orderRepository := repositories.NewOrderRepositoryImpl(pool)
+	stockRepository := repositories.NewStockRepositoryImpl(pool)
+	modifierRepository := repositories.NewModifierRepositoryImpl(pool)
+	catalogRepository := repositories.NewCatalogRepositoryImpl(pool)
+	categoryRepository := repositories.NewCategoryRepositoryImpl(pool)
+	availabilityRepository := repositories.NewAvailabilityRepositoryImpl(pool)
+	translationRepository := repositories.NewTranslationRepositoryImpl(pool)
+	recommendationRepository := repositories.NewRecommendationRepositoryImpl(pool)
+	refundRepository := repositories.NewRefundRepositoryImpl(pool)
+	idempotencyRepository := repositories.NewIdempotencyRepositoryImpl(pool)
+	imageStorage := repositories.NewLocalImageStorageImpl(configs.Images.StorageDir, configs.Images.BaseURL)
+
+	// Stock changes are written by the stock repository, the stock service reads the products uncached to see its
+	// own writes
+	var cachedProductRepository repositoryBase.ProductRepository = productRepository
+	var caches []repositoryBase.Cache
+	if configs.ProductCacheEnabled {
+		productCache := repositories.NewCachingProductRepository(productRepository)
+		cachedProductRepository = productCache
+		caches = append(caches, productCache)
+		go repositories.ListenForCatalogChanges(context.Background(), pool, caches...)
+	}
+
+	recommendationService := services.NewRecommendationServiceImpl(cachedProductRepository, recommendationRepository, availabilityRepository, translationRepository, configs.Recommendations)
+	go recommendationService.RunRefreshJob(context.Background())
 
-	productService := services.NewProductServiceImpl(productRepository)
-	orderService := services.NewOrderServiceImpl(orderRepository, productRepository, services.CouponServiceImpl)
+	productService := services.NewProductServiceImpl(cachedProductRepository, categoryRepository, translationRepository)
+	orderService := services.NewOrderServiceImpl(orderRepository, cachedProductRepository, modifierRepository, availabilityRepository, translationRepository, services.CouponServiceImpl, recommendationService)
+	stockService := services.NewStockServiceImpl(productRepository, stockRepository)
+	modifierService := services.NewModifierServiceImpl(cachedProductRepository, modifierRepository)
+	catalogService := services.NewCatalogServiceImpl(catalogRepository)
+	categoryService := services.NewCategoryServiceImpl(categoryRepository, translationRepository)
+	cacheService := services.NewCacheServiceImpl(caches...)
+	productImageService := services.NewProductImageServiceImpl(cachedProductRepository, imageStorage, configs.Images.Renditions())
+	availabilityService := services.NewAvailabilityServiceImpl(cachedProductRepository, categoryRepository, availabilityRepository)
+	translationService := services.NewTranslationServiceImpl(cachedProductRepository, categoryRepository, translationRepository)
+	refundService := services.NewRefundServiceImpl(orderRepository, cachedProductRepository, refundRepository, translationRepository)
+
+	idempotencyService := services.NewIdempotencyServiceImpl(idempotencyRepository, configs.Idempotency)
+	go idempotencyService.RunPurgeJob(context.Background())
 
 	productController := controllers.NewProductController(productService)
 	orderController := controllers.NewOrderController(orderService)
+	stockController := controllers.NewStockController(stockService)
+	modifierController := controllers.NewModifierController(modifierService)
+	catalogController := controllers.NewCatalogController(catalogService)
+	categoryController := controllers.NewCategoryController(categoryService)
+	cacheController := controllers.NewCacheController(cacheService)
+	productImageController := controllers.NewProductImageController(productImageService, configs.Images.MaxUploadSize)
+	availabilityController := controllers.NewAvailabilityController(availabilityService)
+	translationController := controllers.NewTranslationController(translationService)
+	recommendationController := controllers.NewRecommendationController(recommendationService)
+	refundController := controllers.NewRefundController(refundService)
+
+	// Images of the local image storage are served by the app
+	router.Static(constants.ImageRoute, configs.Images.StorageDir)
 
 	product := kartRouter.Group("/product")
 	product.GET("", productController.GetProducts)
 	product.GET("/:productId", productController.GetProductById)
-
-	kartRouter.POST("/order", middlewares.APIKeyMiddleware(), orderController.PlaceOrder)
+	product.GET("/:productId/prices", productController.GetProductPrices)
+	product.POST("", middlewares.APIKeyMiddleware(), productController.CreateProduct)
+	product.PUT("/:productId", middlewares.APIKeyMiddleware(), productController.UpdateProduct)
+	product.PATCH("/:productId", middlewares.APIKeyMiddleware(), productController.PatchProduct)
+	product.PUT("/:productId/status", middlewares.APIKeyMiddleware(), productController.UpdateProductStatus)
+	product.DELETE("/:productId", middlewares.APIKeyMiddleware(), productController.DeleteProduct)
+	product.POST("/:productId/image", middlewares.APIKeyMiddleware(), productImageController.UploadProductImage)
+	product.GET("/:productId/recommendations", recommendationController.GetProductRecommendations)
+	product.GET("/:productId/availability", availabilityController.GetProductAvailability)
+	product.PUT("/:productId/availability", middlewares.APIKeyMiddleware(), availabilityController.SetProductAvailability)
+	product.GET("/:productId/translations", middlewares.APIKeyMiddleware(), translationController.GetProductTranslations)
+	product.PUT("/:productId/translations/:locale", middlewares.APIKeyMiddleware(), translationController.SetProductTranslation)
+	product.DELETE("/:productId/translations/:locale", middlewares.APIKeyMiddleware(), translationController.DeleteProductTranslation)
+	product.GET("/:productId/stock", middlewares.APIKeyMiddleware(), stockController.GetStock)
+	product.POST("/:productId/stock", middlewares.APIKeyMiddleware(), stockController.AdjustStock)
+	product.GET("/:productId/modifier-groups", modifierController.GetModifierGroups)
+	product.POST("/:productId/modifier-groups", middlewares.APIKeyMiddleware(), modifierController.CreateModifierGroup)
+	product.PUT("/:productId/modifier-groups/:groupId", middlewares.APIKeyMiddleware(), modifierController.UpdateModifierGroup)
+	product.DELETE("/:productId/modifier-groups/:groupId", middlewares.APIKeyMiddleware(), modifierController.DeleteModifierGroup)
+
+	category := kartRouter.Group("/category")
+	category.GET("", categoryController.GetCategoryTree)
+	category.GET("/:categoryId", categoryController.GetCategory)
+	category.GET("/:categoryId/availability", availabilityController.GetCategoryAvailability)
+	category.PUT("/:categoryId/availability", middlewares.APIKeyMiddleware(), availabilityController.SetCategoryAvailability)
+	category.GET("/:categoryId/translations", middlewares.APIKeyMiddleware(), translationController.GetCategoryTranslations)
+	category.PUT("/:categoryId/translations/:locale", middlewares.APIKeyMiddleware(), translationController.SetCategoryTranslation)
+	category.DELETE("/:categoryId/translations/:locale", middlewares.APIKeyMiddleware(), translationController.DeleteCategoryTranslation)
+	category.POST("", middlewares.APIKeyMiddleware(), categoryController.CreateCategory)
+	category.PUT("/:categoryId", middlewares.APIKeyMiddleware(), categoryController.UpdateCategory)
+	category.DELETE("/:categoryId", middlewares.APIKeyMiddleware(), categoryController.DeleteCategory)
+
+	kartRouter.GET("/order", middlewares.APIKeyMiddleware(), orderController.ListOrders)
+	kartRouter.POST("/order", middlewares.APIKeyMiddleware(), middlewares.IdempotencyMiddleware(idempotencyService), orderController.PlaceOrder)
+	kartRouter.POST("/order/quote", middlewares.APIKeyMiddleware(), orderController.QuoteOrder)
+	kartRouter.GET("/order/:orderId", middlewares.APIKeyMiddleware(), orderController.GetOrderById)
+	kartRouter.GET("/order/:orderId/transitions", middlewares.APIKeyMiddleware(), orderController.GetOrderTransitions)
+	kartRouter.POST("/order/:orderId/transitions", middlewares.APIKeyMiddleware(), orderController.TransitionOrder)
+	kartRouter.GET("/order/:orderId/refunds", middlewares.APIKeyMiddleware(), refundController.GetRefunds)
+	kartRouter.POST("/order/:orderId/refunds", middlewares.APIKeyMiddleware(), refundController.RefundOrder)
+	kartRouter.GET("/order/:orderId/refunds/:refundId", middlewares.APIKeyMiddleware(), refundController.GetRefund)
+
+	admin := kartRouter.Group("/admin")
+	admin.POST("/products/import", middlewares.APIKeyMiddleware(), catalogController.ImportProducts)
+	admin.GET("/products/export", middlewares.APIKeyMiddleware(), catalogController.ExportProducts)
+	admin.GET("/products/deleted", middlewares.APIKeyMiddleware(), productController.GetDeletedProducts)
+	admin.POST("/products/:productId/restore", middlewares.APIKeyMiddleware(), productController.RestoreProduct)
+	admin.GET("/cache/stats", middlewares.APIKeyMiddleware(), cacheController.GetCacheStats)
 
 	return router
 }
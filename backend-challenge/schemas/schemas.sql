@@ -1,23 +1,249 @@
-CREATE SCHEMA kart;
+CREATE SCHEMA IF NOT EXISTS kart;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Menu categories, path is the names from the top level category down and is kept in sync by the category repository
+CREATE TABLE IF NOT EXISTS kart.categories (
+    id          BIGSERIAL PRIMARY KEY,
+    parent_id   BIGINT REFERENCES kart.categories(id),
+    name        VARCHAR(100) NOT NULL,
+    path        TEXT NOT NULL UNIQUE,
+    sort_order  INTEGER NOT NULL DEFAULT 0,
+    active      BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON kart.categories(parent_id);
+
+-- Allergens and diets are the values of the constants in models/dietary.go
+CREATE OR REPLACE FUNCTION kart.known_allergens() RETURNS TEXT[] AS $$
+    SELECT ARRAY['celery', 'crustaceans', 'dairy', 'eggs', 'fish', 'gluten', 'lupin', 'molluscs', 'mustard', 'nuts',
+                 'peanuts', 'sesame', 'soy', 'sulphites']
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The allergens a product does not contain, indexed so that excluding allergens is a containment query
+CREATE OR REPLACE FUNCTION kart.absent_allergens(allergens TEXT[]) RETURNS TEXT[] AS $$
+    SELECT ARRAY(SELECT a FROM unnest(kart.known_allergens()) AS a WHERE a <> ALL (allergens) ORDER BY a)
+$$ LANGUAGE sql IMMUTABLE;
 
 CREATE TABLE IF NOT EXISTS kart.products (
       id          BIGSERIAL PRIMARY KEY,
       name        Varchar(255) NOT NULL,
-      category    Varchar(100) NOT NULL,
+      description TEXT NOT NULL DEFAULT '',
+      category_id BIGINT NOT NULL REFERENCES kart.categories(id),
       price       NUMERIC(10, 2) NOT NULL,
-      status      Varchar(20) NOT NULL,
+      status      Varchar(20) NOT NULL DEFAULT 'available'
+                  CHECK (status IN ('available', 'sold_out', 'hidden', 'discontinued')),
+      stock_quantity INTEGER CHECK (stock_quantity >= 0),
+      price_version  INTEGER NOT NULL DEFAULT 1,
       image       JSONB NOT NULL,
+      allergens   TEXT[] NOT NULL DEFAULT '{}' CHECK (allergens <@ kart.known_allergens()),
+      diets       TEXT[] NOT NULL DEFAULT '{}'
+                  CHECK (diets <@ ARRAY['vegetarian', 'vegan', 'halal', 'kosher', 'gluten_free', 'dairy_free']),
       meta        JSONB,
       created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       modified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-      UNIQUE (name, category)
+      -- Deleted products stay in the table, orders placed before keep referring to them
+      deleted_at  TIMESTAMPTZ
 );
 
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_name_category_id ON kart.products(name, category_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON kart.products(deleted_at) WHERE deleted_at IS NOT NULL;
+
 CREATE INDEX IF NOT EXISTS idx_products_created_at ON kart.products(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON kart.products(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON kart.products(category_id);
+CREATE INDEX IF NOT EXISTS idx_products_status ON kart.products(status);
+CREATE INDEX IF NOT EXISTS idx_products_price ON kart.products(price, id);
+CREATE INDEX IF NOT EXISTS idx_products_name ON kart.products(name, id);
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON kart.products USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_modified_at ON kart.products(modified_at);
+-- Serve the excludeAllergens and diet filters of the product listing
+CREATE INDEX IF NOT EXISTS idx_products_absent_allergens ON kart.products USING GIN (kart.absent_allergens(allergens));
+CREATE INDEX IF NOT EXISTS idx_products_diets ON kart.products USING GIN (diets);
+
+-- Rows removed from the products table leave no modified_at behind, the time of the last removal keeps the
+-- Last-Modified of the product listings honest. The table holds a single row.
+CREATE TABLE IF NOT EXISTS kart.product_deletions (
+    id         BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    deleted_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION kart.record_product_deletion() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO kart.product_deletions (deleted_at) VALUES (NOW())
+    ON CONFLICT (id) DO UPDATE SET deleted_at = EXCLUDED.deleted_at;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_products_deleted
+    AFTER DELETE ON kart.products
+    FOR EACH ROW EXECUTE FUNCTION kart.record_product_deletion();
+
+-- Every price a product ever had, written by the triggers below so no code path can change a price without a trace
+CREATE TABLE IF NOT EXISTS kart.product_price_history (
+    id             BIGSERIAL PRIMARY KEY,
+    product_id     BIGINT NOT NULL REFERENCES kart.products(id) ON DELETE CASCADE,
+    version        INTEGER NOT NULL,
+    price          NUMERIC(10, 2) NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (product_id, version)
+);
+
+CREATE OR REPLACE FUNCTION kart.bump_product_price_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.price IS DISTINCT FROM OLD.price THEN
+        NEW.price_version := OLD.price_version + 1;
+    ELSE
+        NEW.price_version := OLD.price_version;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION kart.record_product_price() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO kart.product_price_history (product_id, version, price)
+    VALUES (NEW.id, NEW.price_version, NEW.price);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION kart.reject_price_history_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'product price history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_products_price_version
+    BEFORE UPDATE ON kart.products
+    FOR EACH ROW EXECUTE FUNCTION kart.bump_product_price_version();
+
+CREATE OR REPLACE TRIGGER trg_products_price_inserted
+    AFTER INSERT ON kart.products
+    FOR EACH ROW EXECUTE FUNCTION kart.record_product_price();
+
+CREATE OR REPLACE TRIGGER trg_products_price_changed
+    AFTER UPDATE OF price ON kart.products
+    FOR EACH ROW WHEN (NEW.price IS DISTINCT FROM OLD.price)
+    EXECUTE FUNCTION kart.record_product_price();
+
+CREATE OR REPLACE TRIGGER trg_product_price_history_append_only
+    BEFORE UPDATE ON kart.product_price_history
+    FOR EACH ROW EXECUTE FUNCTION kart.reject_price_history_change();
+
+-- Every change of the catalog is announced on the kart_catalog channel, the API replicas drop their product caches on
+-- it. Notifications are only delivered once the transaction commits.
+CREATE OR REPLACE FUNCTION kart.notify_catalog_change() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('kart_catalog', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_products_notify
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON kart.products
+    FOR EACH STATEMENT EXECUTE FUNCTION kart.notify_catalog_change();
+
+CREATE OR REPLACE TRIGGER trg_categories_notify
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON kart.categories
+    FOR EACH STATEMENT EXECUTE FUNCTION kart.notify_catalog_change();
+
+-- Names and descriptions of the products and categories in the locales other than the default one, which is stored on
+-- the products and categories themselves. Writing a translation touches the modified_at of its owner.
+CREATE TABLE IF NOT EXISTS kart.product_translations (
+    product_id  BIGINT NOT NULL REFERENCES kart.products(id) ON DELETE CASCADE,
+    locale      VARCHAR(35) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS kart.category_translations (
+    category_id BIGINT NOT NULL REFERENCES kart.categories(id) ON DELETE CASCADE,
+    locale      VARCHAR(35) NOT NULL,
+    name        VARCHAR(100) NOT NULL CHECK (strpos(name, '>') = 0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category_id, locale)
+);
+
+-- Hours a product or all products of a category can be ordered, in the store time zone. Days are ISO days of the week,
+-- 1 is Monday. Times are minutes since midnight, a window ending before it starts runs past midnight into the next day.
+-- Changing the windows of a product or category touches its modified_at, which announces the change to the caches.
+CREATE TABLE IF NOT EXISTS kart.availability_windows (
+    id           BIGSERIAL PRIMARY KEY,
+    product_id   BIGINT REFERENCES kart.products(id) ON DELETE CASCADE,
+    category_id  BIGINT REFERENCES kart.categories(id) ON DELETE CASCADE,
+    days_of_week SMALLINT[] NOT NULL CHECK (cardinality(days_of_week) > 0 AND days_of_week <@ '{1,2,3,4,5,6,7}'),
+    start_minute SMALLINT NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute   SMALLINT NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (start_minute <> end_minute),
+    CHECK ((product_id IS NULL) <> (category_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_windows_product_id ON kart.availability_windows(product_id);
+CREATE INDEX IF NOT EXISTS idx_availability_windows_category_id ON kart.availability_windows(category_id);
+
+-- Windows in effect for every product: its own windows, or else the windows of its nearest category having any.
+-- Products without any window in effect can be ordered at any time.
+CREATE OR REPLACE VIEW kart.product_availability_windows AS
+SELECT p.id AS product_id, w.id, w.product_id AS owner_product_id, w.category_id AS owner_category_id,
+       w.days_of_week, w.start_minute, w.end_minute
+FROM kart.products p
+JOIN kart.availability_windows w ON w.product_id = p.id
+UNION ALL
+SELECT p.id AS product_id, w.id, w.product_id AS owner_product_id, w.category_id AS owner_category_id,
+       w.days_of_week, w.start_minute, w.end_minute
+FROM kart.products p
+JOIN kart.categories c ON c.id = p.category_id
+CROSS JOIN LATERAL (
+    SELECT a.id
+    FROM kart.categories a
+    WHERE (a.id = c.id OR starts_with(c.path, a.path || ' > '))
+      AND EXISTS (SELECT 1 FROM kart.availability_windows aw WHERE aw.category_id = a.id)
+    ORDER BY length(a.path) DESC
+    LIMIT 1
+) nearest
+JOIN kart.availability_windows w ON w.category_id = nearest.id
+WHERE NOT EXISTS (SELECT 1 FROM kart.availability_windows own WHERE own.product_id = p.id);
+
+-- Whether a window is open at a wall clock time of the store, models.AvailabilityWindow.IsOpenAt is the Go counterpart
+CREATE OR REPLACE FUNCTION kart.availability_window_open(days_of_week SMALLINT[], start_minute SMALLINT, end_minute SMALLINT, local_time TIMESTAMP)
+RETURNS BOOLEAN AS $$
+    SELECT CASE
+        WHEN $2 < $3 THEN
+            EXTRACT(ISODOW FROM $4)::SMALLINT = ANY($1)
+                AND EXTRACT(HOUR FROM $4) * 60 + EXTRACT(MINUTE FROM $4) BETWEEN $2 AND $3 - 1
+        ELSE
+            (EXTRACT(ISODOW FROM $4)::SMALLINT = ANY($1) AND EXTRACT(HOUR FROM $4) * 60 + EXTRACT(MINUTE FROM $4) >= $2)
+                OR (EXTRACT(ISODOW FROM $4 - INTERVAL '1 day')::SMALLINT = ANY($1) AND EXTRACT(HOUR FROM $4) * 60 + EXTRACT(MINUTE FROM $4) < $3)
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Whether a product can be ordered at a wall clock time of the store as far as its availability windows are concerned
+CREATE OR REPLACE FUNCTION kart.product_available_at(product_id BIGINT, local_time TIMESTAMP) RETURNS BOOLEAN AS $$
+    SELECT NOT EXISTS (SELECT 1 FROM kart.product_availability_windows w WHERE w.product_id = $1)
+        OR EXISTS (
+            SELECT 1
+            FROM kart.product_availability_windows w
+            WHERE w.product_id = $1 AND kart.availability_window_open(w.days_of_week, w.start_minute, w.end_minute, $2)
+        )
+$$ LANGUAGE sql STABLE;
 
 CREATE TABLE IF NOT EXISTS kart.orders (
     id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     coupon_code VARCHAR(20),
+    status      VARCHAR(20) NOT NULL DEFAULT 'placed'
+                CHECK (status IN ('placed', 'accepted', 'preparing', 'ready', 'completed', 'cancelled')),
+    -- Incremented by every change of status so that concurrent changes of the same order cannot both apply
+    version     INTEGER NOT NULL DEFAULT 1,
     subtotal    NUMERIC(10, 2),
     discount    NUMERIC(10, 2) DEFAULT 0,
     total       NUMERIC(10, 2),
@@ -31,16 +257,133 @@ CREATE TABLE IF NOT EXISTS kart.order_items (
      order_id    UUID NOT NULL REFERENCES kart.orders(id) ON DELETE CASCADE,
      product_id  BIGINT NOT NULL REFERENCES kart.products(id),
      quantity    INTEGER NOT NULL CHECK (quantity > 0),
-     unit_price  NUMERIC(10, 2) NOT NULL,
+     unit_price  NUMERIC(10, 2) NOT NULL CHECK (unit_price >= 0),
+     price_version INTEGER,
      discount    NUMERIC(10, 2) DEFAULT 0,
      price       NUMERIC(10, 2) NOT NULL,
      meta        JSONB,
      created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-     UNIQUE (order_id, product_id)
+     FOREIGN KEY (product_id, price_version) REFERENCES kart.product_price_history(product_id, version)
 );
 
 CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON kart.order_items(order_id);
+
+CREATE TABLE IF NOT EXISTS kart.modifier_groups (
+    id          BIGSERIAL PRIMARY KEY,
+    product_id  BIGINT NOT NULL REFERENCES kart.products(id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    min_select  INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select  INTEGER NOT NULL CHECK (max_select >= 1 AND max_select >= min_select),
+    sort_order  INTEGER NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    modified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS kart.modifiers (
+    id          BIGSERIAL PRIMARY KEY,
+    group_id    BIGINT NOT NULL REFERENCES kart.modifier_groups(id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    price_delta NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    sort_order  INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (group_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_modifier_groups_product_id ON kart.modifier_groups(product_id);
+
+CREATE TABLE IF NOT EXISTS kart.order_item_modifiers (
+    id            BIGSERIAL PRIMARY KEY,
+    order_item_id BIGINT NOT NULL REFERENCES kart.order_items(id) ON DELETE CASCADE,
+    modifier_id   BIGINT REFERENCES kart.modifiers(id) ON DELETE SET NULL,
+    group_name    VARCHAR(100) NOT NULL,
+    name          VARCHAR(100) NOT NULL,
+    price_delta   NUMERIC(10, 2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_item_modifiers_order_item_id ON kart.order_item_modifiers(order_item_id);
 CREATE INDEX IF NOT EXISTS idx_orders_created_at ON kart.orders(created_at DESC);
+-- The back-office listing pages through orders by (created_at, id) or (total, id), narrowed by status or coupon
+CREATE INDEX IF NOT EXISTS idx_orders_status_created_at ON kart.orders(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_coupon_code_created_at ON kart.orders(coupon_code, created_at DESC)
+    WHERE coupon_code <> '';
+CREATE INDEX IF NOT EXISTS idx_orders_total ON kart.orders((COALESCE(total, 0)), id);
+
+CREATE TABLE IF NOT EXISTS kart.order_status_history (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    UUID NOT NULL REFERENCES kart.orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status   VARCHAR(20) NOT NULL,
+    actor       VARCHAR(100) NOT NULL,
+    reason      VARCHAR(500),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (from_status <> to_status)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON kart.order_status_history(order_id, id);
+
+-- Refunds are a ledger of their own, orders keep the total they were charged
+CREATE TABLE IF NOT EXISTS kart.order_refunds (
+    id         BIGSERIAL PRIMARY KEY,
+    order_id   UUID NOT NULL REFERENCES kart.orders(id) ON DELETE CASCADE,
+    amount     NUMERIC(10, 2) NOT NULL CHECK (amount >= 0),
+    reason     VARCHAR(500),
+    actor      VARCHAR(100) NOT NULL,
+    restock    BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_refunds_order_id ON kart.order_refunds(order_id, id);
+
+CREATE TABLE IF NOT EXISTS kart.order_refund_items (
+    refund_id     BIGINT NOT NULL REFERENCES kart.order_refunds(id) ON DELETE CASCADE,
+    order_item_id BIGINT NOT NULL REFERENCES kart.order_items(id) ON DELETE CASCADE,
+    quantity      INTEGER NOT NULL CHECK (quantity > 0),
+    amount        NUMERIC(10, 2) NOT NULL,
+    PRIMARY KEY (refund_id, order_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_refund_items_order_item_id ON kart.order_refund_items(order_item_id);
+
+CREATE TABLE IF NOT EXISTS kart.product_stock_adjustments (
+    id             BIGSERIAL PRIMARY KEY,
+    product_id     BIGINT NOT NULL REFERENCES kart.products(id) ON DELETE CASCADE,
+    order_id       UUID REFERENCES kart.orders(id) ON DELETE SET NULL,
+    delta          INTEGER,
+    quantity_after INTEGER CHECK (quantity_after >= 0),
+    reason         VARCHAR(255) NOT NULL,
+    actor          VARCHAR(100),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_stock_adjustments_product ON kart.product_stock_adjustments(product_id, created_at DESC);
+
+-- Requests made with an Idempotency-Key header and their responses, replayed to the retries of the requests until the
+-- keys expire. The response is unset while the request is being processed. Keys are prefixed with 16 hex digits of
+-- the hash of the API key they were sent with and a colon.
+CREATE TABLE IF NOT EXISTS kart.idempotency_keys (
+    key           VARCHAR(272) PRIMARY KEY,
+    fingerprint   CHAR(64) NOT NULL,
+    status_code   INTEGER,
+    response_body BYTEA,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON kart.idempotency_keys(expires_at);
+
+-- Products frequently bought together, recomputed from the recent orders by the recommendation job. The score is the
+-- cosine similarity of the orders of both products, so that products in every order are not recommended for all.
+CREATE TABLE IF NOT EXISTS kart.product_recommendations (
+    product_id             BIGINT NOT NULL REFERENCES kart.products(id) ON DELETE CASCADE,
+    recommended_product_id BIGINT NOT NULL REFERENCES kart.products(id) ON DELETE CASCADE,
+    score                  DOUBLE PRECISION NOT NULL CHECK (score > 0 AND score <= 1),
+    order_count            INTEGER NOT NULL CHECK (order_count > 0),
+    computed_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, recommended_product_id),
+    CHECK (product_id <> recommended_product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_recommendations_score ON kart.product_recommendations(product_id, score DESC);
 
 CREATE TABLE IF NOT EXISTS kart.coupons (
     code         VARCHAR(10) PRIMARY KEY,
@@ -49,4 +392,16 @@ CREATE TABLE IF NOT EXISTS kart.coupons (
     created_at   TIMESTAMPTZ DEFAULT NOW()
 );
 
-CREATE INDEX IF NOT EXISTS idx_coupons_file_count ON kart.coupons(file_count);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_coupons_file_count ON kart.coupons(file_count);
+
+-- Uses of the coupons, one for each order placed with one. Cancelling the order releases its use, the row stays to
+-- tell when it was released. The uses of a coupon are its rows not released.
+CREATE TABLE IF NOT EXISTS kart.coupon_redemptions (
+    order_id    UUID PRIMARY KEY REFERENCES kart.orders(id) ON DELETE CASCADE,
+    coupon_code VARCHAR(20) NOT NULL,
+    redeemed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_code ON kart.coupon_redemptions(coupon_code)
+    WHERE released_at IS NULL;
\ No newline at end of file
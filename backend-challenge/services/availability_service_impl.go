@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"slices"
+	"time"
+)
+
+type AvailabilityServiceImpl struct {
+	productRepository      base.ProductRepository
+	categoryRepository     base.CategoryRepository
+	availabilityRepository base.AvailabilityRepository
+}
+
+// NewAvailabilityServiceImpl creates a new instance of AvailabilityServiceImpl
+func NewAvailabilityServiceImpl(productRepository base.ProductRepository, categoryRepository base.CategoryRepository, availabilityRepository base.AvailabilityRepository) *AvailabilityServiceImpl {
+	return &AvailabilityServiceImpl{
+		productRepository:      productRepository,
+		categoryRepository:     categoryRepository,
+		availabilityRepository: availabilityRepository,
+	}
+}
+
+// GetProductAvailability Retrieves the availability windows in effect for a product, its own or the ones of its category
+func (s *AvailabilityServiceImpl) GetProductAvailability(ctx context.Context, productId int64) (*models.Availability, *errors.ErrorDetails) {
+	if _, err := s.productRepository.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	windows, err := s.availabilityRepository.GetWindowsByProductIds(ctx, []int64{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	availability := newAvailability(windows[productId])
+	availability.Inherited = len(availability.Windows) > 0 && availability.Windows[0].CategoryId != nil
+	return availability, nil
+}
+
+// SetProductAvailability Replaces the availability windows of a product, without windows the product follows the
+// windows of its category
+func (s *AvailabilityServiceImpl) SetProductAvailability(ctx context.Context, productId int64, request *requests.AvailabilityRequest) (*models.Availability, *errors.ErrorDetails) {
+	windows, err := toAvailabilityWindows(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.availabilityRepository.ReplaceProductWindows(ctx, productId, windows); err != nil {
+		return nil, err
+	}
+
+	return s.GetProductAvailability(ctx, productId)
+}
+
+// GetCategoryAvailability Retrieves the availability windows defined on a category
+func (s *AvailabilityServiceImpl) GetCategoryAvailability(ctx context.Context, categoryId int64) (*models.Availability, *errors.ErrorDetails) {
+	if _, err := s.categoryRepository.GetById(ctx, categoryId); err != nil {
+		return nil, err
+	}
+
+	windows, err := s.availabilityRepository.GetCategoryWindows(ctx, categoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAvailability(windows), nil
+}
+
+// SetCategoryAvailability Replaces the availability windows of a category
+func (s *AvailabilityServiceImpl) SetCategoryAvailability(ctx context.Context, categoryId int64, request *requests.AvailabilityRequest) (*models.Availability, *errors.ErrorDetails) {
+	windows, err := toAvailabilityWindows(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.availabilityRepository.ReplaceCategoryWindows(ctx, categoryId, windows); err != nil {
+		return nil, err
+	}
+
+	return newAvailability(windows), nil
+}
+
+// newAvailability wraps windows and reports whether they are open now
+func newAvailability(windows []*models.AvailabilityWindow) *models.Availability {
+	if windows == nil {
+		windows = []*models.AvailabilityWindow{}
+	}
+
+	return &models.Availability{
+		Windows:      windows,
+		AvailableNow: models.IsAvailableAt(windows, time.Now().In(configs.StoreLocation)),
+	}
+}
+
+// toAvailabilityWindows converts and validates the windows of an availability request
+func toAvailabilityWindows(request *requests.AvailabilityRequest) ([]*models.AvailabilityWindow, *errors.ErrorDetails) {
+	windows := make([]*models.AvailabilityWindow, 0, len(request.Windows))
+	for i, windowRequest := range request.Windows {
+		start, err := models.ParseMinute(windowRequest.Start)
+		if err != nil {
+			return nil, exceptions.BadRequestException(fmt.Sprintf("window %d: %s", i+1, err.Error()))
+		}
+		end, err := models.ParseMinute(windowRequest.End)
+		if err != nil {
+			return nil, exceptions.BadRequestException(fmt.Sprintf("window %d: %s", i+1, err.Error()))
+		}
+
+		if start == models.MinutesPerDay {
+			return nil, exceptions.BadRequestException(fmt.Sprintf("window %d: start must be before 24:00", i+1))
+		}
+		if start == end {
+			return nil, exceptions.BadRequestException(fmt.Sprintf("window %d: start and end must differ", i+1))
+		}
+
+		days := slices.Clone(windowRequest.DaysOfWeek)
+		slices.Sort(days)
+		windows = append(windows, &models.AvailabilityWindow{
+			DaysOfWeek:  slices.Compact(days),
+			StartMinute: start,
+			EndMinute:   end,
+		})
+	}
+	return windows, nil
+}
@@ -0,0 +1,22 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type AvailabilityService interface {
+	// GetProductAvailability retrieves the availability windows in effect for a product
+	GetProductAvailability(ctx context.Context, productId int64) (*models.Availability, *errors.ErrorDetails)
+
+	// SetProductAvailability replaces the availability windows of a product
+	SetProductAvailability(ctx context.Context, productId int64, request *requests.AvailabilityRequest) (*models.Availability, *errors.ErrorDetails)
+
+	// GetCategoryAvailability retrieves the availability windows defined on a category
+	GetCategoryAvailability(ctx context.Context, categoryId int64) (*models.Availability, *errors.ErrorDetails)
+
+	// SetCategoryAvailability replaces the availability windows of a category
+	SetCategoryAvailability(ctx context.Context, categoryId int64, request *requests.AvailabilityRequest) (*models.Availability, *errors.ErrorDetails)
+}
@@ -0,0 +1,8 @@
+package base
+
+import "oolio.com/kart/models"
+
+type CacheService interface {
+	// GetCacheStats returns the counters of every in-memory cache
+	GetCacheStats() []*models.CacheStats
+}
@@ -0,0 +1,16 @@
+package base
+
+import (
+	"context"
+	"io"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type CatalogService interface {
+	// ImportProducts creates and updates products from a catalog file, a dry run only reports what would change
+	ImportProducts(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ProductImport, *errors.ErrorDetails)
+
+	// ExportProducts writes every product to w as a catalog file
+	ExportProducts(ctx context.Context, w io.Writer, format string) *errors.ErrorDetails
+}
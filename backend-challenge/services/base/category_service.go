@@ -0,0 +1,25 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type CategoryService interface {
+	// GetCategoryTree retrieves the top level categories with their subcategories and product counts
+	GetCategoryTree(ctx context.Context, includeInactive bool) ([]*models.Category, *errors.ErrorDetails)
+
+	// GetCategory retrieves a category with its subcategories and product count
+	GetCategory(ctx context.Context, id int64) (*models.Category, *errors.ErrorDetails)
+
+	// CreateCategory creates a new category
+	CreateCategory(ctx context.Context, request *requests.CategoryRequest) (*models.Category, *errors.ErrorDetails)
+
+	// UpdateCategory renames, moves or reorders a category
+	UpdateCategory(ctx context.Context, id int64, request *requests.CategoryRequest) (*models.Category, *errors.ErrorDetails)
+
+	// DeleteCategory deletes a category without subcategories or products
+	DeleteCategory(ctx context.Context, id int64) *errors.ErrorDetails
+}
@@ -0,0 +1,17 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type IdempotencyService interface {
+	// Begin claims an idempotency key for a request with the fingerprint. It returns nil when the request is to be
+	// processed, or the record of the request the key was first used with to replay its response.
+	Begin(ctx context.Context, key string, fingerprint string) (*models.IdempotencyRecord, *errors.ErrorDetails)
+
+	// Complete stores the response of a request that claimed a key, responses of server errors release the key instead
+	// so that the retries are processed again
+	Complete(ctx context.Context, key string, fingerprint string, statusCode int, body []byte) *errors.ErrorDetails
+}
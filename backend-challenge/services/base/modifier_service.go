@@ -0,0 +1,22 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type ModifierService interface {
+	// GetModifierGroups retrieves the modifier groups of a product
+	GetModifierGroups(ctx context.Context, productId int64) ([]*models.ModifierGroup, *errors.ErrorDetails)
+
+	// CreateModifierGroup adds a modifier group to a product
+	CreateModifierGroup(ctx context.Context, productId int64, request *requests.ModifierGroupRequest) (*models.ModifierGroup, *errors.ErrorDetails)
+
+	// UpdateModifierGroup replaces a modifier group of a product
+	UpdateModifierGroup(ctx context.Context, productId int64, groupId int64, request *requests.ModifierGroupRequest) (*models.ModifierGroup, *errors.ErrorDetails)
+
+	// DeleteModifierGroup removes a modifier group from a product
+	DeleteModifierGroup(ctx context.Context, productId int64, groupId int64) *errors.ErrorDetails
+}
@@ -6,9 +6,25 @@ import (
 	"oolio.com/kart/dtos/requests"
 	"oolio.com/kart/dtos/responses"
 	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
 )
 
 type OrderService interface {
 	// PlaceOrder places a new order
 	PlaceOrder(ctx context.Context, request *requests.PlaceOrderRequest) (*responses.OrderResponse, *errors.ErrorDetails)
+
+	// GetOrderById retrieves a placed order by its ID
+	GetOrderById(ctx context.Context, id string) (*responses.OrderResponse, *errors.ErrorDetails)
+
+	// TransitionOrder moves an order to a new status
+	TransitionOrder(ctx context.Context, id string, request *requests.OrderTransitionRequest) (*responses.OrderResponse, *errors.ErrorDetails)
+
+	// GetOrderTransitions retrieves the status changes of an order, oldest first
+	GetOrderTransitions(ctx context.Context, id string) ([]*models.OrderStatusChange, *errors.ErrorDetails)
+
+	// ListOrders retrieves a page of the orders matching the filter
+	ListOrders(ctx context.Context, filter *models.OrderFilter) (*models.OrderPage, *errors.ErrorDetails)
+
+	// QuoteOrder prices an order without placing it
+	QuoteOrder(ctx context.Context, request *requests.PlaceOrderRequest) (*responses.OrderQuoteResponse, *errors.ErrorDetails)
 }
@@ -0,0 +1,13 @@
+package base
+
+import (
+	"context"
+	"io"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type ProductImageService interface {
+	// UploadProductImage generates the renditions of a source image, stores them and sets them as the image of a product
+	UploadProductImage(ctx context.Context, productId int64, source io.Reader) (*models.Product, *errors.ErrorDetails)
+}
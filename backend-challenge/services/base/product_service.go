@@ -2,14 +2,47 @@ package base
 
 import (
 	"context"
+	"oolio.com/kart/dtos/requests"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"time"
 )
 
 type ProductService interface {
-	// GetProducts retrieves a list of products from the database
-	GetProducts(ctx context.Context, offset, limit *int) []*models.Product
+	// GetProducts retrieves a page of products matching the filter from the database, or of deleted products when the
+	// filter asks for them
+	GetProducts(ctx context.Context, filter *models.ProductFilter) (*models.ProductPage, *errors.ErrorDetails)
+
+	// ValidateProductFilter checks a product filter the way GetProducts does, without retrieving the products
+	ValidateProductFilter(filter *models.ProductFilter) *errors.ErrorDetails
 
 	// GetProductById retrieves a product by its ID from the database
 	GetProductById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails)
+
+	// GetProductLastModified retrieves when a product or its category was last changed
+	GetProductLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails)
+
+	// GetCatalogLastModified retrieves when the products were last changed, product listings do not change in between
+	GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails)
+
+	// GetProductPrices retrieves a product with its price history, newest version first
+	GetProductPrices(ctx context.Context, id int64) (*models.Product, []*models.ProductPrice, *errors.ErrorDetails)
+
+	// CreateProduct creates a new product
+	CreateProduct(ctx context.Context, request *requests.ProductRequest) (*models.Product, *errors.ErrorDetails)
+
+	// UpdateProduct replaces all writable fields of an existing product
+	UpdateProduct(ctx context.Context, id int64, request *requests.ProductRequest) (*models.Product, *errors.ErrorDetails)
+
+	// PatchProduct updates only the provided fields of an existing product
+	PatchProduct(ctx context.Context, id int64, request *requests.PatchProductRequest) (*models.Product, *errors.ErrorDetails)
+
+	// UpdateProductStatus moves a product to a new status if the transition is allowed
+	UpdateProductStatus(ctx context.Context, id int64, request *requests.ProductStatusRequest) (*models.Product, *errors.ErrorDetails)
+
+	// DeleteProduct removes a product from the menu, orders placed before keep referring to it
+	DeleteProduct(ctx context.Context, id int64) *errors.ErrorDetails
+
+	// RestoreProduct puts a deleted product back on the menu
+	RestoreProduct(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails)
 }
@@ -0,0 +1,18 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type RecommendationService interface {
+	// GetProductRecommendations retrieves the available products most frequently bought together with a product
+	GetProductRecommendations(ctx context.Context, productId int64, limit int) ([]*models.Product, *errors.ErrorDetails)
+
+	// SuggestProducts retrieves the available products most frequently bought together with the products of an order
+	SuggestProducts(ctx context.Context, productIds []int64) ([]*models.Product, *errors.ErrorDetails)
+
+	// RefreshRecommendations recomputes the recommendations from the recent orders
+	RefreshRecommendations(ctx context.Context) *errors.ErrorDetails
+}
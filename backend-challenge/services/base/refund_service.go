@@ -0,0 +1,19 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/exceptions/errors"
+)
+
+type RefundService interface {
+	// RefundOrder refunds a completed order, in full or some of its items, and returns the receipt of the refund
+	RefundOrder(ctx context.Context, orderId string, request *requests.RefundRequest) (*responses.RefundResponse, *errors.ErrorDetails)
+
+	// GetRefunds retrieves the receipts of the refunds of an order, oldest first
+	GetRefunds(ctx context.Context, orderId string) ([]*responses.RefundResponse, *errors.ErrorDetails)
+
+	// GetRefund retrieves the receipt of a refund of an order
+	GetRefund(ctx context.Context, orderId string, refundId int64) (*responses.RefundResponse, *errors.ErrorDetails)
+}
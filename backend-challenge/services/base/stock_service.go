@@ -0,0 +1,16 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type StockService interface {
+	// GetStock retrieves the product with its current stock and latest stock adjustments
+	GetStock(ctx context.Context, productId int64) (*models.Product, []*models.StockAdjustment, *errors.ErrorDetails)
+
+	// AdjustStock changes the stock of a product and returns the product with its latest stock adjustments
+	AdjustStock(ctx context.Context, productId int64, request *requests.StockAdjustmentRequest) (*models.Product, []*models.StockAdjustment, *errors.ErrorDetails)
+}
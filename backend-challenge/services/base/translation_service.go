@@ -0,0 +1,28 @@
+package base
+
+import (
+	"context"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+type TranslationService interface {
+	// GetProductTranslations retrieves the translations of a product in every locale
+	GetProductTranslations(ctx context.Context, productId int64) ([]*models.ProductTranslation, *errors.ErrorDetails)
+
+	// SetProductTranslation creates or replaces the translation of a product in a locale
+	SetProductTranslation(ctx context.Context, productId int64, locale string, request *requests.ProductTranslationRequest) (*models.ProductTranslation, *errors.ErrorDetails)
+
+	// DeleteProductTranslation deletes the translation of a product in a locale
+	DeleteProductTranslation(ctx context.Context, productId int64, locale string) *errors.ErrorDetails
+
+	// GetCategoryTranslations retrieves the translations of a category in every locale
+	GetCategoryTranslations(ctx context.Context, categoryId int64) ([]*models.CategoryTranslation, *errors.ErrorDetails)
+
+	// SetCategoryTranslation creates or replaces the translation of a category in a locale
+	SetCategoryTranslation(ctx context.Context, categoryId int64, locale string, request *requests.CategoryTranslationRequest) (*models.CategoryTranslation, *errors.ErrorDetails)
+
+	// DeleteCategoryTranslation deletes the translation of a category in a locale
+	DeleteCategoryTranslation(ctx context.Context, categoryId int64, locale string) *errors.ErrorDetails
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+)
+
+type CacheServiceImpl struct {
+	caches []base.Cache
+}
+
+// NewCacheServiceImpl creates a new instance of CacheServiceImpl
+func NewCacheServiceImpl(caches ...base.Cache) *CacheServiceImpl {
+	return &CacheServiceImpl{
+		caches: caches,
+	}
+}
+
+// GetCacheStats Returns the counters of every in-memory cache
+func (s *CacheServiceImpl) GetCacheStats() []*models.CacheStats {
+	stats := make([]*models.CacheStats, len(s.caches))
+	for i, cache := range s.caches {
+		stats[i] = cache.Stats()
+	}
+	return stats
+}
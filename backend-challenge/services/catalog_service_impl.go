@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"oolio.com/kart/catalog"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"strings"
+)
+
+type CatalogServiceImpl struct {
+	catalogRepository base.CatalogRepository
+	maxImportRows     int
+}
+
+// NewCatalogServiceImpl creates a new instance of CatalogServiceImpl
+func NewCatalogServiceImpl(catalogRepository base.CatalogRepository) *CatalogServiceImpl {
+	return &CatalogServiceImpl{
+		catalogRepository: catalogRepository,
+		maxImportRows:     10000,
+	}
+}
+
+// ImportProducts Creates and updates products from a catalog file, a dry run only reports what would change. Nothing is
+// imported unless every row of the catalog is valid.
+func (s *CatalogServiceImpl) ImportProducts(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ProductImport, *errors.ErrorDetails) {
+	records, decodeErr := catalog.Decode(r, format)
+	if decodeErr != nil {
+		configs.Logger.Error("failed to decode catalog", zap.Error(decodeErr))
+		return nil, exceptions.BadRequestException(decodeErr.Error())
+	}
+
+	if len(records) > s.maxImportRows {
+		configs.Logger.Error("catalog has too many products", zap.Int("count", len(records)))
+		return nil, exceptions.BadRequestException(fmt.Sprintf("catalog must not contain more than %d products", s.maxImportRows))
+	}
+
+	result := &models.ProductImport{DryRun: dryRun}
+	rows := make(map[models.ProductKey]int, len(records))
+	var valid []*models.ProductChange
+	for i := range records {
+		row := i + 1
+		product := toImportedProduct(&records[i])
+
+		message := validateImportedProduct(product)
+		if previous, found := rows[product.Key()]; found && message == "" {
+			message = fmt.Sprintf("duplicate product, already defined in row %d", previous)
+		}
+		if message != "" {
+			result.Errors = append(result.Errors, &models.ProductImportError{Row: row, Name: product.Name, Category: product.Category, Message: message})
+			continue
+		}
+
+		rows[product.Key()] = row
+		valid = append(valid, &models.ProductChange{Row: row, Product: product})
+	}
+
+	keys := make([]models.ProductKey, len(valid))
+	for i, change := range valid {
+		keys[i] = change.Product.Key()
+	}
+
+	existingProducts, err := s.catalogRepository.GetByKeys(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[models.ProductKey]*models.Product, len(existingProducts))
+	for _, product := range existingProducts {
+		existing[product.Key()] = product
+	}
+
+	var changed []*models.Product
+	for _, change := range valid {
+		product := change.Product
+		current, found := existing[product.Key()]
+		if !found {
+			if product.Status == "" {
+				product.Status = models.ProductStatusAvailable
+			}
+			change.Action = models.ImportActionCreate
+			result.Created++
+			result.Changes = append(result.Changes, change)
+			changed = append(changed, product)
+			continue
+		}
+
+		product.Id = current.Id
+		if product.Status == "" {
+			product.Status = current.Status
+		}
+		if !models.CanTransitionProductStatus(current.Status, product.Status) {
+			result.Errors = append(result.Errors, &models.ProductImportError{
+				Row:      change.Row,
+				Name:     product.Name,
+				Category: product.Category,
+				Message:  fmt.Sprintf("cannot change product status from %s to %s", current.Status, product.Status),
+			})
+			continue
+		}
+
+		change.Fields = current.ChangedFields(product)
+		if len(change.Fields) == 0 {
+			change.Action = models.ImportActionUnchanged
+			result.Unchanged++
+		} else {
+			change.Action = models.ImportActionUpdate
+			result.Updated++
+			changed = append(changed, product)
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if len(result.Errors) > 0 {
+		configs.Logger.Error("catalog contains invalid products", zap.Int("errors", len(result.Errors)))
+		return nil, exceptions.UnprocessableEntityException(fmt.Sprintf("%d products of the catalog are invalid, nothing was imported", len(result.Errors)))
+	}
+
+	if err = s.catalogRepository.UpsertProducts(ctx, changed); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExportProducts Writes every product to w as a catalog file
+func (s *CatalogServiceImpl) ExportProducts(ctx context.Context, w io.Writer, format string) *errors.ErrorDetails {
+	encoder, encoderErr := catalog.NewEncoder(w, format)
+	if encoderErr != nil {
+		return exceptions.BadRequestException(encoderErr.Error())
+	}
+
+	err := s.catalogRepository.ExportProducts(ctx, func(product *models.Product) error {
+		return encoder.Encode(toCatalogRecord(product))
+	})
+	if err != nil {
+		return err
+	}
+
+	if closeErr := encoder.Close(); closeErr != nil {
+		configs.Logger.Error("failed to finish catalog export", zap.Error(closeErr))
+		return exceptions.GenericException("failed to export products", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func toImportedProduct(record *catalog.Record) *models.Product {
+	// Category paths are compared as the database stores them, so that "Pizza>Vegetarian" matches "Pizza > Vegetarian"
+	category := strings.TrimSpace(record.Category)
+	if names, err := models.ParseCategoryPath(category); err == nil {
+		category = models.CategoryPath(names)
+	}
+
+	return &models.Product{
+		Name:        strings.TrimSpace(record.Name),
+		Description: strings.TrimSpace(record.Description),
+		Category:    category,
+		Price:       record.Price,
+		Status:      strings.TrimSpace(record.Status),
+		Image: models.Image{
+			Thumbnail: record.Image.Thumbnail,
+			Mobile:    record.Image.Mobile,
+			Tablet:    record.Image.Tablet,
+			Desktop:   record.Image.Desktop,
+		},
+		Allergens: record.Allergens,
+		Diets:     record.Diets,
+		Meta:      record.Meta,
+	}
+}
+
+func toCatalogRecord(product *models.Product) *catalog.Record {
+	return &catalog.Record{
+		Id:          product.Id,
+		Name:        product.Name,
+		Description: product.Description,
+		Category:    product.Category,
+		Price:       product.Price,
+		Status:      product.Status,
+		Image: catalog.Image{
+			Thumbnail: product.Image.Thumbnail,
+			Mobile:    product.Image.Mobile,
+			Tablet:    product.Image.Tablet,
+			Desktop:   product.Image.Desktop,
+		},
+		Allergens: product.Allergens,
+		Diets:     product.Diets,
+		Meta:      product.Meta,
+	}
+}
+
+// validateImportedProduct applies the rules of the product endpoints to an imported product and describes the first
+// violation, an empty status is allowed and resolved against the existing product. Its allergens and diets are normalized.
+func validateImportedProduct(product *models.Product) string {
+	if product.Name == "" {
+		return "product name is required"
+	}
+	if len(product.Name) > 255 {
+		return "product name must not be longer than 255 characters"
+	}
+	if _, err := models.ParseCategoryPath(product.Category); err != nil {
+		return "invalid product category, " + err.Error()
+	}
+
+	switch {
+	case product.Price.Sign() < 0:
+		return "product price must not be negative"
+	case product.Price.Cmp(models.MaxPrice) > 0:
+		return "product price must not be greater than 99999999.99"
+	case product.Status != "" && !models.IsValidProductStatus(product.Status):
+		return "invalid product status"
+	}
+
+	allergens, diets, err := models.NormalizeDietaryAttributes(product.Allergens, product.Diets)
+	if err != nil {
+		return "invalid product dietary attributes, " + err.Error()
+	}
+	product.Allergens, product.Diets = allergens, diets
+	return ""
+}
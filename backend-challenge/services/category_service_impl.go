@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"strconv"
+	"strings"
+)
+
+type CategoryServiceImpl struct {
+	categoryRepository    base.CategoryRepository
+	translationRepository base.TranslationRepository
+}
+
+// NewCategoryServiceImpl creates a new instance of CategoryServiceImpl
+func NewCategoryServiceImpl(categoryRepository base.CategoryRepository, translationRepository base.TranslationRepository) *CategoryServiceImpl {
+	return &CategoryServiceImpl{
+		categoryRepository:    categoryRepository,
+		translationRepository: translationRepository,
+	}
+}
+
+// GetCategoryTree Retrieves the top level categories with their subcategories, an inactive category is left out with
+// its whole subtree unless includeInactive is set. Names are in the locale of the request.
+func (s *CategoryServiceImpl) GetCategoryTree(ctx context.Context, includeInactive bool) ([]*models.Category, *errors.ErrorDetails) {
+	roots, _, err := s.buildTree(ctx, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = localizeCategories(ctx, s.translationRepository, roots); err != nil {
+		return nil, err
+	}
+
+	return roots, nil
+}
+
+// GetCategory Retrieves a category with all its subcategories and product count
+func (s *CategoryServiceImpl) GetCategory(ctx context.Context, id int64) (*models.Category, *errors.ErrorDetails) {
+	_, categories, err := s.buildTree(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	category, found := categories[id]
+	if !found {
+		configs.Logger.Error("category not found", zap.Int64("id", id))
+		return nil, exceptions.GenericException("category not found", http.StatusNotFound)
+	}
+
+	if err = localizeCategories(ctx, s.translationRepository, []*models.Category{category}); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// CreateCategory Creates a new category from the request
+func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, request *requests.CategoryRequest) (*models.Category, *errors.ErrorDetails) {
+	category, err := toCategory(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.categoryRepository.Save(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// UpdateCategory Replaces the name, parent, sort order and active flag of a category, its subcategories move with it
+func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, id int64, request *requests.CategoryRequest) (*models.Category, *errors.ErrorDetails) {
+	category, err := toCategory(request)
+	if err != nil {
+		return nil, err
+	}
+	category.Id = id
+
+	if err = s.categoryRepository.Update(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return s.GetCategory(ctx, id)
+}
+
+// DeleteCategory Deletes a category without subcategories or products
+func (s *CategoryServiceImpl) DeleteCategory(ctx context.Context, id int64) *errors.ErrorDetails {
+	return s.categoryRepository.Delete(ctx, id)
+}
+
+// buildTree links every category to its subcategories and adds the product counts of the subcategories to their
+// parents. It returns the top level categories and every category of the tree by ID.
+func (s *CategoryServiceImpl) buildTree(ctx context.Context, includeInactive bool) ([]*models.Category, map[int64]*models.Category, *errors.ErrorDetails) {
+	categories, err := s.categoryRepository.ListCategories(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byId := make(map[int64]*models.Category, len(categories))
+	for _, category := range categories {
+		byId[category.Id] = category
+	}
+
+	// Categories are listed in menu order, so the children end up in menu order too
+	var roots []*models.Category
+	for _, category := range categories {
+		if category.ParentId == nil {
+			roots = append(roots, category)
+		} else if parent, found := byId[*category.ParentId]; found {
+			parent.Children = append(parent.Children, category)
+		}
+	}
+
+	tree := make(map[int64]*models.Category, len(categories))
+	roots = pruneCategories(roots, includeInactive, tree)
+	if roots == nil {
+		roots = []*models.Category{}
+	}
+
+	return roots, tree, nil
+}
+
+// pruneCategories drops the inactive categories unless includeInactive is set, totals the product counts of the
+// remaining subtrees and indexes the remaining categories by ID
+func pruneCategories(categories []*models.Category, includeInactive bool, tree map[int64]*models.Category) []*models.Category {
+	var kept []*models.Category
+	for _, category := range categories {
+		if !category.Active && !includeInactive {
+			continue
+		}
+
+		category.Children = pruneCategories(category.Children, includeInactive, tree)
+		for _, child := range category.Children {
+			category.ProductCount += child.ProductCount
+		}
+
+		tree[category.Id] = category
+		kept = append(kept, category)
+	}
+	return kept
+}
+
+func toCategory(request *requests.CategoryRequest) (*models.Category, *errors.ErrorDetails) {
+	category := &models.Category{
+		Name:      strings.TrimSpace(request.Name),
+		SortOrder: request.SortOrder,
+		Active:    request.Active == nil || *request.Active,
+	}
+
+	if err := models.ValidateCategoryName(category.Name); err != nil {
+		configs.Logger.Error("invalid category name", zap.String("name", category.Name), zap.Error(err))
+		return nil, exceptions.BadRequestException(err.Error())
+	}
+
+	if request.ParentId != "" {
+		parentId, err := parseCategoryId(request.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentId = parentId
+	}
+
+	return category, nil
+}
+
+// parseCategoryId parses a category ID given in a request body
+func parseCategoryId(value string) (*int64, *errors.ErrorDetails) {
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id <= 0 {
+		configs.Logger.Error("invalid category id", zap.String("id", value))
+		return nil, exceptions.BadRequestException("invalid category id")
+	}
+	return &id, nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// encodeCursor encodes a keyset position into an opaque, URL safe cursor
+func encodeCursor(payload any) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes an opaque cursor created by encodeCursor into the payload
+func decodeCursor(cursor string, payload any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, payload)
+}
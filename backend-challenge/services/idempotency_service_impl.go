@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"time"
+)
+
+type IdempotencyServiceImpl struct {
+	idempotencyRepository base.IdempotencyRepository
+	config                configs.IdempotencyConfiguration
+}
+
+// NewIdempotencyServiceImpl creates a new instance of IdempotencyServiceImpl
+func NewIdempotencyServiceImpl(idempotencyRepository base.IdempotencyRepository, config configs.IdempotencyConfiguration) *IdempotencyServiceImpl {
+	return &IdempotencyServiceImpl{
+		idempotencyRepository: idempotencyRepository,
+		config:                config,
+	}
+}
+
+// Begin Claims an idempotency key for a request. A key used with another request is rejected, and a key whose request
+// is still being processed is a conflict the client retries later.
+func (s *IdempotencyServiceImpl) Begin(ctx context.Context, key string, fingerprint string) (*models.IdempotencyRecord, *errors.ErrorDetails) {
+	record, err := s.idempotencyRepository.ClaimKey(ctx, key, fingerprint, s.config.TTL, s.config.InFlightTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		return nil, nil
+	}
+
+	if record.Fingerprint != fingerprint {
+		configs.Logger.Error("idempotency key reused with another request", zap.String("key", key))
+		return nil, exceptions.UnprocessableEntityException("idempotency key was already used with a different request")
+	}
+
+	if !record.Completed() {
+		configs.Logger.Info("request with idempotency key still in flight", zap.String("key", key))
+		return nil, exceptions.GenericException("a request with this idempotency key is being processed, retry later", http.StatusConflict)
+	}
+
+	return record, nil
+}
+
+// Complete Stores the response of a request that claimed a key, or releases the key after a server error
+func (s *IdempotencyServiceImpl) Complete(ctx context.Context, key string, fingerprint string, statusCode int, body []byte) *errors.ErrorDetails {
+	if statusCode >= http.StatusInternalServerError {
+		return s.idempotencyRepository.ReleaseKey(ctx, key, fingerprint)
+	}
+	return s.idempotencyRepository.SaveResponse(ctx, key, fingerprint, statusCode, body)
+}
+
+// RunPurgeJob Deletes the expired idempotency keys at the configured interval until ctx is done, starting right away.
+// Expired keys are never replayed, the job only keeps the table from growing.
+func (s *IdempotencyServiceImpl) RunPurgeJob(ctx context.Context) {
+	if s.config.PurgeInterval <= 0 {
+		configs.Logger.Info("idempotency key purge job is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.idempotencyRepository.DeleteExpiredKeys(ctx); err != nil {
+			configs.Logger.Error("failed to purge idempotency keys", zap.Any("error", err))
+		} else if count > 0 {
+			configs.Logger.Info("purged expired idempotency keys", zap.Int64("count", count))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
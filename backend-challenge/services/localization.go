@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/i18n"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"strings"
+)
+
+// requestLocale returns the locale negotiated for the request of ctx, empty when it is the default locale, in which
+// products and categories need no translation
+func requestLocale(ctx context.Context) string {
+	locale := i18n.LocaleFrom(ctx)
+	if locale == configs.Locales.Default {
+		return ""
+	}
+	return locale
+}
+
+// localizeProducts replaces the names, descriptions and category paths of the products with their translations in the
+// locale of the request, keeping the default locale ones where there is no translation
+func localizeProducts(ctx context.Context, translationRepository base.TranslationRepository, products []*models.Product) *errors.ErrorDetails {
+	locale := requestLocale(ctx)
+	if locale == "" || len(products) == 0 {
+		return nil
+	}
+
+	productIds := make([]int64, len(products))
+	var paths []string
+	for i, product := range products {
+		productIds[i] = product.Id
+		paths = append(paths, categoryPathPrefixes(product.Category)...)
+	}
+
+	translations, err := translationRepository.GetProductTranslations(ctx, productIds, locale)
+	if err != nil {
+		return err
+	}
+	categoryTranslations, err := translationRepository.GetCategoryTranslations(ctx, paths, locale)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		if translation, found := translations[product.Id]; found {
+			product.Name = translation.Name
+			product.Description = translation.Description
+		}
+		product.Category = localizeCategoryPath(product.Category, categoryTranslations)
+	}
+	return nil
+}
+
+// localizeCategories replaces the names and paths of the categories and their subcategories with their translations
+// in the locale of the request
+func localizeCategories(ctx context.Context, translationRepository base.TranslationRepository, categories []*models.Category) *errors.ErrorDetails {
+	locale := requestLocale(ctx)
+	if locale == "" || len(categories) == 0 {
+		return nil
+	}
+
+	var paths []string
+	collectCategoryPaths(categories, &paths)
+
+	translations, err := translationRepository.GetCategoryTranslations(ctx, paths, locale)
+	if err != nil {
+		return err
+	}
+
+	translateCategories(categories, translations)
+	return nil
+}
+
+func collectCategoryPaths(categories []*models.Category, paths *[]string) {
+	for _, category := range categories {
+		*paths = append(*paths, category.Path)
+		collectCategoryPaths(category.Children, paths)
+	}
+}
+
+// translateCategories translates the names and paths of categories and their subcategories, each path is translated
+// from its own default locale path
+func translateCategories(categories []*models.Category, translations map[string]*models.CategoryTranslation) {
+	for _, category := range categories {
+		if translation, found := translations[category.Path]; found {
+			category.Name = translation.Name
+		}
+		category.Path = localizeCategoryPath(category.Path, translations)
+		translateCategories(category.Children, translations)
+	}
+}
+
+// categoryPathPrefixes returns the paths of a category and its ancestors, e.g. "Pizza" and "Pizza > Vegetarian"
+func categoryPathPrefixes(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	names := strings.Split(path, models.CategoryPathSeparator)
+	prefixes := make([]string, len(names))
+	for i := range names {
+		prefixes[i] = models.CategoryPath(names[:i+1])
+	}
+	return prefixes
+}
+
+// localizeCategoryPath translates each category of a path, keeping the names of the categories without a translation
+func localizeCategoryPath(path string, translations map[string]*models.CategoryTranslation) string {
+	prefixes := categoryPathPrefixes(path)
+	if len(prefixes) == 0 {
+		return path
+	}
+
+	names := strings.Split(path, models.CategoryPathSeparator)
+	for i, prefix := range prefixes {
+		if translation, found := translations[prefix]; found {
+			names[i] = translation.Name
+		}
+	}
+	return models.CategoryPath(names)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"strings"
+)
+
+type ModifierServiceImpl struct {
+	productRepository  base.ProductRepository
+	modifierRepository base.ModifierRepository
+}
+
+// NewModifierServiceImpl creates a new instance of ModifierServiceImpl
+func NewModifierServiceImpl(productRepository base.ProductRepository, modifierRepository base.ModifierRepository) *ModifierServiceImpl {
+	return &ModifierServiceImpl{
+		productRepository:  productRepository,
+		modifierRepository: modifierRepository,
+	}
+}
+
+// GetModifierGroups Retrieves the modifier groups of a product
+func (m *ModifierServiceImpl) GetModifierGroups(ctx context.Context, productId int64) ([]*models.ModifierGroup, *errors.ErrorDetails) {
+	if _, err := m.productRepository.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	groups, err := m.modifierRepository.GetGroupsByProductIds(ctx, []int64{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	if groups[productId] == nil {
+		return []*models.ModifierGroup{}, nil
+	}
+	return groups[productId], nil
+}
+
+// CreateModifierGroup Adds a modifier group to a product
+func (m *ModifierServiceImpl) CreateModifierGroup(ctx context.Context, productId int64, request *requests.ModifierGroupRequest) (*models.ModifierGroup, *errors.ErrorDetails) {
+	group := toModifierGroup(productId, request)
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	if err := m.modifierRepository.SaveGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// UpdateModifierGroup Replaces a modifier group of a product
+func (m *ModifierServiceImpl) UpdateModifierGroup(ctx context.Context, productId int64, groupId int64, request *requests.ModifierGroupRequest) (*models.ModifierGroup, *errors.ErrorDetails) {
+	group := toModifierGroup(productId, request)
+	group.Id = groupId
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	if err := m.modifierRepository.UpdateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// DeleteModifierGroup Removes a modifier group from a product
+func (m *ModifierServiceImpl) DeleteModifierGroup(ctx context.Context, productId int64, groupId int64) *errors.ErrorDetails {
+	return m.modifierRepository.DeleteGroup(ctx, productId, groupId)
+}
+
+func toModifierGroup(productId int64, request *requests.ModifierGroupRequest) *models.ModifierGroup {
+	group := &models.ModifierGroup{
+		ProductId: productId,
+		Name:      strings.TrimSpace(request.Name),
+		MinSelect: request.MinSelect,
+		MaxSelect: request.MaxSelect,
+		SortOrder: request.SortOrder,
+		Modifiers: make([]*models.Modifier, len(request.Modifiers)),
+	}
+
+	for i, modifier := range request.Modifiers {
+		group.Modifiers[i] = &models.Modifier{
+			Name:       strings.TrimSpace(modifier.Name),
+			PriceDelta: modifier.PriceDelta,
+			SortOrder:  modifier.SortOrder,
+		}
+	}
+
+	return group
+}
+
+// validateModifierGroup checks that the selection rules of a group can be satisfied
+func validateModifierGroup(group *models.ModifierGroup) *errors.ErrorDetails {
+	if group.Name == "" {
+		configs.Logger.Error("modifier group name must not be blank")
+		return exceptions.BadRequestException("modifier group name must not be blank")
+	}
+
+	if group.MinSelect > group.MaxSelect {
+		configs.Logger.Error("minSelect must not be greater than maxSelect")
+		return exceptions.BadRequestException("minSelect must not be greater than maxSelect")
+	}
+
+	if group.MinSelect > len(group.Modifiers) {
+		configs.Logger.Error("minSelect must not be greater than the number of modifiers")
+		return exceptions.BadRequestException("minSelect must not be greater than the number of modifiers")
+	}
+
+	names := make(map[string]bool, len(group.Modifiers))
+	for _, modifier := range group.Modifiers {
+		if modifier.Name == "" {
+			configs.Logger.Error("modifier name must not be blank")
+			return exceptions.BadRequestException("modifier name must not be blank")
+		}
+		if names[modifier.Name] {
+			configs.Logger.Error("modifier names must be unique within a group")
+			return exceptions.BadRequestException("modifier names must be unique within a group")
+		}
+		names[modifier.Name] = true
+	}
+
+	return nil
+}
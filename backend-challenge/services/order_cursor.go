@@ -0,0 +1,70 @@
+package services
+
+import (
+	"go.uber.org/zap"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"time"
+)
+
+// orderCursor is the payload of an order listing cursor, the sort and direction are kept so that a cursor cannot be
+// replayed against a listing with a different order
+type orderCursor struct {
+	Sort      string `json:"s"`
+	Direction string `json:"d"`
+	Value     string `json:"v"`
+	Id        string `json:"i"`
+}
+
+// encodeOrderCursor creates the cursor pointing after the given order
+func encodeOrderCursor(filter *models.OrderFilter, order *models.Order) (string, error) {
+	cursor := orderCursor{
+		Sort:      filter.Sort,
+		Direction: filter.Direction,
+		Id:        order.Id,
+	}
+
+	switch filter.Sort {
+	case models.OrderSortTotal:
+		cursor.Value = order.Total.String()
+	case models.OrderSortCreatedAt:
+		cursor.Value = order.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return encodeCursor(cursor)
+}
+
+// decodeOrderCursor validates a cursor against the filter and converts it to a typed keyset position
+func decodeOrderCursor(filter *models.OrderFilter) (*models.OrderCursor, *errors.ErrorDetails) {
+	var cursor orderCursor
+	if err := decodeCursor(filter.Cursor, &cursor); err != nil {
+		configs.Logger.Error("invalid order cursor", zap.Error(err))
+		return nil, exceptions.BadRequestException("invalid cursor")
+	}
+
+	if cursor.Sort != filter.Sort || cursor.Direction != filter.Direction {
+		configs.Logger.Error("order cursor does not match the requested sort",
+			zap.String("sort", filter.Sort), zap.String("direction", filter.Direction))
+		return nil, exceptions.BadRequestException("cursor does not match the requested sort")
+	}
+
+	position := &models.OrderCursor{Id: cursor.Id}
+
+	var err error
+	switch cursor.Sort {
+	case models.OrderSortTotal:
+		position.Value, err = money.Parse(cursor.Value)
+	case models.OrderSortCreatedAt:
+		position.Value, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	}
+
+	if err != nil || !models.IsOrderId(cursor.Id) {
+		configs.Logger.Error("invalid order cursor value", zap.Error(err))
+		return nil, exceptions.BadRequestException("invalid cursor")
+	}
+
+	return position, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// orderLine is a line of an order being placed together with the modifiers requested for it
+type orderLine struct {
+	item        models.OrderItem
+	modifierIds []int64
+}
+
+// parseModifierIds parses the requested modifier IDs and sorts them, so the same selection always compares equal
+func parseModifierIds(values []string) ([]int64, *errors.ErrorDetails) {
+	ids := make([]int64, 0, len(values))
+	for _, value := range values {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			configs.Logger.Error("invalid modifier id")
+			return nil, exceptions.BadRequestException("invalid modifier id")
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for i := 1; i < len(ids); i++ {
+		if ids[i] == ids[i-1] {
+			configs.Logger.Error("modifier selected more than once")
+			return nil, exceptions.BadRequestException("modifier selected more than once")
+		}
+	}
+
+	return ids, nil
+}
+
+func joinIds(ids []int64) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(values, ",")
+}
+
+// selectModifiers checks the selection against the modifier groups of the product and returns the chosen modifiers
+// in menu order together with the price they add to one unit of the product
+func selectModifiers(product *models.Product, groups []*models.ModifierGroup, selectedIds []int64) ([]models.OrderItemModifier, money.Money, *errors.ItemError) {
+	selected := make(map[int64]bool, len(selectedIds))
+	for _, id := range selectedIds {
+		selected[id] = true
+	}
+
+	var modifiers []models.OrderItemModifier
+	var priceDelta money.Money
+	for _, group := range groups {
+		count := 0
+		for _, modifier := range group.Modifiers {
+			if !selected[modifier.Id] {
+				continue
+			}
+			delete(selected, modifier.Id)
+			count++
+
+			modifiers = append(modifiers, models.OrderItemModifier{
+				ModifierId: modifier.Id,
+				GroupName:  group.Name,
+				Name:       modifier.Name,
+				PriceDelta: modifier.PriceDelta,
+			})
+			var err error
+			if priceDelta, err = priceDelta.Add(modifier.PriceDelta); err != nil {
+				return nil, money.Money{}, &errors.ItemError{
+					ProductId: strconv.FormatInt(product.Id, 10),
+					Reason:    "price_out_of_range",
+					Message:   "the chosen modifiers change the price of the product by more than an amount can be",
+				}
+			}
+		}
+
+		if count < group.MinSelect || count > group.MaxSelect {
+			return nil, money.Money{}, &errors.ItemError{
+				ProductId: strconv.FormatInt(product.Id, 10),
+				Reason:    "invalid_modifier_selection",
+				Message:   selectionRule(group),
+			}
+		}
+	}
+
+	for _, id := range selectedIds {
+		if selected[id] {
+			return nil, money.Money{}, &errors.ItemError{
+				ProductId: strconv.FormatInt(product.Id, 10),
+				Reason:    "invalid_modifier",
+				Message:   fmt.Sprintf("modifier %d is not available for this product", id),
+			}
+		}
+	}
+
+	return modifiers, priceDelta, nil
+}
+
+// selectionRule describes how many modifiers of a group must be chosen
+func selectionRule(group *models.ModifierGroup) string {
+	if group.MinSelect == group.MaxSelect {
+		return fmt.Sprintf("choose exactly %d of %s", group.MinSelect, group.Name)
+	}
+	if group.MinSelect == 0 {
+		return fmt.Sprintf("choose at most %d of %s", group.MaxSelect, group.Name)
+	}
+	return fmt.Sprintf("choose between %d and %d of %s", group.MinSelect, group.MaxSelect, group.Name)
+}
+
+func containsItemError(items []errors.ItemError, productId int64) bool {
+	id := strconv.FormatInt(productId, 10)
+	for _, item := range items {
+		if item.ProductId == id {
+			return true
+		}
+	}
+	return false
+}
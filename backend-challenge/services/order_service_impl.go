@@ -2,39 +2,246 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"go.uber.org/zap"
 	"net/http"
 	"oolio.com/kart/configs"
 	"oolio.com/kart/exceptions"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"oolio.com/kart/dtos/requests"
 	"oolio.com/kart/dtos/responses"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"oolio.com/kart/money"
 	repoBase "oolio.com/kart/repositories/base"
 	serviceBase "oolio.com/kart/services/base"
 )
 
 type OrderServiceImpl struct {
-	orderRepository       repoBase.OrderRepository
-	productRepository     repoBase.ProductRepository
-	couponService         serviceBase.CouponService
-	maxQuantityPerProduct int
+	orderRepository        repoBase.OrderRepository
+	productRepository      repoBase.ProductRepository
+	modifierRepository     repoBase.ModifierRepository
+	availabilityRepository repoBase.AvailabilityRepository
+	translationRepository  repoBase.TranslationRepository
+	couponService          serviceBase.CouponService
+	recommendationService  serviceBase.RecommendationService
+	maxQuantityPerProduct  int
 }
 
 // NewOrderServiceImpl creates a new instance of OrderServiceImpl
-func NewOrderServiceImpl(orderRepository repoBase.OrderRepository, productRepository repoBase.ProductRepository, couponService serviceBase.CouponService) *OrderServiceImpl {
+func NewOrderServiceImpl(orderRepository repoBase.OrderRepository, productRepository repoBase.ProductRepository, modifierRepository repoBase.ModifierRepository, availabilityRepository repoBase.AvailabilityRepository, translationRepository repoBase.TranslationRepository, couponService serviceBase.CouponService, recommendationService serviceBase.RecommendationService) *OrderServiceImpl {
 	return &OrderServiceImpl{
-		orderRepository:       orderRepository,
-		productRepository:     productRepository,
-		couponService:         couponService,
-		maxQuantityPerProduct: 1000,
+		orderRepository:        orderRepository,
+		productRepository:      productRepository,
+		modifierRepository:     modifierRepository,
+		availabilityRepository: availabilityRepository,
+		translationRepository:  translationRepository,
+		couponService:          couponService,
+		recommendationService:  recommendationService,
+		maxQuantityPerProduct:  1000,
 	}
 }
 
+// pricedOrder is an order priced from a request, with its lines and their products
+type pricedOrder struct {
+	order    *models.Order
+	items    []models.OrderItem
+	products []*models.Product
+}
+
 // PlaceOrder places a new order
 func (s *OrderServiceImpl) PlaceOrder(ctx context.Context, request *requests.PlaceOrderRequest) (*responses.OrderResponse, *errors.ErrorDetails) {
+	priced, err := s.priceOrder(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if saveErr := s.orderRepository.CreateOrder(ctx, priced.order, priced.items); saveErr != nil {
+		configs.Logger.Error("failed to create order", zap.Any("error", saveErr))
+		return nil, saveErr
+	}
+
+	// The order is placed by now, products without their translations are rendered in the default locale
+	if localizeErr := localizeProducts(ctx, s.translationRepository, priced.products); localizeErr != nil {
+		configs.Logger.Warn("failed to localize order products", zap.Any("error", localizeErr))
+	}
+
+	return responses.ToOrderResponse(priced.order, priced.items, priced.products), nil
+}
+
+// GetOrderById retrieves a placed order with the products of its items, deleted ones included, in the locale of the
+// request
+func (s *OrderServiceImpl) GetOrderById(ctx context.Context, id string) (*responses.OrderResponse, *errors.ErrorDetails) {
+	order, items, err := s.orderRepository.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toOrderResponse(ctx, order, items)
+}
+
+// TransitionOrder moves an order to the requested status if the transition is allowed and the order is still at the
+// version the transition was decided on
+func (s *OrderServiceImpl) TransitionOrder(ctx context.Context, id string, request *requests.OrderTransitionRequest) (*responses.OrderResponse, *errors.ErrorDetails) {
+	order, items, err := s.orderRepository.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Version != request.Version {
+		configs.Logger.Error("order version mismatch", zap.String("id", id),
+			zap.Int("version", order.Version), zap.Int("expected", request.Version))
+		return nil, exceptions.GenericException(
+			fmt.Sprintf("order is at version %d, not %d, reload it and retry", order.Version, request.Version), http.StatusConflict)
+	}
+
+	if !models.CanTransitionOrderStatus(order.Status, request.Status) {
+		configs.Logger.Error("invalid order status transition", zap.String("from", order.Status), zap.String("to", request.Status))
+		return nil, exceptions.UnprocessableEntityException(invalidTransitionMessage(order.Status, request.Status))
+	}
+
+	change := &models.OrderStatusChange{
+		FromStatus: order.Status,
+		ToStatus:   request.Status,
+		Actor:      request.Actor,
+		Reason:     request.Reason,
+	}
+	if err = s.orderRepository.TransitionOrderStatus(ctx, order, change); err != nil {
+		return nil, err
+	}
+
+	return s.toOrderResponse(ctx, order, items)
+}
+
+// GetOrderTransitions retrieves the status changes of an order, oldest first
+func (s *OrderServiceImpl) GetOrderTransitions(ctx context.Context, id string) ([]*models.OrderStatusChange, *errors.ErrorDetails) {
+	return s.orderRepository.GetStatusHistory(ctx, id)
+}
+
+// toOrderResponse converts an order to a response along with the products of its items, deleted ones included, in
+// the locale of the request
+func (s *OrderServiceImpl) toOrderResponse(ctx context.Context, order *models.Order, items []models.OrderItem) (*responses.OrderResponse, *errors.ErrorDetails) {
+	productIds := make([]int64, 0, len(items))
+	for _, item := range items {
+		if !slices.Contains(productIds, item.ProductId) {
+			productIds = append(productIds, item.ProductId)
+		}
+	}
+
+	products := []*models.Product{}
+	if len(productIds) > 0 {
+		var err *errors.ErrorDetails
+		if products, err = s.productRepository.GetByIds(ctx, productIds); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := localizeProducts(ctx, s.translationRepository, products); err != nil {
+		return nil, err
+	}
+
+	return responses.ToOrderResponse(order, items, products), nil
+}
+
+// invalidTransitionMessage explains why an order cannot move between two statuses and where it can move instead
+func invalidTransitionMessage(from, to string) string {
+	next := models.NextOrderStatuses(from)
+	if len(next) == 0 {
+		return fmt.Sprintf("cannot move an order from %s to %s, %s orders are final", from, to, from)
+	}
+	return fmt.Sprintf("cannot move an order from %s to %s, it can only move to %s", from, to, strings.Join(next, ", "))
+}
+
+// ListOrders retrieves a page of the orders matching the filter, newest first unless sorted otherwise
+func (s *OrderServiceImpl) ListOrders(ctx context.Context, filter *models.OrderFilter) (*models.OrderPage, *errors.ErrorDetails) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		configs.Logger.Error("from must be before to")
+		return nil, exceptions.BadRequestException("from must be before to")
+	}
+	if filter.MinTotal != nil && filter.MaxTotal != nil && filter.MinTotal.Cmp(*filter.MaxTotal) > 0 {
+		configs.Logger.Error("minTotal must not be greater than maxTotal")
+		return nil, exceptions.BadRequestException("minTotal must not be greater than maxTotal")
+	}
+
+	query := *filter
+	if query.Sort == "" {
+		query.Sort = models.OrderSortCreatedAt
+	}
+	if query.Direction == "" {
+		query.Direction = models.SortDesc
+	}
+
+	if query.Cursor != "" {
+		after, err := decodeOrderCursor(&query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+	}
+
+	// One extra row is fetched to find out whether there is a next page
+	query.Limit = filter.Limit + 1
+
+	orders, totalCount, err := s.orderRepository.ListOrders(ctx, &query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.OrderPage{
+		Orders:     orders,
+		TotalCount: totalCount,
+	}
+
+	if len(orders) > filter.Limit {
+		page.Orders = orders[:filter.Limit]
+
+		nextCursor, encodeErr := encodeOrderCursor(&query, page.Orders[len(page.Orders)-1])
+		if encodeErr != nil {
+			configs.Logger.Error("failed to encode order cursor", zap.Error(encodeErr))
+			return nil, exceptions.GenericException("failed to encode cursor", http.StatusInternalServerError)
+		}
+		page.NextCursor = nextCursor
+	}
+
+	return page, nil
+}
+
+// QuoteOrder prices an order the way PlaceOrder would without placing it, along with the products frequently bought
+// together with the ones of the order
+func (s *OrderServiceImpl) QuoteOrder(ctx context.Context, request *requests.PlaceOrderRequest) (*responses.OrderQuoteResponse, *errors.ErrorDetails) {
+	priced, err := s.priceOrder(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = localizeProducts(ctx, s.translationRepository, priced.products); err != nil {
+		return nil, err
+	}
+
+	// Suggestions are an extra, a quote is still given without them
+	var suggestions []*models.Product
+	if s.recommendationService != nil {
+		productIds := make([]int64, len(priced.products))
+		for i, product := range priced.products {
+			productIds[i] = product.Id
+		}
+
+		var suggestErr *errors.ErrorDetails
+		if suggestions, suggestErr = s.recommendationService.SuggestProducts(ctx, productIds); suggestErr != nil {
+			configs.Logger.Warn("failed to suggest products", zap.Any("error", suggestErr))
+			suggestions = nil
+		}
+	}
+
+	return responses.ToOrderQuoteResponse(priced.order, priced.items, priced.products, suggestions), nil
+}
+
+// priceOrder validates an order request and prices its lines with the current prices of the products and modifiers
+func (s *OrderServiceImpl) priceOrder(ctx context.Context, request *requests.PlaceOrderRequest) (*pricedOrder, *errors.ErrorDetails) {
 	if request.CouponCode != "" {
 		if s.couponService == nil {
 			configs.Logger.Error("coupon service not available")
@@ -53,15 +260,23 @@ func (s *OrderServiceImpl) PlaceOrder(ctx context.Context, request *requests.Pla
 		}
 	}
 
-	itemMap := make(map[string]*models.OrderItem)
+	// Lines of the same product with the same modifiers are merged, the same product with other modifiers is a new line
+	itemMap := make(map[string]*orderLine)
+	var itemOrder []string
 	for _, reqItem := range request.Items {
-		if existing, found := itemMap[reqItem.ProductId]; found {
-			newQty := existing.Quantity + *reqItem.Quantity
+		modifierIds, err := parseModifierIds(reqItem.Modifiers)
+		if err != nil {
+			return nil, err
+		}
+
+		key := reqItem.ProductId + ":" + joinIds(modifierIds)
+		if existing, found := itemMap[key]; found {
+			newQty := existing.item.Quantity + *reqItem.Quantity
 			if newQty > s.maxQuantityPerProduct {
 				configs.Logger.Error("quantity exceeds maximum limit")
 				return nil, exceptions.BadRequestException("quantity exceeds maximum limit")
 			}
-			existing.Quantity = newQty
+			existing.item.Quantity = newQty
 		} else {
 			productId, err := strconv.ParseInt(reqItem.ProductId, 10, 64)
 			if err != nil {
@@ -74,26 +289,32 @@ func (s *OrderServiceImpl) PlaceOrder(ctx context.Context, request *requests.Pla
 				return nil, exceptions.BadRequestException("quantity exceeds maximum limit")
 			}
 
-			domainItem := models.OrderItem{
-				ProductId: productId,
-				Quantity:  *reqItem.Quantity,
+			itemMap[key] = &orderLine{
+				item: models.OrderItem{
+					ProductId: productId,
+					Quantity:  *reqItem.Quantity,
+				},
+				modifierIds: modifierIds,
 			}
-			itemMap[reqItem.ProductId] = &domainItem
+			itemOrder = append(itemOrder, key)
 		}
 	}
 
-	var aggregatedItems []models.OrderItem
-	for _, item := range itemMap {
-		aggregatedItems = append(aggregatedItems, *item)
+	lines := make([]*orderLine, 0, len(itemOrder))
+	for _, key := range itemOrder {
+		lines = append(lines, itemMap[key])
 	}
 
-	var subtotal float64
-	var discount float64 = 0
+	var discount money.Money
 	var products []*models.Product
 
-	productIds := make([]int64, 0, len(aggregatedItems))
-	for i := range aggregatedItems {
-		productIds = append(productIds, aggregatedItems[i].ProductId)
+	productIds := make([]int64, 0, len(lines))
+	seen := make(map[int64]bool)
+	for _, line := range lines {
+		if !seen[line.item.ProductId] {
+			seen[line.item.ProductId] = true
+			productIds = append(productIds, line.item.ProductId)
+		}
 	}
 
 	const batchSize = 100
@@ -116,36 +337,138 @@ func (s *OrderServiceImpl) PlaceOrder(ctx context.Context, request *requests.Pla
 		productMap[product.Id] = product
 	}
 
-	for i := range aggregatedItems {
-		product, exists := productMap[aggregatedItems[i].ProductId]
+	var unavailableItems []errors.ItemError
+	for _, line := range lines {
+		product, exists := productMap[line.item.ProductId]
 		if !exists {
 			return nil, exceptions.BadRequestException("product not found")
 		}
 
-		aggregatedItems[i].UnitPrice = product.Price
-		aggregatedItems[i].Price = product.Price * float64(aggregatedItems[i].Quantity)
-		subtotal += aggregatedItems[i].Price
+		if !product.IsOrderable() && !containsItemError(unavailableItems, product.Id) {
+			unavailableItems = append(unavailableItems, unavailableItemError(product))
+		}
+	}
+
+	windows, err := s.availabilityRepository.GetWindowsByProductIds(ctx, productIds)
+	if err != nil {
+		configs.Logger.Error("failed to fetch availability windows", zap.Any("error", err))
+		return nil, err
+	}
+
+	// Windows are wall clock times of the store, whatever the time zone of the server
+	now := time.Now().In(configs.StoreLocation)
+	for _, productId := range productIds {
+		if !models.IsAvailableAt(windows[productId], now) && !containsItemError(unavailableItems, productId) {
+			unavailableItems = append(unavailableItems, outOfHoursItemError(productId, windows[productId]))
+		}
+	}
+
+	if len(unavailableItems) > 0 {
+		configs.Logger.Error("order contains unavailable products", zap.Any("items", unavailableItems))
+		return nil, exceptions.UnprocessableItemsException("some products are not available", unavailableItems)
+	}
+
+	modifierGroups, err := s.modifierRepository.GetGroupsByProductIds(ctx, productIds)
+	if err != nil {
+		configs.Logger.Error("failed to fetch modifier groups", zap.Any("error", err))
+		return nil, err
+	}
+
+	var invalidItems []errors.ItemError
+	aggregatedItems := make([]models.OrderItem, 0, len(lines))
+	for _, line := range lines {
+		product := productMap[line.item.ProductId]
+
+		modifiers, priceDelta, itemErr := selectModifiers(product, modifierGroups[product.Id], line.modifierIds)
+		if itemErr != nil {
+			invalidItems = append(invalidItems, *itemErr)
+			continue
+		}
+
+		item := line.item
+		item.Modifiers = modifiers
+		unitPrice, addErr := product.Price.Add(priceDelta)
+		if addErr != nil || unitPrice.Cmp(models.MaxPrice) > 0 {
+			invalidItems = append(invalidItems, errors.ItemError{
+				ProductId: strconv.FormatInt(product.Id, 10),
+				Reason:    "price_out_of_range",
+				Message:   fmt.Sprintf("the chosen modifiers take the price of the product above %s", models.MaxPrice),
+			})
+			continue
+		}
+		item.UnitPrice = unitPrice
+		item.PriceVersion = product.PriceVersion
+		// Modifiers may take money off, but not more than the product costs
+		if item.UnitPrice.Sign() < 0 {
+			invalidItems = append(invalidItems, errors.ItemError{
+				ProductId: strconv.FormatInt(product.Id, 10),
+				Reason:    "negative_price",
+				Message:   "the chosen modifiers take the price of the product below zero",
+			})
+			continue
+		}
+		aggregatedItems = append(aggregatedItems, item)
 	}
 
-	// TODO Discount logic comes here
-	var total = subtotal
-	if discount > 0 {
-		// TODO Apply discount with limit
-		total -= discount
+	if len(invalidItems) > 0 {
+		configs.Logger.Error("order contains invalid modifiers", zap.Any("items", invalidItems))
+		return nil, exceptions.UnprocessableItemsException("some items have invalid modifiers", invalidItems)
 	}
 
+	// TODO Discount logic comes here, with a limit
 	order := &models.Order{
 		CouponCode: request.CouponCode,
-		Subtotal:   subtotal,
 		Discount:   discount,
-		Total:      total,
+	}
+	if err := models.PriceOrder(order, aggregatedItems); err != nil {
+		configs.Logger.Error("order amounts are out of range", zap.Error(err))
+		return nil, exceptions.UnprocessableEntityException(err.Error())
 	}
 
-	if saveErr := s.orderRepository.CreateOrder(ctx, order, aggregatedItems); saveErr != nil {
-		configs.Logger.Error("failed to create order", zap.Any("error", saveErr))
-		return nil, saveErr
+	return &pricedOrder{order: order, items: aggregatedItems, products: products}, nil
+}
+
+// unavailableItemError describes why a product that is not available cannot be ordered
+func unavailableItemError(product *models.Product) errors.ItemError {
+	item := errors.ItemError{ProductId: strconv.FormatInt(product.Id, 10)}
+
+	if product.IsDeleted() {
+		item.Reason = "product_deleted"
+		item.Message = "product is no longer on the menu"
+		return item
+	}
+
+	if product.CategoryInactive {
+		item.Reason = "category_inactive"
+		item.Message = "the category of the product is not on the menu"
+		return item
 	}
 
-	response := responses.ToOrderResponse(order, aggregatedItems, products)
-	return response, nil
+	switch product.Status {
+	case models.ProductStatusSoldOut:
+		item.Reason = "product_sold_out"
+		item.Message = "product is sold out"
+	case models.ProductStatusDiscontinued:
+		item.Reason = "product_discontinued"
+		item.Message = "product is discontinued"
+	default:
+		item.Reason = "product_unavailable"
+		item.Message = "product is not available"
+	}
+
+	return item
+}
+
+// outOfHoursItemError describes when a product that is outside of its availability windows can be ordered
+func outOfHoursItemError(productId int64, windows []*models.AvailabilityWindow) errors.ItemError {
+	descriptions := make([]string, len(windows))
+	for i, window := range windows {
+		descriptions[i] = window.String()
+	}
+
+	return errors.ItemError{
+		ProductId: strconv.FormatInt(productId, 10),
+		Reason:    "product_out_of_hours",
+		Message:   "product is only available " + strings.Join(descriptions, ", ") + " (" + configs.StoreLocation.String() + ")",
+	}
 }
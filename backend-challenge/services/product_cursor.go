@@ -0,0 +1,74 @@
+package services
+
+import (
+	"go.uber.org/zap"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"time"
+)
+
+// productCursor is the payload of a product listing cursor, the sort and direction are kept so that a cursor
+// cannot be replayed against a listing with a different order
+type productCursor struct {
+	Sort      string `json:"s"`
+	Direction string `json:"d"`
+	Value     string `json:"v,omitempty"`
+	Id        int64  `json:"i"`
+}
+
+// encodeProductCursor creates the cursor pointing after the given product
+func encodeProductCursor(filter *models.ProductFilter, product *models.Product) (string, error) {
+	cursor := productCursor{
+		Sort:      filter.Sort,
+		Direction: filter.Direction,
+		Id:        product.Id,
+	}
+
+	switch filter.Sort {
+	case models.ProductSortPrice:
+		cursor.Value = product.Price.String()
+	case models.ProductSortName:
+		cursor.Value = product.Name
+	case models.ProductSortCreatedAt:
+		cursor.Value = product.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return encodeCursor(cursor)
+}
+
+// decodeProductCursor validates a cursor against the filter and converts it to a typed keyset position
+func decodeProductCursor(filter *models.ProductFilter) (*models.ProductCursor, *errors.ErrorDetails) {
+	var cursor productCursor
+	if err := decodeCursor(filter.Cursor, &cursor); err != nil {
+		configs.Logger.Error("invalid product cursor", zap.Error(err))
+		return nil, exceptions.BadRequestException("invalid cursor")
+	}
+
+	if cursor.Sort != filter.Sort || cursor.Direction != filter.Direction {
+		configs.Logger.Error("product cursor does not match the requested sort",
+			zap.String("sort", filter.Sort), zap.String("direction", filter.Direction))
+		return nil, exceptions.BadRequestException("cursor does not match the requested sort")
+	}
+
+	position := &models.ProductCursor{Id: cursor.Id}
+
+	var err error
+	switch cursor.Sort {
+	case models.ProductSortPrice:
+		position.Value, err = money.Parse(cursor.Value)
+	case models.ProductSortName:
+		position.Value = cursor.Value
+	case models.ProductSortCreatedAt:
+		position.Value, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	}
+
+	if err != nil {
+		configs.Logger.Error("invalid product cursor value", zap.Error(err))
+		return nil, exceptions.BadRequestException("invalid cursor")
+	}
+
+	return position, nil
+}
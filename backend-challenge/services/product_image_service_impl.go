@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	goErrors "errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/images"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+)
+
+type ProductImageServiceImpl struct {
+	productRepository base.ProductRepository
+	imageStorage      base.ImageStorage
+	renditions        []images.Rendition
+}
+
+// NewProductImageServiceImpl creates a new instance of ProductImageServiceImpl generating the given renditions
+func NewProductImageServiceImpl(productRepository base.ProductRepository, imageStorage base.ImageStorage, renditions []images.Rendition) *ProductImageServiceImpl {
+	return &ProductImageServiceImpl{
+		productRepository: productRepository,
+		imageStorage:      imageStorage,
+		renditions:        renditions,
+	}
+}
+
+// UploadProductImage Generates the renditions of a source image, stores them and sets them as the image of a product.
+// Every upload is stored under new keys so that cached renditions of the previous image are never served for the new
+// one, the renditions of the previous image are deleted once the product refers to the new ones.
+func (s *ProductImageServiceImpl) UploadProductImage(ctx context.Context, productId int64, source io.Reader) (*models.Product, *errors.ErrorDetails) {
+	product, errDetails := s.productRepository.GetById(ctx, productId)
+	if errDetails != nil {
+		return nil, errDetails
+	}
+
+	content, err := io.ReadAll(source)
+	if err != nil {
+		configs.Logger.Error("failed to read image", zap.Error(err))
+		return nil, exceptions.BadRequestException("failed to read image")
+	}
+
+	encoded, err := images.Generate(content, s.renditions)
+	if err != nil {
+		configs.Logger.Error("failed to generate image renditions", zap.Int64("productId", productId), zap.Error(err))
+		if goErrors.Is(err, images.ErrUnsupportedImage) {
+			return nil, exceptions.GenericException(err.Error(), http.StatusUnsupportedMediaType)
+		}
+		return nil, exceptions.UnprocessableEntityException(err.Error())
+	}
+
+	token, err := newImageToken()
+	if err != nil {
+		configs.Logger.Error("failed to generate image token", zap.Error(err))
+		return nil, exceptions.GenericException("failed to store image", http.StatusInternalServerError)
+	}
+
+	previous := product.Image
+	var stored []string
+	for _, rendition := range encoded {
+		key := fmt.Sprintf("products/%d/%s-%s.%s", productId, token, rendition.Name, rendition.Extension)
+		url, errDetails := s.imageStorage.Save(ctx, key, rendition.ContentType, rendition.Content)
+		if errDetails != nil {
+			s.deleteImages(ctx, stored)
+			return nil, errDetails
+		}
+		stored = append(stored, key)
+		setRendition(&product.Image, rendition.Name, url)
+	}
+
+	if errDetails = s.productRepository.UpdateImage(ctx, product); errDetails != nil {
+		s.deleteImages(ctx, stored)
+		return nil, errDetails
+	}
+
+	// Images that were not uploaded, such as the ones of the product migration, are left alone
+	var replaced []string
+	for _, url := range []string{previous.Thumbnail, previous.Mobile, previous.Tablet, previous.Desktop} {
+		if key, ok := s.imageStorage.KeyOf(url); ok {
+			replaced = append(replaced, key)
+		}
+	}
+	s.deleteImages(ctx, replaced)
+
+	return product, nil
+}
+
+// deleteImages removes stored images that are no longer referred to, failures only leave unused files behind
+func (s *ProductImageServiceImpl) deleteImages(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if errDetails := s.imageStorage.Delete(ctx, key); errDetails != nil {
+			configs.Logger.Warn("failed to delete unused image", zap.String("key", key))
+		}
+	}
+}
+
+// setRendition sets the URL of the named rendition on the image of a product
+func setRendition(image *models.Image, name string, url string) {
+	switch name {
+	case images.RenditionThumbnail:
+		image.Thumbnail = url
+	case images.RenditionMobile:
+		image.Mobile = url
+	case images.RenditionTablet:
+		image.Tablet = url
+	case images.RenditionDesktop:
+		image.Desktop = url
+	}
+}
+
+// newImageToken returns a random token making the keys of every upload unique
+func newImageToken() (string, error) {
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -2,28 +2,405 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
 	"oolio.com/kart/repositories/base"
+	"slices"
+	"strings"
+	"time"
 )
 
 type ProductServiceImpl struct {
-	productRepository base.ProductRepository
+	productRepository     base.ProductRepository
+	categoryRepository    base.CategoryRepository
+	translationRepository base.TranslationRepository
 }
 
 // NewProductServiceImpl creates a new instance of ProductServiceImpl
-func NewProductServiceImpl(productRepository base.ProductRepository) *ProductServiceImpl {
+func NewProductServiceImpl(productRepository base.ProductRepository, categoryRepository base.CategoryRepository, translationRepository base.TranslationRepository) *ProductServiceImpl {
 	return &ProductServiceImpl{
-		productRepository: productRepository,
+		productRepository:     productRepository,
+		categoryRepository:    categoryRepository,
+		translationRepository: translationRepository,
 	}
 }
 
-// GetProducts Retrieves a list of products from the database
-func (p *ProductServiceImpl) GetProducts(ctx context.Context, offset, limit *int) []*models.Product {
-	return p.productRepository.ListProducts(ctx, offset, limit)
+// GetProducts Retrieves a page of products matching the filter from the database, in the locale of the request
+func (p *ProductServiceImpl) GetProducts(ctx context.Context, filter *models.ProductFilter) (*models.ProductPage, *errors.ErrorDetails) {
+	query, err := productQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row is fetched to find out whether there is a next page
+	if filter.Limit != nil {
+		probe := *filter.Limit + 1
+		query.Limit = &probe
+	}
+
+	products, totalCount, err := p.productRepository.ListProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ProductPage{
+		Products:   products,
+		TotalCount: totalCount,
+	}
+
+	if filter.Limit != nil && len(products) > *filter.Limit {
+		page.Products = products[:*filter.Limit]
+
+		nextCursor, encodeErr := encodeProductCursor(query, page.Products[len(page.Products)-1])
+		if encodeErr != nil {
+			configs.Logger.Error("failed to encode product cursor", zap.Error(encodeErr))
+			return nil, exceptions.GenericException("failed to encode cursor", http.StatusInternalServerError)
+		}
+		page.NextCursor = nextCursor
+	}
+
+	if err = localizeProducts(ctx, p.translationRepository, page.Products); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ValidateProductFilter Checks a product filter the way GetProducts does, without retrieving the products
+func (p *ProductServiceImpl) ValidateProductFilter(filter *models.ProductFilter) *errors.ErrorDetails {
+	_, err := productQuery(filter)
+	return err
 }
 
-// GetProductById Retrieves a product by its ID from the database
+// productQuery validates a product filter and completes it with its defaults and the position of its cursor
+func productQuery(filter *models.ProductFilter) (*models.ProductFilter, *errors.ErrorDetails) {
+	if filter.MinPrice != nil && filter.MaxPrice != nil && filter.MinPrice.Cmp(*filter.MaxPrice) > 0 {
+		configs.Logger.Error("minPrice must not be greater than maxPrice")
+		return nil, exceptions.BadRequestException("minPrice must not be greater than maxPrice")
+	}
+
+	query := *filter
+	if query.Status == "" && !query.Deleted {
+		// Products that cannot be ordered are only listed when explicitly asked for
+		query.Status = models.ProductStatusAvailable
+	}
+	if query.AvailableNow {
+		if query.AvailableAt != nil {
+			configs.Logger.Error("availableNow and at cannot be combined")
+			return nil, exceptions.BadRequestException("availableNow and at cannot be combined")
+		}
+
+		// Windows are set in minutes, the listing stays the same for the rest of the minute
+		now := time.Now().Truncate(time.Minute)
+		query.AvailableAt = &now
+		query.AvailableNow = false
+	}
+	// Sorted lists give the same cache key whatever the order they were asked in
+	query.ExcludeAllergens = slices.Compact(slices.Sorted(slices.Values(query.ExcludeAllergens)))
+	query.Diets = slices.Compact(slices.Sorted(slices.Values(query.Diets)))
+	if query.Sort == "" {
+		query.Sort = models.ProductSortId
+	}
+	if query.Direction == "" {
+		query.Direction = models.SortAsc
+	}
+
+	if query.Cursor != "" {
+		if query.Offset != nil {
+			configs.Logger.Error("cursor and offset cannot be combined")
+			return nil, exceptions.BadRequestException("cursor and offset cannot be combined")
+		}
+
+		after, err := decodeProductCursor(&query)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+	}
+
+	return &query, nil
+}
+
+// GetProductById Retrieves a product by its ID from the database, in the locale of the request
 func (p *ProductServiceImpl) GetProductById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
+	product, err := p.productRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = localizeProducts(ctx, p.translationRepository, []*models.Product{product}); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// GetProductLastModified Retrieves when a product or its category was last changed
+func (p *ProductServiceImpl) GetProductLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails) {
+	return p.productRepository.GetLastModified(ctx, id)
+}
+
+// GetCatalogLastModified Retrieves when the products were last changed. Filters, sorting and pages all read from the
+// same products, so a single time covers every listing.
+func (p *ProductServiceImpl) GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails) {
+	return p.productRepository.GetCatalogLastModified(ctx)
+}
+
+// GetProductPrices Retrieves a product with its price history, newest version first
+func (p *ProductServiceImpl) GetProductPrices(ctx context.Context, id int64) (*models.Product, []*models.ProductPrice, *errors.ErrorDetails) {
+	product, err := p.productRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prices, err := p.productRepository.GetPriceHistory(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, prices, nil
+}
+
+// CreateProduct Creates a new product from the request
+func (p *ProductServiceImpl) CreateProduct(ctx context.Context, request *requests.ProductRequest) (*models.Product, *errors.ErrorDetails) {
+	product := &models.Product{Status: models.ProductStatusAvailable}
+	if err := applyProductRequest(product, request); err != nil {
+		return nil, err
+	}
+
+	if err := p.resolveCategory(ctx, product, request.CategoryId, request.Category); err != nil {
+		return nil, err
+	}
+
+	if err := p.productRepository.Save(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// UpdateProduct Replaces all writable fields of an existing product
+func (p *ProductServiceImpl) UpdateProduct(ctx context.Context, id int64, request *requests.ProductRequest) (*models.Product, *errors.ErrorDetails) {
+	product, err := p.productRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentStatus := product.Status
+	if err = applyProductRequest(product, request); err != nil {
+		return nil, err
+	}
+
+	if err = validateStatusTransition(currentStatus, product.Status); err != nil {
+		return nil, err
+	}
+
+	if err = p.resolveCategory(ctx, product, request.CategoryId, request.Category); err != nil {
+		return nil, err
+	}
+
+	if err = p.productRepository.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// PatchProduct Updates only the fields present in the request
+func (p *ProductServiceImpl) PatchProduct(ctx context.Context, id int64, request *requests.PatchProductRequest) (*models.Product, *errors.ErrorDetails) {
+	product, err := p.productRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentStatus := product.Status
+	if request.Name != nil {
+		product.Name = strings.TrimSpace(*request.Name)
+	}
+	if request.Description != nil {
+		product.Description = strings.TrimSpace(*request.Description)
+	}
+	if request.Price != nil {
+		product.Price = *request.Price
+	}
+	if request.Status != nil {
+		product.Status = strings.TrimSpace(*request.Status)
+	}
+	if request.Image != nil {
+		product.Image = toImage(request.Image)
+	}
+	if request.Allergens != nil {
+		product.Allergens = *request.Allergens
+	}
+	if request.Diets != nil {
+		product.Diets = *request.Diets
+	}
+	if request.Meta != nil {
+		product.Meta = request.Meta
+	}
+
+	if err = validateProduct(product); err != nil {
+		return nil, err
+	}
+
+	if err = validateStatusTransition(currentStatus, product.Status); err != nil {
+		return nil, err
+	}
+
+	if request.CategoryId != nil || request.Category != nil {
+		var categoryId, category string
+		if request.CategoryId != nil {
+			categoryId = *request.CategoryId
+		}
+		if request.Category != nil {
+			category = *request.Category
+		}
+		if err = p.resolveCategory(ctx, product, categoryId, category); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = p.productRepository.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// UpdateProductStatus Moves a product to a new status if the transition is allowed
+func (p *ProductServiceImpl) UpdateProductStatus(ctx context.Context, id int64, request *requests.ProductStatusRequest) (*models.Product, *errors.ErrorDetails) {
+	product, err := p.productRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentStatus := product.Status
+	if err = validateStatusTransition(currentStatus, request.Status); err != nil {
+		return nil, err
+	}
+
+	product.Status = request.Status
+	if err = p.productRepository.UpdateStatus(ctx, product, currentStatus); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// DeleteProduct Removes a product from the menu, orders placed before keep referring to it
+func (p *ProductServiceImpl) DeleteProduct(ctx context.Context, id int64) *errors.ErrorDetails {
+	return p.productRepository.Delete(ctx, id)
+}
+
+// RestoreProduct Puts a deleted product back on the menu
+func (p *ProductServiceImpl) RestoreProduct(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
+	if err := p.productRepository.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
 	return p.productRepository.GetById(ctx, id)
 }
+
+// resolveCategory sets the category of the product from its ID when given, otherwise from its path, creating the
+// categories of the path that do not exist yet
+func (p *ProductServiceImpl) resolveCategory(ctx context.Context, product *models.Product, categoryId string, path string) *errors.ErrorDetails {
+	var category *models.Category
+	if categoryId != "" {
+		id, err := parseCategoryId(categoryId)
+		if err != nil {
+			return err
+		}
+
+		category, err = p.categoryRepository.GetById(ctx, *id)
+		if err != nil {
+			if err.ErrorCode == http.StatusNotFound {
+				return exceptions.UnprocessableEntityException("product category not found")
+			}
+			return err
+		}
+	} else {
+		names, parseErr := models.ParseCategoryPath(path)
+		if parseErr != nil {
+			configs.Logger.Error("invalid product category", zap.String("category", path), zap.Error(parseErr))
+			return exceptions.BadRequestException("invalid product category, " + parseErr.Error())
+		}
+
+		var err *errors.ErrorDetails
+		category, err = p.categoryRepository.EnsurePath(ctx, names)
+		if err != nil {
+			return err
+		}
+	}
+
+	product.CategoryId = category.Id
+	product.Category = category.Path
+	return nil
+}
+
+// applyProductRequest copies the request fields onto the product and validates the result, the status is kept when none is given
+func applyProductRequest(product *models.Product, request *requests.ProductRequest) *errors.ErrorDetails {
+	product.Name = strings.TrimSpace(request.Name)
+	product.Description = strings.TrimSpace(request.Description)
+	product.Price = *request.Price
+	if status := strings.TrimSpace(request.Status); status != "" {
+		product.Status = status
+	}
+	product.Image = toImage(&request.Image)
+	product.Allergens = request.Allergens
+	product.Diets = request.Diets
+	product.Meta = request.Meta
+
+	return validateProduct(product)
+}
+
+// validateProduct validates the fields of a product before it is written, its allergens and diets are normalized
+func validateProduct(product *models.Product) *errors.ErrorDetails {
+	if product.Name == "" {
+		configs.Logger.Error("product name is required")
+		return exceptions.BadRequestException("product name is required")
+	}
+
+	if product.Price.Sign() < 0 {
+		configs.Logger.Error("product price must not be negative", zap.Stringer("price", product.Price))
+		return exceptions.BadRequestException("product price must not be negative")
+	}
+
+	if !models.IsValidProductStatus(product.Status) {
+		configs.Logger.Error("invalid product status", zap.String("status", product.Status))
+		return exceptions.BadRequestException("invalid product status")
+	}
+
+	allergens, diets, err := models.NormalizeDietaryAttributes(product.Allergens, product.Diets)
+	if err != nil {
+		configs.Logger.Error("invalid product dietary attributes", zap.Strings("allergens", product.Allergens), zap.Strings("diets", product.Diets), zap.Error(err))
+		return exceptions.BadRequestException("invalid product dietary attributes, " + err.Error())
+	}
+	product.Allergens, product.Diets = allergens, diets
+
+	return nil
+}
+
+// validateStatusTransition checks that a product may move from its current status to the requested one
+func validateStatusTransition(from, to string) *errors.ErrorDetails {
+	if !models.CanTransitionProductStatus(from, to) {
+		configs.Logger.Error("invalid product status transition", zap.String("from", from), zap.String("to", to))
+		return exceptions.UnprocessableEntityException(fmt.Sprintf("cannot change product status from %s to %s", from, to))
+	}
+
+	return nil
+}
+
+func toImage(request *requests.ImageRequest) models.Image {
+	return models.Image{
+		Thumbnail: request.Thumbnail,
+		Mobile:    request.Mobile,
+		Tablet:    request.Tablet,
+		Desktop:   request.Desktop,
+	}
+}
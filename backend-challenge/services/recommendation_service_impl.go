@@ -0,0 +1,166 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"go.uber.org/zap"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/constants"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"slices"
+	"time"
+)
+
+type RecommendationServiceImpl struct {
+	productRepository        base.ProductRepository
+	recommendationRepository base.RecommendationRepository
+	availabilityRepository   base.AvailabilityRepository
+	translationRepository    base.TranslationRepository
+	config                   configs.RecommendationConfiguration
+}
+
+// NewRecommendationServiceImpl creates a new instance of RecommendationServiceImpl
+func NewRecommendationServiceImpl(productRepository base.ProductRepository, recommendationRepository base.RecommendationRepository, availabilityRepository base.AvailabilityRepository, translationRepository base.TranslationRepository, config configs.RecommendationConfiguration) *RecommendationServiceImpl {
+	return &RecommendationServiceImpl{
+		productRepository:        productRepository,
+		recommendationRepository: recommendationRepository,
+		availabilityRepository:   availabilityRepository,
+		translationRepository:    translationRepository,
+		config:                   config,
+	}
+}
+
+// GetProductRecommendations Retrieves the products most frequently bought together with a product, among the ones
+// that can be ordered now, in the locale of the request
+func (s *RecommendationServiceImpl) GetProductRecommendations(ctx context.Context, productId int64, limit int) ([]*models.Product, *errors.ErrorDetails) {
+	if _, err := s.productRepository.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	recommendations, err := s.recommendationRepository.GetRecommendations(ctx, []int64{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	productIds := make([]int64, len(recommendations))
+	for i, recommendation := range recommendations {
+		productIds[i] = recommendation.RecommendedProductId
+	}
+	return s.recommendableProducts(ctx, productIds, limit)
+}
+
+// SuggestProducts Retrieves the products most frequently bought together with the products of an order and not in it
+// yet, among the ones that can be ordered now. A product recommended for several products of the order ranks by the
+// sum of its scores.
+func (s *RecommendationServiceImpl) SuggestProducts(ctx context.Context, productIds []int64) ([]*models.Product, *errors.ErrorDetails) {
+	if s.config.QuoteSuggestions <= 0 || len(productIds) == 0 {
+		return nil, nil
+	}
+
+	recommendations, err := s.recommendationRepository.GetRecommendations(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int64]float64)
+	for _, recommendation := range recommendations {
+		if !slices.Contains(productIds, recommendation.RecommendedProductId) {
+			scores[recommendation.RecommendedProductId] += recommendation.Score
+		}
+	}
+
+	suggestedIds := make([]int64, 0, len(scores))
+	for productId := range scores {
+		suggestedIds = append(suggestedIds, productId)
+	}
+	slices.SortFunc(suggestedIds, func(a, b int64) int {
+		return cmp.Or(cmp.Compare(scores[b], scores[a]), cmp.Compare(a, b))
+	})
+
+	return s.recommendableProducts(ctx, suggestedIds, s.config.QuoteSuggestions)
+}
+
+// RefreshRecommendations Recomputes the recommendations from the orders placed within the configured window
+func (s *RecommendationServiceImpl) RefreshRecommendations(ctx context.Context) *errors.ErrorDetails {
+	started := time.Now()
+	count, refreshed, err := s.recommendationRepository.RefreshRecommendations(ctx, started.Add(-s.config.Window),
+		s.config.MinOrders, constants.MaxRecommendations)
+	if err != nil {
+		return err
+	}
+
+	if !refreshed {
+		configs.Logger.Info("recommendations are being refreshed by another instance")
+		return nil
+	}
+	configs.Logger.Info("refreshed recommendations", zap.Int64("count", count), zap.Duration("took", time.Since(started)))
+	return nil
+}
+
+// RunRefreshJob Refreshes the recommendations at the configured interval until ctx is done, starting right away. A
+// failed refresh is retried at the next interval, the previous recommendations are served meanwhile.
+func (s *RecommendationServiceImpl) RunRefreshJob(ctx context.Context) {
+	if s.config.RefreshInterval <= 0 {
+		configs.Logger.Info("recommendation refresh job is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RefreshRecommendations(ctx); err != nil {
+			configs.Logger.Error("failed to refresh recommendations", zap.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recommendableProducts returns up to limit of the products in the order of their ids, leaving out the ones that
+// cannot be ordered now
+func (s *RecommendationServiceImpl) recommendableProducts(ctx context.Context, productIds []int64, limit int) ([]*models.Product, *errors.ErrorDetails) {
+	if len(productIds) == 0 {
+		return []*models.Product{}, nil
+	}
+
+	products, err := s.productRepository.GetByIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := s.availabilityRepository.GetWindowsByProductIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	productMap := make(map[int64]*models.Product, len(products))
+	for _, product := range products {
+		productMap[product.Id] = product
+	}
+
+	now := time.Now().In(configs.StoreLocation)
+	recommended := make([]*models.Product, 0, min(limit, len(productIds)))
+	for _, productId := range productIds {
+		product, found := productMap[productId]
+		if !found || !product.IsOrderable() || !models.IsAvailableAt(windows[productId], now) {
+			continue
+		}
+
+		recommended = append(recommended, product)
+		if len(recommended) == limit {
+			break
+		}
+	}
+
+	if err = localizeProducts(ctx, s.translationRepository, recommended); err != nil {
+		return nil, err
+	}
+	return recommended, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"strconv"
+)
+
+type RefundServiceImpl struct {
+	orderRepository       base.OrderRepository
+	productRepository     base.ProductRepository
+	refundRepository      base.RefundRepository
+	translationRepository base.TranslationRepository
+}
+
+// NewRefundServiceImpl creates a new instance of RefundServiceImpl
+func NewRefundServiceImpl(orderRepository base.OrderRepository, productRepository base.ProductRepository, refundRepository base.RefundRepository, translationRepository base.TranslationRepository) *RefundServiceImpl {
+	return &RefundServiceImpl{
+		orderRepository:       orderRepository,
+		productRepository:     productRepository,
+		refundRepository:      refundRepository,
+		translationRepository: translationRepository,
+	}
+}
+
+// RefundOrder Refunds the requested items of a completed order, or everything not refunded yet when none are
+// requested, and returns the receipt of the refund. Orders that are not completed yet are cancelled instead.
+func (s *RefundServiceImpl) RefundOrder(ctx context.Context, orderId string, request *requests.RefundRequest) (*responses.RefundResponse, *errors.ErrorDetails) {
+	quantities, err := toRefundQuantities(request.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	order, items, err := s.orderRepository.GetOrderById(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != models.OrderStatusCompleted {
+		configs.Logger.Error("order cannot be refunded", zap.String("id", orderId), zap.String("status", order.Status))
+		return nil, exceptions.UnprocessableEntityException(
+			fmt.Sprintf("cannot refund a %s order, only completed orders are refunded", order.Status))
+	}
+
+	previous, err := s.refundRepository.GetRefundsByOrderId(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	refund, planErr := models.PlanRefund(order, items, previous, quantities)
+	if planErr != nil {
+		configs.Logger.Error("invalid refund", zap.String("id", orderId), zap.Error(planErr))
+		return nil, exceptions.UnprocessableEntityException(planErr.Error())
+	}
+	refund.Actor = request.Actor
+	refund.Reason = request.Reason
+	refund.Restock = request.Restock
+
+	if err = s.refundRepository.CreateRefund(ctx, refund, len(previous)); err != nil {
+		return nil, err
+	}
+
+	names, err := s.productNames(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	refunded, sumErr := models.RefundedAmount(append(previous, refund))
+	if sumErr != nil {
+		configs.Logger.Error("failed to sum refunds", zap.String("id", orderId), zap.Error(sumErr))
+		return nil, exceptions.GenericException("some internal error occurred", http.StatusInternalServerError)
+	}
+
+	return responses.ToRefundResponse(order, refund, refunded, names), nil
+}
+
+// GetRefunds Retrieves the receipts of the refunds of an order, oldest first
+func (s *RefundServiceImpl) GetRefunds(ctx context.Context, orderId string) ([]*responses.RefundResponse, *errors.ErrorDetails) {
+	order, items, err := s.orderRepository.GetOrderById(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.refundRepository.GetRefundsByOrderId(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := s.productNames(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]*responses.RefundResponse, len(refunds))
+	for i, refund := range refunds {
+		refunded, sumErr := models.RefundedAmount(refunds[:i+1])
+		if sumErr != nil {
+			configs.Logger.Error("failed to sum refunds", zap.String("id", orderId), zap.Error(sumErr))
+			return nil, exceptions.GenericException("some internal error occurred", http.StatusInternalServerError)
+		}
+		receipts[i] = responses.ToRefundResponse(order, refund, refunded, names)
+	}
+	return receipts, nil
+}
+
+// GetRefund Retrieves the receipt of a refund of an order
+func (s *RefundServiceImpl) GetRefund(ctx context.Context, orderId string, refundId int64) (*responses.RefundResponse, *errors.ErrorDetails) {
+	receipts, err := s.GetRefunds(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, receipt := range receipts {
+		if receipt.Id == strconv.FormatInt(refundId, 10) {
+			return receipt, nil
+		}
+	}
+
+	configs.Logger.Error("refund not found", zap.String("orderId", orderId), zap.Int64("id", refundId))
+	return nil, exceptions.GenericException("refund not found", http.StatusNotFound)
+}
+
+// productNames returns the names of the products of the items in the locale of the request, keyed by product ID
+func (s *RefundServiceImpl) productNames(ctx context.Context, items []models.OrderItem) (map[int64]string, *errors.ErrorDetails) {
+	productIds := make([]int64, 0, len(items))
+	for _, item := range items {
+		productIds = append(productIds, item.ProductId)
+	}
+
+	names := make(map[int64]string, len(productIds))
+	if len(productIds) == 0 {
+		return names, nil
+	}
+
+	products, err := s.productRepository.GetByIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = localizeProducts(ctx, s.translationRepository, products); err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		names[product.Id] = product.Name
+	}
+	return names, nil
+}
+
+// toRefundQuantities converts the items of a refund request to the quantities to refund keyed by order item ID, nil
+// when the whole order is refunded
+func toRefundQuantities(items []requests.RefundItemRequest) (map[int64]int, *errors.ErrorDetails) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	quantities := make(map[int64]int, len(items))
+	for _, item := range items {
+		id, err := strconv.ParseInt(item.OrderItemId, 10, 64)
+		if err != nil || id <= 0 {
+			configs.Logger.Error("invalid order item id", zap.String("orderItemId", item.OrderItemId))
+			return nil, exceptions.BadRequestException("invalid order item id")
+		}
+		if _, found := quantities[id]; found {
+			configs.Logger.Error("order item refunded twice", zap.Int64("orderItemId", id))
+			return nil, exceptions.BadRequestException(fmt.Sprintf("order item %d is listed more than once", id))
+		}
+		quantities[id] = item.Quantity
+	}
+	return quantities, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+)
+
+type StockServiceImpl struct {
+	productRepository base.ProductRepository
+	stockRepository   base.StockRepository
+	adjustmentsLimit  int
+}
+
+// NewStockServiceImpl creates a new instance of StockServiceImpl
+func NewStockServiceImpl(productRepository base.ProductRepository, stockRepository base.StockRepository) *StockServiceImpl {
+	return &StockServiceImpl{
+		productRepository: productRepository,
+		stockRepository:   stockRepository,
+		adjustmentsLimit:  50,
+	}
+}
+
+// GetStock Retrieves the product with its current stock and latest stock adjustments
+func (s *StockServiceImpl) GetStock(ctx context.Context, productId int64) (*models.Product, []*models.StockAdjustment, *errors.ErrorDetails) {
+	product, err := s.productRepository.GetById(ctx, productId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	adjustments, err := s.stockRepository.GetAdjustments(ctx, productId, s.adjustmentsLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, adjustments, nil
+}
+
+// AdjustStock Changes the stock of a product and returns the product with its latest stock adjustments
+func (s *StockServiceImpl) AdjustStock(ctx context.Context, productId int64, request *requests.StockAdjustmentRequest) (*models.Product, []*models.StockAdjustment, *errors.ErrorDetails) {
+	provided := 0
+	if request.Delta != nil {
+		provided++
+	}
+	if request.Quantity != nil {
+		provided++
+	}
+	if request.Unlimited {
+		provided++
+	}
+	if provided != 1 {
+		configs.Logger.Error("exactly one of delta, quantity and unlimited must be provided")
+		return nil, nil, exceptions.BadRequestException("exactly one of delta, quantity and unlimited must be provided")
+	}
+
+	// An unlimited stock is stored as a nil quantity
+	adjustment := &models.StockAdjustment{
+		ProductId:     productId,
+		Delta:         request.Delta,
+		QuantityAfter: request.Quantity,
+		Reason:        request.Reason,
+		Actor:         request.Actor,
+	}
+
+	if err := s.stockRepository.AdjustStock(ctx, adjustment); err != nil {
+		return nil, nil, err
+	}
+
+	return s.GetStock(ctx, productId)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/repositories/base"
+	"strings"
+)
+
+type TranslationServiceImpl struct {
+	productRepository     base.ProductRepository
+	categoryRepository    base.CategoryRepository
+	translationRepository base.TranslationRepository
+}
+
+// NewTranslationServiceImpl creates a new instance of TranslationServiceImpl
+func NewTranslationServiceImpl(productRepository base.ProductRepository, categoryRepository base.CategoryRepository, translationRepository base.TranslationRepository) *TranslationServiceImpl {
+	return &TranslationServiceImpl{
+		productRepository:     productRepository,
+		categoryRepository:    categoryRepository,
+		translationRepository: translationRepository,
+	}
+}
+
+// GetProductTranslations Retrieves the translations of a product in every locale
+func (s *TranslationServiceImpl) GetProductTranslations(ctx context.Context, productId int64) ([]*models.ProductTranslation, *errors.ErrorDetails) {
+	if _, err := s.productRepository.GetById(ctx, productId); err != nil {
+		return nil, err
+	}
+
+	return s.translationRepository.ListProductTranslations(ctx, productId)
+}
+
+// SetProductTranslation Creates or replaces the translation of a product in a supported locale
+func (s *TranslationServiceImpl) SetProductTranslation(ctx context.Context, productId int64, locale string, request *requests.ProductTranslationRequest) (*models.ProductTranslation, *errors.ErrorDetails) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &models.ProductTranslation{
+		ProductId:   productId,
+		Locale:      locale,
+		Name:        strings.TrimSpace(request.Name),
+		Description: strings.TrimSpace(request.Description),
+	}
+	if translation.Name == "" {
+		configs.Logger.Error("translated product name is required")
+		return nil, exceptions.BadRequestException("name is required")
+	}
+
+	if err = s.translationRepository.SaveProductTranslation(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// DeleteProductTranslation Deletes the translation of a product in a supported locale
+func (s *TranslationServiceImpl) DeleteProductTranslation(ctx context.Context, productId int64, locale string) *errors.ErrorDetails {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	return s.translationRepository.DeleteProductTranslation(ctx, productId, locale)
+}
+
+// GetCategoryTranslations Retrieves the translations of a category in every locale
+func (s *TranslationServiceImpl) GetCategoryTranslations(ctx context.Context, categoryId int64) ([]*models.CategoryTranslation, *errors.ErrorDetails) {
+	if _, err := s.categoryRepository.GetById(ctx, categoryId); err != nil {
+		return nil, err
+	}
+
+	return s.translationRepository.ListCategoryTranslations(ctx, categoryId)
+}
+
+// SetCategoryTranslation Creates or replaces the translation of a category in a supported locale
+func (s *TranslationServiceImpl) SetCategoryTranslation(ctx context.Context, categoryId int64, locale string, request *requests.CategoryTranslationRequest) (*models.CategoryTranslation, *errors.ErrorDetails) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &models.CategoryTranslation{
+		CategoryId: categoryId,
+		Locale:     locale,
+		Name:       strings.TrimSpace(request.Name),
+	}
+	if validationErr := models.ValidateCategoryName(translation.Name); validationErr != nil {
+		configs.Logger.Error("invalid translated category name", zap.Error(validationErr))
+		return nil, exceptions.BadRequestException(validationErr.Error())
+	}
+
+	if err = s.translationRepository.SaveCategoryTranslation(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// DeleteCategoryTranslation Deletes the translation of a category in a supported locale
+func (s *TranslationServiceImpl) DeleteCategoryTranslation(ctx context.Context, categoryId int64, locale string) *errors.ErrorDetails {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	return s.translationRepository.DeleteCategoryTranslation(ctx, categoryId, locale)
+}
+
+// translationLocale returns the canonical form of a locale translations can be written in, a supported locale other
+// than the default one, whose names are the ones of the products and categories themselves
+func translationLocale(locale string) (string, *errors.ErrorDetails) {
+	canonical, found := configs.Locales.Canonical(locale)
+	if !found {
+		configs.Logger.Error("unsupported locale", zap.String("locale", locale))
+		return "", exceptions.BadRequestException(fmt.Sprintf("unsupported locale %q, supported locales are %s", locale, strings.Join(configs.Locales.Supported, ", ")))
+	}
+	if canonical == configs.Locales.Default {
+		configs.Logger.Error("translation in the default locale", zap.String("locale", canonical))
+		return "", exceptions.BadRequestException(fmt.Sprintf("%s is the default locale, update the product or category instead", canonical))
+	}
+	return canonical, nil
+}
@@ -0,0 +1,121 @@
+package catalog_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oolio.com/kart/catalog"
+	"oolio.com/kart/money"
+	"strings"
+	"testing"
+)
+
+var records = []*catalog.Record{
+	{
+		Id:          1,
+		Name:        "Waffle with Berries",
+		Description: "Belgian waffle, berries and cream",
+		Category:    "Waffle",
+		Price:       money.MustParse("6.5"),
+		Status:      "available",
+		Image:       catalog.Image{Thumbnail: "thumb.jpg", Mobile: "mobile.jpg", Tablet: "tablet.jpg", Desktop: "desktop.jpg"},
+		Allergens:   []string{"dairy", "gluten"},
+		Diets:       []string{"vegetarian"},
+		Meta:        map[string]any{"spicy": false, "tags": []any{"sweet"}},
+	},
+	{
+		Id:       2,
+		Name:     "Pizza, \"Large\"",
+		Category: "Pizza",
+		Price:    money.MustParse("12.99"),
+		Status:   "sold_out",
+	},
+}
+
+// TestCatalog_RoundTrip tests that an exported catalog decodes to the same records in every format
+func TestCatalog_RoundTrip(t *testing.T) {
+	for _, format := range []string{catalog.FormatJSON, catalog.FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buffer bytes.Buffer
+			encoder, err := catalog.NewEncoder(&buffer, format)
+			require.NoError(t, err)
+
+			for _, record := range records {
+				require.NoError(t, encoder.Encode(record))
+			}
+			require.NoError(t, encoder.Close())
+
+			decoded, err := catalog.Decode(&buffer, format)
+			require.NoError(t, err)
+			require.Len(t, decoded, len(records))
+			for i := range records {
+				assert.Equal(t, *records[i], decoded[i])
+			}
+		})
+	}
+}
+
+// TestCatalog_EmptyCatalog tests that an empty export is still a valid catalog
+func TestCatalog_EmptyCatalog(t *testing.T) {
+	for _, format := range []string{catalog.FormatJSON, catalog.FormatCSV} {
+		var buffer bytes.Buffer
+		encoder, err := catalog.NewEncoder(&buffer, format)
+		require.NoError(t, err)
+		require.NoError(t, encoder.Close())
+
+		decoded, err := catalog.Decode(&buffer, format)
+		assert.NoError(t, err)
+		assert.Empty(t, decoded)
+	}
+}
+
+// TestCatalog_DecodeCSV_ColumnsInAnyOrder tests that only name, category and price are required, in any order
+func TestCatalog_DecodeCSV_ColumnsInAnyOrder(t *testing.T) {
+	decoded, err := catalog.DecodeCSV(strings.NewReader("price,Category,name\n4.5,Drinks,Lemonade\n"))
+
+	require.NoError(t, err)
+	require.Len(t, decoded, 1)
+	assert.Equal(t, catalog.Record{Name: "Lemonade", Category: "Drinks", Price: money.MustParse("4.5")}, decoded[0])
+}
+
+// TestCatalog_DecodeCSV_Invalid tests that malformed CSV catalogs report the offending row
+func TestCatalog_DecodeCSV_Invalid(t *testing.T) {
+	_, err := catalog.DecodeCSV(strings.NewReader("name,category\nLemonade,Drinks\n"))
+	assert.ErrorContains(t, err, "missing the price column")
+
+	_, err = catalog.DecodeCSV(strings.NewReader("name,category,price\nLemonade,Drinks,cheap\n"))
+	assert.ErrorContains(t, err, "row 2")
+}
+
+// TestCatalog_Decode_RoundsPrices tests that prices with fractions of a cent are rounded half up in every format
+func TestCatalog_Decode_RoundsPrices(t *testing.T) {
+	decoded, err := catalog.DecodeCSV(strings.NewReader("name,category,price\nIced Tea,Drinks,3.495\nCola,Drinks,2.001\n"))
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("3.5"), decoded[0].Price)
+	assert.Equal(t, money.MustParse("2"), decoded[1].Price)
+
+	decoded, err = catalog.DecodeJSON(strings.NewReader(`[{"name":"Iced Tea","category":"Drinks","price":3.495},{"name":"Cola","category":"Drinks"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, catalog.Record{Name: "Iced Tea", Category: "Drinks", Price: money.MustParse("3.5")}, decoded[0])
+	assert.True(t, decoded[1].Price.IsZero())
+
+	_, err = catalog.DecodeJSON(strings.NewReader(`[{"name":"Iced Tea","category":"Drinks","price":"cheap"}]`))
+	assert.Error(t, err)
+}
+
+// TestCatalog_ParseFormat tests that formats are recognised by name and content type
+func TestCatalog_ParseFormat(t *testing.T) {
+	for value, expected := range map[string]string{
+		"json":                            catalog.FormatJSON,
+		"application/json; charset=utf-8": catalog.FormatJSON,
+		"CSV":                             catalog.FormatCSV,
+		"text/csv":                        catalog.FormatCSV,
+	} {
+		format, err := catalog.ParseFormat(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, format, value)
+	}
+
+	_, err := catalog.ParseFormat("application/xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/models"
+	"testing"
+)
+
+// TestAvailabilityController_GetProductAvailability_Success tests that inherited windows are returned with the time zone
+func TestAvailabilityController_GetProductAvailability_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAvailabilityService)
+	controller := controllers.NewAvailabilityController(mockService)
+
+	breakfast := int64(3)
+	availability := &models.Availability{
+		Windows: []*models.AvailabilityWindow{
+			{Id: 7, CategoryId: &breakfast, DaysOfWeek: []int{1, 2, 3, 4, 5}, StartMinute: 7 * 60, EndMinute: 11 * 60},
+		},
+		Inherited: true,
+	}
+	mockService.On("GetProductAvailability", mock.Anything, int64(1)).Return(availability, nil)
+
+	router := gin.New()
+	router.GET("/product/:productId/availability", controller.GetProductAvailability)
+
+	req, _ := http.NewRequest(http.MethodGet, "/product/1/availability", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.AvailabilityResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "UTC", response.TimeZone)
+	assert.True(t, response.Inherited)
+	assert.False(t, response.AvailableNow)
+	assert.Len(t, response.Windows, 1)
+	assert.Equal(t, "3", response.Windows[0].CategoryId)
+	assert.Equal(t, "07:00", response.Windows[0].Start)
+	assert.Equal(t, "11:00", response.Windows[0].End)
+}
+
+// TestAvailabilityController_SetProductAvailability_Success tests that the windows of the request reach the service
+func TestAvailabilityController_SetProductAvailability_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAvailabilityService)
+	controller := controllers.NewAvailabilityController(mockService)
+
+	mockService.On("SetProductAvailability", mock.Anything, int64(1), mock.MatchedBy(func(request *requests.AvailabilityRequest) bool {
+		return len(request.Windows) == 1 && request.Windows[0].Start == "22:00" && request.Windows[0].End == "02:00"
+	})).Return(&models.Availability{Windows: []*models.AvailabilityWindow{}, AvailableNow: true}, nil)
+
+	router := gin.New()
+	router.PUT("/product/:productId/availability", controller.SetProductAvailability)
+
+	body := `{"windows":[{"daysOfWeek":[5,6],"start":"22:00","end":"02:00"}]}`
+	req, _ := http.NewRequest(http.MethodPut, "/product/1/availability", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestAvailabilityController_SetProductAvailability_InvalidDay tests that days outside of 1 to 7 are rejected
+func TestAvailabilityController_SetProductAvailability_InvalidDay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAvailabilityService)
+	controller := controllers.NewAvailabilityController(mockService)
+
+	router := gin.New()
+	router.PUT("/product/:productId/availability", controller.SetProductAvailability)
+
+	body := `{"windows":[{"daysOfWeek":[0],"start":"07:00","end":"11:00"}]}`
+	req, _ := http.NewRequest(http.MethodPut, "/product/1/availability", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "SetProductAvailability", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAvailabilityController_GetCategoryAvailability_NotFound tests that a missing category is reported as 404
+func TestAvailabilityController_GetCategoryAvailability_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockAvailabilityService)
+	controller := controllers.NewAvailabilityController(mockService)
+
+	mockService.On("GetCategoryAvailability", mock.Anything, int64(9)).Return(nil, exceptions.GenericException("category not found", http.StatusNotFound))
+
+	router := gin.New()
+	router.GET("/category/:categoryId/availability", controller.GetCategoryAvailability)
+
+	req, _ := http.NewRequest(http.MethodGet, "/category/9/availability", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,44 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/models"
+	"testing"
+)
+
+// TestCacheController_GetCacheStats tests that the counters of every cache are returned with their hit ratio
+func TestCacheController_GetCacheStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCacheService)
+	controller := controllers.NewCacheController(mockService)
+
+	mockService.On("GetCacheStats").Return([]*models.CacheStats{
+		{Name: "products", Active: true, Entries: 4, Hits: 3, Misses: 1, Invalidations: 2},
+	})
+
+	router := gin.New()
+	router.GET("/admin/cache/stats", controller.GetCacheStats)
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin/cache/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "products", response[0]["name"])
+	assert.Equal(t, true, response[0]["active"])
+	assert.Equal(t, float64(3), response[0]["hits"])
+	assert.Equal(t, float64(1), response[0]["misses"])
+	assert.Equal(t, 0.75, response[0]["hitRatio"])
+
+	mockService.AssertExpectations(t)
+}
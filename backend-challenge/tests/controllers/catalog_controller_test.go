@@ -0,0 +1,118 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/models"
+	"testing"
+)
+
+// TestCatalogController_ImportProducts_DryRun tests that the format comes from the Content-Type and the diff is returned
+func TestCatalogController_ImportProducts_DryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCatalogService)
+	controller := controllers.NewCatalogController(mockService)
+
+	result := &models.ProductImport{
+		DryRun:  true,
+		Created: 1,
+		Changes: []*models.ProductChange{
+			{Row: 1, Action: models.ImportActionCreate, Product: &models.Product{Name: "Lemonade", Category: "Drinks"}},
+		},
+		Errors: []*models.ProductImportError{{Row: 2, Name: "Cola", Category: "Drinks", Message: "product price must not be negative"}},
+	}
+	mockService.On("ImportProducts", mock.Anything, mock.Anything, "csv", true).Return(result, nil)
+
+	router := gin.New()
+	router.POST("/admin/products/import", controller.ImportProducts)
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/products/import?dryRun=true", bytes.NewBufferString("name,category,price\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, true, response["dryRun"])
+	assert.Equal(t, float64(1), response["created"])
+	changes := response["changes"].([]interface{})
+	assert.Equal(t, "created", changes[0].(map[string]interface{})["action"])
+	assert.NotContains(t, changes[0], "id")
+	errs := response["errors"].([]interface{})
+	assert.Equal(t, float64(2), errs[0].(map[string]interface{})["row"])
+
+	mockService.AssertExpectations(t)
+}
+
+// TestCatalogController_ImportProducts_UnsupportedFormat tests that a body in an unknown format is rejected
+func TestCatalogController_ImportProducts_UnsupportedFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCatalogService)
+	controller := controllers.NewCatalogController(mockService)
+
+	router := gin.New()
+	router.POST("/admin/products/import", controller.ImportProducts)
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/products/import", bytes.NewBufferString("<products/>"))
+	req.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ImportProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCatalogController_ExportProducts_CSV tests that the export is streamed as a CSV attachment
+func TestCatalogController_ExportProducts_CSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCatalogService)
+	controller := controllers.NewCatalogController(mockService)
+
+	mockService.On("ExportProducts", mock.Anything, mock.Anything, "csv").Run(func(args mock.Arguments) {
+		_, _ = io.WriteString(args.Get(1).(io.Writer), "id,name\n1,Lemonade\n")
+	}).Return(nil)
+
+	router := gin.New()
+	router.GET("/admin/products/export", controller.ExportProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin/products/export?format=csv", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="products.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id,name\n1,Lemonade\n", w.Body.String())
+}
+
+// TestCatalogController_ExportProducts_Failure tests that an export failing before any output returns an error response
+func TestCatalogController_ExportProducts_Failure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCatalogService)
+	controller := controllers.NewCatalogController(mockService)
+
+	mockService.On("ExportProducts", mock.Anything, mock.Anything, "json").
+		Return(exceptions.GenericException("failed to export products", http.StatusInternalServerError))
+
+	router := gin.New()
+	router.GET("/admin/products/export", controller.ExportProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin/products/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+}
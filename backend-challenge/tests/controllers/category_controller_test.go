@@ -0,0 +1,139 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"testing"
+)
+
+// TestCategoryController_GetCategoryTree_Success tests that the tree is returned with nested subcategories
+func TestCategoryController_GetCategoryTree_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCategoryService)
+	controller := controllers.NewCategoryController(mockService)
+
+	pizza := int64(1)
+	tree := []*models.Category{
+		{Id: pizza, Name: "Pizza", Path: "Pizza", Active: true, ProductCount: 5, Children: []*models.Category{
+			{Id: 3, ParentId: &pizza, Name: "Vegetarian", Path: "Pizza > Vegetarian", Active: true, ProductCount: 3},
+		}},
+	}
+	mockService.On("GetCategoryTree", mock.Anything, true).Return(tree, nil)
+
+	router := gin.New()
+	router.GET("/category", controller.GetCategoryTree)
+
+	req, _ := http.NewRequest(http.MethodGet, "/category?includeInactive=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []*responses.CategoryResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, int64(5), response[0].ProductCount)
+	assert.Empty(t, response[0].ParentId)
+	assert.Equal(t, "1", response[0].Children[0].ParentId)
+	assert.Equal(t, "Pizza > Vegetarian", response[0].Children[0].Path)
+	assert.NotNil(t, response[0].Children[0].Children)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestCategoryController_GetCategory_InvalidID tests that a non numeric category ID is rejected
+func TestCategoryController_GetCategory_InvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCategoryService)
+	controller := controllers.NewCategoryController(mockService)
+
+	router := gin.New()
+	router.GET("/category/:categoryId", controller.GetCategory)
+
+	req, _ := http.NewRequest(http.MethodGet, "/category/pizza", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetCategory", mock.Anything, mock.Anything)
+}
+
+// TestCategoryController_CreateCategory_Success tests that a created category is returned with 201
+func TestCategoryController_CreateCategory_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCategoryService)
+	controller := controllers.NewCategoryController(mockService)
+
+	pizza := int64(1)
+	category := &models.Category{Id: 3, ParentId: &pizza, Name: "Vegetarian", Path: "Pizza > Vegetarian", Active: true}
+	mockService.On("CreateCategory", mock.Anything, mock.Anything).Return(category, nil)
+
+	router := gin.New()
+	router.POST("/category", controller.CreateCategory)
+
+	req, _ := http.NewRequest(http.MethodPost, "/category", bytes.NewBufferString(`{"name":"Vegetarian","parentId":"1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response responses.CategoryResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", response.Id)
+	assert.Equal(t, "1", response.ParentId)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestCategoryController_CreateCategory_MissingName tests that a category without a name is rejected
+func TestCategoryController_CreateCategory_MissingName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCategoryService)
+	controller := controllers.NewCategoryController(mockService)
+
+	router := gin.New()
+	router.POST("/category", controller.CreateCategory)
+
+	req, _ := http.NewRequest(http.MethodPost, "/category", bytes.NewBufferString(`{"sortOrder":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateCategory", mock.Anything, mock.Anything)
+}
+
+// TestCategoryController_DeleteCategory_HasProducts tests that deleting a category still in use returns 409
+func TestCategoryController_DeleteCategory_HasProducts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockCategoryService)
+	controller := controllers.NewCategoryController(mockService)
+
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusConflict,
+		Message:   "category still has subcategories or products",
+	}
+	mockService.On("DeleteCategory", mock.Anything, int64(1)).Return(mockError)
+
+	router := gin.New()
+	router.DELETE("/category/:categoryId", controller.DeleteCategory)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/category/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -3,10 +3,12 @@ package controllers_test
 import (
 	"context"
 	"github.com/stretchr/testify/mock"
+	"io"
 	"oolio.com/kart/dtos/requests"
 	"oolio.com/kart/dtos/responses"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"time"
 )
 
 // MockProductService is a mock implementation of ProductService
@@ -14,12 +16,20 @@ type MockProductService struct {
 	mock.Mock
 }
 
-func (m *MockProductService) GetProducts(ctx context.Context, offset, limit *int) []*models.Product {
-	args := m.Called(ctx, offset, limit)
+func (m *MockProductService) GetProducts(ctx context.Context, filter *models.ProductFilter) (*models.ProductPage, *errors.ErrorDetails) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.ProductPage), nil
+}
+
+func (m *MockProductService) ValidateProductFilter(filter *models.ProductFilter) *errors.ErrorDetails {
+	args := m.Called(filter)
 	if args.Get(0) == nil {
 		return nil
 	}
-	return args.Get(0).([]*models.Product)
+	return args.Get(0).(*errors.ErrorDetails)
 }
 
 func (m *MockProductService) GetProductById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
@@ -30,6 +40,78 @@ func (m *MockProductService) GetProductById(ctx context.Context, id int64) (*mod
 	return args.Get(0).(*models.Product), nil
 }
 
+func (m *MockProductService) GetProductLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(1) != nil {
+		return time.Time{}, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(time.Time), nil
+}
+
+func (m *MockProductService) GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails) {
+	args := m.Called(ctx)
+	if args.Get(1) != nil {
+		return time.Time{}, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(time.Time), nil
+}
+
+func (m *MockProductService) GetProductPrices(ctx context.Context, id int64) (*models.Product, []*models.ProductPrice, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Get(2).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), args.Get(1).([]*models.ProductPrice), nil
+}
+
+func (m *MockProductService) CreateProduct(ctx context.Context, request *requests.ProductRequest) (*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), nil
+}
+
+func (m *MockProductService) UpdateProduct(ctx context.Context, id int64, request *requests.ProductRequest) (*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, id, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), nil
+}
+
+func (m *MockProductService) PatchProduct(ctx context.Context, id int64, request *requests.PatchProductRequest) (*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, id, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), nil
+}
+
+func (m *MockProductService) UpdateProductStatus(ctx context.Context, id int64, request *requests.ProductStatusRequest) (*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, id, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), nil
+}
+
+func (m *MockProductService) DeleteProduct(ctx context.Context, id int64) *errors.ErrorDetails {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductService) RestoreProduct(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), nil
+}
+
 // MockOrderService is a mock implementation of OrderService
 type MockOrderService struct {
 	mock.Mock
@@ -42,3 +124,338 @@ func (m *MockOrderService) PlaceOrder(ctx context.Context, request *requests.Pla
 	}
 	return args.Get(0).(*responses.OrderResponse), nil
 }
+
+func (m *MockOrderService) GetOrderById(ctx context.Context, id string) (*responses.OrderResponse, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*responses.OrderResponse), nil
+}
+
+func (m *MockOrderService) TransitionOrder(ctx context.Context, id string, request *requests.OrderTransitionRequest) (*responses.OrderResponse, *errors.ErrorDetails) {
+	args := m.Called(ctx, id, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*responses.OrderResponse), nil
+}
+
+func (m *MockOrderService) GetOrderTransitions(ctx context.Context, id string) ([]*models.OrderStatusChange, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.OrderStatusChange), nil
+}
+
+func (m *MockOrderService) ListOrders(ctx context.Context, filter *models.OrderFilter) (*models.OrderPage, *errors.ErrorDetails) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.OrderPage), nil
+}
+
+func (m *MockOrderService) QuoteOrder(ctx context.Context, request *requests.PlaceOrderRequest) (*responses.OrderQuoteResponse, *errors.ErrorDetails) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*responses.OrderQuoteResponse), nil
+}
+
+// MockStockService is a mock implementation of StockService
+type MockStockService struct {
+	mock.Mock
+}
+
+func (m *MockStockService) GetStock(ctx context.Context, productId int64) (*models.Product, []*models.StockAdjustment, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId)
+	if args.Get(0) == nil {
+		return nil, nil, args.Get(2).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), args.Get(1).([]*models.StockAdjustment), nil
+}
+
+func (m *MockStockService) AdjustStock(ctx context.Context, productId int64, request *requests.StockAdjustmentRequest) (*models.Product, []*models.StockAdjustment, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, request)
+	if args.Get(0) == nil {
+		return nil, nil, args.Get(2).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), args.Get(1).([]*models.StockAdjustment), nil
+}
+
+// MockModifierService is a mock implementation of ModifierService
+type MockModifierService struct {
+	mock.Mock
+}
+
+func (m *MockModifierService) GetModifierGroups(ctx context.Context, productId int64) ([]*models.ModifierGroup, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.ModifierGroup), nil
+}
+
+func (m *MockModifierService) CreateModifierGroup(ctx context.Context, productId int64, request *requests.ModifierGroupRequest) (*models.ModifierGroup, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.ModifierGroup), nil
+}
+
+func (m *MockModifierService) UpdateModifierGroup(ctx context.Context, productId int64, groupId int64, request *requests.ModifierGroupRequest) (*models.ModifierGroup, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, groupId, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.ModifierGroup), nil
+}
+
+func (m *MockModifierService) DeleteModifierGroup(ctx context.Context, productId int64, groupId int64) *errors.ErrorDetails {
+	args := m.Called(ctx, productId, groupId)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+// MockCatalogService is a mock implementation of CatalogService
+type MockCatalogService struct {
+	mock.Mock
+}
+
+func (m *MockCatalogService) ImportProducts(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ProductImport, *errors.ErrorDetails) {
+	args := m.Called(ctx, r, format, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.ProductImport), nil
+}
+
+func (m *MockCatalogService) ExportProducts(ctx context.Context, w io.Writer, format string) *errors.ErrorDetails {
+	args := m.Called(ctx, w, format)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+// MockCategoryService is a mock implementation of CategoryService
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) GetCategoryTree(ctx context.Context, includeInactive bool) ([]*models.Category, *errors.ErrorDetails) {
+	args := m.Called(ctx, includeInactive)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Category), nil
+}
+
+func (m *MockCategoryService) GetCategory(ctx context.Context, id int64) (*models.Category, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Category), nil
+}
+
+func (m *MockCategoryService) CreateCategory(ctx context.Context, request *requests.CategoryRequest) (*models.Category, *errors.ErrorDetails) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Category), nil
+}
+
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, id int64, request *requests.CategoryRequest) (*models.Category, *errors.ErrorDetails) {
+	args := m.Called(ctx, id, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Category), nil
+}
+
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id int64) *errors.ErrorDetails {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+// MockCacheService is a mock implementation of CacheService
+type MockCacheService struct {
+	mock.Mock
+}
+
+func (m *MockCacheService) GetCacheStats() []*models.CacheStats {
+	args := m.Called()
+	return args.Get(0).([]*models.CacheStats)
+}
+
+// MockProductImageService is a mock implementation of ProductImageService
+type MockProductImageService struct {
+	mock.Mock
+}
+
+func (m *MockProductImageService) UploadProductImage(ctx context.Context, productId int64, source io.Reader) (*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, source)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), nil
+}
+
+// MockAvailabilityService is a mock implementation of AvailabilityService
+type MockAvailabilityService struct {
+	mock.Mock
+}
+
+func (m *MockAvailabilityService) GetProductAvailability(ctx context.Context, productId int64) (*models.Availability, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Availability), nil
+}
+
+func (m *MockAvailabilityService) SetProductAvailability(ctx context.Context, productId int64, request *requests.AvailabilityRequest) (*models.Availability, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Availability), nil
+}
+
+func (m *MockAvailabilityService) GetCategoryAvailability(ctx context.Context, categoryId int64) (*models.Availability, *errors.ErrorDetails) {
+	args := m.Called(ctx, categoryId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Availability), nil
+}
+
+func (m *MockAvailabilityService) SetCategoryAvailability(ctx context.Context, categoryId int64, request *requests.AvailabilityRequest) (*models.Availability, *errors.ErrorDetails) {
+	args := m.Called(ctx, categoryId, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Availability), nil
+}
+
+// MockTranslationService is a mock implementation of TranslationService
+type MockTranslationService struct {
+	mock.Mock
+}
+
+func (m *MockTranslationService) GetProductTranslations(ctx context.Context, productId int64) ([]*models.ProductTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.ProductTranslation), nil
+}
+
+func (m *MockTranslationService) SetProductTranslation(ctx context.Context, productId int64, locale string, request *requests.ProductTranslationRequest) (*models.ProductTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, locale, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.ProductTranslation), nil
+}
+
+func (m *MockTranslationService) DeleteProductTranslation(ctx context.Context, productId int64, locale string) *errors.ErrorDetails {
+	args := m.Called(ctx, productId, locale)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockTranslationService) GetCategoryTranslations(ctx context.Context, categoryId int64) ([]*models.CategoryTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, categoryId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.CategoryTranslation), nil
+}
+
+func (m *MockTranslationService) SetCategoryTranslation(ctx context.Context, categoryId int64, locale string, request *requests.CategoryTranslationRequest) (*models.CategoryTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, categoryId, locale, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.CategoryTranslation), nil
+}
+
+func (m *MockTranslationService) DeleteCategoryTranslation(ctx context.Context, categoryId int64, locale string) *errors.ErrorDetails {
+	args := m.Called(ctx, categoryId, locale)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+// MockRecommendationService is a mock implementation of RecommendationService
+type MockRecommendationService struct {
+	mock.Mock
+}
+
+func (m *MockRecommendationService) GetProductRecommendations(ctx context.Context, productId int64, limit int) ([]*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Product), nil
+}
+
+func (m *MockRecommendationService) SuggestProducts(ctx context.Context, productIds []int64) ([]*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, productIds)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Product), nil
+}
+
+func (m *MockRecommendationService) RefreshRecommendations(ctx context.Context) *errors.ErrorDetails {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+// MockRefundService is a mock implementation of RefundService
+type MockRefundService struct {
+	mock.Mock
+}
+
+func (m *MockRefundService) RefundOrder(ctx context.Context, orderId string, request *requests.RefundRequest) (*responses.RefundResponse, *errors.ErrorDetails) {
+	args := m.Called(ctx, orderId, request)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*responses.RefundResponse), nil
+}
+
+func (m *MockRefundService) GetRefunds(ctx context.Context, orderId string) ([]*responses.RefundResponse, *errors.ErrorDetails) {
+	args := m.Called(ctx, orderId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*responses.RefundResponse), nil
+}
+
+func (m *MockRefundService) GetRefund(ctx context.Context, orderId string, refundId int64) (*responses.RefundResponse, *errors.ErrorDetails) {
+	args := m.Called(ctx, orderId, refundId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*responses.RefundResponse), nil
+}
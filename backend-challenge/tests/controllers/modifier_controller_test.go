@@ -0,0 +1,108 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"testing"
+)
+
+// TestModifierController_GetModifierGroups_Success tests that the groups are returned with their modifiers
+func TestModifierController_GetModifierGroups_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockModifierService)
+	controller := controllers.NewModifierController(mockService)
+
+	groups := []*models.ModifierGroup{
+		{Id: 1, ProductId: 1, Name: "Size", MinSelect: 1, MaxSelect: 1, Modifiers: []*models.Modifier{
+			{Id: 10, GroupId: 1, Name: "Large", PriceDelta: money.MustParse("2.5")},
+		}},
+	}
+	mockService.On("GetModifierGroups", mock.Anything, int64(1)).Return(groups, nil)
+
+	router := gin.New()
+	router.GET("/products/:productId/modifier-groups", controller.GetModifierGroups)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products/1/modifier-groups", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "Size", response[0]["name"])
+	modifiers := response[0]["modifiers"].([]interface{})
+	assert.Equal(t, "10", modifiers[0].(map[string]interface{})["id"])
+	assert.Equal(t, 2.5, modifiers[0].(map[string]interface{})["priceDelta"])
+
+	mockService.AssertExpectations(t)
+}
+
+// TestModifierController_CreateModifierGroup_Success tests that a created group is returned with 201
+func TestModifierController_CreateModifierGroup_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockModifierService)
+	controller := controllers.NewModifierController(mockService)
+
+	group := &models.ModifierGroup{Id: 1, ProductId: 1, Name: "Size", MinSelect: 1, MaxSelect: 1, Modifiers: []*models.Modifier{
+		{Id: 10, GroupId: 1, Name: "Large", PriceDelta: money.MustParse("2.5")},
+	}}
+	mockService.On("CreateModifierGroup", mock.Anything, int64(1), mock.Anything).Return(group, nil)
+
+	router := gin.New()
+	router.POST("/products/:productId/modifier-groups", controller.CreateModifierGroup)
+
+	body := `{"name":"Size","minSelect":1,"maxSelect":1,"modifiers":[{"name":"Large","priceDelta":2.5}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/products/1/modifier-groups", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestModifierController_CreateModifierGroup_NoModifiers tests that a group without modifiers is rejected
+func TestModifierController_CreateModifierGroup_NoModifiers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockModifierService)
+	controller := controllers.NewModifierController(mockService)
+
+	router := gin.New()
+	router.POST("/products/:productId/modifier-groups", controller.CreateModifierGroup)
+
+	req, _ := http.NewRequest(http.MethodPost, "/products/1/modifier-groups", bytes.NewBufferString(`{"name":"Size","maxSelect":1,"modifiers":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateModifierGroup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestModifierController_DeleteModifierGroup_InvalidGroupId tests that a malformed group id is rejected
+func TestModifierController_DeleteModifierGroup_InvalidGroupId(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockModifierService)
+	controller := controllers.NewModifierController(mockService)
+
+	router := gin.New()
+	router.DELETE("/products/:productId/modifier-groups/:groupId", controller.DeleteModifierGroup)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/products/1/modifier-groups/abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "DeleteModifierGroup", mock.Anything, mock.Anything, mock.Anything)
+}
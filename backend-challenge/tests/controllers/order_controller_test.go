@@ -12,7 +12,10 @@ import (
 	"oolio.com/kart/dtos/requests"
 	"oolio.com/kart/dtos/responses"
 	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
 	"testing"
+	"time"
 )
 
 // MockOrderService is a mock implementation of OrderService
@@ -181,3 +184,345 @@ func TestOrderController_PlaceOrder_WithCoupon(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+// TestOrderController_PlaceOrder_UnavailableItems tests that per-item reasons are returned with the 422 response
+func TestOrderController_PlaceOrder_UnavailableItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	quantity := 1
+	requestBody := requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "2", Quantity: &quantity},
+		},
+	}
+
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusUnprocessableEntity,
+		Message:   "some products are not available",
+		Items: []errors.ItemError{
+			{ProductId: "2", Reason: "product_sold_out", Message: "product is sold out"},
+		},
+	}
+
+	mockService.On("PlaceOrder", mock.Anything, mock.AnythingOfType("*requests.PlaceOrderRequest")).Return(nil, mockError)
+
+	router := gin.New()
+	router.POST("/orders", controller.PlaceOrder)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response responses.APIResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Details, 1)
+	assert.Equal(t, "2", response.Details[0].ProductId)
+	assert.Equal(t, "product_sold_out", response.Details[0].Reason)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestOrderController_QuoteOrder_Success tests that a quote is returned with its suggestions
+func TestOrderController_QuoteOrder_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	mockService.On("QuoteOrder", mock.Anything, mock.AnythingOfType("*requests.PlaceOrderRequest")).Return(&responses.OrderQuoteResponse{
+		Items:       []responses.OrderItemResponse{{ProductId: "1", Quantity: 2}},
+		Products:    []*responses.ProductResponse{{Id: "1", Name: "Chicken Waffle"}},
+		Subtotal:    money.MustParse("27"),
+		Total:       money.MustParse("27"),
+		Suggestions: []*responses.ProductResponse{{Id: "4", Name: "Lemonade"}},
+	}, nil)
+
+	router := gin.New()
+	router.POST("/order/quote", controller.QuoteOrder)
+
+	req, _ := http.NewRequest(http.MethodPost, "/order/quote", bytes.NewBufferString(`{"items":[{"productId":"1","quantity":2}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 27.0, response["total"])
+	assert.Len(t, response["suggestions"], 1)
+}
+
+// TestOrderController_GetOrderById_Success tests that an order is returned with its totals
+func TestOrderController_GetOrderById_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockService.On("GetOrderById", mock.Anything, orderId).Return(&responses.OrderResponse{
+		Id:        orderId,
+		Items:     []responses.OrderItemResponse{{ProductId: "1", Quantity: 2}},
+		Products:  []*responses.ProductResponse{{Id: "1", Name: "Chicken Waffle"}},
+		Subtotal:  money.MustParse("27"),
+		Discounts: money.MustParse("2.7"),
+		Total:     money.MustParse("24.3"),
+	}, nil)
+
+	router := gin.New()
+	router.GET("/order/:orderId", controller.GetOrderById)
+
+	req, _ := http.NewRequest(http.MethodGet, "/order/"+orderId, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.OrderResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, orderId, response.Id)
+	assert.Equal(t, money.MustParse("24.3"), response.Total)
+	assert.Equal(t, money.MustParse("2.7"), response.Discounts)
+}
+
+// TestOrderController_GetOrderById_AmountsRoundTrip tests that the amounts of an order are written as the exact
+// decimal numbers they were priced at and read back from the response as the same amounts
+func TestOrderController_GetOrderById_AmountsRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	order := &models.Order{
+		Id:        orderId,
+		Status:    models.OrderStatusPlaced,
+		Version:   1,
+		Discount:  money.MustParse("3.99"),
+		CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	items := []models.OrderItem{
+		{Id: 11, ProductId: 1, Quantity: 3, UnitPrice: money.MustParse("12.99")},
+		{Id: 12, ProductId: 2, Quantity: 3, UnitPrice: money.MustParse("0.3"), Modifiers: []models.OrderItemModifier{
+			{ModifierId: 4, GroupName: "Extras", Name: "Honey", PriceDelta: money.MustParse("0.2")},
+		}},
+	}
+	assert.NoError(t, models.PriceOrder(order, items))
+	products := []*models.Product{
+		{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Status: "available"},
+		{Id: 2, Name: "Plain Waffle", Price: money.MustParse("0.1"), Status: "available"},
+	}
+	expected := responses.ToOrderResponse(order, items, products)
+	mockService.On("GetOrderById", mock.Anything, orderId).Return(expected, nil)
+
+	router := gin.New()
+	router.GET("/order/:orderId", controller.GetOrderById)
+
+	req, _ := http.NewRequest(http.MethodGet, "/order/"+orderId, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	for _, amount := range []string{
+		`"unitPrice":12.99`, `"lineTotal":38.97`,
+		`"unitPrice":0.3`, `"lineTotal":0.9`, `"priceDelta":0.2`,
+		`"price":0.1`, `"subtotal":39.87`, `"discounts":3.99`, `"total":35.88`, `"currency":"USD"`,
+	} {
+		assert.Contains(t, body, amount)
+	}
+
+	var response responses.OrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, *expected, response)
+}
+
+// TestOrderController_GetOrderById_Errors tests that malformed IDs are rejected and missing orders are a 404
+func TestOrderController_GetOrderById_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	missingId := "550e8400-e29b-41d4-a716-446655440001"
+	mockService.On("GetOrderById", mock.Anything, missingId).Return(nil, &errors.ErrorDetails{ErrorCode: http.StatusNotFound, Message: "order not found"})
+
+	router := gin.New()
+	router.GET("/order/:orderId", controller.GetOrderById)
+
+	req, _ := http.NewRequest(http.MethodGet, "/order/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/order/"+missingId, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertNumberOfCalls(t, "GetOrderById", 1)
+}
+
+// TestOrderController_ListOrders_Success tests that the filters are passed on and the page is returned with its
+// pagination headers
+func TestOrderController_ListOrders_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	page := &models.OrderPage{
+		Orders:     []*models.Order{{Id: "550e8400-e29b-41d4-a716-446655440000", CouponCode: "HAPPYHRS", Status: models.OrderStatusReady, Total: money.MustParse("24.3")}},
+		TotalCount: 7,
+		NextCursor: "abc",
+	}
+	mockService.On("ListOrders", mock.Anything, mock.MatchedBy(func(filter *models.OrderFilter) bool {
+		return filter.CouponCode == "HAPPYHRS" && *filter.MinTotal == money.MustParse("10") && filter.From.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) &&
+			assert.ObjectsAreEqual([]string{"placed", "ready"}, filter.Statuses) && filter.Sort == "total" && filter.Limit == 20
+	})).Return(page, nil)
+
+	router := gin.New()
+	router.GET("/order", controller.ListOrders)
+
+	req, _ := http.NewRequest(http.MethodGet, "/order?couponCode=HAPPYHRS&minTotal=10&from=2025-03-01T00:00:00Z&status=placed,ready&sort=total", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "7", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, "abc", w.Header().Get("X-Next-Cursor"))
+
+	var response []responses.OrderSummaryResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "ready", response[0].Status)
+	assert.Equal(t, money.MustParse("24.3"), response[0].Total)
+	mockService.AssertExpectations(t)
+}
+
+// TestOrderController_ListOrders_InvalidQuery tests that invalid filters and pagination parameters are rejected
+func TestOrderController_ListOrders_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	router := gin.New()
+	router.GET("/order", controller.ListOrders)
+
+	for _, query := range []string{"status=shipped", "sort=id", "limit=0", "limit=101", "minTotal=-1", "from=yesterday"} {
+		req, _ := http.NewRequest(http.MethodGet, "/order?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	mockService.AssertNotCalled(t, "ListOrders", mock.Anything, mock.Anything)
+}
+
+// TestOrderController_TransitionOrder_Success tests that the transition is passed on and the moved order returned
+func TestOrderController_TransitionOrder_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockService.On("TransitionOrder", mock.Anything, orderId, mock.MatchedBy(func(request *requests.OrderTransitionRequest) bool {
+		return request.Status == "preparing" && request.Version == 2 && request.Actor == "kitchen"
+	})).Return(&responses.OrderResponse{Id: orderId, Status: "preparing", Version: 3}, nil)
+
+	router := gin.New()
+	router.POST("/order/:orderId/transitions", controller.TransitionOrder)
+
+	body := []byte(`{"status": "preparing", "version": 2, "actor": "kitchen"}`)
+	req, _ := http.NewRequest(http.MethodPost, "/order/"+orderId+"/transitions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.OrderResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "preparing", response.Status)
+	assert.Equal(t, 3, response.Version)
+}
+
+// TestOrderController_TransitionOrder_Errors tests that invalid transitions are rejected before the service and the
+// conflicts of the service are returned as they are
+func TestOrderController_TransitionOrder_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockService.On("TransitionOrder", mock.Anything, orderId, mock.Anything).
+		Return(nil, &errors.ErrorDetails{ErrorCode: http.StatusConflict, Message: "order is at version 3, not 2, reload it and retry"})
+
+	router := gin.New()
+	router.POST("/order/:orderId/transitions", controller.TransitionOrder)
+
+	tests := []struct {
+		name    string
+		orderId string
+		body    string
+		code    int
+	}{
+		{"malformed order id", "42", `{"status": "ready", "version": 2, "actor": "kitchen"}`, http.StatusBadRequest},
+		{"unknown status", orderId, `{"status": "shipped", "version": 2, "actor": "kitchen"}`, http.StatusBadRequest},
+		{"missing version", orderId, `{"status": "ready", "actor": "kitchen"}`, http.StatusBadRequest},
+		{"missing actor", orderId, `{"status": "ready", "version": 2}`, http.StatusBadRequest},
+		{"stale version", orderId, `{"status": "ready", "version": 2, "actor": "kitchen"}`, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/order/"+tt.orderId+"/transitions", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	mockService.AssertNumberOfCalls(t, "TransitionOrder", 1)
+}
+
+// TestOrderController_GetOrderTransitions_Success tests that the status history of an order is returned oldest first
+func TestOrderController_GetOrderTransitions_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockOrderService)
+	controller := controllers.NewOrderController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	acceptedAt := time.Date(2025, 3, 1, 10, 31, 0, 0, time.UTC)
+	mockService.On("GetOrderTransitions", mock.Anything, orderId).Return([]*models.OrderStatusChange{
+		{Id: 1, OrderId: orderId, FromStatus: "placed", ToStatus: "accepted", Actor: "counter", CreatedAt: acceptedAt},
+		{Id: 2, OrderId: orderId, FromStatus: "accepted", ToStatus: "cancelled", Actor: "counter", Reason: "Out of waffles", CreatedAt: acceptedAt.Add(time.Minute)},
+	}, nil)
+
+	router := gin.New()
+	router.GET("/order/:orderId/transitions", controller.GetOrderTransitions)
+
+	req, _ := http.NewRequest(http.MethodGet, "/order/"+orderId+"/transitions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []responses.OrderTransitionResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 2)
+	assert.Equal(t, "accepted", response[0].To)
+	assert.Equal(t, "Out of waffles", response[1].Reason)
+	assert.Equal(t, acceptedAt, response[0].CreatedAt)
+}
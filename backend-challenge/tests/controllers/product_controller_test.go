@@ -1,6 +1,7 @@
 package controllers_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -8,9 +9,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"oolio.com/kart/money"
 	"testing"
+	"time"
 )
 
 // MockProductService is a mock implementation of ProductService
@@ -23,20 +28,22 @@ func TestProductController_GetProducts_Success(t *testing.T) {
 		{
 			Id:       1,
 			Name:     "Margherita Pizza",
-			Price:    12.99,
+			Price:    money.MustParse("12.99"),
 			Category: "Pizza",
 			Status:   "available",
 		},
 		{
 			Id:       2,
 			Name:     "Pepperoni Pizza",
-			Price:    14.99,
+			Price:    money.MustParse("14.99"),
 			Category: "Pizza",
 			Status:   "available",
 		},
 	}
 
-	mockService.On("GetProducts", mock.Anything, mock.Anything, mock.Anything).Return(mockProducts)
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(time.Time{}, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.Anything).Return(&models.ProductPage{Products: mockProducts, TotalCount: 2}, nil)
 
 	router := gin.New()
 	router.GET("/products", controller.GetProducts)
@@ -63,10 +70,14 @@ func TestProductController_GetProducts_WithPagination(t *testing.T) {
 	controller := controllers.NewProductController(mockService)
 
 	mockProducts := []*models.Product{
-		{Id: 1, Name: "Product 1", Price: 10.00, Category: "Category1", Status: "available"},
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Category1", Status: "available"},
 	}
 
-	mockService.On("GetProducts", mock.Anything, mock.Anything, mock.Anything).Return(mockProducts)
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(time.Time{}, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return filter.Limit != nil && *filter.Limit == 10 && filter.Offset != nil && *filter.Offset == 0
+	})).Return(&models.ProductPage{Products: mockProducts, TotalCount: 1}, nil)
 
 	router := gin.New()
 	router.GET("/products", controller.GetProducts)
@@ -79,6 +90,194 @@ func TestProductController_GetProducts_WithPagination(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// TestProductController_GetProducts_WithFilters tests that filter, search and sort parameters reach the service
+func TestProductController_GetProducts_WithFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(time.Time{}, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return filter.Category == "Pizza" &&
+			filter.Status == "available" &&
+			filter.MinPrice != nil && *filter.MinPrice == money.MustParse("5") &&
+			filter.MaxPrice != nil && *filter.MaxPrice == money.MustParse("20") &&
+			filter.Query == "pepp" &&
+			filter.Sort == "price" &&
+			filter.Direction == "desc"
+	})).Return(&models.ProductPage{Products: []*models.Product{}}, nil)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products?category=Pizza&status=available&minPrice=5&maxPrice=20&q=pepp&sort=price&direction=desc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_GetProducts_AvailableNow tests that listings of products available now are never revalidated
+func TestProductController_GetProducts_AvailableNow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return filter.AvailableNow && filter.AvailableAt == nil
+	})).Return(&models.ProductPage{Products: []*models.Product{}}, nil)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products?availableNow=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("ETag"))
+	mockService.AssertNotCalled(t, "GetCatalogLastModified", mock.Anything)
+}
+
+// TestProductController_GetProducts_AvailableAt tests the at filter and that it cannot be combined with availableNow
+func TestProductController_GetProducts_AvailableAt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	at := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(time.Time{}, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return filter.AvailableAt != nil && filter.AvailableAt.Equal(at)
+	})).Return(&models.ProductPage{Products: []*models.Product{}}, nil)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products?at=2025-03-03T19:00:00%2B11:00", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/products?availableNow=true&at=2025-03-03T08:00:00Z", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNumberOfCalls(t, "GetProducts", 1)
+}
+
+// TestProductController_GetProducts_DietaryFilters tests that allergens and diets are read as comma separated lists
+func TestProductController_GetProducts_DietaryFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(time.Time{}, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return assert.ObjectsAreEqual([]string{"gluten", "nuts"}, filter.ExcludeAllergens) &&
+			assert.ObjectsAreEqual([]string{"vegan"}, filter.Diets)
+	})).Return(&models.ProductPage{Products: []*models.Product{}}, nil)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products?excludeAllergens=gluten,nuts&diet=vegan", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/products?excludeAllergens=gluten,shellfish", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNumberOfCalls(t, "GetProducts", 1)
+}
+
+// TestProductController_GetProducts_InvalidSort tests that an unknown sort key is rejected
+func TestProductController_GetProducts_InvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products?sort=popularity", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "validation_error", response["type"])
+
+	mockService.AssertNotCalled(t, "GetProducts", mock.Anything, mock.Anything)
+}
+
+// TestProductController_GetProducts_PaginationHeaders tests that the cursor and total count are returned as headers
+func TestProductController_GetProducts_PaginationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	page := &models.ProductPage{
+		Products:   []*models.Product{{Id: 1, Name: "Product 1", Price: money.MustParse("10.00")}},
+		TotalCount: 5,
+		NextCursor: "eyJzIjoiaWQiLCJkIjoiYXNjIiwiaSI6MX0",
+	}
+
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(time.Time{}, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return filter.Cursor == "abc" && *filter.Limit == 1
+	})).Return(page, nil)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products?limit=1&cursor=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+	assert.Equal(t, page.NextCursor, w.Header().Get("X-Next-Cursor"))
+
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_GetProducts_InvalidPagination tests that invalid pagination parameters are rejected
+func TestProductController_GetProducts_InvalidPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	for _, query := range []string{"limit=abc", "limit=0", "limit=1000", "offset=-1", "offset=5&cursor=abc"} {
+		req, _ := http.NewRequest(http.MethodGet, "/products?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	mockService.AssertNotCalled(t, "GetProducts", mock.Anything, mock.Anything)
+}
+
 // MockProductService is a mock implementation of ProductService
 func TestProductController_GetProductById_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -88,11 +287,12 @@ func TestProductController_GetProductById_Success(t *testing.T) {
 	mockProduct := &models.Product{
 		Id:       1,
 		Name:     "Margherita Pizza",
-		Price:    12.99,
+		Price:    money.MustParse("12.99"),
 		Category: "Pizza",
 		Status:   "available",
 	}
 
+	mockService.On("GetProductLastModified", mock.Anything, int64(1)).Return(time.Time{}, nil)
 	mockService.On("GetProductById", mock.Anything, int64(1)).Return(mockProduct, nil)
 
 	router := gin.New()
@@ -113,6 +313,85 @@ func TestProductController_GetProductById_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// TestProductController_GetProductPrices_Success tests that the price history is returned newest first
+func TestProductController_GetProductPrices_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	changedAt := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	mockProduct := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("13.49"), PriceVersion: 2, Category: "Pizza"}
+	mockPrices := []*models.ProductPrice{
+		{ProductId: 1, Version: 2, Price: money.MustParse("13.49"), EffectiveFrom: changedAt},
+		{ProductId: 1, Version: 1, Price: money.MustParse("12.99"), EffectiveFrom: changedAt.AddDate(0, -1, 0), EffectiveTo: &changedAt},
+	}
+
+	mockService.On("GetProductPrices", mock.Anything, int64(1)).Return(mockProduct, mockPrices, nil)
+
+	router := gin.New()
+	router.GET("/products/:productId/prices", controller.GetProductPrices)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products/1/prices", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.ProductPricesResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", response.ProductId)
+	assert.Equal(t, 2, response.CurrentVersion)
+	assert.Len(t, response.Prices, 2)
+	assert.Nil(t, response.Prices[0].EffectiveTo)
+	assert.Equal(t, money.MustParse("12.99"), response.Prices[1].Price)
+	assert.Equal(t, changedAt, *response.Prices[1].EffectiveTo)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_GetProductById_IncludesImageAndStatus tests that the image set and status are returned
+func TestProductController_GetProductById_IncludesImageAndStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockProduct := &models.Product{
+		Id:       1,
+		Name:     "Waffle with Berries",
+		Price:    money.MustParse("6.5"),
+		Category: "Waffle",
+		Status:   "available",
+		Image: models.Image{
+			Thumbnail: "https://example.com/waffle-thumbnail.jpg",
+			Mobile:    "https://example.com/waffle-mobile.jpg",
+			Tablet:    "https://example.com/waffle-tablet.jpg",
+			Desktop:   "https://example.com/waffle-desktop.jpg",
+		},
+	}
+
+	mockService.On("GetProductLastModified", mock.Anything, int64(1)).Return(time.Time{}, nil)
+	mockService.On("GetProductById", mock.Anything, int64(1)).Return(mockProduct, nil)
+
+	router := gin.New()
+	router.GET("/products/:productId", controller.GetProductById)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.ProductResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "available", response.Status)
+	assert.Equal(t, "https://example.com/waffle-thumbnail.jpg", response.Image.Thumbnail)
+	assert.Equal(t, "https://example.com/waffle-desktop.jpg", response.Image.Desktop)
+
+	mockService.AssertExpectations(t)
+}
+
 // MockProductService is a mock implementation of ProductService
 func TestProductController_GetProductById_InvalidID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -146,7 +425,7 @@ func TestProductController_GetProductById_NotFound(t *testing.T) {
 		Message:   "product not found",
 	}
 
-	mockService.On("GetProductById", mock.Anything, int64(999)).Return(nil, mockError)
+	mockService.On("GetProductLastModified", mock.Anything, int64(999)).Return(time.Time{}, mockError)
 
 	router := gin.New()
 	router.GET("/products/:productId", controller.GetProductById)
@@ -164,3 +443,403 @@ func TestProductController_GetProductById_NotFound(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+// TestProductController_GetProducts_NotModified tests that a listing revalidated with a current ETag gets a 304 without
+// loading the products
+func TestProductController_GetProducts_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	lastModified := time.Date(2025, 3, 1, 10, 30, 0, 123456000, time.UTC)
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(lastModified, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.Anything).Return(&models.ProductPage{Products: []*models.Product{}}, nil)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "Sat, 01 Mar 2025 10:30:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "public, no-cache", w.Header().Get("Cache-Control"))
+
+	req, _ = http.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	mockService.AssertNumberOfCalls(t, "GetProducts", 1)
+}
+
+// TestProductController_GetProducts_InvalidFilterNotModified tests that an invalid filter is rejected even when the
+// validators of the request are current, rather than revalidated with a 304
+func TestProductController_GetProducts_InvalidFilterNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	lastModified := time.Date(2025, 3, 1, 10, 30, 0, 123456000, time.UTC)
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(lastModified, nil)
+	mockService.On("ValidateProductFilter", mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return filter.Cursor == "garbage"
+	})).Return(&errors.ErrorDetails{ErrorCode: http.StatusBadRequest, Message: "invalid cursor"})
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products?cursor=garbage", nil)
+	req.Header.Set("If-None-Match", "*")
+	req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 10:30:00 GMT")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid cursor")
+	mockService.AssertNotCalled(t, "GetCatalogLastModified", mock.Anything)
+	mockService.AssertNotCalled(t, "GetProducts", mock.Anything, mock.Anything)
+}
+
+// TestProductController_GetProducts_ModifiedSince tests that a stale ETag takes precedence over If-Modified-Since
+func TestProductController_GetProducts_ModifiedSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	lastModified := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	mockService.On("GetCatalogLastModified", mock.Anything).Return(lastModified, nil)
+	mockService.On("ValidateProductFilter", mock.Anything).Return(nil)
+	mockService.On("GetProducts", mock.Anything, mock.Anything).Return(&models.ProductPage{Products: []*models.Product{}}, nil)
+
+	router := gin.New()
+	router.GET("/products", controller.GetProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 10:30:00 GMT")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 10:29:59 GMT")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	req.Header.Set("If-Modified-Since", "Sat, 01 Mar 2025 10:30:00 GMT")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockService.AssertNumberOfCalls(t, "GetProducts", 2)
+}
+
+// TestProductController_GetProductById_NotModified tests that a product revalidated with its current ETag gets a 304
+func TestProductController_GetProductById_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	lastModified := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	mockService.On("GetProductLastModified", mock.Anything, int64(1)).Return(lastModified, nil)
+	mockService.On("GetProductById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Name: "Margherita Pizza"}, nil)
+
+	router := gin.New()
+	router.GET("/products/:productId", controller.GetProductById)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/products/1", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	mockService.AssertNumberOfCalls(t, "GetProductById", 1)
+}
+
+// TestProductController_CreateProduct_Success tests that a valid product is created with a 201 response
+func TestProductController_CreateProduct_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	price := money.MustParse("12.99")
+	requestBody := requests.ProductRequest{Name: "Margherita Pizza", Category: "Pizza", Price: &price}
+	mockProduct := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"}
+
+	mockService.On("CreateProduct", mock.Anything, mock.AnythingOfType("*requests.ProductRequest")).Return(mockProduct, nil)
+
+	router := gin.New()
+	router.POST("/products", controller.CreateProduct)
+
+	jsonBody, _ := json.Marshal(requestBody)
+	req, _ := http.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", response["id"])
+
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_CreateProduct_MissingPrice tests that a product without a price is rejected
+func TestProductController_CreateProduct_MissingPrice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	router := gin.New()
+	router.POST("/products", controller.CreateProduct)
+
+	req, _ := http.NewRequest(http.MethodPost, "/products", bytes.NewBufferString(`{"name":"Margherita Pizza","category":"Pizza"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_request", response["type"])
+
+	mockService.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+}
+
+// TestProductController_CreateProduct_InvalidPrice tests that negative prices and prices with fractions of a cent are
+// rejected
+func TestProductController_CreateProduct_InvalidPrice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	router := gin.New()
+	router.POST("/products", controller.CreateProduct)
+
+	for _, price := range []string{"-1", "12.999", `"abc"`, "100000000"} {
+		body := `{"name":"Margherita Pizza","category":"Pizza","price":` + price + `}`
+		req, _ := http.NewRequest(http.MethodPost, "/products", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, price)
+	}
+
+	mockService.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+}
+
+// TestProductController_CreateProduct_Conflict tests that a duplicate product is reported as a conflict
+func TestProductController_CreateProduct_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusConflict,
+		Message:   "product with this name and category already exists",
+	}
+
+	mockService.On("CreateProduct", mock.Anything, mock.AnythingOfType("*requests.ProductRequest")).Return(nil, mockError)
+
+	router := gin.New()
+	router.POST("/products", controller.CreateProduct)
+
+	req, _ := http.NewRequest(http.MethodPost, "/products", bytes.NewBufferString(`{"name":"Margherita Pizza","category":"Pizza","price":12.99}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_PatchProduct_Success tests that a partial update is forwarded to the service
+func TestProductController_PatchProduct_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockProduct := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("9.99"), Category: "Pizza", Status: "available"}
+
+	mockService.On("PatchProduct", mock.Anything, int64(1), mock.MatchedBy(func(request *requests.PatchProductRequest) bool {
+		return request.Price != nil && *request.Price == money.MustParse("9.99") && request.Name == nil
+	})).Return(mockProduct, nil)
+
+	router := gin.New()
+	router.PATCH("/products/:productId", controller.PatchProduct)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/products/1", bytes.NewBufferString(`{"price":9.99}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_DeleteProduct_Success tests that deleting a product returns 204
+func TestProductController_DeleteProduct_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockService.On("DeleteProduct", mock.Anything, int64(1)).Return(nil)
+
+	router := gin.New()
+	router.DELETE("/products/:productId", controller.DeleteProduct)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/products/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_UpdateProductStatus_InvalidStatus tests that an unknown status is rejected before reaching the service
+func TestProductController_UpdateProductStatus_InvalidStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	router := gin.New()
+	router.PUT("/products/:productId/status", controller.UpdateProductStatus)
+
+	req, _ := http.NewRequest(http.MethodPut, "/products/1/status", bytes.NewBufferString(`{"status":"out_of_stock"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UpdateProductStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestProductController_UpdateProductStatus_Success tests that a status change returns the updated product
+func TestProductController_UpdateProductStatus_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockProduct := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "sold_out"}
+	mockService.On("UpdateProductStatus", mock.Anything, int64(1), &requests.ProductStatusRequest{Status: "sold_out"}).Return(mockProduct, nil)
+
+	router := gin.New()
+	router.PUT("/products/:productId/status", controller.UpdateProductStatus)
+
+	req, _ := http.NewRequest(http.MethodPut, "/products/1/status", bytes.NewBufferString(`{"status":"sold_out"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "sold_out", response["status"])
+
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_GetDeletedProducts_Success tests that the deleted product listing asks the service for deleted products
+func TestProductController_GetDeletedProducts_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	deletedAt := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	page := &models.ProductPage{
+		Products:   []*models.Product{{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Status: "available", DeletedAt: &deletedAt}},
+		TotalCount: 1,
+	}
+
+	mockService.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *models.ProductFilter) bool {
+		return filter.Deleted
+	})).Return(page, nil)
+
+	router := gin.New()
+	router.GET("/admin/products/deleted", controller.GetDeletedProducts)
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin/products/deleted", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+
+	var response []responses.ProductResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response, 1)
+	assert.Equal(t, deletedAt, *response[0].DeletedAt)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_RestoreProduct_Success tests that restoring a product returns it
+func TestProductController_RestoreProduct_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockProduct := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"}
+	mockService.On("RestoreProduct", mock.Anything, int64(1)).Return(mockProduct, nil)
+
+	router := gin.New()
+	router.POST("/admin/products/:productId/restore", controller.RestoreProduct)
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/products/1/restore", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.ProductResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "1", response.Id)
+	assert.Nil(t, response.DeletedAt)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestProductController_RestoreProduct_NotFound tests that restoring a product that is not deleted returns 404
+func TestProductController_RestoreProduct_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	mockService.On("RestoreProduct", mock.Anything, int64(1)).Return(nil, &errors.ErrorDetails{
+		ErrorCode: http.StatusNotFound,
+		Message:   "deleted product not found",
+	})
+
+	router := gin.New()
+	router.POST("/admin/products/:productId/restore", controller.RestoreProduct)
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/products/1/restore", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/models"
+	"testing"
+)
+
+// multipartImage builds a multipart body with content in the given form field
+func multipartImage(t *testing.T, field string, content []byte) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "waffle.jpg")
+	assert.NoError(t, err)
+	_, _ = part.Write(content)
+	assert.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+// TestProductImageController_UploadProductImage_Success tests that the uploaded file is passed to the service
+func TestProductImageController_UploadProductImage_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductImageService)
+	controller := controllers.NewProductImageController(mockService, 1<<20)
+
+	mockProduct := &models.Product{Id: 1, Name: "Waffle", Image: models.Image{Thumbnail: "/images/products/1/abc-thumbnail.jpg"}}
+	mockService.On("UploadProductImage", mock.Anything, int64(1), mock.MatchedBy(func(source io.Reader) bool {
+		content, _ := io.ReadAll(source)
+		return string(content) == "image content"
+	})).Return(mockProduct, nil)
+
+	router := gin.New()
+	router.POST("/products/:productId/image", controller.UploadProductImage)
+
+	body, contentType := multipartImage(t, "image", []byte("image content"))
+	req, _ := http.NewRequest(http.MethodPost, "/products/1/image", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.ProductResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "/images/products/1/abc-thumbnail.jpg", response.Image.Thumbnail)
+	mockService.AssertExpectations(t)
+}
+
+// TestProductImageController_UploadProductImage_MissingFile tests that a form without the image field is rejected
+func TestProductImageController_UploadProductImage_MissingFile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductImageService)
+	controller := controllers.NewProductImageController(mockService, 1<<20)
+
+	router := gin.New()
+	router.POST("/products/:productId/image", controller.UploadProductImage)
+
+	body, contentType := multipartImage(t, "file", []byte("image content"))
+	req, _ := http.NewRequest(http.MethodPost, "/products/1/image", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UploadProductImage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestProductImageController_UploadProductImage_TooLarge tests that an upload above the limit is rejected with 413
+func TestProductImageController_UploadProductImage_TooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockProductImageService)
+	controller := controllers.NewProductImageController(mockService, 1024)
+
+	router := gin.New()
+	router.POST("/products/:productId/image", controller.UploadProductImage)
+
+	body, contentType := multipartImage(t, "image", bytes.Repeat([]byte("x"), 4096))
+	req, _ := http.NewRequest(http.MethodPost, "/products/1/image", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockService.AssertNotCalled(t, "UploadProductImage", mock.Anything, mock.Anything, mock.Anything)
+}
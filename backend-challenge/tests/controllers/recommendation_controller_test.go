@@ -0,0 +1,62 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"testing"
+)
+
+// TestRecommendationController_GetProductRecommendations_DefaultLimit tests that five recommendations are asked for
+// without a limit
+func TestRecommendationController_GetProductRecommendations_DefaultLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRecommendationService)
+	controller := controllers.NewRecommendationController(mockService)
+
+	mockService.On("GetProductRecommendations", mock.Anything, int64(1), 5).Return([]*models.Product{
+		{Id: 4, Name: "Lemonade", Price: money.MustParse("4"), Status: models.ProductStatusAvailable},
+	}, nil)
+
+	router := gin.New()
+	router.GET("/product/:productId/recommendations", controller.GetProductRecommendations)
+
+	req, _ := http.NewRequest(http.MethodGet, "/product/1/recommendations", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []responses.ProductResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "4", response[0].Id)
+	mockService.AssertExpectations(t)
+}
+
+// TestRecommendationController_GetProductRecommendations_InvalidLimit tests that limits out of range are rejected
+func TestRecommendationController_GetProductRecommendations_InvalidLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRecommendationService)
+	controller := controllers.NewRecommendationController(mockService)
+
+	router := gin.New()
+	router.GET("/product/:productId/recommendations", controller.GetProductRecommendations)
+
+	for _, limit := range []string{"0", "21", "abc"} {
+		req, _ := http.NewRequest(http.MethodGet, "/product/1/recommendations?limit="+limit, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, limit)
+	}
+	mockService.AssertNotCalled(t, "GetProductRecommendations", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,130 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/money"
+	"testing"
+)
+
+// TestRefundController_RefundOrder_Success tests that a refund is created and its receipt returned
+func TestRefundController_RefundOrder_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRefundService)
+	controller := controllers.NewRefundController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockService.On("RefundOrder", mock.Anything, orderId, mock.MatchedBy(func(request *requests.RefundRequest) bool {
+		return len(request.Items) == 1 && request.Items[0].OrderItemId == "11" && request.Items[0].Quantity == 1 &&
+			request.Actor == "counter" && request.Restock
+	})).Return(&responses.RefundResponse{Id: "2", OrderId: orderId, Amount: money.MustParse("9"), OrderTotal: money.MustParse("27"), RefundedTotal: money.MustParse("9"), Remaining: money.MustParse("18")}, nil)
+
+	router := gin.New()
+	router.POST("/order/:orderId/refunds", controller.RefundOrder)
+
+	body := []byte(`{"items": [{"orderItemId": "11", "quantity": 1}], "actor": "counter", "restock": true}`)
+	req, _ := http.NewRequest(http.MethodPost, "/order/"+orderId+"/refunds", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response responses.RefundResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", response.Id)
+	assert.Equal(t, money.MustParse("18.0"), response.Remaining)
+}
+
+// TestRefundController_RefundOrder_Errors tests that invalid refunds are rejected before the service and the errors
+// of the service are returned as they are
+func TestRefundController_RefundOrder_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRefundService)
+	controller := controllers.NewRefundController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockService.On("RefundOrder", mock.Anything, orderId, mock.Anything).
+		Return(nil, &errors.ErrorDetails{ErrorCode: http.StatusUnprocessableEntity, Message: "cannot refund a placed order, only completed orders are refunded"})
+
+	router := gin.New()
+	router.POST("/order/:orderId/refunds", controller.RefundOrder)
+
+	tests := []struct {
+		name    string
+		orderId string
+		body    string
+		code    int
+	}{
+		{"malformed order id", "42", `{"actor": "counter"}`, http.StatusBadRequest},
+		{"missing actor", orderId, `{}`, http.StatusBadRequest},
+		{"zero quantity", orderId, `{"items": [{"orderItemId": "11", "quantity": 0}], "actor": "counter"}`, http.StatusBadRequest},
+		{"order not completed", orderId, `{"actor": "counter"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/order/"+tt.orderId+"/refunds", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	mockService.AssertNumberOfCalls(t, "RefundOrder", 1)
+}
+
+// TestRefundController_GetRefunds tests that the receipts of an order and a single receipt are returned
+func TestRefundController_GetRefunds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockRefundService)
+	controller := controllers.NewRefundController(mockService)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	receipts := []*responses.RefundResponse{
+		{Id: "1", OrderId: orderId, Amount: money.MustParse("9"), RefundedTotal: money.MustParse("9")},
+		{Id: "2", OrderId: orderId, Amount: money.MustParse("18"), RefundedTotal: money.MustParse("27")},
+	}
+	mockService.On("GetRefunds", mock.Anything, orderId).Return(receipts, nil)
+	mockService.On("GetRefund", mock.Anything, orderId, int64(2)).Return(receipts[1], nil)
+
+	router := gin.New()
+	router.GET("/order/:orderId/refunds", controller.GetRefunds)
+	router.GET("/order/:orderId/refunds/:refundId", controller.GetRefund)
+
+	req, _ := http.NewRequest(http.MethodGet, "/order/"+orderId+"/refunds", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list []responses.RefundResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list, 2)
+
+	req, _ = http.NewRequest(http.MethodGet, "/order/"+orderId+"/refunds/2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var receipt responses.RefundResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &receipt))
+	assert.Equal(t, money.MustParse("27.0"), receipt.RefundedTotal)
+
+	req, _ = http.NewRequest(http.MethodGet, "/order/"+orderId+"/refunds/latest", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,125 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"testing"
+)
+
+// TestStockController_GetStock_Success tests that the stock and its adjustments are returned
+func TestStockController_GetStock_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockStockService)
+	controller := controllers.NewStockController(mockService)
+
+	quantity := 5
+	delta := -1
+	product := &models.Product{Id: 1, Name: "Daily Special", Price: money.MustParse("15.00"), Category: "Specials", Status: "available", StockQuantity: &quantity}
+	adjustments := []*models.StockAdjustment{
+		{Id: 2, ProductId: 1, OrderId: "0f8fad5b-d9cb-469f-a165-70867728950e", Delta: &delta, QuantityAfter: &quantity, Reason: "order"},
+	}
+	mockService.On("GetStock", mock.Anything, int64(1)).Return(product, adjustments, nil)
+
+	router := gin.New()
+	router.GET("/products/:productId/stock", controller.GetStock)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products/1/stock", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", response["productId"])
+	assert.Equal(t, float64(5), response["quantity"])
+	assert.Equal(t, false, response["unlimited"])
+	assert.Len(t, response["adjustments"], 1)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestStockController_GetStock_Unlimited tests that an untracked stock is reported as unlimited
+func TestStockController_GetStock_Unlimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockStockService)
+	controller := controllers.NewStockController(mockService)
+
+	product := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"}
+	mockService.On("GetStock", mock.Anything, int64(1)).Return(product, []*models.StockAdjustment{}, nil)
+
+	router := gin.New()
+	router.GET("/products/:productId/stock", controller.GetStock)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products/1/stock", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, true, response["unlimited"])
+	assert.NotContains(t, response, "quantity")
+}
+
+// TestStockController_AdjustStock_MissingReason tests that an adjustment without a reason is rejected
+func TestStockController_AdjustStock_MissingReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockStockService)
+	controller := controllers.NewStockController(mockService)
+
+	router := gin.New()
+	router.POST("/products/:productId/stock", controller.AdjustStock)
+
+	req, _ := http.NewRequest(http.MethodPost, "/products/1/stock", bytes.NewBufferString(`{"delta":5}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "AdjustStock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestStockController_AdjustStock_Insufficient tests that an adjustment below zero returns the failing item
+func TestStockController_AdjustStock_Insufficient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockStockService)
+	controller := controllers.NewStockController(mockService)
+
+	delta := -10
+	stockErr := exceptions.InsufficientStockException([]errors.ItemError{{ProductId: "1", Reason: "insufficient_stock", Message: "only 3 left in stock"}})
+	mockService.On("AdjustStock", mock.Anything, int64(1), &requests.StockAdjustmentRequest{Delta: &delta, Reason: "spoiled"}).Return(nil, nil, stockErr)
+
+	router := gin.New()
+	router.POST("/products/:productId/stock", controller.AdjustStock)
+
+	req, _ := http.NewRequest(http.MethodPost, "/products/1/stock", bytes.NewBufferString(`{"delta":-10,"reason":"spoiled"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	details := response["details"].([]interface{})
+	assert.Len(t, details, 1)
+	assert.Equal(t, "insufficient_stock", details[0].(map[string]interface{})["reason"])
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,141 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/controllers"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/dtos/responses"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/i18n"
+	"oolio.com/kart/middlewares"
+	"oolio.com/kart/models"
+	"testing"
+	"time"
+)
+
+// TestTranslationController_SetProductTranslation_Success tests that the locale of the path and the translation of
+// the body reach the service
+func TestTranslationController_SetProductTranslation_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTranslationService)
+	controller := controllers.NewTranslationController(mockService)
+
+	request := &requests.ProductTranslationRequest{Name: "Gaufre au chocolat", Description: "Gaufre croustillante"}
+	mockService.On("SetProductTranslation", mock.Anything, int64(1), "fr", request).Return(&models.ProductTranslation{
+		ProductId:   1,
+		Locale:      "fr",
+		Name:        "Gaufre au chocolat",
+		Description: "Gaufre croustillante",
+		ModifiedAt:  time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC),
+	}, nil)
+
+	router := gin.New()
+	router.PUT("/product/:productId/translations/:locale", controller.SetProductTranslation)
+
+	body, _ := json.Marshal(request)
+	req, _ := http.NewRequest(http.MethodPut, "/product/1/translations/fr", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response responses.ProductTranslationResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", response.ProductId)
+	assert.Equal(t, "fr", response.Locale)
+	assert.Equal(t, "Gaufre au chocolat", response.Name)
+	mockService.AssertExpectations(t)
+}
+
+// TestTranslationController_SetProductTranslation_MissingName tests that a translation without a name is rejected
+// before reaching the service
+func TestTranslationController_SetProductTranslation_MissingName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTranslationService)
+	controller := controllers.NewTranslationController(mockService)
+
+	router := gin.New()
+	router.PUT("/product/:productId/translations/:locale", controller.SetProductTranslation)
+
+	req, _ := http.NewRequest(http.MethodPut, "/product/1/translations/fr", bytes.NewBufferString(`{"description":"Gaufre"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "SetProductTranslation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestTranslationController_DeleteCategoryTranslation_NotFound tests that deleting a missing translation is a 404
+func TestTranslationController_DeleteCategoryTranslation_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockTranslationService)
+	controller := controllers.NewTranslationController(mockService)
+
+	mockService.On("DeleteCategoryTranslation", mock.Anything, int64(3), "fr").
+		Return(exceptions.GenericException("translation not found", http.StatusNotFound))
+
+	router := gin.New()
+	router.DELETE("/category/:categoryId/translations/:locale", controller.DeleteCategoryTranslation)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/category/3/translations/fr", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestLocaleMiddleware_ETagVariesByLocale tests that the negotiated locale reaches the service and that each locale of
+// a product gets its own ETag
+func TestLocaleMiddleware_ETagVariesByLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	locales, err := i18n.NewLocales("en", []string{"fr"})
+	assert.NoError(t, err)
+	previous := configs.Locales
+	configs.Locales = locales
+	t.Cleanup(func() { configs.Locales = previous })
+
+	mockService := new(MockProductService)
+	controller := controllers.NewProductController(mockService)
+
+	lastModified := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	mockService.On("GetProductLastModified", mock.Anything, int64(1)).Return(lastModified, nil)
+	mockService.On("GetProductById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Name: "Chocolate Waffle"}, nil)
+
+	router := gin.New()
+	router.Use(middlewares.LocaleMiddleware())
+	router.GET("/products/:productId", controller.GetProductById)
+
+	req, _ := http.NewRequest(http.MethodGet, "/products/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	defaultETag := w.Header().Get("ETag")
+
+	req, _ = http.NewRequest(http.MethodGet, "/products/1", nil)
+	req.Header.Set("Accept-Language", "fr-CA, en;q=0.5")
+	req.Header.Set("If-None-Match", defaultETag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "fr", w.Header().Get("Content-Language"))
+	assert.NotEqual(t, defaultETag, w.Header().Get("ETag"))
+	mockService.AssertCalled(t, "GetProductById", mock.MatchedBy(func(ctx context.Context) bool {
+		return i18n.LocaleFrom(ctx) == "fr"
+	}), int64(1))
+}
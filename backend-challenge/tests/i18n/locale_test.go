@@ -0,0 +1,68 @@
+package i18n_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"oolio.com/kart/i18n"
+	"testing"
+)
+
+// TestNewLocales tests that locales are canonicalized and deduplicated with the default one first
+func TestNewLocales(t *testing.T) {
+	locales, err := i18n.NewLocales("en", []string{"fr", " pt-br", "", "EN", "fr-FR"})
+	assert.NoError(t, err)
+	assert.Equal(t, "en", locales.Default)
+	assert.Equal(t, []string{"en", "fr", "pt-BR", "fr-FR"}, locales.Supported)
+
+	_, err = i18n.NewLocales("not a locale", nil)
+	assert.Error(t, err)
+
+	_, err = i18n.NewLocales("en", []string{"fr", "x_y_z!"})
+	assert.Error(t, err)
+}
+
+// TestLocales_Negotiate tests that the best supported locale of an Accept-Language header is picked, falling back to
+// the default locale
+func TestLocales_Negotiate(t *testing.T) {
+	locales, err := i18n.NewLocales("en", []string{"fr", "pt-BR"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "missing", acceptLanguage: "", want: "en"},
+		{name: "exact", acceptLanguage: "fr", want: "fr"},
+		{name: "regional variant", acceptLanguage: "fr-CA", want: "fr"},
+		{name: "quality values", acceptLanguage: "de;q=1.0, pt-BR;q=0.8, fr;q=0.5", want: "pt-BR"},
+		{name: "unsupported", acceptLanguage: "ja", want: "en"},
+		{name: "wildcard", acceptLanguage: "*", want: "en"},
+		{name: "malformed", acceptLanguage: "fr;q=abc", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, locales.Negotiate(tt.acceptLanguage))
+		})
+	}
+}
+
+// TestLocales_Canonical tests that supported locales are found regardless of their case
+func TestLocales_Canonical(t *testing.T) {
+	locales, err := i18n.NewLocales("en", []string{"pt-BR"})
+	assert.NoError(t, err)
+
+	locale, found := locales.Canonical("PT-br")
+	assert.True(t, found)
+	assert.Equal(t, "pt-BR", locale)
+
+	_, found = locales.Canonical("pt")
+	assert.False(t, found)
+}
+
+// TestLocaleFrom tests that the locale travels through the context, empty when none was negotiated
+func TestLocaleFrom(t *testing.T) {
+	assert.Empty(t, i18n.LocaleFrom(context.Background()))
+	assert.Equal(t, "fr", i18n.LocaleFrom(i18n.WithLocale(context.Background(), "fr")))
+}
@@ -0,0 +1,100 @@
+package images_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"oolio.com/kart/images"
+	"testing"
+)
+
+var renditions = []images.Rendition{
+	{Name: images.RenditionThumbnail, Width: 40},
+	{Name: images.RenditionMobile, Width: 100},
+	{Name: images.RenditionTablet, Width: 160},
+	{Name: images.RenditionDesktop, Width: 400},
+}
+
+// source encodes a width x height image, opaque images as JPEG and the others as PNG
+func source(t *testing.T, width, height int, alpha uint8) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: alpha})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if alpha == 255 {
+		require.NoError(t, jpeg.Encode(&buffer, img, nil))
+	} else {
+		require.NoError(t, png.Encode(&buffer, img))
+	}
+	return buffer.Bytes()
+}
+
+// TestImages_Generate tests that every rendition is scaled to its width keeping the aspect ratio, without scaling up
+func TestImages_Generate(t *testing.T) {
+	encoded, err := images.Generate(source(t, 200, 100, 255), renditions)
+	require.NoError(t, err)
+	require.Len(t, encoded, 4)
+
+	expected := []struct {
+		name          string
+		width, height int
+	}{
+		{images.RenditionThumbnail, 40, 20},
+		{images.RenditionMobile, 100, 50},
+		{images.RenditionTablet, 160, 80},
+		{images.RenditionDesktop, 200, 100},
+	}
+	for i, rendition := range encoded {
+		assert.Equal(t, expected[i].name, rendition.Name)
+		assert.Equal(t, "image/jpeg", rendition.ContentType)
+		assert.Equal(t, "jpg", rendition.Extension)
+
+		decoded, format, err := image.DecodeConfig(bytes.NewReader(rendition.Content))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, expected[i].width, decoded.Width)
+		assert.Equal(t, expected[i].height, decoded.Height)
+		assert.Equal(t, expected[i].width, rendition.Width)
+		assert.Equal(t, expected[i].height, rendition.Height)
+	}
+}
+
+// TestImages_Generate_KeepsTransparency tests that sources with transparency are encoded as PNG
+func TestImages_Generate_KeepsTransparency(t *testing.T) {
+	encoded, err := images.Generate(source(t, 200, 100, 100), renditions[:1])
+	require.NoError(t, err)
+
+	assert.Equal(t, "image/png", encoded[0].ContentType)
+	assert.Equal(t, "png", encoded[0].Extension)
+
+	decoded, err := png.Decode(bytes.NewReader(encoded[0].Content))
+	require.NoError(t, err)
+	_, _, _, a := decoded.At(10, 10).RGBA()
+	assert.InDelta(t, 100, a>>8, 1)
+}
+
+// TestImages_Generate_Unsupported tests that anything but an image is rejected
+func TestImages_Generate_Unsupported(t *testing.T) {
+	_, err := images.Generate([]byte("not an image"), renditions)
+	assert.ErrorIs(t, err, images.ErrUnsupportedImage)
+}
+
+// TestImages_Resize tests that the area average of a uniform image keeps its color
+func TestImages_Resize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 30, 9))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []uint8{200, 100, 50, 255})
+	}
+
+	resized := images.Resize(src, 7)
+	assert.Equal(t, image.Rect(0, 0, 7, 2), resized.Bounds())
+	assert.Equal(t, color.RGBA{R: 200, G: 100, B: 50, A: 255}, resized.RGBAAt(6, 1))
+}
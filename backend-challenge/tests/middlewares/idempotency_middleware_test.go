@@ -0,0 +1,193 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"oolio.com/kart/constants"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/middlewares"
+	"oolio.com/kart/models"
+	"strings"
+	"testing"
+)
+
+// idempotentRouter serves POST /order with the middleware, the handler echoes the body it read and counts its calls
+func idempotentRouter(service *MockIdempotencyService, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/order", middlewares.IdempotencyMiddleware(service), func(c *gin.Context) {
+		*calls++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	})
+	return router
+}
+
+// scopedKey matches the key stored for an Idempotency-Key header, prefixed with the scope of its API key
+func scopedKey(key string) any {
+	return mock.MatchedBy(func(stored string) bool {
+		return strings.HasSuffix(stored, ":"+key)
+	})
+}
+
+func postOrder(router *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	return postOrderWithAPIKey(router, "kiosk-api-key", key, body)
+}
+
+func postOrderWithAPIKey(router *gin.Engine, apiKey string, key string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/order", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constants.APIKeyHeader, apiKey)
+	if key != "" {
+		req.Header.Set(constants.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestIdempotencyMiddleware_WithoutKey tests that requests without the header are processed as usual
+func TestIdempotencyMiddleware_WithoutKey(t *testing.T) {
+	service := new(MockIdempotencyService)
+	calls := 0
+	router := idempotentRouter(service, &calls)
+
+	w := postOrder(router, "", `{"items":[]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, calls)
+	service.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestIdempotencyMiddleware_FirstRequest tests that the first request with a key is processed with its body intact and
+// its response stored
+func TestIdempotencyMiddleware_FirstRequest(t *testing.T) {
+	service := new(MockIdempotencyService)
+	calls := 0
+	router := idempotentRouter(service, &calls)
+
+	body := `{"items":[{"productId":"1","quantity":1}]}`
+	service.On("Begin", mock.Anything, scopedKey("kiosk-7-0042"), mock.AnythingOfType("string")).Return(nil, nil)
+	service.On("Complete", mock.Anything, scopedKey("kiosk-7-0042"), mock.AnythingOfType("string"), http.StatusOK, []byte(body)).Return(nil)
+
+	w := postOrder(router, "kiosk-7-0042", body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Empty(t, w.Header().Get(constants.IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+	service.AssertExpectations(t)
+}
+
+// TestIdempotencyMiddleware_Replay tests that retries get the stored response without being processed again
+func TestIdempotencyMiddleware_Replay(t *testing.T) {
+	service := new(MockIdempotencyService)
+	calls := 0
+	router := idempotentRouter(service, &calls)
+
+	stored := &models.IdempotencyRecord{Key: "kiosk-7-0042", StatusCode: http.StatusOK, Body: []byte(`{"id":"550e8400-e29b-41d4-a716-446655440000"}`)}
+	service.On("Begin", mock.Anything, scopedKey("kiosk-7-0042"), mock.AnythingOfType("string")).Return(stored, nil)
+
+	w := postOrder(router, "kiosk-7-0042", `{"items":[{"productId":"1","quantity":1}]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(stored.Body), w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(constants.IdempotentReplayedHeader))
+	assert.Equal(t, 0, calls)
+	service.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestIdempotencyMiddleware_Fingerprint tests that the same body formatted differently has the same fingerprint and a
+// different body another one
+func TestIdempotencyMiddleware_Fingerprint(t *testing.T) {
+	service := new(MockIdempotencyService)
+	calls := 0
+	router := idempotentRouter(service, &calls)
+
+	var fingerprints []string
+	service.On("Begin", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fingerprints = append(fingerprints, args.String(2))
+	}).Return(nil, nil)
+	service.On("Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	postOrder(router, "kiosk-7-0042", `{"items":[{"productId":"1","quantity":1}]}`)
+	postOrder(router, "kiosk-7-0042", "{\n  \"items\": [ {\"productId\": \"1\", \"quantity\": 1} ]\n}")
+	postOrder(router, "kiosk-7-0042", `{"items":[{"productId":"1","quantity":2}]}`)
+
+	assert.Len(t, fingerprints, 3)
+	assert.Len(t, fingerprints[0], 64)
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+}
+
+// TestIdempotencyMiddleware_Rejected tests that malformed keys, bodies too large to fingerprint and the errors of the
+// service stop the request
+func TestIdempotencyMiddleware_Rejected(t *testing.T) {
+	service := new(MockIdempotencyService)
+	calls := 0
+	router := idempotentRouter(service, &calls)
+
+	service.On("Begin", mock.Anything, scopedKey("kiosk-7-0042"), mock.Anything).
+		Return(nil, &errors.ErrorDetails{ErrorCode: http.StatusUnprocessableEntity, Message: "idempotency key was already used with a different request"})
+	service.On("Begin", mock.Anything, scopedKey("kiosk-7-0043"), mock.Anything).
+		Return(nil, &errors.ErrorDetails{ErrorCode: http.StatusConflict, Message: "a request with this idempotency key is being processed, retry later"})
+
+	tests := []struct {
+		name string
+		key  string
+		code int
+	}{
+		{"key too long", strings.Repeat("k", constants.MaxIdempotencyKeyLength+1), http.StatusBadRequest},
+		{"key with spaces", "kiosk 7", http.StatusBadRequest},
+		{"key reused", "kiosk-7-0042", http.StatusUnprocessableEntity},
+		{"key in flight", "kiosk-7-0043", http.StatusConflict},
+		{"body too large", "kiosk-7-0044", http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"items":[]}`
+			if tt.code == http.StatusRequestEntityTooLarge {
+				body = `{"items":[],"note":"` + strings.Repeat("x", constants.MaxIdempotentRequestSize) + `"}`
+			}
+
+			w := postOrder(router, tt.key, body)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	assert.Equal(t, 0, calls)
+}
+
+// TestIdempotencyMiddleware_ScopedToAPIKey tests that the same key sent with different API keys is stored as different
+// keys, so that a client cannot replay the response of another
+func TestIdempotencyMiddleware_ScopedToAPIKey(t *testing.T) {
+	service := new(MockIdempotencyService)
+	calls := 0
+	router := idempotentRouter(service, &calls)
+
+	var keys []string
+	service.On("Begin", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		keys = append(keys, args.String(1))
+	}).Return(nil, nil)
+	service.On("Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	body := `{"items":[{"productId":"1","quantity":1}]}`
+	postOrderWithAPIKey(router, "kiosk-api-key", "order-1", body)
+	postOrderWithAPIKey(router, "kiosk-api-key", "order-1", body)
+	postOrderWithAPIKey(router, "web-api-key", "order-1", body)
+
+	assert.Len(t, keys, 3)
+	assert.Equal(t, keys[0], keys[1])
+	assert.NotEqual(t, keys[0], keys[2])
+	for _, key := range keys {
+		assert.True(t, strings.HasSuffix(key, ":order-1"), key)
+		assert.NotContains(t, key, "api-key")
+	}
+}
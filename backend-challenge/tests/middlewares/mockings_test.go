@@ -0,0 +1,32 @@
+package middlewares_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+)
+
+// MockIdempotencyService is a mock implementation of IdempotencyService
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyService) Begin(ctx context.Context, key string, fingerprint string) (*models.IdempotencyRecord, *errors.ErrorDetails) {
+	args := m.Called(ctx, key, fingerprint)
+	if args.Get(1) != nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	if args.Get(0) == nil {
+		return nil, nil
+	}
+	return args.Get(0).(*models.IdempotencyRecord), nil
+}
+
+func (m *MockIdempotencyService) Complete(ctx context.Context, key string, fingerprint string, statusCode int, body []byte) *errors.ErrorDetails {
+	args := m.Called(ctx, key, fingerprint, statusCode, body)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
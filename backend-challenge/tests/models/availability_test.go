@@ -0,0 +1,82 @@
+package models_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"oolio.com/kart/models"
+	"testing"
+	"time"
+)
+
+// TestAvailabilityWindow_IsOpenAt tests daytime and overnight windows, 2024-01-01 is a Monday
+func TestAvailabilityWindow_IsOpenAt(t *testing.T) {
+	breakfast := &models.AvailabilityWindow{DaysOfWeek: []int{1, 2, 3, 4, 5}, StartMinute: 7 * 60, EndMinute: 11 * 60}
+	lateNight := &models.AvailabilityWindow{DaysOfWeek: []int{5, 6}, StartMinute: 22 * 60, EndMinute: 2 * 60}
+	untilMidnight := &models.AvailabilityWindow{DaysOfWeek: []int{7}, StartMinute: 18 * 60, EndMinute: models.MinutesPerDay}
+
+	tests := []struct {
+		name   string
+		window *models.AvailabilityWindow
+		at     time.Time
+		open   bool
+	}{
+		{"breakfast on Monday", breakfast, time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC), true},
+		{"breakfast ends exclusive", breakfast, time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC), false},
+		{"breakfast before opening", breakfast, time.Date(2024, 1, 1, 6, 59, 0, 0, time.UTC), false},
+		{"no breakfast on Saturday", breakfast, time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC), false},
+		{"late night on Friday", lateNight, time.Date(2024, 1, 5, 23, 30, 0, 0, time.UTC), true},
+		{"late night past midnight into Saturday", lateNight, time.Date(2024, 1, 6, 1, 0, 0, 0, time.UTC), true},
+		{"late night past midnight into Monday", lateNight, time.Date(2024, 1, 8, 1, 0, 0, 0, time.UTC), false},
+		{"late night not on Friday morning", lateNight, time.Date(2024, 1, 5, 1, 0, 0, 0, time.UTC), false},
+		{"until midnight on Sunday", untilMidnight, time.Date(2024, 1, 7, 23, 59, 0, 0, time.UTC), true},
+		{"until midnight not on Monday", untilMidnight, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.open, tt.window.IsOpenAt(tt.at))
+		})
+	}
+}
+
+// TestIsAvailableAt tests that a product without windows is always available
+func TestIsAvailableAt(t *testing.T) {
+	monday := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	dinner := &models.AvailabilityWindow{DaysOfWeek: []int{1}, StartMinute: 17 * 60, EndMinute: 22 * 60}
+
+	assert.True(t, models.IsAvailableAt(nil, monday))
+	assert.False(t, models.IsAvailableAt([]*models.AvailabilityWindow{dinner}, monday))
+	assert.Equal(t, "Mon 17:00-22:00", dinner.String())
+}
+
+// TestParseMinute tests the HH:MM times of day accepted by the availability windows
+func TestParseMinute(t *testing.T) {
+	tests := []struct {
+		value   string
+		minute  int
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"07:30", 450, false},
+		{"24:00", models.MinutesPerDay, false},
+		{"24:01", 0, true},
+		{"12:60", 0, true},
+		{"7:30", 0, true},
+		{"07:30:00", 0, true},
+		{"ab:cd", 0, true},
+		{"7:00a", 0, true},
+		{"+7:00", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			minute, err := models.ParseMinute(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.minute, minute)
+			assert.Equal(t, tt.value, models.FormatMinute(minute))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package models_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"oolio.com/kart/models"
+	"testing"
+)
+
+// TestNormalizeDietaryAttributes tests that dietary attributes are sorted, deduplicated, completed and checked
+func TestNormalizeDietaryAttributes(t *testing.T) {
+	tests := []struct {
+		name      string
+		allergens []string
+		diets     []string
+		want      []string
+		wantDiets []string
+		wantErr   string
+	}{
+		{"empty", nil, nil, []string{}, []string{}, ""},
+		{"sorted and deduplicated", []string{"nuts", "gluten", "nuts"}, []string{"halal"}, []string{"gluten", "nuts"}, []string{"halal"}, ""},
+		{"vegan implies vegetarian and dairy free", []string{"soy"}, []string{"vegan"}, []string{"soy"}, []string{"dairy_free", "vegan", "vegetarian"}, ""},
+		{"vegetarian with fish", []string{"fish"}, []string{"vegetarian"}, nil, nil, "a vegetarian product cannot contain fish"},
+		{"gluten free with gluten", []string{"gluten"}, []string{"gluten_free"}, nil, nil, "a gluten_free product cannot contain gluten"},
+		{"unknown allergen", []string{"shellfish"}, nil, nil, nil, `unknown allergen "shellfish"`},
+		{"unknown diet", nil, []string{"keto"}, nil, nil, `unknown diet "keto"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allergens, diets, err := models.NormalizeDietaryAttributes(tt.allergens, tt.diets)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, allergens)
+			assert.Equal(t, tt.wantDiets, diets)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package models_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"math/rand/v2"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"testing"
+)
+
+// pricingRuns is the number of random orders each property is checked against
+const pricingRuns = 2000
+
+// randomOrder builds an order of 1 to 6 lines priced up to 500 a unit, with a discount of up to its subtotal
+func randomOrder(r *rand.Rand) (*models.Order, []models.OrderItem) {
+	order := &models.Order{Id: "550e8400-e29b-41d4-a716-446655440000", Status: models.OrderStatusCompleted}
+	items := make([]models.OrderItem, 1+r.IntN(6))
+	var subtotal int64
+	for i := range items {
+		items[i] = models.OrderItem{
+			Id:        int64(i + 1),
+			OrderId:   order.Id,
+			ProductId: int64(i + 1),
+			Quantity:  1 + r.IntN(9),
+			UnitPrice: money.FromCents(r.Int64N(50000)),
+		}
+		subtotal += items[i].UnitPrice.Cents() * int64(items[i].Quantity)
+	}
+	order.Discount = money.FromCents(r.Int64N(subtotal + 1))
+	return order, items
+}
+
+// randomAmount writes a random amount of up to limit cents, negative when negative is set, the way a client would:
+// with no, one or two decimals
+func randomAmount(r *rand.Rand, limit int64, negative bool) string {
+	cents := r.Int64N(limit + 1)
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	switch r.IntN(3) {
+	case 0:
+		return fmt.Sprintf("%s%d", sign, cents/100)
+	case 1:
+		return fmt.Sprintf("%s%d.%d", sign, cents/100, cents%100/10)
+	default:
+		return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+	}
+}
+
+// exactAmount reads a decimal amount into an exact rational, independently of the money package
+func exactAmount(t *testing.T, value string) *big.Rat {
+	amount, ok := new(big.Rat).SetString(value)
+	require.True(t, ok, value)
+	return amount
+}
+
+// TestPriceOrder_MatchesExactSum tests that the lines, subtotal and total of random orders with modifiers are the exact
+// sums of the decimal prices they are made of, and that orders whose lines or subtotal exceed the greatest price are
+// rejected rather than priced
+func TestPriceOrder_MatchesExactSum(t *testing.T) {
+	maxPrice := exactAmount(t, "99999999.99")
+	r := rand.New(rand.NewPCG(3, 4))
+	linesRejected, subtotalsRejected := 0, 0
+	for range pricingRuns {
+		// One order in four has a few items priced close to the greatest price, for its lines or only its subtotal
+		// to go past it
+		limit, maxQuantity := int64(50000), 1000
+		if r.IntN(4) == 0 {
+			limit, maxQuantity = models.MaxPrice.Cents(), 2
+		}
+
+		discount := randomAmount(r, 10000, false)
+		order := &models.Order{Discount: money.MustParse(discount)}
+		items := make([]models.OrderItem, 1+r.IntN(6))
+		lines := make([]*big.Rat, len(items))
+		subtotal := new(big.Rat)
+		lineOverMax := false
+		for i := range items {
+			price := randomAmount(r, limit, false)
+			unitPrice := money.MustParse(price)
+			exactUnitPrice := exactAmount(t, price)
+			var modifiers []models.OrderItemModifier
+			for range r.IntN(4) {
+				delta := randomAmount(r, 500, r.IntN(3) == 0)
+				modifiers = append(modifiers, models.OrderItemModifier{PriceDelta: money.MustParse(delta)})
+
+				var err error
+				unitPrice, err = unitPrice.Add(money.MustParse(delta))
+				require.NoError(t, err)
+				exactUnitPrice.Add(exactUnitPrice, exactAmount(t, delta))
+			}
+
+			quantity := 1 + r.IntN(maxQuantity)
+			items[i] = models.OrderItem{ProductId: int64(i + 1), Quantity: quantity, UnitPrice: unitPrice, Modifiers: modifiers}
+
+			lines[i] = new(big.Rat).Mul(exactUnitPrice, big.NewRat(int64(quantity), 1))
+			lineOverMax = lineOverMax || lines[i].Cmp(maxPrice) > 0
+			subtotal.Add(subtotal, lines[i])
+		}
+
+		err := models.PriceOrder(order, items)
+		if lineOverMax {
+			require.Error(t, err)
+			linesRejected++
+			continue
+		}
+		if subtotal.Cmp(maxPrice) > 0 {
+			require.Error(t, err)
+			subtotalsRejected++
+			continue
+		}
+		require.NoError(t, err)
+
+		for i, item := range items {
+			require.Zero(t, lines[i].Cmp(big.NewRat(item.Price.Cents(), 100)), "line %s", lines[i].FloatString(2))
+		}
+		require.Zero(t, subtotal.Cmp(big.NewRat(order.Subtotal.Cents(), 100)), "subtotal %s", subtotal.FloatString(2))
+		total := new(big.Rat).Sub(subtotal, exactAmount(t, discount))
+		require.Zero(t, total.Cmp(big.NewRat(order.Total.Cents(), 100)), "total %s", total.FloatString(2))
+	}
+
+	// Every path is taken often enough for the property to mean something
+	require.Greater(t, linesRejected, pricingRuns/50)
+	require.Greater(t, subtotalsRejected, pricingRuns/50)
+	require.Less(t, linesRejected+subtotalsRejected, pricingRuns/2)
+}
+
+// TestPlanRefund_AddsUpToTotal tests that however an order is refunded, in any number of partial refunds, the refunds
+// never exceed the total charged and add up to it exactly once everything is refunded
+func TestPlanRefund_AddsUpToTotal(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	for range pricingRuns {
+		order, items := randomOrder(r)
+		require.NoError(t, models.PriceOrder(order, items))
+
+		left := make(map[int64]int, len(items))
+		for _, item := range items {
+			left[item.Id] = item.Quantity
+		}
+
+		var refunds []*models.Refund
+		for len(left) > 0 {
+			quantities := make(map[int64]int)
+			for itemId, quantity := range left {
+				if r.IntN(2) == 0 {
+					quantities[itemId] = 1 + r.IntN(quantity)
+				}
+			}
+			if len(quantities) == 0 {
+				continue
+			}
+
+			refund, err := models.PlanRefund(order, items, refunds, quantities)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, refund.Amount.Sign(), 0)
+
+			lines := make([]money.Money, len(refund.Items))
+			for i, item := range refund.Items {
+				lines[i] = item.Amount
+			}
+			linesTotal, err := money.Sum(lines...)
+			require.NoError(t, err)
+			require.Equal(t, refund.Amount, linesTotal)
+
+			refunds = append(refunds, refund)
+			refunded, err := models.RefundedAmount(refunds)
+			require.NoError(t, err)
+			require.LessOrEqual(t, refunded.Cmp(order.Total), 0)
+
+			for itemId, quantity := range quantities {
+				if left[itemId] -= quantity; left[itemId] == 0 {
+					delete(left, itemId)
+				}
+			}
+		}
+
+		refunded, err := models.RefundedAmount(refunds)
+		require.NoError(t, err)
+		require.Equal(t, order.Total, refunded)
+	}
+}
@@ -0,0 +1,37 @@
+package models_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"oolio.com/kart/models"
+	"testing"
+)
+
+// TestCanTransitionOrderStatus tests that orders move forward one step at a time and can only be cancelled before
+// their preparation starts
+func TestCanTransitionOrderStatus(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{models.OrderStatusPlaced, models.OrderStatusAccepted, true},
+		{models.OrderStatusAccepted, models.OrderStatusPreparing, true},
+		{models.OrderStatusPreparing, models.OrderStatusReady, true},
+		{models.OrderStatusReady, models.OrderStatusCompleted, true},
+		{models.OrderStatusPlaced, models.OrderStatusCancelled, true},
+		{models.OrderStatusAccepted, models.OrderStatusCancelled, true},
+		{models.OrderStatusPreparing, models.OrderStatusCancelled, false},
+		{models.OrderStatusPlaced, models.OrderStatusReady, false},
+		{models.OrderStatusReady, models.OrderStatusPreparing, false},
+		{models.OrderStatusPlaced, models.OrderStatusPlaced, false},
+		{models.OrderStatusCompleted, models.OrderStatusCancelled, false},
+		{models.OrderStatusCancelled, models.OrderStatusPlaced, false},
+		{"shipped", models.OrderStatusCompleted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, models.CanTransitionOrderStatus(tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"testing"
+)
+
+// refundableOrder is a completed order of 2 waffles and a coffee, charged 27 after a 10% coupon
+func refundableOrder() (*models.Order, []models.OrderItem) {
+	order := &models.Order{Id: "550e8400-e29b-41d4-a716-446655440000", Status: models.OrderStatusCompleted, Subtotal: money.MustParse("30"), Discount: money.MustParse("3"), Total: money.MustParse("27")}
+	items := []models.OrderItem{
+		{Id: 1, OrderId: order.Id, ProductId: 1, Quantity: 2, UnitPrice: money.MustParse("10"), Price: money.MustParse("20")},
+		{Id: 2, OrderId: order.Id, ProductId: 2, Quantity: 1, UnitPrice: money.MustParse("10"), Price: money.MustParse("10")},
+	}
+	return order, items
+}
+
+// TestPlanRefund_WholeOrder tests that refunding without quantities gives back the total charged, coupon included
+func TestPlanRefund_WholeOrder(t *testing.T) {
+	order, items := refundableOrder()
+
+	refund, err := models.PlanRefund(order, items, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("27.0"), refund.Amount)
+	assert.Equal(t, []models.RefundItem{
+		{OrderItemId: 1, ProductId: 1, Quantity: 2, Amount: money.MustParse("18")},
+		{OrderItemId: 2, ProductId: 2, Quantity: 1, Amount: money.MustParse("9")},
+	}, refund.Items)
+}
+
+// TestPlanRefund_Partial tests that partial refunds are prorated and the next refund without quantities only gives back
+// what is left
+func TestPlanRefund_Partial(t *testing.T) {
+	order, items := refundableOrder()
+
+	first, err := models.PlanRefund(order, items, nil, map[int64]int{1: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("9.0"), first.Amount)
+
+	rest, err := models.PlanRefund(order, items, []*models.Refund{first}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("18.0"), rest.Amount)
+	assert.Equal(t, []models.RefundItem{
+		{OrderItemId: 1, ProductId: 1, Quantity: 1, Amount: money.MustParse("9")},
+		{OrderItemId: 2, ProductId: 2, Quantity: 1, Amount: money.MustParse("9")},
+	}, rest.Items)
+}
+
+// TestPlanRefund_Rounding tests that the refund leaving nothing to refund absorbs the cents lost to rounding so that
+// the refunds add up to the total
+func TestPlanRefund_Rounding(t *testing.T) {
+	order := &models.Order{Id: "550e8400-e29b-41d4-a716-446655440000", Status: models.OrderStatusCompleted, Subtotal: money.MustParse("9.99"), Total: money.MustParse("6.67")}
+	items := []models.OrderItem{{Id: 1, OrderId: order.Id, ProductId: 1, Quantity: 3, UnitPrice: money.MustParse("3.33"), Price: money.MustParse("9.99")}}
+
+	var refunds []*models.Refund
+	for range 3 {
+		refund, err := models.PlanRefund(order, items, refunds, map[int64]int{1: 1})
+		assert.NoError(t, err)
+		refunds = append(refunds, refund)
+	}
+
+	assert.Equal(t, money.MustParse("2.22"), refunds[0].Amount)
+	assert.Equal(t, money.MustParse("2.22"), refunds[1].Amount)
+	assert.Equal(t, money.MustParse("2.23"), refunds[2].Amount)
+	refunded, err := models.RefundedAmount(refunds)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("6.67"), refunded)
+}
+
+// TestPlanRefund_Invalid tests that refunds of unknown items, of more than is left or of nothing are rejected
+func TestPlanRefund_Invalid(t *testing.T) {
+	order, items := refundableOrder()
+	previous := []*models.Refund{{Amount: money.MustParse("9"), Items: []models.RefundItem{{OrderItemId: 1, ProductId: 1, Quantity: 1, Amount: money.MustParse("9")}}}}
+
+	tests := []struct {
+		name       string
+		previous   []*models.Refund
+		quantities map[int64]int
+		message    string
+	}{
+		{"unknown item", nil, map[int64]int{3: 1}, "order item 3 is not part of the order"},
+		{"more than ordered", nil, map[int64]int{2: 2}, "only 1 of order item 2 left to refund"},
+		{"more than left", previous, map[int64]int{1: 2}, "only 1 of order item 1 left to refund"},
+		{"nothing requested", nil, map[int64]int{}, "nothing left to refund"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refund, err := models.PlanRefund(order, items, tt.previous, tt.quantities)
+
+			assert.Nil(t, refund)
+			assert.EqualError(t, err, tt.message)
+		})
+	}
+
+	rest, err := models.PlanRefund(order, items, nil, nil)
+	assert.NoError(t, err)
+	_, err = models.PlanRefund(order, items, []*models.Refund{rest}, nil)
+	assert.EqualError(t, err, "nothing left to refund")
+}
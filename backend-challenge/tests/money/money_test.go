@@ -0,0 +1,243 @@
+package money_test
+
+import (
+	"encoding/json"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/big"
+	"oolio.com/kart/money"
+	"testing"
+)
+
+// TestParse tests that decimal amounts are read exactly and printed back as the shortest decimal
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		cents int64
+		text  string
+	}{
+		"12.99":  {1299, "12.99"},
+		"15.50":  {1550, "15.5"},
+		"31":     {3100, "31"},
+		"0.1":    {10, "0.1"},
+		"-0.05":  {-5, "-0.05"},
+		" 7.00 ": {700, "7"},
+		"1e2":    {10000, "100"},
+	}
+
+	for value, expected := range cases {
+		amount, err := money.Parse(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected.cents, amount.Cents(), value)
+		assert.Equal(t, expected.text, amount.String(), value)
+	}
+}
+
+// TestParse_Invalid tests that malformed amounts and amounts with fractions of a cent are rejected
+func TestParse_Invalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "1/3", "0x10", "1_000", "12.999", "NaN", "99999999999999999999"} {
+		_, err := money.Parse(value)
+		assert.Error(t, err, value)
+	}
+}
+
+// TestParseRound tests that fractions of a cent are rounded with the requested mode
+func TestParseRound(t *testing.T) {
+	cases := []struct {
+		value string
+		mode  money.RoundingMode
+		cents int64
+	}{
+		{"3.499", money.HalfUp, 350},
+		{"0.125", money.HalfUp, 13},
+		{"-0.125", money.HalfUp, -13},
+		{"0.125", money.HalfEven, 12},
+		{"0.135", money.HalfEven, 14},
+		{"0.129", money.Down, 12},
+		{"-0.129", money.Down, -12},
+	}
+
+	for _, c := range cases {
+		amount, err := money.ParseRound(c.value, c.mode)
+		require.NoError(t, err, c.value)
+		assert.Equal(t, c.cents, amount.Cents(), c.value)
+	}
+}
+
+// TestArithmetic tests that sums, differences and products by a quantity are exact, where float64 drifts
+func TestArithmetic(t *testing.T) {
+	total, err := money.Sum(money.MustParse("0.1"), money.MustParse("0.2"))
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("0.3"), total)
+
+	product, err := money.MustParse("12.99").Mul(3)
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("38.97"), product)
+	sum, err := money.MustParse("1").Add(money.MustParse("2.5"))
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("3.5"), sum)
+
+	difference, err := money.MustParse("1").Sub(money.MustParse("2.5"))
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("-1.5"), difference)
+	assert.Equal(t, money.MustParse("-2"), money.MustParse("2").Neg())
+	assert.Equal(t, 1, money.MustParse("2").Cmp(money.MustParse("1.99")))
+	assert.Equal(t, 0, money.MustParse("2").Cmp(money.FromCents(200)))
+	assert.True(t, money.Money{}.IsZero())
+}
+
+// TestOverflow tests that products, sums and differences that do not fit in the range of Money fail rather than wrap around
+func TestOverflow(t *testing.T) {
+	_, err := money.FromCents(math.MaxInt64 / 2).Mul(3)
+	assert.Error(t, err)
+	_, err = money.FromCents(-2).Mul(math.MaxInt64)
+	assert.Error(t, err)
+
+	product, err := money.FromCents(-1).Mul(math.MaxInt64)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-math.MaxInt64), product.Cents())
+
+	_, err = money.Sum(money.FromCents(math.MaxInt64), money.FromCents(1))
+	assert.Error(t, err)
+	total, err := money.Sum(money.FromCents(math.MaxInt64), money.FromCents(1), money.FromCents(-2))
+	require.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64-1), total.Cents())
+
+	_, err = money.FromCents(math.MaxInt64).Add(money.FromCents(1))
+	assert.Error(t, err)
+	_, err = money.FromCents(math.MinInt64).Sub(money.FromCents(1))
+	assert.Error(t, err)
+	_, err = money.FromCents(0).Sub(money.FromCents(math.MinInt64))
+	assert.Error(t, err)
+	assert.Equal(t, -1, money.FromCents(math.MinInt64).Cmp(money.FromCents(math.MaxInt64)))
+	assert.Equal(t, 1, money.FromCents(math.MaxInt64).Cmp(money.FromCents(-1)))
+
+	assert.Panics(t, func() { money.FromCents(math.MaxInt64).Scale(2, 1, money.HalfUp) })
+}
+
+// TestScale tests that scaling rounds to the cent with the requested mode
+func TestScale(t *testing.T) {
+	amount := money.FromCents(100)
+
+	assert.Equal(t, int64(33), amount.Scale(1, 3, money.HalfUp).Cents())
+	assert.Equal(t, int64(67), amount.Scale(2, 3, money.HalfUp).Cents())
+	assert.Equal(t, int64(66), amount.Scale(2, 3, money.Down).Cents())
+	assert.Equal(t, int64(-67), amount.Neg().Scale(2, 3, money.HalfUp).Cents())
+	assert.Equal(t, int64(-67), amount.Scale(2, -3, money.HalfUp).Cents())
+
+	half := money.FromCents(5)
+	assert.Equal(t, int64(3), half.Scale(1, 2, money.HalfUp).Cents())
+	assert.Equal(t, int64(2), half.Scale(1, 2, money.HalfEven).Cents())
+	assert.Equal(t, int64(4), money.FromCents(7).Scale(1, 2, money.HalfEven).Cents())
+
+	assert.Panics(t, func() { amount.Scale(1, 0, money.HalfUp) })
+}
+
+// TestParseCurrency tests that store currencies are upper cased and that currencies not counted in cents are rejected
+func TestParseCurrency(t *testing.T) {
+	eur, err := money.ParseCurrency(" eur")
+	require.NoError(t, err)
+	assert.Equal(t, money.Currency("EUR"), eur)
+
+	for _, code := range []string{"", "US", "U5D", "USDT", "JPY", "KWD"} {
+		_, err = money.ParseCurrency(code)
+		assert.Error(t, err, code)
+	}
+}
+
+// TestJSON tests that amounts are encoded as JSON numbers and decoded from numbers or strings
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price money.Money `json:"price"`
+	}{money.MustParse("12.50")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":12.5}`, string(data))
+
+	var decoded struct {
+		Price *money.Money `json:"price"`
+		Total money.Money  `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":"0.30","total":39.87}`), &decoded))
+	assert.Equal(t, money.MustParse("0.3"), *decoded.Price)
+	assert.Equal(t, money.MustParse("39.87"), decoded.Total)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"total":0.001}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"total":true}`), &decoded))
+}
+
+// TestNumeric tests that amounts round trip through the binary and text formats of NUMERIC columns
+func TestNumeric(t *testing.T) {
+	typeMap := pgtype.NewMap()
+
+	for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+		for _, amount := range []money.Money{money.MustParse("12.99"), money.MustParse("-0.05"), money.MustParse("31"), {}} {
+			encoded, err := typeMap.Encode(pgtype.NumericOID, format, amount, nil)
+			require.NoError(t, err)
+
+			var scanned money.Money
+			require.NoError(t, typeMap.Scan(pgtype.NumericOID, format, encoded, &scanned))
+			assert.Equal(t, amount, scanned, amount.String())
+		}
+	}
+
+	var scanned money.Money
+	assert.NoError(t, typeMap.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte("7.500"), &scanned))
+	assert.Equal(t, money.MustParse("7.5"), scanned)
+	assert.Error(t, typeMap.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte("7.505"), &scanned))
+	assert.Error(t, typeMap.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte("NaN"), &scanned))
+	assert.Error(t, typeMap.Scan(pgtype.NumericOID, pgtype.TextFormatCode, nil, &scanned))
+
+	var nullable *money.Money
+	assert.NoError(t, typeMap.Scan(pgtype.NumericOID, pgtype.TextFormatCode, nil, &nullable))
+	assert.Nil(t, nullable)
+}
+
+// TestNumeric_Scan tests that NUMERIC values read from the database, whatever their scale, scan into the same amount
+// in the binary and text formats, and that values with fractions of a cent are refused rather than rounded
+func TestNumeric_Scan(t *testing.T) {
+	typeMap := pgtype.NewMap()
+
+	tests := []struct {
+		name    string
+		numeric pgtype.Numeric
+		want    string
+		written string
+	}{
+		{"scale of three", pgtype.Numeric{Int: big.NewInt(12990), Exp: -3, Valid: true}, "12.99", "12.99"},
+		{"scale of two", pgtype.Numeric{Int: big.NewInt(1299), Exp: -2, Valid: true}, "12.99", "12.99"},
+		{"positive exponent", pgtype.Numeric{Int: big.NewInt(1), Exp: 2, Valid: true}, "100", "100.00"},
+		{"negative", pgtype.Numeric{Int: big.NewInt(-5), Exp: -2, Valid: true}, "-0.05", "-0.05"},
+		{"largest price", pgtype.Numeric{Int: big.NewInt(9999999999), Exp: -2, Valid: true}, "99999999.99", "99999999.99"},
+		{"fraction of a cent", pgtype.Numeric{Int: big.NewInt(12345), Exp: -3, Valid: true}, "", ""},
+		{"tenth of a cent", pgtype.Numeric{Int: big.NewInt(1), Exp: -3, Valid: true}, "", ""},
+		{"out of range", pgtype.Numeric{Int: big.NewInt(1), Exp: 30, Valid: true}, "", ""},
+	}
+
+	for _, tt := range tests {
+		for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+			encoded, err := typeMap.Encode(pgtype.NumericOID, format, tt.numeric, nil)
+			require.NoError(t, err, tt.name)
+
+			var scanned money.Money
+			err = typeMap.Scan(pgtype.NumericOID, format, encoded, &scanned)
+			if tt.want == "" {
+				assert.Error(t, err, "%s: %s", tt.name, encoded)
+				continue
+			}
+			require.NoError(t, err, tt.name)
+			assert.Equal(t, money.MustParse(tt.want), scanned, "%s: %s", tt.name, encoded)
+
+			// The amount is written back at the scale of the columns
+			value, err := scanned.NumericValue()
+			require.NoError(t, err)
+			written, err := typeMap.Encode(pgtype.NumericOID, pgtype.TextFormatCode, value, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.written, string(written), tt.name)
+		}
+	}
+
+	var scanned money.Money
+	assert.NoError(t, typeMap.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte("12.990"), &scanned))
+	assert.Equal(t, money.MustParse("12.99"), scanned)
+}
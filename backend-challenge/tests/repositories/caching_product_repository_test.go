@@ -0,0 +1,255 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"oolio.com/kart/repositories"
+	"testing"
+	"time"
+)
+
+// TestCachingProductRepository_GetById_ServesFromMemory tests that a product is only read from the database once
+func TestCachingProductRepository_GetById_ServesFromMemory(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99")}, nil).Once()
+
+	first, err := cache.GetById(context.Background(), 1)
+	assert.Nil(t, err)
+	second, err := cache.GetById(context.Background(), 1)
+	assert.Nil(t, err)
+
+	assert.Equal(t, first, second)
+	stats := cache.Stats()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_GetById_ReturnsCopies tests that changing a returned product does not change the cache
+func TestCachingProductRepository_GetById_ReturnsCopies(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Name: "Margherita Pizza", Meta: map[string]any{"spicy": false}}, nil).Once()
+
+	product, _ := cache.GetById(context.Background(), 1)
+	product.Name = "Changed"
+	product.Meta["spicy"] = true
+
+	cached, _ := cache.GetById(context.Background(), 1)
+	assert.Equal(t, "Margherita Pizza", cached.Name)
+	assert.Equal(t, false, cached.Meta["spicy"])
+}
+
+// TestCachingProductRepository_Inactive tests that an inactive cache sends every read to the database
+func TestCachingProductRepository_Inactive(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil).Twice()
+
+	_, _ = cache.GetById(context.Background(), 1)
+	_, _ = cache.GetById(context.Background(), 1)
+
+	assert.False(t, cache.Stats().Active)
+	assert.Equal(t, int64(2), cache.Stats().Misses)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_Invalidate tests that entries are read again after an invalidation
+func TestCachingProductRepository_Invalidate(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Price: money.MustParse("12.99")}, nil).Once()
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Price: money.MustParse("13.49")}, nil).Once()
+
+	_, _ = cache.GetById(context.Background(), 1)
+	cache.Invalidate()
+	product, _ := cache.GetById(context.Background(), 1)
+
+	assert.Equal(t, money.MustParse("13.49"), product.Price)
+	assert.Equal(t, int64(1), cache.Stats().Invalidations)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_WriteInvalidates tests that writes made through the cache drop it right away
+func TestCachingProductRepository_WriteInvalidates(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	filter := &models.ProductFilter{Status: models.ProductStatusAvailable}
+	mockRepo.On("ListProducts", mock.Anything, filter).Return([]*models.Product{{Id: 1}}, int64(1), nil).Twice()
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	_, _, _ = cache.ListProducts(context.Background(), filter)
+	_, _, _ = cache.ListProducts(context.Background(), filter)
+	assert.Nil(t, cache.Update(context.Background(), &models.Product{Id: 1}))
+	products, totalCount, err := cache.ListProducts(context.Background(), filter)
+
+	assert.Nil(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, int64(1), totalCount)
+	mockRepo.AssertNumberOfCalls(t, "ListProducts", 2)
+}
+
+// TestCachingProductRepository_ListProducts_KeyedByFilter tests that listings with different filters are cached apart
+func TestCachingProductRepository_ListProducts_KeyedByFilter(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	pizza := &models.ProductFilter{Category: "Pizza"}
+	waffle := &models.ProductFilter{Category: "Waffle"}
+	mockRepo.On("ListProducts", mock.Anything, pizza).Return([]*models.Product{{Id: 1}}, int64(1), nil).Once()
+	mockRepo.On("ListProducts", mock.Anything, waffle).Return([]*models.Product{{Id: 2}, {Id: 3}}, int64(2), nil).Once()
+
+	products, _, _ := cache.ListProducts(context.Background(), pizza)
+	assert.Len(t, products, 1)
+	products, _, _ = cache.ListProducts(context.Background(), waffle)
+	assert.Len(t, products, 2)
+	products, _, _ = cache.ListProducts(context.Background(), &models.ProductFilter{Category: "Pizza"})
+	assert.Len(t, products, 1)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_ListProducts_EvictsLeastRecentlyUsed tests that a full cache only drops the listing used
+// the longest time ago to make room for a new one
+func TestCachingProductRepository_ListProducts_EvictsLeastRecentlyUsed(t *testing.T) {
+	// The number of listings the cache holds, maxCachedProductLists
+	const maxLists = 1000
+
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	mockRepo.On("ListProducts", mock.Anything, mock.Anything).Return([]*models.Product{{Id: 1}}, int64(1), nil)
+	search := func(i int) {
+		_, _, err := cache.ListProducts(context.Background(), &models.ProductFilter{Query: fmt.Sprintf("dish %d", i)})
+		assert.Nil(t, err)
+	}
+
+	for i := range maxLists {
+		search(i)
+	}
+	search(0)
+	search(maxLists)
+	mockRepo.AssertNumberOfCalls(t, "ListProducts", maxLists+1)
+	assert.Equal(t, maxLists, cache.Stats().Entries)
+
+	// The first search was used again after the second one, which made room for the last one
+	search(0)
+	search(2)
+	search(maxLists)
+	mockRepo.AssertNumberOfCalls(t, "ListProducts", maxLists+1)
+	search(1)
+	mockRepo.AssertNumberOfCalls(t, "ListProducts", maxLists+2)
+}
+
+// TestCachingProductRepository_ListProducts_SkipsAvailableAt tests that listings of the products available at a time
+// are always read from the database, their key would change every minute
+func TestCachingProductRepository_ListProducts_SkipsAvailableAt(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	availableAt := time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC)
+	filter := &models.ProductFilter{AvailableAt: &availableAt}
+	mockRepo.On("ListProducts", mock.Anything, filter).Return([]*models.Product{{Id: 1}}, int64(1), nil).Twice()
+
+	_, _, _ = cache.ListProducts(context.Background(), filter)
+	products, _, err := cache.ListProducts(context.Background(), filter)
+
+	assert.Nil(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, 0, cache.Stats().Entries)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_GetByIds_FetchesMissing tests that only the products missing from memory are read
+func TestCachingProductRepository_GetByIds_FetchesMissing(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil).Once()
+	mockRepo.On("GetByIds", mock.Anything, []int64{2}).Return([]*models.Product{{Id: 2}}, nil).Once()
+
+	_, _ = cache.GetById(context.Background(), 1)
+	products, err := cache.GetByIds(context.Background(), []int64{1, 2})
+	assert.Nil(t, err)
+	assert.Len(t, products, 2)
+
+	products, err = cache.GetByIds(context.Background(), []int64{2, 1})
+	assert.Nil(t, err)
+	assert.Len(t, products, 2)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_ErrorsAreNotCached tests that a failed read is tried again
+func TestCachingProductRepository_ErrorsAreNotCached(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	notFound := &errors.ErrorDetails{ErrorCode: http.StatusNotFound, Message: "product not found"}
+	mockRepo.On("GetById", mock.Anything, int64(9)).Return(nil, notFound).Twice()
+
+	_, err := cache.GetById(context.Background(), 9)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+	_, err = cache.GetById(context.Background(), 9)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_InvalidatedDuringRead tests that a product read while the cache is invalidated is not
+// stored, it may predate the change that caused the invalidation
+func TestCachingProductRepository_InvalidatedDuringRead(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Price: money.MustParse("12.99")}, nil).Once().
+		Run(func(args mock.Arguments) { cache.Invalidate() })
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Price: money.MustParse("13.49")}, nil).Once()
+
+	_, _ = cache.GetById(context.Background(), 1)
+	product, _ := cache.GetById(context.Background(), 1)
+
+	assert.Equal(t, money.MustParse("13.49"), product.Price)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCachingProductRepository_GetById_SkipsDeleted tests that a deleted product cached for an order is not served by GetById
+func TestCachingProductRepository_GetById_SkipsDeleted(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	cache := repositories.NewCachingProductRepository(mockRepo)
+	cache.Activate()
+
+	deletedAt := time.Now()
+	mockRepo.On("GetByIds", mock.Anything, []int64{1}).Return([]*models.Product{{Id: 1, DeletedAt: &deletedAt}}, nil).Once()
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(nil, &errors.ErrorDetails{ErrorCode: http.StatusNotFound}).Once()
+
+	products, _ := cache.GetByIds(context.Background(), []int64{1})
+	assert.Len(t, products, 1)
+
+	product, err := cache.GetById(context.Background(), 1)
+	assert.Nil(t, product)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+	mockRepo.AssertExpectations(t)
+}
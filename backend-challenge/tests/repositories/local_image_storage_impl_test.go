@@ -0,0 +1,50 @@
+package repositories_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oolio.com/kart/repositories"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLocalImageStorage_SaveAndDelete tests that a stored image is written under the directory and served from the base URL
+func TestLocalImageStorage_SaveAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	storage := repositories.NewLocalImageStorageImpl(dir, "/images/")
+
+	url, err := storage.Save(context.Background(), "products/1/abc-mobile.jpg", "image/jpeg", []byte("content"))
+	require.Nil(t, err)
+	assert.Equal(t, "/images/products/1/abc-mobile.jpg", url)
+
+	content, readErr := os.ReadFile(filepath.Join(dir, "products", "1", "abc-mobile.jpg"))
+	require.NoError(t, readErr)
+	assert.Equal(t, "content", string(content))
+
+	key, ok := storage.KeyOf(url)
+	assert.True(t, ok)
+	assert.Equal(t, "products/1/abc-mobile.jpg", key)
+
+	assert.Nil(t, storage.Delete(context.Background(), key))
+	_, statErr := os.Stat(filepath.Join(dir, "products", "1", "abc-mobile.jpg"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	// Deleting twice is not an error
+	assert.Nil(t, storage.Delete(context.Background(), key))
+}
+
+// TestLocalImageStorage_KeysStayInDirectory tests that keys cannot point outside the storage directory
+func TestLocalImageStorage_KeysStayInDirectory(t *testing.T) {
+	storage := repositories.NewLocalImageStorageImpl(t.TempDir(), "/images")
+
+	_, err := storage.Save(context.Background(), "../escape.jpg", "image/jpeg", []byte("content"))
+	assert.NotNil(t, err)
+
+	_, ok := storage.KeyOf("/images/products/../../escape.jpg")
+	assert.False(t, ok)
+
+	_, ok = storage.KeyOf("https://orderfoodonline.deno.dev/public/images/image-waffle-mobile.jpg")
+	assert.False(t, ok)
+}
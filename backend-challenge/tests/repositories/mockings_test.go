@@ -0,0 +1,110 @@
+package repositories_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"time"
+)
+
+// MockProductRepository is a mock implementation of ProductRepository
+type MockProductRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRepository) ListProducts(ctx context.Context, filter *models.ProductFilter) ([]*models.Product, int64, *errors.ErrorDetails) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Get(2).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Product), args.Get(1).(int64), nil
+}
+
+func (m *MockProductRepository) GetById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Product), nil
+}
+
+func (m *MockProductRepository) Save(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) UpdateStatus(ctx context.Context, product *models.Product, expectedStatus string) *errors.ErrorDetails {
+	args := m.Called(ctx, product, expectedStatus)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) UpdateImage(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id int64) *errors.ErrorDetails {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, id int64) *errors.ErrorDetails {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) GetByIds(ctx context.Context, ids []int64) ([]*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Product), nil
+}
+
+func (m *MockProductRepository) GetLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(1) != nil {
+		return time.Time{}, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(time.Time), nil
+}
+
+func (m *MockProductRepository) GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails) {
+	args := m.Called(ctx)
+	if args.Get(1) != nil {
+		return time.Time{}, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(time.Time), nil
+}
+
+func (m *MockProductRepository) GetPriceHistory(ctx context.Context, productId int64) ([]*models.ProductPrice, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.ProductPrice), nil
+}
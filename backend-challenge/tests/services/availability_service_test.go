@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/models"
+	"oolio.com/kart/services"
+	"testing"
+)
+
+// TestAvailabilityService_GetProductAvailability_Inherited tests that the windows of a category are reported as inherited
+func TestAvailabilityService_GetProductAvailability_Inherited(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockAvailabilityRepo := new(MockAvailabilityRepository)
+	service := services.NewAvailabilityServiceImpl(mockProductRepo, mockCategoryRepo, mockAvailabilityRepo)
+
+	categoryId := int64(3)
+	windows := map[int64][]*models.AvailabilityWindow{
+		1: {{Id: 7, CategoryId: &categoryId, DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, StartMinute: 0, EndMinute: models.MinutesPerDay}},
+	}
+
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil)
+	mockAvailabilityRepo.On("GetWindowsByProductIds", mock.Anything, []int64{1}).Return(windows, nil)
+
+	availability, err := service.GetProductAvailability(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.True(t, availability.Inherited)
+	assert.True(t, availability.AvailableNow)
+	assert.Len(t, availability.Windows, 1)
+}
+
+// TestAvailabilityService_GetProductAvailability_NoWindows tests that a product without windows is always available
+func TestAvailabilityService_GetProductAvailability_NoWindows(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockAvailabilityRepo := new(MockAvailabilityRepository)
+	service := services.NewAvailabilityServiceImpl(mockProductRepo, mockCategoryRepo, mockAvailabilityRepo)
+
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil)
+	mockAvailabilityRepo.On("GetWindowsByProductIds", mock.Anything, []int64{1}).Return(map[int64][]*models.AvailabilityWindow{}, nil)
+
+	availability, err := service.GetProductAvailability(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.False(t, availability.Inherited)
+	assert.True(t, availability.AvailableNow)
+	assert.Empty(t, availability.Windows)
+}
+
+// TestAvailabilityService_SetProductAvailability tests that the windows of the request are parsed, days sorted and deduplicated
+func TestAvailabilityService_SetProductAvailability(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockAvailabilityRepo := new(MockAvailabilityRepository)
+	service := services.NewAvailabilityServiceImpl(mockProductRepo, mockCategoryRepo, mockAvailabilityRepo)
+
+	request := &requests.AvailabilityRequest{
+		Windows: []requests.AvailabilityWindowRequest{
+			{DaysOfWeek: []int{5, 1, 5}, Start: "22:00", End: "02:00"},
+		},
+	}
+	expected := []*models.AvailabilityWindow{
+		{DaysOfWeek: []int{1, 5}, StartMinute: 22 * 60, EndMinute: 2 * 60},
+	}
+	productId := int64(1)
+	stored := map[int64][]*models.AvailabilityWindow{
+		1: {{Id: 1, ProductId: &productId, DaysOfWeek: []int{1, 5}, StartMinute: 22 * 60, EndMinute: 2 * 60}},
+	}
+
+	mockAvailabilityRepo.On("ReplaceProductWindows", mock.Anything, int64(1), expected).Return(nil)
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil)
+	mockAvailabilityRepo.On("GetWindowsByProductIds", mock.Anything, []int64{1}).Return(stored, nil)
+
+	availability, err := service.SetProductAvailability(context.Background(), 1, request)
+
+	assert.Nil(t, err)
+	assert.False(t, availability.Inherited)
+	assert.Len(t, availability.Windows, 1)
+	mockAvailabilityRepo.AssertExpectations(t)
+}
+
+// TestAvailabilityService_SetProductAvailability_InvalidWindow tests that invalid times are rejected before any write
+func TestAvailabilityService_SetProductAvailability_InvalidWindow(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		end   string
+	}{
+		{"malformed", "7:00a", "11:00"},
+		{"out of range", "07:00", "25:00"},
+		{"starts at the end of the day", "24:00", "02:00"},
+		{"empty", "07:00", "07:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAvailabilityRepo := new(MockAvailabilityRepository)
+			service := services.NewAvailabilityServiceImpl(new(MockProductRepository), new(MockCategoryRepository), mockAvailabilityRepo)
+
+			request := &requests.AvailabilityRequest{
+				Windows: []requests.AvailabilityWindowRequest{{DaysOfWeek: []int{1}, Start: tt.start, End: tt.end}},
+			}
+
+			availability, err := service.SetProductAvailability(context.Background(), 1, request)
+
+			assert.Nil(t, availability)
+			assert.NotNil(t, err)
+			assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+			mockAvailabilityRepo.AssertNotCalled(t, "ReplaceProductWindows", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestAvailabilityService_GetCategoryAvailability_NotFound tests that the windows of a missing category are not read
+func TestAvailabilityService_GetCategoryAvailability_NotFound(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockAvailabilityRepo := new(MockAvailabilityRepository)
+	service := services.NewAvailabilityServiceImpl(new(MockProductRepository), mockCategoryRepo, mockAvailabilityRepo)
+
+	mockCategoryRepo.On("GetById", mock.Anything, int64(9)).Return(nil, exceptions.GenericException("category not found", http.StatusNotFound))
+
+	availability, err := service.GetCategoryAvailability(context.Background(), 9)
+
+	assert.Nil(t, availability)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+	mockAvailabilityRepo.AssertNotCalled(t, "GetCategoryWindows", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,186 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"oolio.com/kart/services"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+)
+
+const importCatalog = `[
+  {"name": "Waffle with Berries", "category": "Waffle", "price": 6.5, "image": {"thumbnail": "waffle.jpg"}},
+  {"name": "Lemonade", "category": "Drinks", "price": 4.5},
+  {"name": "Margherita Pizza", "category": "Pizza", "price": 12.99, "status": "sold_out"},
+  {"name": "", "category": "Pizza", "price": 10},
+  {"name": "Lemonade", "category": "Drinks", "price": 5}
+]`
+
+// TestCatalogService_ImportProducts_DryRun tests that a dry run reports the diff without writing anything
+func TestCatalogService_ImportProducts_DryRun(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := services.NewCatalogServiceImpl(mockRepo)
+
+	existing := []*models.Product{
+		{Id: 1, Name: "Waffle with Berries", Category: "Waffle", Price: money.MustParse("6.5"), Status: "available", Image: models.Image{Thumbnail: "waffle.jpg"}},
+		{Id: 2, Name: "Margherita Pizza", Category: "Pizza", Price: money.MustParse("12.99"), Status: "available"},
+	}
+	mockRepo.On("GetByKeys", mock.Anything, []models.ProductKey{
+		{Name: "Waffle with Berries", Category: "Waffle"},
+		{Name: "Lemonade", Category: "Drinks"},
+		{Name: "Margherita Pizza", Category: "Pizza"},
+	}).Return(existing, nil)
+
+	result, err := service.ImportProducts(context.Background(), strings.NewReader(importCatalog), "json", true)
+
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+
+	assert.Len(t, result.Changes, 3)
+	assert.Equal(t, models.ImportActionUnchanged, result.Changes[0].Action)
+	assert.Equal(t, models.ImportActionCreate, result.Changes[1].Action)
+	assert.Equal(t, "available", result.Changes[1].Product.Status)
+	assert.Equal(t, models.ImportActionUpdate, result.Changes[2].Action)
+	assert.Equal(t, []string{"status"}, result.Changes[2].Fields)
+	assert.Equal(t, int64(2), result.Changes[2].Product.Id)
+
+	assert.Len(t, result.Errors, 2)
+	assert.Equal(t, 4, result.Errors[0].Row)
+	assert.Equal(t, "product name is required", result.Errors[0].Message)
+	assert.Equal(t, 5, result.Errors[1].Row)
+	assert.Equal(t, "duplicate product, already defined in row 2", result.Errors[1].Message)
+
+	mockRepo.AssertNotCalled(t, "UpsertProducts", mock.Anything, mock.Anything)
+}
+
+// TestCatalogService_ImportProducts_InvalidRows tests that nothing is imported while the catalog has invalid rows
+func TestCatalogService_ImportProducts_InvalidRows(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := services.NewCatalogServiceImpl(mockRepo)
+
+	mockRepo.On("GetByKeys", mock.Anything, mock.Anything).Return([]*models.Product{}, nil)
+
+	result, err := service.ImportProducts(context.Background(), strings.NewReader(importCatalog), "json", false)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "UpsertProducts", mock.Anything, mock.Anything)
+}
+
+// TestCatalogService_ImportProducts_DietaryAttributes tests that imported diets are normalized and checked against the allergens
+func TestCatalogService_ImportProducts_DietaryAttributes(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := services.NewCatalogServiceImpl(mockRepo)
+
+	catalog := `[
+  {"name": "Falafel Wrap", "category": "Wraps", "price": 9, "allergens": ["sesame", "gluten"], "diets": ["vegan"]},
+  {"name": "Cheese Wrap", "category": "Wraps", "price": 8, "allergens": ["dairy"], "diets": ["vegan"]},
+  {"name": "Mystery Wrap", "category": "Wraps", "price": 8, "allergens": ["shellfish"]}
+]`
+
+	existing := []*models.Product{
+		{Id: 1, Name: "Falafel Wrap", Category: "Wraps", Price: money.MustParse("9"), Status: "available", Allergens: []string{"gluten", "sesame"}},
+	}
+	mockRepo.On("GetByKeys", mock.Anything, mock.Anything).Return(existing, nil)
+
+	result, err := service.ImportProducts(context.Background(), strings.NewReader(catalog), "json", true)
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Changes, 1)
+	assert.Equal(t, []string{"diets"}, result.Changes[0].Fields)
+	assert.Equal(t, []string{"gluten", "sesame"}, result.Changes[0].Product.Allergens)
+	assert.Equal(t, []string{"dairy_free", "vegan", "vegetarian"}, result.Changes[0].Product.Diets)
+
+	assert.Len(t, result.Errors, 2)
+	assert.Equal(t, "invalid product dietary attributes, a vegan product cannot contain dairy", result.Errors[0].Message)
+	assert.Equal(t, `invalid product dietary attributes, unknown allergen "shellfish"`, result.Errors[1].Message)
+}
+
+// TestCatalogService_ImportProducts_Commit tests that only created and updated products are written
+func TestCatalogService_ImportProducts_Commit(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := services.NewCatalogServiceImpl(mockRepo)
+
+	existing := []*models.Product{
+		{Id: 1, Name: "Lemonade", Category: "Drinks", Price: money.MustParse("4.5"), Status: "available"},
+		{Id: 2, Name: "Iced Tea", Category: "Drinks", Price: money.MustParse("3.0"), Status: "hidden"},
+	}
+	mockRepo.On("GetByKeys", mock.Anything, mock.Anything).Return(existing, nil)
+	mockRepo.On("UpsertProducts", mock.Anything, mock.MatchedBy(func(products []*models.Product) bool {
+		return len(products) == 2 &&
+			products[0].Name == "Iced Tea" && products[0].Price == money.MustParse("3.5") && products[0].Status == "hidden" &&
+			products[1].Name == "Cola" && products[1].Id == 0
+	})).Return(nil)
+
+	csvCatalog := "name,category,price\nLemonade,Drinks,4.5\nIced Tea,Drinks,3.499\nCola,Drinks,2\n"
+	result, err := service.ImportProducts(context.Background(), strings.NewReader(csvCatalog), "csv", false)
+
+	assert.Nil(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCatalogService_ImportProducts_DiscontinuedIsFinal tests that an import obeys the status lifecycle
+func TestCatalogService_ImportProducts_DiscontinuedIsFinal(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := services.NewCatalogServiceImpl(mockRepo)
+
+	existing := []*models.Product{{Id: 1, Name: "Lemonade", Category: "Drinks", Price: money.MustParse("4.5"), Status: "discontinued"}}
+	mockRepo.On("GetByKeys", mock.Anything, mock.Anything).Return(existing, nil)
+
+	catalog := `[{"name": "Lemonade", "category": "Drinks", "price": 4.5, "status": "available"}]`
+	result, err := service.ImportProducts(context.Background(), strings.NewReader(catalog), "json", true)
+
+	assert.Nil(t, err)
+	assert.Empty(t, result.Changes)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, "cannot change product status from discontinued to available", result.Errors[0].Message)
+}
+
+// TestCatalogService_ImportProducts_MalformedCatalog tests that a catalog that cannot be decoded is rejected
+func TestCatalogService_ImportProducts_MalformedCatalog(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := services.NewCatalogServiceImpl(mockRepo)
+
+	result, err := service.ImportProducts(context.Background(), strings.NewReader(`{"name": "Lemonade"`), "json", true)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+}
+
+// TestCatalogService_ExportProducts_CSV tests that every exported product is written as a catalog row
+func TestCatalogService_ExportProducts_CSV(t *testing.T) {
+	mockRepo := new(MockCatalogRepository)
+	service := services.NewCatalogServiceImpl(mockRepo)
+
+	mockRepo.On("ExportProducts", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(product *models.Product) error)
+		_ = fn(&models.Product{Id: 1, Name: "Lemonade", Category: "Drinks", Price: money.MustParse("4.5"), Status: "available"})
+		_ = fn(&models.Product{Id: 2, Name: "Iced Tea", Category: "Drinks", Price: money.MustParse("3"), Status: "hidden", Allergens: []string{"dairy", "gluten"}, Diets: []string{"halal"}})
+	}).Return(nil)
+
+	var buffer bytes.Buffer
+	err := service.ExportProducts(context.Background(), &buffer, "csv")
+
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "id,name,category,price,status"))
+	assert.Equal(t, `2,Iced Tea,Drinks,3,hidden,,,,,,"dairy,gluten",halal,`, lines[2])
+}
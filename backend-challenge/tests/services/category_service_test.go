@@ -0,0 +1,159 @@
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/services"
+	"testing"
+)
+
+func categoryFixtures() []*models.Category {
+	pizza, drinks, vegetarian := int64(1), int64(2), int64(3)
+	return []*models.Category{
+		{Id: pizza, Name: "Pizza", Path: "Pizza", Active: true, ProductCount: 2},
+		{Id: drinks, Name: "Drinks", Path: "Drinks", SortOrder: 1, Active: true, ProductCount: 4},
+		{Id: vegetarian, ParentId: &pizza, Name: "Vegetarian", Path: "Pizza > Vegetarian", Active: true, ProductCount: 3},
+		{Id: 4, ParentId: &vegetarian, Name: "Seasonal", Path: "Pizza > Vegetarian > Seasonal", Active: false, ProductCount: 1},
+		{Id: 5, ParentId: &drinks, Name: "Hot", Path: "Drinks > Hot", Active: false, ProductCount: 5},
+	}
+}
+
+// TestCategoryService_GetCategoryTree_Success tests that the tree totals the product counts of the active subcategories
+func TestCategoryService_GetCategoryTree_Success(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	mockRepo.On("ListCategories", mock.Anything).Return(categoryFixtures(), nil)
+
+	tree, err := service.GetCategoryTree(context.Background(), false)
+
+	assert.Nil(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Pizza", tree[0].Name)
+	assert.Equal(t, int64(5), tree[0].ProductCount)
+	assert.Len(t, tree[0].Children, 1)
+	assert.Empty(t, tree[0].Children[0].Children)
+	assert.Equal(t, "Drinks", tree[1].Name)
+	assert.Equal(t, int64(4), tree[1].ProductCount)
+	assert.Empty(t, tree[1].Children)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCategoryService_GetCategoryTree_IncludeInactive tests that inactive categories are kept when asked for
+func TestCategoryService_GetCategoryTree_IncludeInactive(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	mockRepo.On("ListCategories", mock.Anything).Return(categoryFixtures(), nil)
+
+	tree, err := service.GetCategoryTree(context.Background(), true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), tree[0].ProductCount)
+	assert.Equal(t, "Seasonal", tree[0].Children[0].Children[0].Name)
+	assert.Equal(t, int64(9), tree[1].ProductCount)
+}
+
+// TestCategoryService_GetCategory_NotFound tests that a missing category returns 404
+func TestCategoryService_GetCategory_NotFound(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	mockRepo.On("ListCategories", mock.Anything).Return(categoryFixtures(), nil)
+
+	category, err := service.GetCategory(context.Background(), 99)
+
+	assert.Nil(t, category)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+}
+
+// TestCategoryService_CreateCategory_Success tests that a subcategory is saved under its parent and active by default
+func TestCategoryService_CreateCategory_Success(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(category *models.Category) bool {
+		return category.Name == "Vegetarian" && category.ParentId != nil && *category.ParentId == 1 && category.Active
+	})).Run(func(args mock.Arguments) {
+		category := args.Get(1).(*models.Category)
+		category.Id = 3
+		category.Path = "Pizza > Vegetarian"
+	}).Return(nil)
+
+	category, err := service.CreateCategory(context.Background(), &requests.CategoryRequest{Name: " Vegetarian ", ParentId: "1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), category.Id)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCategoryService_CreateCategory_InvalidName tests that the path separator cannot be used in a category name
+func TestCategoryService_CreateCategory_InvalidName(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	category, err := service.CreateCategory(context.Background(), &requests.CategoryRequest{Name: "Pizza > Vegetarian"})
+
+	assert.Nil(t, category)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+// TestCategoryService_CreateCategory_InvalidParentId tests that the parent ID must be a positive number
+func TestCategoryService_CreateCategory_InvalidParentId(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	category, err := service.CreateCategory(context.Background(), &requests.CategoryRequest{Name: "Vegetarian", ParentId: "pizza"})
+
+	assert.Nil(t, category)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+	assert.Equal(t, "invalid category id", err.Message)
+}
+
+// TestCategoryService_UpdateCategory_Success tests that the updated category is returned with its subcategories
+func TestCategoryService_UpdateCategory_Success(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	active := false
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(category *models.Category) bool {
+		return category.Id == 3 && category.Name == "Veggie" && !category.Active
+	})).Return(nil)
+	mockRepo.On("ListCategories", mock.Anything).Return(categoryFixtures(), nil)
+
+	category, err := service.UpdateCategory(context.Background(), 3, &requests.CategoryRequest{Name: "Veggie", ParentId: "1", Active: &active})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), category.Id)
+	assert.Len(t, category.Children, 1)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCategoryService_UpdateCategory_Cycle tests that the repository error for a move into a subcategory is returned
+func TestCategoryService_UpdateCategory_Cycle(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, new(MockTranslationRepository))
+
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusUnprocessableEntity,
+		Message:   "a category cannot be moved into one of its subcategories",
+	}
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Category")).Return(mockError)
+
+	category, err := service.UpdateCategory(context.Background(), 1, &requests.CategoryRequest{Name: "Pizza", ParentId: "3"})
+
+	assert.Nil(t, category)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "ListCategories", mock.Anything)
+}
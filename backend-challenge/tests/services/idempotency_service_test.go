@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/services"
+	"testing"
+	"time"
+)
+
+var idempotencyConfig = configs.IdempotencyConfiguration{TTL: 24 * time.Hour, InFlightTimeout: time.Minute, PurgeInterval: time.Hour}
+
+// TestIdempotencyService_Begin tests that a claimed key is processed, a completed one replayed, and that keys in flight
+// or used with another request are rejected
+func TestIdempotencyService_Begin(t *testing.T) {
+	completed := &models.IdempotencyRecord{Key: "kiosk-7-0042", Fingerprint: "abc", StatusCode: http.StatusOK, Body: []byte(`{"id":"1"}`)}
+
+	tests := []struct {
+		name     string
+		existing *models.IdempotencyRecord
+		replay   bool
+		code     int
+		message  string
+	}{
+		{"claimed", nil, false, 0, ""},
+		{"completed", completed, true, 0, ""},
+		{"in flight", &models.IdempotencyRecord{Key: "kiosk-7-0042", Fingerprint: "abc"}, false,
+			http.StatusConflict, "a request with this idempotency key is being processed, retry later"},
+		{"another request", &models.IdempotencyRecord{Key: "kiosk-7-0042", Fingerprint: "def", StatusCode: http.StatusOK}, false,
+			http.StatusUnprocessableEntity, "idempotency key was already used with a different request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockIdempotencyRepository)
+			service := services.NewIdempotencyServiceImpl(mockRepo, idempotencyConfig)
+			mockRepo.On("ClaimKey", mock.Anything, "kiosk-7-0042", "abc", 24*time.Hour, time.Minute).Return(tt.existing, nil)
+
+			record, err := service.Begin(context.Background(), "kiosk-7-0042", "abc")
+
+			if tt.code != 0 {
+				assert.Nil(t, record)
+				assert.Equal(t, tt.code, err.ErrorCode)
+				assert.Equal(t, tt.message, err.Message)
+				return
+			}
+			assert.Nil(t, err)
+			if tt.replay {
+				assert.Equal(t, completed, record)
+			} else {
+				assert.Nil(t, record)
+			}
+		})
+	}
+}
+
+// TestIdempotencyService_Complete tests that responses are stored, except server errors which release the key so that
+// retries are processed again
+func TestIdempotencyService_Complete(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepository)
+	service := services.NewIdempotencyServiceImpl(mockRepo, idempotencyConfig)
+
+	body := []byte(`{"id":"1"}`)
+	mockRepo.On("SaveResponse", mock.Anything, "kiosk-7-0042", "abc", http.StatusOK, body).Return(nil)
+	mockRepo.On("SaveResponse", mock.Anything, "kiosk-7-0043", "abc", http.StatusUnprocessableEntity, body).Return(nil)
+	mockRepo.On("ReleaseKey", mock.Anything, "kiosk-7-0044", "abc").Return(nil)
+
+	assert.Nil(t, service.Complete(context.Background(), "kiosk-7-0042", "abc", http.StatusOK, body))
+	assert.Nil(t, service.Complete(context.Background(), "kiosk-7-0043", "abc", http.StatusUnprocessableEntity, body))
+	assert.Nil(t, service.Complete(context.Background(), "kiosk-7-0044", "abc", http.StatusInternalServerError, body))
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestIdempotencyService_RunPurgeJob tests that the job deletes the expired keys right away and stops with its context
+func TestIdempotencyService_RunPurgeJob(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepository)
+	service := services.NewIdempotencyServiceImpl(mockRepo, idempotencyConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockRepo.On("DeleteExpiredKeys", mock.Anything).Run(func(mock.Arguments) { cancel() }).
+		Return(int64(0), &errors.ErrorDetails{ErrorCode: http.StatusInternalServerError, Message: "failed to delete expired idempotency keys"})
+
+	service.RunPurgeJob(ctx)
+
+	mockRepo.AssertNumberOfCalls(t, "DeleteExpiredKeys", 1)
+}
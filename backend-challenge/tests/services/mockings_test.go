@@ -5,6 +5,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"time"
 )
 
 // MockProductRepository is a mock implementation of ProductRepository
@@ -12,12 +13,12 @@ type MockProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockProductRepository) ListProducts(ctx context.Context, offset, limit *int) []*models.Product {
-	args := m.Called(ctx, offset, limit)
+func (m *MockProductRepository) ListProducts(ctx context.Context, filter *models.ProductFilter) ([]*models.Product, int64, *errors.ErrorDetails) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
-		return nil
+		return nil, 0, args.Get(2).(*errors.ErrorDetails)
 	}
-	return args.Get(0).([]*models.Product)
+	return args.Get(0).([]*models.Product), args.Get(1).(int64), nil
 }
 
 func (m *MockProductRepository) GetById(ctx context.Context, id int64) (*models.Product, *errors.ErrorDetails) {
@@ -44,6 +45,38 @@ func (m *MockProductRepository) Update(ctx context.Context, product *models.Prod
 	return args.Get(0).(*errors.ErrorDetails)
 }
 
+func (m *MockProductRepository) UpdateStatus(ctx context.Context, product *models.Product, expectedStatus string) *errors.ErrorDetails {
+	args := m.Called(ctx, product, expectedStatus)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) UpdateImage(ctx context.Context, product *models.Product) *errors.ErrorDetails {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id int64) *errors.ErrorDetails {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, id int64) *errors.ErrorDetails {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
 func (m *MockProductRepository) GetByIds(ctx context.Context, ids []int64) ([]*models.Product, *errors.ErrorDetails) {
 	args := m.Called(ctx, ids)
 	if args.Get(0) == nil {
@@ -52,6 +85,30 @@ func (m *MockProductRepository) GetByIds(ctx context.Context, ids []int64) ([]*m
 	return args.Get(0).([]*models.Product), nil
 }
 
+func (m *MockProductRepository) GetLastModified(ctx context.Context, id int64) (time.Time, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(1) != nil {
+		return time.Time{}, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(time.Time), nil
+}
+
+func (m *MockProductRepository) GetCatalogLastModified(ctx context.Context) (time.Time, *errors.ErrorDetails) {
+	args := m.Called(ctx)
+	if args.Get(1) != nil {
+		return time.Time{}, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(time.Time), nil
+}
+
+func (m *MockProductRepository) GetPriceHistory(ctx context.Context, productId int64) ([]*models.ProductPrice, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.ProductPrice), nil
+}
+
 // MockOrderRepository is a mock implementation of OrderRepository
 type MockOrderRepository struct {
 	mock.Mock
@@ -65,6 +122,38 @@ func (m *MockOrderRepository) CreateOrder(ctx context.Context, order *models.Ord
 	return args.Get(0).(*errors.ErrorDetails)
 }
 
+func (m *MockOrderRepository) GetOrderById(ctx context.Context, id string) (*models.Order, []models.OrderItem, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Get(2).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Order), args.Get(1).([]models.OrderItem), nil
+}
+
+func (m *MockOrderRepository) TransitionOrderStatus(ctx context.Context, order *models.Order, change *models.OrderStatusChange) *errors.ErrorDetails {
+	args := m.Called(ctx, order, change)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockOrderRepository) GetStatusHistory(ctx context.Context, orderId string) ([]*models.OrderStatusChange, *errors.ErrorDetails) {
+	args := m.Called(ctx, orderId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.OrderStatusChange), nil
+}
+
+func (m *MockOrderRepository) ListOrders(ctx context.Context, filter *models.OrderFilter) ([]*models.Order, int64, *errors.ErrorDetails) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Get(2).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Order), args.Get(1).(int64), nil
+}
+
 // MockCouponRepository is a mock implementation of CouponRepository
 type MockCouponRepository struct {
 	mock.Mock
@@ -87,3 +176,393 @@ func (m *MockCouponRepository) GetCouponFileCount(ctx context.Context, code stri
 	args := m.Called(ctx, code)
 	return args.Int(0), args.Bool(1), nil
 }
+
+// MockStockRepository is a mock implementation of StockRepository
+type MockStockRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockRepository) AdjustStock(ctx context.Context, adjustment *models.StockAdjustment) *errors.ErrorDetails {
+	args := m.Called(ctx, adjustment)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockStockRepository) GetAdjustments(ctx context.Context, productId int64, limit int) ([]*models.StockAdjustment, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.StockAdjustment), nil
+}
+
+// MockModifierRepository is a mock implementation of ModifierRepository
+type MockModifierRepository struct {
+	mock.Mock
+}
+
+func (m *MockModifierRepository) SaveGroup(ctx context.Context, group *models.ModifierGroup) *errors.ErrorDetails {
+	args := m.Called(ctx, group)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockModifierRepository) UpdateGroup(ctx context.Context, group *models.ModifierGroup) *errors.ErrorDetails {
+	args := m.Called(ctx, group)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockModifierRepository) DeleteGroup(ctx context.Context, productId int64, groupId int64) *errors.ErrorDetails {
+	args := m.Called(ctx, productId, groupId)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockModifierRepository) GetGroupsByProductIds(ctx context.Context, productIds []int64) (map[int64][]*models.ModifierGroup, *errors.ErrorDetails) {
+	args := m.Called(ctx, productIds)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(map[int64][]*models.ModifierGroup), nil
+}
+
+// MockCatalogRepository is a mock implementation of CatalogRepository
+type MockCatalogRepository struct {
+	mock.Mock
+}
+
+func (m *MockCatalogRepository) GetByKeys(ctx context.Context, keys []models.ProductKey) ([]*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, keys)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Product), nil
+}
+
+func (m *MockCatalogRepository) UpsertProducts(ctx context.Context, products []*models.Product) *errors.ErrorDetails {
+	args := m.Called(ctx, products)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockCatalogRepository) ExportProducts(ctx context.Context, fn func(product *models.Product) error) *errors.ErrorDetails {
+	args := m.Called(ctx, fn)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+// MockCategoryRepository is a mock implementation of CategoryRepository
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Save(ctx context.Context, category *models.Category) *errors.ErrorDetails {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *models.Category) *errors.ErrorDetails {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id int64) *errors.ErrorDetails {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockCategoryRepository) GetById(ctx context.Context, id int64) (*models.Category, *errors.ErrorDetails) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Category), nil
+}
+
+func (m *MockCategoryRepository) ListCategories(ctx context.Context) ([]*models.Category, *errors.ErrorDetails) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Category), nil
+}
+
+func (m *MockCategoryRepository) EnsurePath(ctx context.Context, names []string) (*models.Category, *errors.ErrorDetails) {
+	args := m.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(*models.Category), nil
+}
+
+// MockImageStorage is a mock implementation of ImageStorage
+type MockImageStorage struct {
+	mock.Mock
+}
+
+func (m *MockImageStorage) Save(ctx context.Context, key string, contentType string, content []byte) (string, *errors.ErrorDetails) {
+	args := m.Called(ctx, key, contentType, content)
+	if args.Get(1) != nil {
+		return "", args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.String(0), nil
+}
+
+func (m *MockImageStorage) Delete(ctx context.Context, key string) *errors.ErrorDetails {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockImageStorage) KeyOf(url string) (string, bool) {
+	args := m.Called(url)
+	return args.String(0), args.Bool(1)
+}
+
+// MockAvailabilityRepository is a mock implementation of AvailabilityRepository
+type MockAvailabilityRepository struct {
+	mock.Mock
+}
+
+func (m *MockAvailabilityRepository) ReplaceProductWindows(ctx context.Context, productId int64, windows []*models.AvailabilityWindow) *errors.ErrorDetails {
+	args := m.Called(ctx, productId, windows)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockAvailabilityRepository) ReplaceCategoryWindows(ctx context.Context, categoryId int64, windows []*models.AvailabilityWindow) *errors.ErrorDetails {
+	args := m.Called(ctx, categoryId, windows)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockAvailabilityRepository) GetCategoryWindows(ctx context.Context, categoryId int64) ([]*models.AvailabilityWindow, *errors.ErrorDetails) {
+	args := m.Called(ctx, categoryId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.AvailabilityWindow), nil
+}
+
+func (m *MockAvailabilityRepository) GetWindowsByProductIds(ctx context.Context, productIds []int64) (map[int64][]*models.AvailabilityWindow, *errors.ErrorDetails) {
+	args := m.Called(ctx, productIds)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(map[int64][]*models.AvailabilityWindow), nil
+}
+
+// alwaysAvailable returns an availability repository without any windows, for the tests of orders not about availability
+func alwaysAvailable() *MockAvailabilityRepository {
+	repo := new(MockAvailabilityRepository)
+	repo.On("GetWindowsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.AvailabilityWindow{}, nil).Maybe()
+	return repo
+}
+
+// MockTranslationRepository is a mock implementation of TranslationRepository
+type MockTranslationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTranslationRepository) GetProductTranslations(ctx context.Context, productIds []int64, locale string) (map[int64]*models.ProductTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, productIds, locale)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(map[int64]*models.ProductTranslation), nil
+}
+
+func (m *MockTranslationRepository) GetCategoryTranslations(ctx context.Context, paths []string, locale string) (map[string]*models.CategoryTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, paths, locale)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(map[string]*models.CategoryTranslation), nil
+}
+
+func (m *MockTranslationRepository) ListProductTranslations(ctx context.Context, productId int64) ([]*models.ProductTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.ProductTranslation), nil
+}
+
+func (m *MockTranslationRepository) SaveProductTranslation(ctx context.Context, translation *models.ProductTranslation) *errors.ErrorDetails {
+	args := m.Called(ctx, translation)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockTranslationRepository) DeleteProductTranslation(ctx context.Context, productId int64, locale string) *errors.ErrorDetails {
+	args := m.Called(ctx, productId, locale)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockTranslationRepository) ListCategoryTranslations(ctx context.Context, categoryId int64) ([]*models.CategoryTranslation, *errors.ErrorDetails) {
+	args := m.Called(ctx, categoryId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.CategoryTranslation), nil
+}
+
+func (m *MockTranslationRepository) SaveCategoryTranslation(ctx context.Context, translation *models.CategoryTranslation) *errors.ErrorDetails {
+	args := m.Called(ctx, translation)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockTranslationRepository) DeleteCategoryTranslation(ctx context.Context, categoryId int64, locale string) *errors.ErrorDetails {
+	args := m.Called(ctx, categoryId, locale)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+// MockRecommendationRepository is a mock implementation of RecommendationRepository
+type MockRecommendationRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecommendationRepository) GetRecommendations(ctx context.Context, productIds []int64) ([]*models.Recommendation, *errors.ErrorDetails) {
+	args := m.Called(ctx, productIds)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Recommendation), nil
+}
+
+func (m *MockRecommendationRepository) RefreshRecommendations(ctx context.Context, since time.Time, minOrders int, perProduct int) (int64, bool, *errors.ErrorDetails) {
+	args := m.Called(ctx, since, minOrders, perProduct)
+	if args.Get(2) == nil {
+		return args.Get(0).(int64), args.Bool(1), nil
+	}
+	return 0, false, args.Get(2).(*errors.ErrorDetails)
+}
+
+// MockRefundRepository is a mock implementation of RefundRepository
+type MockRefundRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefundRepository) CreateRefund(ctx context.Context, refund *models.Refund, previousRefunds int) *errors.ErrorDetails {
+	args := m.Called(ctx, refund, previousRefunds)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockRefundRepository) GetRefundsByOrderId(ctx context.Context, orderId string) ([]*models.Refund, *errors.ErrorDetails) {
+	args := m.Called(ctx, orderId)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Refund), nil
+}
+
+// MockIdempotencyRepository is a mock implementation of IdempotencyRepository
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) ClaimKey(ctx context.Context, key string, fingerprint string, ttl time.Duration, inFlightTimeout time.Duration) (*models.IdempotencyRecord, *errors.ErrorDetails) {
+	args := m.Called(ctx, key, fingerprint, ttl, inFlightTimeout)
+	if args.Get(1) != nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	if args.Get(0) == nil {
+		return nil, nil
+	}
+	return args.Get(0).(*models.IdempotencyRecord), nil
+}
+
+func (m *MockIdempotencyRepository) SaveResponse(ctx context.Context, key string, fingerprint string, statusCode int, body []byte) *errors.ErrorDetails {
+	args := m.Called(ctx, key, fingerprint, statusCode, body)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockIdempotencyRepository) ReleaseKey(ctx context.Context, key string, fingerprint string) *errors.ErrorDetails {
+	args := m.Called(ctx, key, fingerprint)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpiredKeys(ctx context.Context) (int64, *errors.ErrorDetails) {
+	args := m.Called(ctx)
+	if args.Get(1) != nil {
+		return 0, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).(int64), nil
+}
+
+// MockRecommendationService is a mock implementation of RecommendationService
+type MockRecommendationService struct {
+	mock.Mock
+}
+
+func (m *MockRecommendationService) GetProductRecommendations(ctx context.Context, productId int64, limit int) ([]*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, productId, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Product), nil
+}
+
+func (m *MockRecommendationService) SuggestProducts(ctx context.Context, productIds []int64) ([]*models.Product, *errors.ErrorDetails) {
+	args := m.Called(ctx, productIds)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(*errors.ErrorDetails)
+	}
+	return args.Get(0).([]*models.Product), nil
+}
+
+func (m *MockRecommendationService) RefreshRecommendations(ctx context.Context) *errors.ErrorDetails {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*errors.ErrorDetails)
+}
@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+)
+
+// TestModifierService_CreateModifierGroup_Success tests that a valid group is saved with its modifiers
+func TestModifierService_CreateModifierGroup_Success(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewModifierServiceImpl(mockProductRepo, mockModifierRepo)
+
+	mockModifierRepo.On("SaveGroup", mock.Anything, mock.MatchedBy(func(group *models.ModifierGroup) bool {
+		return group.ProductId == 1 && group.Name == "Size" && len(group.Modifiers) == 2 && group.Modifiers[0].PriceDelta == money.MustParse("2.5")
+	})).Return(nil)
+
+	result, err := service.CreateModifierGroup(context.Background(), 1, &requests.ModifierGroupRequest{
+		Name:      " Size ",
+		MinSelect: 1,
+		MaxSelect: 1,
+		Modifiers: []requests.ModifierRequest{{Name: "Large", PriceDelta: money.MustParse("2.5")}, {Name: "Regular"}},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Size", result.Name)
+
+	mockModifierRepo.AssertExpectations(t)
+}
+
+// TestModifierService_CreateModifierGroup_InvalidRules tests that selection rules that cannot be satisfied are rejected
+func TestModifierService_CreateModifierGroup_InvalidRules(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewModifierServiceImpl(mockProductRepo, mockModifierRepo)
+
+	for _, request := range []*requests.ModifierGroupRequest{
+		{Name: "Size", MinSelect: 2, MaxSelect: 1, Modifiers: []requests.ModifierRequest{{Name: "Large"}, {Name: "Small"}}},
+		{Name: "Size", MinSelect: 2, MaxSelect: 2, Modifiers: []requests.ModifierRequest{{Name: "Large"}}},
+		{Name: "Size", MinSelect: 0, MaxSelect: 1, Modifiers: []requests.ModifierRequest{{Name: "Large"}, {Name: "Large"}}},
+	} {
+		result, err := service.CreateModifierGroup(context.Background(), 1, request)
+
+		assert.Nil(t, result)
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+	}
+
+	mockModifierRepo.AssertNotCalled(t, "SaveGroup", mock.Anything, mock.Anything)
+}
+
+// TestModifierService_GetModifierGroups_ProductNotFound tests that the groups of an unknown product are not found
+func TestModifierService_GetModifierGroups_ProductNotFound(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewModifierServiceImpl(mockProductRepo, mockModifierRepo)
+
+	mockProductRepo.On("GetById", mock.Anything, int64(99)).Return(nil, exceptions.GenericException("product not found", http.StatusNotFound))
+
+	result, err := service.GetModifierGroups(context.Background(), 99)
+
+	assert.Nil(t, result)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+
+	mockModifierRepo.AssertNotCalled(t, "GetGroupsByProductIds", mock.Anything, mock.Anything)
+}
+
+// TestModifierService_GetModifierGroups_NoGroups tests that a product without modifiers returns an empty list
+func TestModifierService_GetModifierGroups_NoGroups(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewModifierServiceImpl(mockProductRepo, mockModifierRepo)
+
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, []int64{1}).Return(map[int64][]*models.ModifierGroup{}, nil)
+
+	result, err := service.GetModifierGroups(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.Empty(t, result)
+}
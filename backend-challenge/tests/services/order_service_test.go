@@ -2,14 +2,20 @@ package services_test
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"math"
 	"net/http"
+	"oolio.com/kart/configs"
 	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"oolio.com/kart/money"
 	"oolio.com/kart/services"
 	"testing"
+	"time"
 )
 
 // TestOrderService_PlaceOrder_Success tests the PlaceOrder method of the OrderService
@@ -23,7 +29,8 @@ func TestOrderService_PlaceOrder_Success(t *testing.T) {
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity := 2
 	request := &requests.PlaceOrderRequest{
@@ -36,13 +43,14 @@ func TestOrderService_PlaceOrder_Success(t *testing.T) {
 		{
 			Id:       1,
 			Name:     "Margherita Pizza",
-			Price:    12.99,
+			Price:    money.MustParse("12.99"),
 			Category: "Pizza",
 			Status:   "available",
 		},
 	}
 
 	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
 	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.AnythingOfType("[]models.OrderItem")).
 		Run(func(args mock.Arguments) {
 			order := args.Get(1).(*models.Order)
@@ -66,6 +74,7 @@ func TestOrderService_PlaceOrder_Success(t *testing.T) {
 func TestOrderService_PlaceOrder_WithValidCoupon(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
 	mockCouponRepo := new(MockCouponRepository)
 
 	mockCouponRepo.On("GetCouponCounts", mock.Anything).Return(int64(100), int64(2), nil)
@@ -75,7 +84,7 @@ func TestOrderService_PlaceOrder_WithValidCoupon(t *testing.T) {
 	err := services.InitializeCouponService(mockCouponRepo)
 	assert.Nil(t, err)
 
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity := 2
 	request := &requests.PlaceOrderRequest{
@@ -89,13 +98,14 @@ func TestOrderService_PlaceOrder_WithValidCoupon(t *testing.T) {
 		{
 			Id:       1,
 			Name:     "Margherita Pizza",
-			Price:    12.99,
+			Price:    money.MustParse("12.99"),
 			Category: "Pizza",
 			Status:   "available",
 		},
 	}
 
 	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
 	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.AnythingOfType("[]models.OrderItem")).
 		Run(func(args mock.Arguments) {
 			order := args.Get(1).(*models.Order)
@@ -119,6 +129,7 @@ func TestOrderService_PlaceOrder_WithValidCoupon(t *testing.T) {
 func TestOrderService_PlaceOrder_InvalidCoupon(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
 	mockCouponRepo := new(MockCouponRepository)
 
 	mockCouponRepo.On("GetCouponCounts", mock.Anything).Return(int64(100), int64(2), nil)
@@ -127,7 +138,7 @@ func TestOrderService_PlaceOrder_InvalidCoupon(t *testing.T) {
 	err := services.InitializeCouponService(mockCouponRepo)
 	assert.Nil(t, err)
 
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity := 2
 	request := &requests.PlaceOrderRequest{
@@ -151,6 +162,7 @@ func TestOrderService_PlaceOrder_InvalidCoupon(t *testing.T) {
 func TestOrderService_PlaceOrder_InvalidCouponFormat(t *testing.T) {
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
 	mockCouponRepo := new(MockCouponRepository)
 
 	mockCouponRepo.On("GetCouponCounts", mock.Anything).Return(int64(100), int64(1), nil)
@@ -159,7 +171,7 @@ func TestOrderService_PlaceOrder_InvalidCouponFormat(t *testing.T) {
 	err := services.InitializeCouponService(mockCouponRepo)
 	assert.Nil(t, err)
 
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity := 2
 	request := &requests.PlaceOrderRequest{
@@ -189,7 +201,8 @@ func TestOrderService_PlaceOrder_ProductNotFound(t *testing.T) {
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity := 2
 	request := &requests.PlaceOrderRequest{
@@ -225,7 +238,8 @@ func TestOrderService_PlaceOrder_InvalidProductId(t *testing.T) {
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity := 2
 	request := &requests.PlaceOrderRequest{
@@ -253,7 +267,8 @@ func TestOrderService_PlaceOrder_MultipleItems(t *testing.T) {
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity1 := 2
 	quantity2 := 3
@@ -265,8 +280,8 @@ func TestOrderService_PlaceOrder_MultipleItems(t *testing.T) {
 	}
 
 	mockProducts := []*models.Product{
-		{Id: 1, Name: "Product 1", Price: 10.00, Category: "Cat1", Status: "available"},
-		{Id: 2, Name: "Product 2", Price: 15.00, Category: "Cat2", Status: "available"},
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Cat1", Status: "available"},
+		{Id: 2, Name: "Product 2", Price: money.MustParse("15.00"), Category: "Cat2", Status: "available"},
 	}
 
 	mockProductRepo.On("GetByIds", mock.Anything, mock.MatchedBy(func(ids []int64) bool {
@@ -279,6 +294,7 @@ func TestOrderService_PlaceOrder_MultipleItems(t *testing.T) {
 		}
 		return idMap[1] && idMap[2]
 	})).Return(mockProducts, nil).Once()
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
 
 	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.AnythingOfType("[]models.OrderItem")).
 		Run(func(args mock.Arguments) {
@@ -309,7 +325,8 @@ func TestOrderService_PlaceOrder_DuplicateItems_ShouldAggregate(t *testing.T) {
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity1 := 2
 	quantity2 := 3
@@ -320,9 +337,10 @@ func TestOrderService_PlaceOrder_DuplicateItems_ShouldAggregate(t *testing.T) {
 		},
 	}
 
-	mockProducts := []*models.Product{{Id: 1, Name: "Product 1", Price: 10.00, Category: "Cat1", Status: "available"}}
+	mockProducts := []*models.Product{{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Cat1", Status: "available"}}
 
 	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
 	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.AnythingOfType("[]models.OrderItem")).
 		Run(func(args mock.Arguments) {
 			order := args.Get(1).(*models.Order)
@@ -353,7 +371,8 @@ func TestOrderService_PlaceOrder_CreateOrderFails(t *testing.T) {
 
 	mockOrderRepo := new(MockOrderRepository)
 	mockProductRepo := new(MockProductRepository)
-	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, services.CouponServiceImpl)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
 
 	quantity := 2
 	request := &requests.PlaceOrderRequest{
@@ -362,13 +381,14 @@ func TestOrderService_PlaceOrder_CreateOrderFails(t *testing.T) {
 		},
 	}
 
-	mockProducts := []*models.Product{{Id: 1, Name: "Product 1", Price: 10.00, Category: "Cat1", Status: "available"}}
+	mockProducts := []*models.Product{{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Cat1", Status: "available"}}
 	mockError := &errors.ErrorDetails{
 		ErrorCode: http.StatusInternalServerError,
 		Message:   "failed to fetch products",
 	}
 
 	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
 	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.AnythingOfType("[]models.OrderItem")).Return(mockError)
 
 	result, err := service.PlaceOrder(context.Background(), request)
@@ -380,3 +400,828 @@ func TestOrderService_PlaceOrder_CreateOrderFails(t *testing.T) {
 	mockProductRepo.AssertExpectations(t)
 	mockOrderRepo.AssertExpectations(t)
 }
+
+// TestOrderService_PlaceOrder_UnavailableProducts tests that every product that cannot be ordered is reported with its reason
+func TestOrderService_PlaceOrder_UnavailableProducts(t *testing.T) {
+	mockCouponRepo := new(MockCouponRepository)
+	mockCouponRepo.On("GetCouponCounts", mock.Anything).Return(int64(100), int64(2), nil)
+	mockCouponRepo.On("GetCouponsByFileCount", mock.Anything, ">= 2").Return([]string{"SAVE1000", "DISCOUNT50"}, nil)
+
+	err := services.InitializeCouponService(mockCouponRepo)
+	assert.Nil(t, err)
+
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), services.CouponServiceImpl, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+			{ProductId: "2", Quantity: &quantity},
+			{ProductId: "3", Quantity: &quantity},
+			{ProductId: "4", Quantity: &quantity},
+		},
+	}
+
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Cat1", Status: "available"},
+		{Id: 2, Name: "Product 2", Price: money.MustParse("15.00"), Category: "Cat2", Status: "sold_out"},
+		{Id: 3, Name: "Product 3", Price: money.MustParse("15.00"), Category: "Cat2", Status: "hidden"},
+		{Id: 4, Name: "Product 4", Price: money.MustParse("15.00"), Category: "Cat3 > Seasonal", CategoryInactive: true, Status: "available"},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2, 3, 4}).Return(mockProducts, nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+	assert.Len(t, errDetails.Items, 3)
+	assert.Equal(t, "2", errDetails.Items[0].ProductId)
+	assert.Equal(t, "product_sold_out", errDetails.Items[0].Reason)
+	assert.Equal(t, "3", errDetails.Items[1].ProductId)
+	assert.Equal(t, "product_unavailable", errDetails.Items[1].Reason)
+	assert.Equal(t, "4", errDetails.Items[2].ProductId)
+	assert.Equal(t, "category_inactive", errDetails.Items[2].Reason)
+
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_PlaceOrder_OutOfHours tests that products outside of their availability windows are rejected
+func TestOrderService_PlaceOrder_OutOfHours(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	mockAvailabilityRepo := new(MockAvailabilityRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, mockAvailabilityRepo, new(MockTranslationRepository), nil, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+			{ProductId: "2", Quantity: &quantity},
+		},
+	}
+
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Pancakes", Price: money.MustParse("8.00"), Category: "Breakfast", Status: "available"},
+		{Id: 2, Name: "Coffee", Price: money.MustParse("3.00"), Category: "Drinks", Status: "available"},
+	}
+
+	// The whole day after tomorrow is never open now
+	closedDay := int(time.Now().In(configs.StoreLocation).AddDate(0, 0, 2).Weekday())
+	if closedDay == 0 {
+		closedDay = 7
+	}
+	windows := map[int64][]*models.AvailabilityWindow{
+		1: {{Id: 1, DaysOfWeek: []int{closedDay}, StartMinute: 0, EndMinute: models.MinutesPerDay}},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).Return(mockProducts, nil)
+	mockAvailabilityRepo.On("GetWindowsByProductIds", mock.Anything, []int64{1, 2}).Return(windows, nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+	assert.Len(t, errDetails.Items, 1)
+	assert.Equal(t, "1", errDetails.Items[0].ProductId)
+	assert.Equal(t, "product_out_of_hours", errDetails.Items[0].Reason)
+	assert.Contains(t, errDetails.Items[0].Message, "00:00-24:00")
+
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_PlaceOrder_InsufficientStock tests that the per product stock error of the repository is surfaced
+func TestOrderService_PlaceOrder_InsufficientStock(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	quantity := 4
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+		},
+	}
+
+	stock := 3
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Daily Special", Price: money.MustParse("15.00"), Category: "Specials", Status: "available", StockQuantity: &stock},
+	}
+
+	stockErr := exceptions.InsufficientStockException([]errors.ItemError{{ProductId: "1", Reason: "insufficient_stock", Message: "only 3 left in stock"}})
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
+	mockOrderRepo.On("CreateOrder", mock.Anything, mock.Anything, mock.Anything).Return(stockErr)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+	assert.Len(t, errDetails.Items, 1)
+	assert.Equal(t, "insufficient_stock", errDetails.Items[0].Reason)
+}
+
+// TestOrderService_PlaceOrder_WithModifiers tests that modifiers are priced into the line and that the same product with
+// other modifiers becomes its own line
+func TestOrderService_PlaceOrder_WithModifiers(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	one := 1
+	two := 2
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &two, Modifiers: []string{"12", "10"}},
+			{ProductId: "1", Quantity: &one, Modifiers: []string{"11"}},
+			{ProductId: "1", Quantity: &one, Modifiers: []string{"10", "12"}},
+		},
+	}
+
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("10.00"), Category: "Pizza", Status: "available"},
+	}
+	groups := map[int64][]*models.ModifierGroup{
+		1: {
+			{Id: 1, ProductId: 1, Name: "Size", MinSelect: 1, MaxSelect: 1, Modifiers: []*models.Modifier{
+				{Id: 10, GroupId: 1, Name: "Large", PriceDelta: money.MustParse("3.00")},
+				{Id: 11, GroupId: 1, Name: "Small", PriceDelta: money.MustParse("-1.00")},
+			}},
+			{Id: 2, ProductId: 1, Name: "Extras", MinSelect: 0, MaxSelect: 3, Modifiers: []*models.Modifier{
+				{Id: 12, GroupId: 2, Name: "Extra Cheese", PriceDelta: money.MustParse("1.50")},
+			}},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, []int64{1}).Return(groups, nil)
+	mockOrderRepo.On("CreateOrder", mock.Anything, mock.MatchedBy(func(order *models.Order) bool {
+		return order.Subtotal == money.MustParse("52.5")
+	}), mock.MatchedBy(func(items []models.OrderItem) bool {
+		return len(items) == 2 &&
+			items[0].Quantity == 3 && items[0].UnitPrice == money.MustParse("14.5") && len(items[0].Modifiers) == 2 &&
+			items[0].Modifiers[0].Name == "Large" && items[0].Modifiers[1].Name == "Extra Cheese" &&
+			items[1].Quantity == 1 && items[1].UnitPrice == money.MustParse("9") && items[1].Modifiers[0].GroupName == "Size"
+	})).Return(nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, errDetails)
+	assert.Len(t, result.Items, 2)
+	assert.Len(t, result.Items[0].Modifiers, 2)
+	assert.Equal(t, "10", result.Items[0].Modifiers[0].Id)
+
+	mockOrderRepo.AssertExpectations(t)
+}
+
+// TestOrderService_PlaceOrder_LinePrices tests that the lines carry their unit price and line total rounded to the cents
+// they are stored with, and that the subtotal is the sum of the line totals
+func TestOrderService_PlaceOrder_LinePrices(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	three := 3
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &three},
+			{ProductId: "2", Quantity: &three, Modifiers: []string{"20"}},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).Return([]*models.Product{
+		{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"},
+		{Id: 2, Name: "Mint", Price: money.MustParse("0.10"), Category: "Extras", Status: "available"},
+	}, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, []int64{1, 2}).Return(map[int64][]*models.ModifierGroup{
+		2: {{Id: 2, ProductId: 2, Name: "Wrap", MinSelect: 0, MaxSelect: 1, Modifiers: []*models.Modifier{
+			{Id: 20, GroupId: 2, Name: "Gift Wrap", PriceDelta: money.MustParse("0.20")},
+		}}},
+	}, nil)
+	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.AnythingOfType("[]models.OrderItem")).Return(nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, errDetails)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, money.MustParse("12.99"), result.Items[0].UnitPrice)
+	assert.Equal(t, money.MustParse("38.97"), result.Items[0].LineTotal)
+	assert.Equal(t, money.MustParse("0.3"), result.Items[1].UnitPrice)
+	assert.Equal(t, money.MustParse("0.9"), result.Items[1].LineTotal)
+	assert.Equal(t, money.MustParse("39.87"), result.Subtotal)
+	assert.Equal(t, money.MustParse("0.0"), result.Discounts)
+	assert.Equal(t, money.MustParse("39.87"), result.Total)
+}
+
+// TestOrderService_PlaceOrder_FractionalPrices tests that fractional prices times quantities are saved and returned to
+// the cent, where floating point would give 3.3000000000000003 and 5.500000000000001
+func TestOrderService_PlaceOrder_FractionalPrices(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	one, three := 1, 3
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &three},
+			{ProductId: "2", Quantity: &one},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).Return([]*models.Product{
+		{Id: 1, Name: "Lemonade", Price: money.MustParse("1.10"), Category: "Drinks", Status: "available"},
+		{Id: 2, Name: "Iced Tea", Price: money.MustParse("2.20"), Category: "Drinks", Status: "available"},
+	}, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, []int64{1, 2}).Return(map[int64][]*models.ModifierGroup{}, nil)
+	var saved *models.Order
+	var savedItems []models.OrderItem
+	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.AnythingOfType("[]models.OrderItem")).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*models.Order)
+			savedItems = args.Get(2).([]models.OrderItem)
+		}).Return(nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, errDetails)
+	assert.Len(t, savedItems, 2)
+	assert.Equal(t, int64(330), savedItems[0].Price.Cents())
+	assert.Equal(t, int64(220), savedItems[1].Price.Cents())
+	assert.Equal(t, int64(550), saved.Subtotal.Cents())
+	assert.Equal(t, int64(550), saved.Total.Cents())
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"lineTotal":3.3}`)
+	assert.Contains(t, string(data), `"subtotal":5.5,`)
+	assert.Contains(t, string(data), `"total":5.5,`)
+}
+
+// TestOrderService_PlaceOrder_AmountOutOfRange tests that orders whose line totals or subtotal do not fit in their
+// columns are rejected
+func TestOrderService_PlaceOrder_AmountOutOfRange(t *testing.T) {
+	one, two := 1, 2
+	tests := []struct {
+		name  string
+		items []requests.OrderItemRequest
+	}{
+		{"line total", []requests.OrderItemRequest{{ProductId: "1", Quantity: &two}}},
+		{"subtotal", []requests.OrderItemRequest{{ProductId: "1", Quantity: &one}, {ProductId: "2", Quantity: &one}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderRepo := new(MockOrderRepository)
+			mockProductRepo := new(MockProductRepository)
+			mockModifierRepo := new(MockModifierRepository)
+			service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+			mockProductRepo.On("GetByIds", mock.Anything, mock.Anything).Return([]*models.Product{
+				{Id: 1, Name: "Gold Leaf Pizza", Price: models.MaxPrice, Category: "Pizza", Status: "available"},
+				{Id: 2, Name: "Gold Leaf Waffle", Price: money.MustParse("0.01"), Category: "Waffle", Status: "available"},
+			}, nil)
+			mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
+
+			result, errDetails := service.PlaceOrder(context.Background(), &requests.PlaceOrderRequest{Items: tt.items})
+
+			assert.Nil(t, result)
+			assert.NotNil(t, errDetails)
+			assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+			mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestOrderService_PlaceOrder_InvalidModifierSelection tests that selection rules and foreign modifiers are reported per item
+func TestOrderService_PlaceOrder_InvalidModifierSelection(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+			{ProductId: "2", Quantity: &quantity, Modifiers: []string{"10"}},
+		},
+	}
+
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("10.00"), Category: "Pizza", Status: "available"},
+		{Id: 2, Name: "Lemonade", Price: money.MustParse("3.00"), Category: "Drinks", Status: "available"},
+	}
+	groups := map[int64][]*models.ModifierGroup{
+		1: {
+			{Id: 1, ProductId: 1, Name: "Size", MinSelect: 1, MaxSelect: 1, Modifiers: []*models.Modifier{
+				{Id: 10, GroupId: 1, Name: "Large", PriceDelta: money.MustParse("3.00")},
+			}},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, []int64{1, 2}).Return(groups, nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+	assert.Len(t, errDetails.Items, 2)
+	assert.Equal(t, "invalid_modifier_selection", errDetails.Items[0].Reason)
+	assert.Equal(t, "choose exactly 1 of Size", errDetails.Items[0].Message)
+	assert.Equal(t, "2", errDetails.Items[1].ProductId)
+	assert.Equal(t, "invalid_modifier", errDetails.Items[1].Reason)
+
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_PlaceOrder_NegativeUnitPrice tests that modifiers cannot take the unit price of an item below zero
+func TestOrderService_PlaceOrder_NegativeUnitPrice(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity, Modifiers: []string{"10"}},
+			{ProductId: "1", Quantity: &quantity, Modifiers: []string{"11"}},
+		},
+	}
+
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Lemonade", Price: money.MustParse("3.00"), Category: "Drinks", Status: "available"},
+	}
+	groups := map[int64][]*models.ModifierGroup{
+		1: {
+			{Id: 1, ProductId: 1, Name: "Size", MinSelect: 1, MaxSelect: 1, Modifiers: []*models.Modifier{
+				{Id: 10, GroupId: 1, Name: "Free refill", PriceDelta: money.MustParse("-3.00")},
+				{Id: 11, GroupId: 1, Name: "Staff price", PriceDelta: money.MustParse("-3.01")},
+			}},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, []int64{1}).Return(groups, nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+	assert.Len(t, errDetails.Items, 1)
+	assert.Equal(t, "1", errDetails.Items[0].ProductId)
+	assert.Equal(t, "negative_price", errDetails.Items[0].Reason)
+
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_PlaceOrder_UnitPriceOutOfRange tests that modifiers cannot take the unit price of an item above the
+// greatest price, nor add up to more than an amount can be
+func TestOrderService_PlaceOrder_UnitPriceOutOfRange(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity, Modifiers: []string{"10"}},
+			{ProductId: "2", Quantity: &quantity, Modifiers: []string{"20", "21"}},
+		},
+	}
+
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Caviar", Price: models.MaxPrice, Category: "Deli", Status: "available"},
+		{Id: 2, Name: "Lemonade", Price: money.MustParse("3.00"), Category: "Drinks", Status: "available"},
+	}
+	groups := map[int64][]*models.ModifierGroup{
+		1: {
+			{Id: 1, ProductId: 1, Name: "Extras", MinSelect: 0, MaxSelect: 1, Modifiers: []*models.Modifier{
+				{Id: 10, GroupId: 1, Name: "Gold leaf", PriceDelta: money.MustParse("0.01")},
+			}},
+		},
+		2: {
+			{Id: 2, ProductId: 2, Name: "Extras", MinSelect: 0, MaxSelect: 2, Modifiers: []*models.Modifier{
+				{Id: 20, GroupId: 2, Name: "Ice", PriceDelta: money.FromCents(math.MaxInt64)},
+				{Id: 21, GroupId: 2, Name: "Lemon", PriceDelta: money.FromCents(1)},
+			}},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, []int64{1, 2}).Return(groups, nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+	assert.Len(t, errDetails.Items, 2)
+	assert.Equal(t, "1", errDetails.Items[0].ProductId)
+	assert.Equal(t, "price_out_of_range", errDetails.Items[0].Reason)
+	assert.Equal(t, "2", errDetails.Items[1].ProductId)
+	assert.Equal(t, "price_out_of_range", errDetails.Items[1].Reason)
+
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_PlaceOrder_DuplicateModifier tests that a modifier cannot be chosen twice for the same item
+func TestOrderService_PlaceOrder_DuplicateModifier(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity, Modifiers: []string{"10", "10"}},
+		},
+	}
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusBadRequest, errDetails.ErrorCode)
+
+	mockProductRepo.AssertNotCalled(t, "GetByIds", mock.Anything, mock.Anything)
+}
+
+// TestOrderService_PlaceOrder_RecordsPriceVersion tests that order items record the price version they were priced with
+func TestOrderService_PlaceOrder_RecordsPriceVersion(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+			{ProductId: "2", Quantity: &quantity},
+		},
+	}
+
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), PriceVersion: 3, Category: "Cat1", Status: "available"},
+		{Id: 2, Name: "Product 2", Price: money.MustParse("15.00"), PriceVersion: 1, Category: "Cat2", Status: "available"},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).Return(mockProducts, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
+	mockOrderRepo.On("CreateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), mock.MatchedBy(func(items []models.OrderItem) bool {
+		versions := map[int64]int{}
+		for _, item := range items {
+			versions[item.ProductId] = item.PriceVersion
+		}
+		return len(items) == 2 && versions[1] == 3 && versions[2] == 1
+	})).Return(nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, errDetails)
+	assert.NotNil(t, result)
+
+	mockOrderRepo.AssertExpectations(t)
+}
+
+// TestOrderService_PlaceOrder_DeletedProduct tests that a deleted product cannot be ordered
+func TestOrderService_PlaceOrder_DeletedProduct(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+		},
+	}
+
+	deletedAt := time.Now()
+	mockProducts := []*models.Product{
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Cat1", Status: "available", DeletedAt: &deletedAt},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return(mockProducts, nil)
+
+	result, errDetails := service.PlaceOrder(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, errDetails)
+	assert.Equal(t, http.StatusUnprocessableEntity, errDetails.ErrorCode)
+	assert.Len(t, errDetails.Items, 1)
+	assert.Equal(t, "product_deleted", errDetails.Items[0].Reason)
+
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_QuoteOrder_Success tests that a quote prices the order with suggestions without placing it
+func TestOrderService_QuoteOrder_Success(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	mockRecommendationService := new(MockRecommendationService)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, mockRecommendationService)
+
+	quantity := 2
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return([]*models.Product{
+		{Id: 1, Name: "Chicken Waffle", Price: money.MustParse("13.5"), Category: "Waffle", Status: "available"},
+	}, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
+	mockRecommendationService.On("SuggestProducts", mock.Anything, []int64{1}).Return([]*models.Product{
+		{Id: 4, Name: "Lemonade", Price: money.MustParse("4"), Category: "Drinks", Status: "available"},
+	}, nil)
+
+	result, err := service.QuoteOrder(context.Background(), request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, money.MustParse("27.0"), result.Subtotal)
+	assert.Equal(t, money.MustParse("27.0"), result.Total)
+	assert.Len(t, result.Items, 1)
+	assert.Len(t, result.Suggestions, 1)
+	assert.Equal(t, "4", result.Suggestions[0].Id)
+	mockOrderRepo.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_QuoteOrder_SuggestionsFailed tests that a quote is still given when the suggestions fail
+func TestOrderService_QuoteOrder_SuggestionsFailed(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockModifierRepo := new(MockModifierRepository)
+	mockRecommendationService := new(MockRecommendationService)
+	service := services.NewOrderServiceImpl(new(MockOrderRepository), mockProductRepo, mockModifierRepo, alwaysAvailable(), new(MockTranslationRepository), nil, mockRecommendationService)
+
+	quantity := 1
+	request := &requests.PlaceOrderRequest{
+		Items: []requests.OrderItemRequest{
+			{ProductId: "1", Quantity: &quantity},
+		},
+	}
+
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return([]*models.Product{
+		{Id: 1, Name: "Chicken Waffle", Price: money.MustParse("13.5"), Category: "Waffle", Status: "available"},
+	}, nil)
+	mockModifierRepo.On("GetGroupsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.ModifierGroup{}, nil)
+	mockRecommendationService.On("SuggestProducts", mock.Anything, []int64{1}).
+		Return(nil, exceptions.GenericException("failed to fetch recommendations", http.StatusInternalServerError))
+
+	result, err := service.QuoteOrder(context.Background(), request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, money.MustParse("13.5"), result.Total)
+	assert.Nil(t, result.Suggestions)
+}
+
+// TestOrderService_GetOrderById_Success tests that an order is returned with its totals, modifiers and the products of
+// its items, deleted ones included
+func TestOrderService_GetOrderById_Success(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	placedAt := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	order := &models.Order{Id: orderId, CouponCode: "HAPPYHRS", Subtotal: money.MustParse("31.5"), Discount: money.MustParse("3.15"), Total: money.MustParse("28.35"), CreatedAt: placedAt, ModifiedAt: placedAt}
+	items := []models.OrderItem{
+		{Id: 1, OrderId: orderId, ProductId: 1, Quantity: 2, UnitPrice: money.MustParse("15.5"), Price: money.MustParse("31"),
+			Modifiers: []models.OrderItemModifier{{ModifierId: 3, GroupName: "Size", Name: "Large", PriceDelta: money.MustParse("2.5")}}},
+		{Id: 2, OrderId: orderId, ProductId: 2, Quantity: 1, UnitPrice: money.MustParse("0.5"), Price: money.MustParse("0.5")},
+		{Id: 3, OrderId: orderId, ProductId: 1, Quantity: 1, UnitPrice: money.MustParse("13"), Price: money.MustParse("13")},
+	}
+	deletedAt := placedAt.Add(time.Hour)
+	mockOrderRepo.On("GetOrderById", mock.Anything, orderId).Return(order, items, nil)
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).Return([]*models.Product{
+		{Id: 1, Name: "Chicken Waffle", Price: money.MustParse("13"), Status: models.ProductStatusAvailable},
+		{Id: 2, Name: "Extra Napkins", Price: money.MustParse("0.5"), Status: models.ProductStatusAvailable, DeletedAt: &deletedAt},
+	}, nil)
+
+	result, err := service.GetOrderById(context.Background(), orderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, orderId, result.Id)
+	assert.Equal(t, "HAPPYHRS", result.CouponCode)
+	assert.Equal(t, money.MustParse("31.5"), result.Subtotal)
+	assert.Equal(t, money.MustParse("3.15"), result.Discounts)
+	assert.Equal(t, money.MustParse("28.35"), result.Total)
+	assert.Equal(t, placedAt, result.CreatedAt)
+	assert.Len(t, result.Items, 3)
+	assert.Equal(t, "Large", result.Items[0].Modifiers[0].Name)
+	assert.Len(t, result.Products, 2)
+}
+
+// TestOrderService_GetOrderById_NotFound tests that the 404 of the repository is returned
+func TestOrderService_GetOrderById_NotFound(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockOrderRepo.On("GetOrderById", mock.Anything, orderId).Return(nil, nil, exceptions.GenericException("order not found", http.StatusNotFound))
+
+	result, err := service.GetOrderById(context.Background(), orderId)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+	mockProductRepo.AssertNotCalled(t, "GetByIds", mock.Anything, mock.Anything)
+}
+
+// TestOrderService_ListOrders_Defaults tests that orders are listed newest first, a page of the requested size at a
+// time
+func TestOrderService_ListOrders_Defaults(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, new(MockProductRepository), new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	orders := []*models.Order{{Id: "550e8400-e29b-41d4-a716-446655440000", Status: models.OrderStatusPlaced, Total: money.MustParse("27")}}
+	mockOrderRepo.On("ListOrders", mock.Anything, mock.MatchedBy(func(query *models.OrderFilter) bool {
+		return query.Sort == models.OrderSortCreatedAt && query.Direction == models.SortDesc && query.Limit == 21 && query.After == nil
+	})).Return(orders, int64(1), nil)
+
+	result, err := service.ListOrders(context.Background(), &models.OrderFilter{Limit: 20})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Orders, 1)
+	assert.Equal(t, int64(1), result.TotalCount)
+	assert.Empty(t, result.NextCursor)
+	mockOrderRepo.AssertExpectations(t)
+}
+
+// TestOrderService_ListOrders_CursorPagination tests that the next cursor resumes after the last order of a page
+func TestOrderService_ListOrders_CursorPagination(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, new(MockProductRepository), new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	placedAt := time.Date(2025, 3, 1, 10, 30, 0, 123456000, time.UTC)
+	firstPage := []*models.Order{
+		{Id: "550e8400-e29b-41d4-a716-446655440003", CreatedAt: placedAt.Add(time.Minute)},
+		{Id: "550e8400-e29b-41d4-a716-446655440002", CreatedAt: placedAt},
+		{Id: "550e8400-e29b-41d4-a716-446655440001", CreatedAt: placedAt},
+	}
+
+	mockOrderRepo.On("ListOrders", mock.Anything, mock.MatchedBy(func(query *models.OrderFilter) bool {
+		return query.After == nil
+	})).Return(firstPage, int64(3), nil).Once()
+
+	result, err := service.ListOrders(context.Background(), &models.OrderFilter{Limit: 2})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Orders, 2)
+	assert.Equal(t, int64(3), result.TotalCount)
+	assert.NotEmpty(t, result.NextCursor)
+
+	mockOrderRepo.On("ListOrders", mock.Anything, mock.MatchedBy(func(query *models.OrderFilter) bool {
+		return query.After != nil && query.After.Id == firstPage[1].Id && placedAt.Equal(query.After.Value.(time.Time))
+	})).Return(firstPage[2:], int64(3), nil).Once()
+
+	result, err = service.ListOrders(context.Background(), &models.OrderFilter{Limit: 2, Cursor: result.NextCursor})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Orders, 1)
+	assert.Empty(t, result.NextCursor)
+	mockOrderRepo.AssertExpectations(t)
+}
+
+// TestOrderService_ListOrders_InvalidFilters tests that empty ranges and cursors of another sort are rejected
+func TestOrderService_ListOrders_InvalidFilters(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, new(MockProductRepository), new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	mockOrderRepo.On("ListOrders", mock.Anything, mock.Anything).
+		Return([]*models.Order{{Id: "550e8400-e29b-41d4-a716-446655440001", Total: money.MustParse("10")}, {Id: "550e8400-e29b-41d4-a716-446655440002", Total: money.MustParse("20")}}, int64(2), nil).Once()
+
+	page, err := service.ListOrders(context.Background(), &models.OrderFilter{Sort: models.OrderSortTotal, Limit: 1})
+	assert.Nil(t, err)
+
+	from := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	minTotal, maxTotal := money.MustParse("50"), money.MustParse("10")
+	for name, filter := range map[string]*models.OrderFilter{
+		"empty time range":  {From: &from, To: &to, Limit: 20},
+		"empty total range": {MinTotal: &minTotal, MaxTotal: &maxTotal, Limit: 20},
+		"malformed cursor":  {Cursor: "not-a-cursor", Limit: 20},
+		"other sort":        {Sort: models.OrderSortCreatedAt, Cursor: page.NextCursor, Limit: 1},
+	} {
+		result, err := service.ListOrders(context.Background(), filter)
+
+		assert.Nil(t, result, name)
+		assert.NotNil(t, err, name)
+		assert.Equal(t, http.StatusBadRequest, err.ErrorCode, name)
+	}
+
+	mockOrderRepo.AssertExpectations(t)
+}
+
+// TestOrderService_TransitionOrder_Success tests that an allowed transition is recorded against the version it was
+// decided on and the moved order is returned
+func TestOrderService_TransitionOrder_Success(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	order := &models.Order{Id: orderId, Status: models.OrderStatusPlaced, Version: 1, Total: money.MustParse("13")}
+	items := []models.OrderItem{{Id: 1, OrderId: orderId, ProductId: 1, Quantity: 1, UnitPrice: money.MustParse("13"), Price: money.MustParse("13")}}
+	mockOrderRepo.On("GetOrderById", mock.Anything, orderId).Return(order, items, nil)
+	mockOrderRepo.On("TransitionOrderStatus", mock.Anything, order, mock.MatchedBy(func(change *models.OrderStatusChange) bool {
+		return change.FromStatus == models.OrderStatusPlaced && change.ToStatus == models.OrderStatusAccepted &&
+			change.Actor == "counter" && change.Reason == "Paid at the till"
+	})).Run(func(args mock.Arguments) {
+		moved := args.Get(1).(*models.Order)
+		moved.Status = models.OrderStatusAccepted
+		moved.Version = 2
+	}).Return(nil)
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1}).Return([]*models.Product{{Id: 1, Name: "Chicken Waffle", Price: money.MustParse("13")}}, nil)
+
+	result, err := service.TransitionOrder(context.Background(), orderId, &requests.OrderTransitionRequest{
+		Status: models.OrderStatusAccepted, Version: 1, Actor: "counter", Reason: "Paid at the till",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.OrderStatusAccepted, result.Status)
+	assert.Equal(t, 2, result.Version)
+	assert.Len(t, result.Products, 1)
+	mockOrderRepo.AssertExpectations(t)
+}
+
+// TestOrderService_TransitionOrder_Rejected tests that stale versions are a 409 and illegal transitions a 422, neither
+// reaching the repository
+func TestOrderService_TransitionOrder_Rejected(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, new(MockProductRepository), new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockOrderRepo.On("GetOrderById", mock.Anything, orderId).
+		Return(&models.Order{Id: orderId, Status: models.OrderStatusPreparing, Version: 3}, []models.OrderItem{}, nil)
+
+	tests := []struct {
+		name    string
+		request *requests.OrderTransitionRequest
+		code    int
+		message string
+	}{
+		{"stale version", &requests.OrderTransitionRequest{Status: models.OrderStatusReady, Version: 2, Actor: "kitchen"},
+			http.StatusConflict, "order is at version 3, not 2, reload it and retry"},
+		{"cancelled while preparing", &requests.OrderTransitionRequest{Status: models.OrderStatusCancelled, Version: 3, Actor: "counter"},
+			http.StatusUnprocessableEntity, "cannot move an order from preparing to cancelled, it can only move to ready"},
+		{"skipping a step", &requests.OrderTransitionRequest{Status: models.OrderStatusCompleted, Version: 3, Actor: "counter"},
+			http.StatusUnprocessableEntity, "cannot move an order from preparing to completed, it can only move to ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.TransitionOrder(context.Background(), orderId, tt.request)
+
+			assert.Nil(t, result)
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.code, err.ErrorCode)
+			assert.Equal(t, tt.message, err.Message)
+		})
+	}
+
+	mockOrderRepo.AssertNotCalled(t, "TransitionOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestOrderService_TransitionOrder_Concurrent tests that a transition losing the race against another terminal is
+// returned as the 409 of the repository
+func TestOrderService_TransitionOrder_Concurrent(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	service := services.NewOrderServiceImpl(mockOrderRepo, mockProductRepo, new(MockModifierRepository), alwaysAvailable(), new(MockTranslationRepository), nil, nil)
+
+	orderId := "550e8400-e29b-41d4-a716-446655440000"
+	mockOrderRepo.On("GetOrderById", mock.Anything, orderId).
+		Return(&models.Order{Id: orderId, Status: models.OrderStatusReady, Version: 4}, []models.OrderItem{}, nil)
+	mockOrderRepo.On("TransitionOrderStatus", mock.Anything, mock.Anything, mock.Anything).
+		Return(exceptions.GenericException("order was changed concurrently, reload it and retry", http.StatusConflict))
+
+	result, err := service.TransitionOrder(context.Background(), orderId, &requests.OrderTransitionRequest{
+		Status: models.OrderStatusCompleted, Version: 4, Actor: "counter",
+	})
+
+	assert.Nil(t, result)
+	assert.Equal(t, http.StatusConflict, err.ErrorCode)
+	mockProductRepo.AssertNotCalled(t, "GetByIds", mock.Anything, mock.Anything)
+}
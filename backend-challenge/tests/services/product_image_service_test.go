@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"image"
+	"image/png"
+	"net/http"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/images"
+	"oolio.com/kart/models"
+	"oolio.com/kart/services"
+	"strings"
+	"testing"
+)
+
+var imageRenditions = []images.Rendition{
+	{Name: images.RenditionThumbnail, Width: 10},
+	{Name: images.RenditionMobile, Width: 20},
+	{Name: images.RenditionTablet, Width: 30},
+	{Name: images.RenditionDesktop, Width: 40},
+}
+
+// sourceImage encodes an opaque PNG to upload
+func sourceImage(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 60, 30))
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+// TestProductImageService_UploadProductImage_Success tests that the renditions are stored and the previous uploads deleted
+func TestProductImageService_UploadProductImage_Success(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockStorage := new(MockImageStorage)
+	service := services.NewProductImageServiceImpl(mockRepo, mockStorage, imageRenditions)
+
+	existing := &models.Product{Id: 1, Name: "Margherita Pizza", Image: models.Image{
+		Thumbnail: "/images/products/1/old-thumbnail.jpg",
+		Mobile:    "https://orderfoodonline.deno.dev/public/images/image-waffle-mobile.jpg",
+	}}
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+	mockStorage.On("Save", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "products/1/") && strings.HasSuffix(key, ".jpg")
+	}), "image/jpeg", mock.Anything).Return("/images/new.jpg", nil).Times(4)
+	mockRepo.On("UpdateImage", mock.Anything, mock.MatchedBy(func(product *models.Product) bool {
+		return product.Image.Thumbnail == "/images/new.jpg" && product.Image.Desktop == "/images/new.jpg"
+	})).Return(nil)
+	mockStorage.On("KeyOf", "/images/products/1/old-thumbnail.jpg").Return("products/1/old-thumbnail.jpg", true)
+	mockStorage.On("KeyOf", mock.Anything).Return("", false)
+	mockStorage.On("Delete", mock.Anything, "products/1/old-thumbnail.jpg").Return(nil).Once()
+
+	result, err := service.UploadProductImage(context.Background(), 1, bytes.NewReader(sourceImage(t)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/images/new.jpg", result.Image.Mobile)
+
+	mockRepo.AssertExpectations(t)
+	mockStorage.AssertExpectations(t)
+}
+
+// TestProductImageService_UploadProductImage_NotAnImage tests that a file that is not an image is rejected before anything is stored
+func TestProductImageService_UploadProductImage_NotAnImage(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockStorage := new(MockImageStorage)
+	service := services.NewProductImageServiceImpl(mockRepo, mockStorage, imageRenditions)
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil)
+
+	result, err := service.UploadProductImage(context.Background(), 1, strings.NewReader("name,price"))
+
+	assert.Nil(t, result)
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.ErrorCode)
+	mockStorage.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestProductImageService_UploadProductImage_UpdateFails tests that the stored renditions are deleted when the product cannot be updated
+func TestProductImageService_UploadProductImage_UpdateFails(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockStorage := new(MockImageStorage)
+	service := services.NewProductImageServiceImpl(mockRepo, mockStorage, imageRenditions)
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1}, nil)
+	mockStorage.On("Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("/images/new.jpg", nil)
+	mockRepo.On("UpdateImage", mock.Anything, mock.Anything).Return(&errors.ErrorDetails{
+		ErrorCode: http.StatusNotFound,
+		Message:   "product not found",
+	})
+	mockStorage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := service.UploadProductImage(context.Background(), 1, bytes.NewReader(sourceImage(t)))
+
+	assert.Nil(t, result)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+	mockStorage.AssertNumberOfCalls(t, "Delete", 4)
+}
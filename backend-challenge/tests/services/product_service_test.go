@@ -3,33 +3,40 @@ package services_test
 import (
 	"context"
 	"net/http"
+	"oolio.com/kart/dtos/requests"
 	"oolio.com/kart/services"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"oolio.com/kart/exceptions/errors"
 	"oolio.com/kart/models"
+	"oolio.com/kart/money"
 )
 
 // TestProductService_GetProducts_Success tests the GetProducts method of the ProductService
 func TestProductService_GetProducts_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
-	service := services.NewProductServiceImpl(mockRepo)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
 
 	mockProducts := []*models.Product{
-		{Id: 1, Name: "Product 1", Price: 10.00, Category: "Category1", Status: "available"},
-		{Id: 2, Name: "Product 2", Price: 20.00, Category: "Category2", Status: "available"},
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Category1", Status: "available"},
+		{Id: 2, Name: "Product 2", Price: money.MustParse("20.00"), Category: "Category2", Status: "available"},
 	}
 
-	mockRepo.On("ListProducts", mock.Anything, mock.Anything, mock.Anything).Return(mockProducts)
+	mockRepo.On("ListProducts", mock.Anything, mock.Anything).Return(mockProducts, int64(2), nil)
 
-	result := service.GetProducts(context.Background(), nil, nil)
+	result, err := service.GetProducts(context.Background(), &models.ProductFilter{})
 
+	assert.Nil(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
-	assert.Equal(t, "Product 1", result[0].Name)
-	assert.Equal(t, "Product 2", result[1].Name)
+	assert.Len(t, result.Products, 2)
+	assert.Equal(t, "Product 1", result.Products[0].Name)
+	assert.Equal(t, "Product 2", result.Products[1].Name)
+	assert.Equal(t, int64(2), result.TotalCount)
+	assert.Empty(t, result.NextCursor)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -37,20 +44,25 @@ func TestProductService_GetProducts_Success(t *testing.T) {
 // TestProductService_GetProducts_WithPagination tests the GetProducts method of the ProductService with pagination
 func TestProductService_GetProducts_WithPagination(t *testing.T) {
 	mockRepo := new(MockProductRepository)
-	service := services.NewProductServiceImpl(mockRepo)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
 
 	limit := 10
 	offset := 0
 	mockProducts := []*models.Product{
-		{Id: 1, Name: "Product 1", Price: 10.00, Category: "Category1", Status: "available"},
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00"), Category: "Category1", Status: "available"},
 	}
 
-	mockRepo.On("ListProducts", mock.Anything, &offset, &limit).Return(mockProducts)
+	filter := &models.ProductFilter{Offset: &offset, Limit: &limit}
+	mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(query *models.ProductFilter) bool {
+		return *query.Offset == 0 && *query.Limit == 11
+	})).Return(mockProducts, int64(1), nil)
 
-	result := service.GetProducts(context.Background(), &offset, &limit)
+	result, err := service.GetProducts(context.Background(), filter)
 
+	assert.Nil(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 1)
+	assert.Len(t, result.Products, 1)
+	assert.Empty(t, result.NextCursor)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -58,14 +70,173 @@ func TestProductService_GetProducts_WithPagination(t *testing.T) {
 // TestProductService_GetProducts_EmptyResult tests the GetProducts method of the ProductService with an empty result
 func TestProductService_GetProducts_EmptyResult(t *testing.T) {
 	mockRepo := new(MockProductRepository)
-	service := services.NewProductServiceImpl(mockRepo)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
 
-	mockRepo.On("ListProducts", mock.Anything, mock.Anything, mock.Anything).Return([]*models.Product{})
+	mockRepo.On("ListProducts", mock.Anything, mock.Anything).Return([]*models.Product{}, int64(0), nil)
 
-	result := service.GetProducts(context.Background(), nil, nil)
+	result, err := service.GetProducts(context.Background(), &models.ProductFilter{})
 
+	assert.Nil(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 0)
+	assert.Len(t, result.Products, 0)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_GetProducts_DefaultsToAvailable tests that only orderable products are listed unless a status is requested
+func TestProductService_GetProducts_DefaultsToAvailable(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(query *models.ProductFilter) bool {
+		return query.Status == "available"
+	})).Return([]*models.Product{}, int64(0), nil).Once()
+	mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(query *models.ProductFilter) bool {
+		return query.Status == "sold_out"
+	})).Return([]*models.Product{}, int64(0), nil).Once()
+
+	_, err := service.GetProducts(context.Background(), &models.ProductFilter{})
+	assert.Nil(t, err)
+
+	_, err = service.GetProducts(context.Background(), &models.ProductFilter{Status: "sold_out"})
+	assert.Nil(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_GetProducts_AvailableNow tests that available now is resolved to the current minute
+func TestProductService_GetProducts_AvailableNow(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	before := time.Now().Truncate(time.Minute)
+	mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(query *models.ProductFilter) bool {
+		return !query.AvailableNow && query.AvailableAt != nil &&
+			!query.AvailableAt.Before(before) && query.AvailableAt.Equal(query.AvailableAt.Truncate(time.Minute))
+	})).Return([]*models.Product{}, int64(0), nil).Once()
+
+	_, err := service.GetProducts(context.Background(), &models.ProductFilter{AvailableNow: true})
+	assert.Nil(t, err)
+
+	at := time.Now()
+	_, err = service.GetProducts(context.Background(), &models.ProductFilter{AvailableNow: true, AvailableAt: &at})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_GetProducts_InvalidPriceRange tests that a minimum price above the maximum price is rejected
+func TestProductService_GetProducts_InvalidPriceRange(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	minPrice := money.MustParse("20")
+	maxPrice := money.MustParse("10")
+
+	result, err := service.GetProducts(context.Background(), &models.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything)
+}
+
+// TestProductService_ValidateProductFilter tests that filters are checked like GetProducts does, without listing the
+// products
+func TestProductService_ValidateProductFilter(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	minPrice := money.MustParse("20")
+	maxPrice := money.MustParse("10")
+	at := time.Now()
+	offset := 5
+
+	for _, filter := range []*models.ProductFilter{
+		{MinPrice: &minPrice, MaxPrice: &maxPrice},
+		{AvailableNow: true, AvailableAt: &at},
+		{Cursor: "garbage"},
+		{Cursor: "garbage", Offset: &offset},
+	} {
+		err := service.ValidateProductFilter(filter)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+	}
+
+	assert.Nil(t, service.ValidateProductFilter(&models.ProductFilter{Sort: "price", MinPrice: &maxPrice, MaxPrice: &minPrice}))
+	mockRepo.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything)
+}
+
+// TestProductService_GetProducts_CursorPagination tests that the next cursor resumes after the last product of a page
+func TestProductService_GetProducts_CursorPagination(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	limit := 2
+	firstPage := []*models.Product{
+		{Id: 1, Name: "Product 1", Price: money.MustParse("10.00")},
+		{Id: 4, Name: "Product 4", Price: money.MustParse("12.50")},
+		{Id: 2, Name: "Product 2", Price: money.MustParse("12.50")},
+	}
+
+	mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(query *models.ProductFilter) bool {
+		return query.After == nil
+	})).Return(firstPage, int64(3), nil).Once()
+
+	result, err := service.GetProducts(context.Background(), &models.ProductFilter{Sort: "price", Limit: &limit})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Products, 2)
+	assert.Equal(t, int64(3), result.TotalCount)
+	assert.NotEmpty(t, result.NextCursor)
+
+	mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(query *models.ProductFilter) bool {
+		return query.After != nil && query.After.Id == 4 && query.After.Value == money.MustParse("12.5")
+	})).Return([]*models.Product{firstPage[2]}, int64(3), nil).Once()
+
+	result, err = service.GetProducts(context.Background(), &models.ProductFilter{Sort: "price", Limit: &limit, Cursor: result.NextCursor})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Products, 1)
+	assert.Empty(t, result.NextCursor)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_GetProducts_InvalidCursor tests that a malformed cursor is rejected
+func TestProductService_GetProducts_InvalidCursor(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	result, err := service.GetProducts(context.Background(), &models.ProductFilter{Cursor: "not-a-cursor"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything)
+}
+
+// TestProductService_GetProducts_CursorSortMismatch tests that a cursor cannot be reused with a different sort
+func TestProductService_GetProducts_CursorSortMismatch(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	limit := 1
+	mockRepo.On("ListProducts", mock.Anything, mock.Anything).
+		Return([]*models.Product{{Id: 1, Name: "A"}, {Id: 2, Name: "B"}}, int64(2), nil).Once()
+
+	page, err := service.GetProducts(context.Background(), &models.ProductFilter{Sort: "name", Limit: &limit})
+	assert.Nil(t, err)
+
+	result, err := service.GetProducts(context.Background(), &models.ProductFilter{Sort: "price", Limit: &limit, Cursor: page.NextCursor})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -73,12 +244,12 @@ func TestProductService_GetProducts_EmptyResult(t *testing.T) {
 // TestProductService_GetProductById_Success tests the GetProductById method of the ProductService
 func TestProductService_GetProductById_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
-	service := services.NewProductServiceImpl(mockRepo)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
 
 	mockProduct := &models.Product{
 		Id:       1,
 		Name:     "Margherita Pizza",
-		Price:    12.99,
+		Price:    money.MustParse("12.99"),
 		Category: "Pizza",
 		Status:   "available",
 	}
@@ -91,7 +262,7 @@ func TestProductService_GetProductById_Success(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, int64(1), result.Id)
 	assert.Equal(t, "Margherita Pizza", result.Name)
-	assert.Equal(t, 12.99, result.Price)
+	assert.Equal(t, money.MustParse("12.99"), result.Price)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -99,7 +270,7 @@ func TestProductService_GetProductById_Success(t *testing.T) {
 // TestProductService_GetProductById_NotFound tests the GetProductById method of the ProductService
 func TestProductService_GetProductById_NotFound(t *testing.T) {
 	mockRepo := new(MockProductRepository)
-	service := services.NewProductServiceImpl(mockRepo)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
 
 	mockError := &errors.ErrorDetails{
 		ErrorCode: http.StatusNotFound,
@@ -117,3 +288,365 @@ func TestProductService_GetProductById_NotFound(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+// TestProductService_GetProductPrices_Success tests that the price history is returned with the product
+func TestProductService_GetProductPrices_Success(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	changedAt := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	mockProduct := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("13.49"), PriceVersion: 2, Category: "Pizza"}
+	mockPrices := []*models.ProductPrice{
+		{ProductId: 1, Version: 2, Price: money.MustParse("13.49"), EffectiveFrom: changedAt},
+		{ProductId: 1, Version: 1, Price: money.MustParse("12.99"), EffectiveFrom: changedAt.AddDate(0, -1, 0), EffectiveTo: &changedAt},
+	}
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(mockProduct, nil)
+	mockRepo.On("GetPriceHistory", mock.Anything, int64(1)).Return(mockPrices, nil)
+
+	product, prices, err := service.GetProductPrices(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, product.PriceVersion)
+	assert.Len(t, prices, 2)
+	assert.Equal(t, money.MustParse("12.99"), prices[1].Price)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_GetProductPrices_NotFound tests that the history of a missing product is not fetched
+func TestProductService_GetProductPrices_NotFound(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusNotFound,
+		Message:   "product not found",
+	}
+
+	mockRepo.On("GetById", mock.Anything, int64(999)).Return(nil, mockError)
+
+	product, prices, err := service.GetProductPrices(context.Background(), 999)
+
+	assert.Nil(t, product)
+	assert.Nil(t, prices)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "GetPriceHistory", mock.Anything, mock.Anything)
+}
+
+// TestProductService_CreateProduct_DefaultsStatus tests that a new product without a status is available
+func TestProductService_CreateProduct_DefaultsStatus(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := services.NewProductServiceImpl(mockRepo, mockCategoryRepo, new(MockTranslationRepository))
+
+	price := money.MustParse("12.99")
+	request := &requests.ProductRequest{Name: " Margherita Pizza ", Category: "Pizza", Price: &price}
+
+	mockCategoryRepo.On("EnsurePath", mock.Anything, []string{"Pizza"}).Return(&models.Category{Id: 3, Name: "Pizza", Path: "Pizza"}, nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(product *models.Product) bool {
+		return product.Name == "Margherita Pizza" && product.Status == "available" && product.Price == money.MustParse("12.99") && product.CategoryId == 3
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Product).Id = 1
+	}).Return(nil)
+
+	result, err := service.CreateProduct(context.Background(), request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.Id)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_CreateProduct_Conflict tests that a duplicate product error is returned unchanged
+func TestProductService_CreateProduct_Conflict(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := services.NewProductServiceImpl(mockRepo, mockCategoryRepo, new(MockTranslationRepository))
+
+	price := money.MustParse("12.99")
+	request := &requests.ProductRequest{Name: "Margherita Pizza", Category: "Pizza", Price: &price}
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusConflict,
+		Message:   "product with this name and category already exists",
+	}
+
+	mockCategoryRepo.On("EnsurePath", mock.Anything, []string{"Pizza"}).Return(&models.Category{Id: 3, Name: "Pizza", Path: "Pizza"}, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*models.Product")).Return(mockError)
+
+	result, err := service.CreateProduct(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, err.ErrorCode)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_PatchProduct_OnlyChangesProvidedFields tests that a patch keeps the fields that were not sent
+func TestProductService_PatchProduct_OnlyChangesProvidedFields(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	existing := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"}
+	price := money.MustParse("9.99")
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(product *models.Product) bool {
+		return product.Name == "Margherita Pizza" && product.Category == "Pizza" && product.Price == money.MustParse("9.99")
+	})).Return(nil)
+
+	result, err := service.PatchProduct(context.Background(), 1, &requests.PatchProductRequest{Price: &price})
+
+	assert.Nil(t, err)
+	assert.Equal(t, money.MustParse("9.99"), result.Price)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_PatchProduct_BlankName tests that a patch cannot blank out the product name
+func TestProductService_PatchProduct_BlankName(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	existing := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"}
+	name := "   "
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+
+	result, err := service.PatchProduct(context.Background(), 1, &requests.PatchProductRequest{Name: &name})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestProductService_PatchProduct_DietaryAttributes tests that patched allergens are kept consistent with the existing diets
+func TestProductService_PatchProduct_DietaryAttributes(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	existing := &models.Product{Id: 1, Name: "Falafel Wrap", Price: money.MustParse("9"), Category: "Wraps", Status: "available", Diets: []string{"vegan"}}
+	allergens := []string{"sesame", "gluten", "sesame"}
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(product *models.Product) bool {
+		return slices.Equal(product.Allergens, []string{"gluten", "sesame"}) &&
+			slices.Equal(product.Diets, []string{"dairy_free", "vegan", "vegetarian"})
+	})).Return(nil)
+
+	_, err := service.PatchProduct(context.Background(), 1, &requests.PatchProductRequest{Allergens: &allergens})
+	assert.Nil(t, err)
+
+	allergens = []string{"eggs"}
+	_, err = service.PatchProduct(context.Background(), 1, &requests.PatchProductRequest{Allergens: &allergens})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+	assert.Contains(t, err.Message, "a vegan product cannot contain eggs")
+
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+// TestProductService_UpdateProduct_NotFound tests that replacing a missing product returns 404
+func TestProductService_UpdateProduct_NotFound(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	price := money.MustParse("12.99")
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusNotFound,
+		Message:   "product not found",
+	}
+
+	mockRepo.On("GetById", mock.Anything, int64(999)).Return(nil, mockError)
+
+	result, err := service.UpdateProduct(context.Background(), 999, &requests.ProductRequest{Name: "A", Category: "B", Price: &price})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_UpdateProductStatus_Success tests that an allowed status transition is saved
+func TestProductService_UpdateProductStatus_Success(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	existing := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"}
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(product *models.Product) bool {
+		return product.Status == "sold_out"
+	}), "available").Return(nil)
+
+	result, err := service.UpdateProductStatus(context.Background(), 1, &requests.ProductStatusRequest{Status: "sold_out"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "sold_out", result.Status)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_UpdateProductStatus_FromDiscontinued tests that a discontinued product cannot be made available again
+func TestProductService_UpdateProductStatus_FromDiscontinued(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	existing := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "discontinued"}
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+
+	result, err := service.UpdateProductStatus(context.Background(), 1, &requests.ProductStatusRequest{Status: "available"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestProductService_PatchProduct_InvalidStatusTransition tests that a patch obeys the status transition rules
+func TestProductService_PatchProduct_InvalidStatusTransition(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	existing := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "discontinued"}
+	status := "hidden"
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+
+	result, err := service.PatchProduct(context.Background(), 1, &requests.PatchProductRequest{Status: &status})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestProductService_CreateProduct_NestedCategoryPath tests that a category path is normalized and resolved to its category
+func TestProductService_CreateProduct_NestedCategoryPath(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := services.NewProductServiceImpl(mockRepo, mockCategoryRepo, new(MockTranslationRepository))
+
+	price := money.MustParse("11.5")
+	request := &requests.ProductRequest{Name: "Garden Pizza", Category: "Pizza>Vegetarian ", Price: &price}
+	category := &models.Category{Id: 7, Name: "Vegetarian", Path: "Pizza > Vegetarian"}
+
+	mockCategoryRepo.On("EnsurePath", mock.Anything, []string{"Pizza", "Vegetarian"}).Return(category, nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(product *models.Product) bool {
+		return product.CategoryId == 7
+	})).Return(nil)
+
+	result, err := service.CreateProduct(context.Background(), request)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Pizza > Vegetarian", result.Category)
+
+	mockCategoryRepo.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_CreateProduct_UnknownCategoryId tests that a product cannot reference a missing category
+func TestProductService_CreateProduct_UnknownCategoryId(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := services.NewProductServiceImpl(mockRepo, mockCategoryRepo, new(MockTranslationRepository))
+
+	price := money.MustParse("11.5")
+	request := &requests.ProductRequest{Name: "Garden Pizza", CategoryId: "42", Price: &price}
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusNotFound,
+		Message:   "category not found",
+	}
+
+	mockCategoryRepo.On("GetById", mock.Anything, int64(42)).Return(nil, mockError)
+
+	result, err := service.CreateProduct(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.ErrorCode)
+	assert.Equal(t, "product category not found", err.Message)
+
+	mockCategoryRepo.AssertNotCalled(t, "EnsurePath", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+// TestProductService_PatchProduct_InvalidCategoryPath tests that a category path with an empty level is rejected
+func TestProductService_PatchProduct_InvalidCategoryPath(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := services.NewProductServiceImpl(mockRepo, mockCategoryRepo, new(MockTranslationRepository))
+
+	existing := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), CategoryId: 3, Category: "Pizza", Status: "available"}
+	category := "Pizza > "
+
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(existing, nil)
+
+	result, err := service.PatchProduct(context.Background(), 1, &requests.PatchProductRequest{Category: &category})
+
+	assert.Nil(t, result)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestProductService_GetProducts_Deleted tests that deleted products are listed whatever their status
+func TestProductService_GetProducts_Deleted(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	mockRepo.On("ListProducts", mock.Anything, mock.MatchedBy(func(query *models.ProductFilter) bool {
+		return query.Deleted && query.Status == ""
+	})).Return([]*models.Product{}, int64(0), nil)
+
+	_, err := service.GetProducts(context.Background(), &models.ProductFilter{Deleted: true})
+	assert.Nil(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_RestoreProduct_Success tests that a restored product is returned as it is back on the menu
+func TestProductService_RestoreProduct_Success(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	restored := &models.Product{Id: 1, Name: "Margherita Pizza", Price: money.MustParse("12.99"), Category: "Pizza", Status: "available"}
+
+	mockRepo.On("Restore", mock.Anything, int64(1)).Return(nil)
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(restored, nil)
+
+	result, err := service.RestoreProduct(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, restored, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestProductService_RestoreProduct_NotDeleted tests that restoring a product that is not deleted is rejected
+func TestProductService_RestoreProduct_NotDeleted(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), new(MockTranslationRepository))
+
+	mockError := &errors.ErrorDetails{
+		ErrorCode: http.StatusNotFound,
+		Message:   "deleted product not found",
+	}
+
+	mockRepo.On("Restore", mock.Anything, int64(1)).Return(mockError)
+
+	result, err := service.RestoreProduct(context.Background(), 1)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+
+	mockRepo.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+}
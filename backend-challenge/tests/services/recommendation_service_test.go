@@ -0,0 +1,134 @@
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/constants"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/models"
+	"oolio.com/kart/services"
+	"testing"
+	"time"
+)
+
+func recommendationConfig() configs.RecommendationConfiguration {
+	return configs.RecommendationConfiguration{Window: 30 * 24 * time.Hour, RefreshInterval: time.Hour, MinOrders: 2, QuoteSuggestions: 2}
+}
+
+// TestRecommendationService_GetProductRecommendations_Success tests that the products that cannot be ordered now are
+// left out and the others returned best score first up to the limit
+func TestRecommendationService_GetProductRecommendations_Success(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockRecommendationRepo := new(MockRecommendationRepository)
+	mockAvailabilityRepo := new(MockAvailabilityRepository)
+	service := services.NewRecommendationServiceImpl(mockProductRepo, mockRecommendationRepo, mockAvailabilityRepo, new(MockTranslationRepository), recommendationConfig())
+
+	deletedAt := time.Now()
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(&models.Product{Id: 1, Name: "Chicken Waffle", Status: models.ProductStatusAvailable}, nil)
+	mockRecommendationRepo.On("GetRecommendations", mock.Anything, []int64{1}).Return([]*models.Recommendation{
+		{ProductId: 1, RecommendedProductId: 5, Score: 0.9},
+		{ProductId: 1, RecommendedProductId: 2, Score: 0.8},
+		{ProductId: 1, RecommendedProductId: 3, Score: 0.7},
+		{ProductId: 1, RecommendedProductId: 4, Score: 0.6},
+		{ProductId: 1, RecommendedProductId: 6, Score: 0.5},
+		{ProductId: 1, RecommendedProductId: 7, Score: 0.4},
+		{ProductId: 1, RecommendedProductId: 8, Score: 0.3},
+	}, nil)
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{5, 2, 3, 4, 6, 7, 8}).Return([]*models.Product{
+		{Id: 2, Name: "Lemonade", Status: models.ProductStatusSoldOut},
+		{Id: 3, Name: "Iced Tea", Status: models.ProductStatusAvailable, DeletedAt: &deletedAt},
+		{Id: 4, Name: "Brownie", Status: models.ProductStatusAvailable},
+		{Id: 6, Name: "Pancakes", Status: models.ProductStatusAvailable},
+		{Id: 7, Name: "Coffee", Status: models.ProductStatusAvailable},
+		{Id: 8, Name: "Milkshake", Status: models.ProductStatusAvailable},
+	}, nil)
+	// Product 6 is never open, product 5 no longer exists
+	mockAvailabilityRepo.On("GetWindowsByProductIds", mock.Anything, mock.Anything).Return(map[int64][]*models.AvailabilityWindow{
+		6: {{DaysOfWeek: []int{}, StartMinute: 0, EndMinute: 1}},
+	}, nil)
+
+	products, err := service.GetProductRecommendations(context.Background(), 1, 2)
+
+	assert.Nil(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, int64(4), products[0].Id)
+	assert.Equal(t, int64(7), products[1].Id)
+}
+
+// TestRecommendationService_GetProductRecommendations_NotFound tests that recommendations of a missing product are a 404
+func TestRecommendationService_GetProductRecommendations_NotFound(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockRecommendationRepo := new(MockRecommendationRepository)
+	service := services.NewRecommendationServiceImpl(mockProductRepo, mockRecommendationRepo, new(MockAvailabilityRepository), new(MockTranslationRepository), recommendationConfig())
+
+	mockProductRepo.On("GetById", mock.Anything, int64(99)).Return(nil, exceptions.GenericException("product not found", http.StatusNotFound))
+
+	products, err := service.GetProductRecommendations(context.Background(), 99, 5)
+
+	assert.Nil(t, products)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+	mockRecommendationRepo.AssertNotCalled(t, "GetRecommendations", mock.Anything, mock.Anything)
+}
+
+// TestRecommendationService_SuggestProducts_Success tests that the scores of a product recommended for several products
+// of an order add up and that the products of the order are not suggested
+func TestRecommendationService_SuggestProducts_Success(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockRecommendationRepo := new(MockRecommendationRepository)
+	service := services.NewRecommendationServiceImpl(mockProductRepo, mockRecommendationRepo, alwaysAvailable(), new(MockTranslationRepository), recommendationConfig())
+
+	mockRecommendationRepo.On("GetRecommendations", mock.Anything, []int64{1, 2}).Return([]*models.Recommendation{
+		{ProductId: 1, RecommendedProductId: 2, Score: 0.9},
+		{ProductId: 1, RecommendedProductId: 3, Score: 0.6},
+		{ProductId: 2, RecommendedProductId: 4, Score: 0.5},
+		{ProductId: 2, RecommendedProductId: 3, Score: 0.3},
+		{ProductId: 1, RecommendedProductId: 5, Score: 0.2},
+	}, nil)
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{3, 4, 5}).Return([]*models.Product{
+		{Id: 3, Name: "Lemonade", Status: models.ProductStatusAvailable},
+		{Id: 4, Name: "Brownie", Status: models.ProductStatusAvailable},
+		{Id: 5, Name: "Coffee", Status: models.ProductStatusAvailable},
+	}, nil)
+
+	products, err := service.SuggestProducts(context.Background(), []int64{1, 2})
+
+	assert.Nil(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, int64(3), products[0].Id)
+	assert.Equal(t, int64(4), products[1].Id)
+}
+
+// TestRecommendationService_SuggestProducts_Disabled tests that no suggestions are looked up when they are disabled
+func TestRecommendationService_SuggestProducts_Disabled(t *testing.T) {
+	mockRecommendationRepo := new(MockRecommendationRepository)
+	config := recommendationConfig()
+	config.QuoteSuggestions = 0
+	service := services.NewRecommendationServiceImpl(new(MockProductRepository), mockRecommendationRepo, alwaysAvailable(), new(MockTranslationRepository), config)
+
+	products, err := service.SuggestProducts(context.Background(), []int64{1})
+
+	assert.Nil(t, err)
+	assert.Empty(t, products)
+	mockRecommendationRepo.AssertNotCalled(t, "GetRecommendations", mock.Anything, mock.Anything)
+}
+
+// TestRecommendationService_RefreshRecommendations tests that the recommendations are computed from the orders of the
+// configured window
+func TestRecommendationService_RefreshRecommendations(t *testing.T) {
+	mockRecommendationRepo := new(MockRecommendationRepository)
+	service := services.NewRecommendationServiceImpl(new(MockProductRepository), mockRecommendationRepo, alwaysAvailable(), new(MockTranslationRepository), recommendationConfig())
+
+	before := time.Now()
+	mockRecommendationRepo.On("RefreshRecommendations", mock.Anything, mock.MatchedBy(func(since time.Time) bool {
+		return !since.Before(before.Add(-30*24*time.Hour)) && since.Before(before.Add(-29*24*time.Hour))
+	}), 2, constants.MaxRecommendations).Return(int64(12), true, nil)
+
+	err := service.RefreshRecommendations(context.Background())
+
+	assert.Nil(t, err)
+	mockRecommendationRepo.AssertExpectations(t)
+}
@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+	"oolio.com/kart/services"
+	"testing"
+	"time"
+)
+
+const refundOrderId = "550e8400-e29b-41d4-a716-446655440000"
+
+// completedOrder is an order of 2 waffles and a coffee, charged 27 after a 10% coupon
+func completedOrder(status string) (*models.Order, []models.OrderItem) {
+	order := &models.Order{Id: refundOrderId, Status: status, Version: 5, Subtotal: money.MustParse("30"), Discount: money.MustParse("3"), Total: money.MustParse("27")}
+	items := []models.OrderItem{
+		{Id: 11, OrderId: refundOrderId, ProductId: 1, Quantity: 2, UnitPrice: money.MustParse("10"), Price: money.MustParse("20")},
+		{Id: 12, OrderId: refundOrderId, ProductId: 2, Quantity: 1, UnitPrice: money.MustParse("10"), Price: money.MustParse("10")},
+	}
+	return order, items
+}
+
+// TestRefundService_RefundOrder_Partial tests that a partial refund is recorded against the refunds it was planned on
+// and its receipt carries the running totals of the order
+func TestRefundService_RefundOrder_Partial(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockRefundRepo := new(MockRefundRepository)
+	service := services.NewRefundServiceImpl(mockOrderRepo, mockProductRepo, mockRefundRepo, new(MockTranslationRepository))
+
+	order, items := completedOrder(models.OrderStatusCompleted)
+	previous := []*models.Refund{{Id: 1, OrderId: refundOrderId, Amount: money.MustParse("9"), Actor: "counter",
+		Items: []models.RefundItem{{OrderItemId: 12, ProductId: 2, Quantity: 1, Amount: money.MustParse("9")}}}}
+	mockOrderRepo.On("GetOrderById", mock.Anything, refundOrderId).Return(order, items, nil)
+	mockRefundRepo.On("GetRefundsByOrderId", mock.Anything, refundOrderId).Return(previous, nil)
+	mockRefundRepo.On("CreateRefund", mock.Anything, mock.MatchedBy(func(refund *models.Refund) bool {
+		return refund.Amount == money.MustParse("9") && len(refund.Items) == 1 && refund.Items[0].OrderItemId == 11 &&
+			refund.Actor == "counter" && refund.Reason == "Cold waffle" && refund.Restock
+	}), 1).Run(func(args mock.Arguments) {
+		refund := args.Get(1).(*models.Refund)
+		refund.Id = 2
+		refund.CreatedAt = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	}).Return(nil)
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).
+		Return([]*models.Product{{Id: 1, Name: "Chicken Waffle"}, {Id: 2, Name: "Flat White"}}, nil)
+
+	receipt, err := service.RefundOrder(context.Background(), refundOrderId, &requests.RefundRequest{
+		Items:   []requests.RefundItemRequest{{OrderItemId: "11", Quantity: 1}},
+		Actor:   "counter",
+		Reason:  "Cold waffle",
+		Restock: true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2", receipt.Id)
+	assert.Equal(t, money.MustParse("9.0"), receipt.Amount)
+	assert.Equal(t, "Chicken Waffle", receipt.Items[0].Name)
+	assert.Equal(t, money.MustParse("27.0"), receipt.OrderTotal)
+	assert.Equal(t, money.MustParse("18.0"), receipt.RefundedTotal)
+	assert.Equal(t, money.MustParse("9.0"), receipt.Remaining)
+	assert.True(t, receipt.Restocked)
+	mockRefundRepo.AssertExpectations(t)
+}
+
+// TestRefundService_RefundOrder_Rejected tests that malformed requests, orders that are not completed and refunds of
+// more than is left never reach the ledger
+func TestRefundService_RefundOrder_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		items   []requests.RefundItemRequest
+		code    int
+		message string
+	}{
+		{"malformed item id", models.OrderStatusCompleted, []requests.RefundItemRequest{{OrderItemId: "waffle", Quantity: 1}},
+			http.StatusBadRequest, "invalid order item id"},
+		{"item listed twice", models.OrderStatusCompleted, []requests.RefundItemRequest{{OrderItemId: "11", Quantity: 1}, {OrderItemId: "11", Quantity: 1}},
+			http.StatusBadRequest, "order item 11 is listed more than once"},
+		{"order not completed", models.OrderStatusPreparing, nil,
+			http.StatusUnprocessableEntity, "cannot refund a preparing order, only completed orders are refunded"},
+		{"more than ordered", models.OrderStatusCompleted, []requests.RefundItemRequest{{OrderItemId: "12", Quantity: 2}},
+			http.StatusUnprocessableEntity, "only 1 of order item 12 left to refund"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderRepo := new(MockOrderRepository)
+			mockRefundRepo := new(MockRefundRepository)
+			service := services.NewRefundServiceImpl(mockOrderRepo, new(MockProductRepository), mockRefundRepo, new(MockTranslationRepository))
+
+			order, items := completedOrder(tt.status)
+			mockOrderRepo.On("GetOrderById", mock.Anything, refundOrderId).Return(order, items, nil)
+			mockRefundRepo.On("GetRefundsByOrderId", mock.Anything, refundOrderId).Return([]*models.Refund{}, nil)
+
+			receipt, err := service.RefundOrder(context.Background(), refundOrderId, &requests.RefundRequest{Items: tt.items, Actor: "counter"})
+
+			assert.Nil(t, receipt)
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.code, err.ErrorCode)
+			assert.Equal(t, tt.message, err.Message)
+			mockRefundRepo.AssertNotCalled(t, "CreateRefund", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestRefundService_RefundOrder_Concurrent tests that a refund losing the race against another refund of the order is
+// returned as the 409 of the repository
+func TestRefundService_RefundOrder_Concurrent(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockRefundRepo := new(MockRefundRepository)
+	service := services.NewRefundServiceImpl(mockOrderRepo, new(MockProductRepository), mockRefundRepo, new(MockTranslationRepository))
+
+	order, items := completedOrder(models.OrderStatusCompleted)
+	mockOrderRepo.On("GetOrderById", mock.Anything, refundOrderId).Return(order, items, nil)
+	mockRefundRepo.On("GetRefundsByOrderId", mock.Anything, refundOrderId).Return([]*models.Refund{}, nil)
+	mockRefundRepo.On("CreateRefund", mock.Anything, mock.Anything, 0).
+		Return(&errors.ErrorDetails{ErrorCode: http.StatusConflict, Message: "order was changed concurrently, reload it and retry"})
+
+	receipt, err := service.RefundOrder(context.Background(), refundOrderId, &requests.RefundRequest{Actor: "counter"})
+
+	assert.Nil(t, receipt)
+	assert.Equal(t, http.StatusConflict, err.ErrorCode)
+}
+
+// TestRefundService_GetRefunds tests that receipts carry the totals refunded up to each refund and that unknown
+// refunds are not found
+func TestRefundService_GetRefunds(t *testing.T) {
+	mockOrderRepo := new(MockOrderRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockRefundRepo := new(MockRefundRepository)
+	service := services.NewRefundServiceImpl(mockOrderRepo, mockProductRepo, mockRefundRepo, new(MockTranslationRepository))
+
+	order, items := completedOrder(models.OrderStatusCompleted)
+	mockOrderRepo.On("GetOrderById", mock.Anything, refundOrderId).Return(order, items, nil)
+	mockRefundRepo.On("GetRefundsByOrderId", mock.Anything, refundOrderId).Return([]*models.Refund{
+		{Id: 1, OrderId: refundOrderId, Amount: money.MustParse("9"), Items: []models.RefundItem{{OrderItemId: 12, ProductId: 2, Quantity: 1, Amount: money.MustParse("9")}}},
+		{Id: 2, OrderId: refundOrderId, Amount: money.MustParse("18"), Items: []models.RefundItem{{OrderItemId: 11, ProductId: 1, Quantity: 2, Amount: money.MustParse("18")}}},
+	}, nil)
+	mockProductRepo.On("GetByIds", mock.Anything, []int64{1, 2}).
+		Return([]*models.Product{{Id: 1, Name: "Chicken Waffle"}, {Id: 2, Name: "Flat White"}}, nil)
+
+	receipts, err := service.GetRefunds(context.Background(), refundOrderId)
+
+	assert.Nil(t, err)
+	assert.Len(t, receipts, 2)
+	assert.Equal(t, money.MustParse("9.0"), receipts[0].RefundedTotal)
+	assert.Equal(t, money.MustParse("18.0"), receipts[0].Remaining)
+	assert.Equal(t, money.MustParse("27.0"), receipts[1].RefundedTotal)
+	assert.Equal(t, money.MustParse("0.0"), receipts[1].Remaining)
+
+	receipt, err := service.GetRefund(context.Background(), refundOrderId, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "Chicken Waffle", receipt.Items[0].Name)
+
+	receipt, err = service.GetRefund(context.Background(), refundOrderId, 3)
+	assert.Nil(t, receipt)
+	assert.Equal(t, http.StatusNotFound, err.ErrorCode)
+}
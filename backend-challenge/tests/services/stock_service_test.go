@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/exceptions"
+	"oolio.com/kart/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"oolio.com/kart/exceptions/errors"
+	"oolio.com/kart/models"
+	"oolio.com/kart/money"
+)
+
+// TestStockService_GetStock_Success tests that the stock is returned with its adjustments
+func TestStockService_GetStock_Success(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockStockRepo := new(MockStockRepository)
+	service := services.NewStockServiceImpl(mockProductRepo, mockStockRepo)
+
+	quantity := 5
+	product := &models.Product{Id: 1, Name: "Daily Special", Price: money.MustParse("15.00"), Category: "Specials", Status: "available", StockQuantity: &quantity}
+	adjustments := []*models.StockAdjustment{{Id: 1, ProductId: 1, Reason: "daily delivery", QuantityAfter: &quantity}}
+
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(product, nil)
+	mockStockRepo.On("GetAdjustments", mock.Anything, int64(1), mock.Anything).Return(adjustments, nil)
+
+	result, resultAdjustments, err := service.GetStock(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, *result.StockQuantity)
+	assert.Len(t, resultAdjustments, 1)
+
+	mockProductRepo.AssertExpectations(t)
+	mockStockRepo.AssertExpectations(t)
+}
+
+// TestStockService_AdjustStock_Delta tests that a relative adjustment is passed to the repository
+func TestStockService_AdjustStock_Delta(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockStockRepo := new(MockStockRepository)
+	service := services.NewStockServiceImpl(mockProductRepo, mockStockRepo)
+
+	delta := -2
+	quantity := 3
+	product := &models.Product{Id: 1, Name: "Daily Special", Price: money.MustParse("15.00"), Category: "Specials", Status: "available", StockQuantity: &quantity}
+
+	mockStockRepo.On("AdjustStock", mock.Anything, mock.MatchedBy(func(adjustment *models.StockAdjustment) bool {
+		return adjustment.ProductId == 1 && *adjustment.Delta == -2 && adjustment.QuantityAfter == nil &&
+			adjustment.Reason == "spoiled" && adjustment.Actor == "kitchen"
+	})).Return(nil)
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(product, nil)
+	mockStockRepo.On("GetAdjustments", mock.Anything, int64(1), mock.Anything).Return([]*models.StockAdjustment{}, nil)
+
+	result, _, err := service.AdjustStock(context.Background(), 1, &requests.StockAdjustmentRequest{Delta: &delta, Reason: "spoiled", Actor: "kitchen"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, *result.StockQuantity)
+
+	mockProductRepo.AssertExpectations(t)
+	mockStockRepo.AssertExpectations(t)
+}
+
+// TestStockService_AdjustStock_Unlimited tests that making the stock unlimited clears the quantity
+func TestStockService_AdjustStock_Unlimited(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockStockRepo := new(MockStockRepository)
+	service := services.NewStockServiceImpl(mockProductRepo, mockStockRepo)
+
+	product := &models.Product{Id: 1, Name: "Daily Special", Price: money.MustParse("15.00"), Category: "Specials", Status: "available"}
+
+	mockStockRepo.On("AdjustStock", mock.Anything, mock.MatchedBy(func(adjustment *models.StockAdjustment) bool {
+		return adjustment.Delta == nil && adjustment.QuantityAfter == nil
+	})).Return(nil)
+	mockProductRepo.On("GetById", mock.Anything, int64(1)).Return(product, nil)
+	mockStockRepo.On("GetAdjustments", mock.Anything, int64(1), mock.Anything).Return([]*models.StockAdjustment{}, nil)
+
+	result, _, err := service.AdjustStock(context.Background(), 1, &requests.StockAdjustmentRequest{Unlimited: true, Reason: "no longer limited"})
+
+	assert.Nil(t, err)
+	assert.Nil(t, result.StockQuantity)
+
+	mockStockRepo.AssertExpectations(t)
+}
+
+// TestStockService_AdjustStock_AmbiguousRequest tests that exactly one kind of adjustment is accepted
+func TestStockService_AdjustStock_AmbiguousRequest(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockStockRepo := new(MockStockRepository)
+	service := services.NewStockServiceImpl(mockProductRepo, mockStockRepo)
+
+	delta := 2
+	quantity := 10
+
+	for _, request := range []*requests.StockAdjustmentRequest{
+		{Reason: "nothing"},
+		{Delta: &delta, Quantity: &quantity, Reason: "both"},
+		{Quantity: &quantity, Unlimited: true, Reason: "both"},
+	} {
+		result, _, err := service.AdjustStock(context.Background(), 1, request)
+
+		assert.Nil(t, result)
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+	}
+
+	mockStockRepo.AssertNotCalled(t, "AdjustStock", mock.Anything, mock.Anything)
+}
+
+// TestStockService_AdjustStock_Insufficient tests that a negative resulting stock is reported per product
+func TestStockService_AdjustStock_Insufficient(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockStockRepo := new(MockStockRepository)
+	service := services.NewStockServiceImpl(mockProductRepo, mockStockRepo)
+
+	delta := -10
+	stockErr := exceptions.InsufficientStockException([]errors.ItemError{{ProductId: "1", Reason: "insufficient_stock", Message: "only 3 left in stock"}})
+	mockStockRepo.On("AdjustStock", mock.Anything, mock.Anything).Return(stockErr)
+
+	result, _, err := service.AdjustStock(context.Background(), 1, &requests.StockAdjustmentRequest{Delta: &delta, Reason: "spoiled"})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.ErrorCode)
+	assert.Equal(t, "insufficient_stock", err.Items[0].Reason)
+
+	mockProductRepo.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,140 @@
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"oolio.com/kart/configs"
+	"oolio.com/kart/dtos/requests"
+	"oolio.com/kart/i18n"
+	"oolio.com/kart/models"
+	"oolio.com/kart/services"
+	"testing"
+)
+
+// withLocales serves the supported locales for the duration of a test, English being the default one
+func withLocales(t *testing.T, supported ...string) {
+	locales, err := i18n.NewLocales("en", supported)
+	assert.NoError(t, err)
+
+	previous := configs.Locales
+	configs.Locales = locales
+	t.Cleanup(func() { configs.Locales = previous })
+}
+
+// TestProductService_GetProductById_Localized tests that the name, description and category path of a product are
+// translated where a translation exists
+func TestProductService_GetProductById_Localized(t *testing.T) {
+	withLocales(t, "fr")
+	mockRepo := new(MockProductRepository)
+	mockTranslationRepo := new(MockTranslationRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), mockTranslationRepo)
+
+	product := &models.Product{Id: 1, Name: "Chocolate Waffle", Description: "Crispy waffle", Category: "Waffles > Sweet"}
+	mockRepo.On("GetById", mock.Anything, int64(1)).Return(product, nil)
+	mockTranslationRepo.On("GetProductTranslations", mock.Anything, []int64{1}, "fr").Return(map[int64]*models.ProductTranslation{
+		1: {ProductId: 1, Locale: "fr", Name: "Gaufre au chocolat", Description: "Gaufre croustillante"},
+	}, nil)
+	mockTranslationRepo.On("GetCategoryTranslations", mock.Anything, []string{"Waffles", "Waffles > Sweet"}, "fr").Return(map[string]*models.CategoryTranslation{
+		"Waffles": {CategoryId: 2, Path: "Waffles", Locale: "fr", Name: "Gaufres"},
+	}, nil)
+
+	ctx := i18n.WithLocale(context.Background(), "fr")
+	result, err := service.GetProductById(ctx, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Gaufre au chocolat", result.Name)
+	assert.Equal(t, "Gaufre croustillante", result.Description)
+	assert.Equal(t, "Gaufres > Sweet", result.Category)
+	mockTranslationRepo.AssertExpectations(t)
+}
+
+// TestProductService_GetProducts_DefaultLocale tests that products in the default locale are not translated
+func TestProductService_GetProducts_DefaultLocale(t *testing.T) {
+	withLocales(t, "fr")
+	mockRepo := new(MockProductRepository)
+	mockTranslationRepo := new(MockTranslationRepository)
+	service := services.NewProductServiceImpl(mockRepo, new(MockCategoryRepository), mockTranslationRepo)
+
+	mockRepo.On("ListProducts", mock.Anything, mock.Anything).Return([]*models.Product{{Id: 1, Name: "Chocolate Waffle"}}, int64(1), nil)
+
+	result, err := service.GetProducts(i18n.WithLocale(context.Background(), "en"), &models.ProductFilter{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Chocolate Waffle", result.Products[0].Name)
+	mockTranslationRepo.AssertNotCalled(t, "GetProductTranslations", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCategoryService_GetCategoryTree_Localized tests that the names and paths of nested categories are translated
+func TestCategoryService_GetCategoryTree_Localized(t *testing.T) {
+	withLocales(t, "fr")
+	mockRepo := new(MockCategoryRepository)
+	mockTranslationRepo := new(MockTranslationRepository)
+	service := services.NewCategoryServiceImpl(mockRepo, mockTranslationRepo)
+
+	mockRepo.On("ListCategories", mock.Anything).Return(categoryFixtures(), nil)
+	mockTranslationRepo.On("GetCategoryTranslations", mock.Anything, mock.Anything, "fr").Return(map[string]*models.CategoryTranslation{
+		"Pizza":              {CategoryId: 1, Path: "Pizza", Locale: "fr", Name: "Pizzas"},
+		"Pizza > Vegetarian": {CategoryId: 3, Path: "Pizza > Vegetarian", Locale: "fr", Name: "Végétariennes"},
+	}, nil)
+
+	tree, err := service.GetCategoryTree(i18n.WithLocale(context.Background(), "fr"), false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Pizzas", tree[0].Name)
+	assert.Equal(t, "Pizzas", tree[0].Path)
+	assert.Equal(t, "Végétariennes", tree[0].Children[0].Name)
+	assert.Equal(t, "Pizzas > Végétariennes", tree[0].Children[0].Path)
+	assert.Equal(t, "Drinks", tree[1].Name)
+}
+
+// TestTranslationService_SetProductTranslation_Success tests that the locale is canonicalized and the fields trimmed
+func TestTranslationService_SetProductTranslation_Success(t *testing.T) {
+	withLocales(t, "fr", "pt-BR")
+	mockTranslationRepo := new(MockTranslationRepository)
+	service := services.NewTranslationServiceImpl(new(MockProductRepository), new(MockCategoryRepository), mockTranslationRepo)
+
+	mockTranslationRepo.On("SaveProductTranslation", mock.Anything, mock.MatchedBy(func(translation *models.ProductTranslation) bool {
+		return translation.ProductId == 1 && translation.Locale == "pt-BR" && translation.Name == "Waffle de chocolate"
+	})).Return(nil)
+
+	translation, err := service.SetProductTranslation(context.Background(), 1, "pt-br", &requests.ProductTranslationRequest{Name: " Waffle de chocolate "})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "pt-BR", translation.Locale)
+	mockTranslationRepo.AssertExpectations(t)
+}
+
+// TestTranslationService_SetProductTranslation_InvalidLocale tests that translations are only written in the supported
+// locales other than the default one
+func TestTranslationService_SetProductTranslation_InvalidLocale(t *testing.T) {
+	withLocales(t, "fr")
+	mockTranslationRepo := new(MockTranslationRepository)
+	service := services.NewTranslationServiceImpl(new(MockProductRepository), new(MockCategoryRepository), mockTranslationRepo)
+
+	_, err := service.SetProductTranslation(context.Background(), 1, "de", &requests.ProductTranslationRequest{Name: "Schokoladenwaffel"})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+	assert.Contains(t, err.Message, "en, fr")
+
+	_, err = service.SetProductTranslation(context.Background(), 1, "EN", &requests.ProductTranslationRequest{Name: "Chocolate Waffle"})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+
+	mockTranslationRepo.AssertNotCalled(t, "SaveProductTranslation", mock.Anything, mock.Anything)
+}
+
+// TestTranslationService_SetCategoryTranslation_InvalidName tests that translated category names follow the rules of
+// category names
+func TestTranslationService_SetCategoryTranslation_InvalidName(t *testing.T) {
+	withLocales(t, "fr")
+	mockTranslationRepo := new(MockTranslationRepository)
+	service := services.NewTranslationServiceImpl(new(MockProductRepository), new(MockCategoryRepository), mockTranslationRepo)
+
+	_, err := service.SetCategoryTranslation(context.Background(), 1, "fr", &requests.CategoryTranslationRequest{Name: "Pizzas > Végétariennes"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.ErrorCode)
+	mockTranslationRepo.AssertNotCalled(t, "SaveCategoryTranslation", mock.Anything, mock.Anything)
+}